@@ -1,8 +1,10 @@
 package chain_task
 
 import (
+	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/difyz9/ytb2bili/internal/chain_task/handlers"
@@ -12,6 +14,7 @@ import (
 	"github.com/difyz9/ytb2bili/internal/core/services"
 	"github.com/difyz9/ytb2bili/internal/core/types"
 	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/translator"
 
 	"sync"
 
@@ -27,20 +30,30 @@ type ChainTaskHandler struct {
 
 	SavedVideoService *services.SavedVideoService
 	TaskStepService   *services.TaskStepService
-
-	isRunning bool
-	Task      *cron.Cron
-	Db        *gorm.DB
-	mutex     sync.Mutex
+	AIService         *services.AIServiceManager // 元数据生成、字幕修复等对话调用按首选服务和故障转移顺序选择提供商
+	PartitionService  *services.BiliPartitionService
+	ReviewService     *services.ReviewService // 判断视频上传前是否需要人工审核
+
+	isRunning       bool
+	budgetCheckedAt time.Time // 上次检查预算暂停视频能否恢复的时间
+	Task            *cron.Cron
+	Db              *gorm.DB
+	mutex           sync.Mutex
 }
 
-func NewChainTaskHandler(app *core.AppServer, task *cron.Cron, db *gorm.DB, savedVideoService *services.SavedVideoService, taskStepService *services.TaskStepService) *ChainTaskHandler {
+// budgetResumeInterval 检查预算暂停的视频能否恢复执行的间隔
+const budgetResumeInterval = time.Minute
+
+func NewChainTaskHandler(app *core.AppServer, task *cron.Cron, db *gorm.DB, savedVideoService *services.SavedVideoService, taskStepService *services.TaskStepService, aiService *services.AIServiceManager, partitionService *services.BiliPartitionService, reviewService *services.ReviewService) *ChainTaskHandler {
 	return &ChainTaskHandler{
 		App:               app,
 		Task:              task,
 		Db:                db,
 		SavedVideoService: savedVideoService,
 		TaskStepService:   taskStepService,
+		AIService:         aiService,
+		PartitionService:  partitionService,
+		ReviewService:     reviewService,
 		mutex:             sync.Mutex{},
 		isRunning:         false,
 	}
@@ -82,7 +95,12 @@ func (h *ChainTaskHandler) SetUp() {
 			return
 		}
 
-		// 2. 处理新的视频任务
+		// 2. 预算调整或进入新周期后，继续执行因 AI 费用超出预算而暂停的视频
+		if h.resumeBudgetPausedVideos() {
+			return
+		}
+
+		// 3. 处理新的视频任务
 		// 查询状态为 '001' 的任务
 		pendingTasks, err := h.getPendingTasks()
 		if err != nil {
@@ -168,7 +186,109 @@ func (h *ChainTaskHandler) getPendingTasks() ([]*models2.TbVideo, error) {
 func (h *ChainTaskHandler) getRetrySteps() ([]*model.TaskStep, error) {
 	return h.TaskStepService.GetPendingSteps()
 }
+
+// resumeBudgetPausedVideos 预算恢复后继续执行因 AI 费用超出预算而暂停的视频：
+// 任务链暂停的视频（003）从暂停的步骤继续执行任务链，单独重试时暂停的步骤重新加入重试队列。返回是否执行了任务链
+func (h *ChainTaskHandler) resumeBudgetPausedVideos() bool {
+	if time.Since(h.budgetCheckedAt) < budgetResumeInterval {
+		return false
+	}
+	h.budgetCheckedAt = time.Now()
+
+	videoIDs, err := h.TaskStepService.GetBudgetPausedVideoIDs()
+	if err != nil {
+		h.App.Logger.Errorf("查询预算暂停的步骤失败: %v", err)
+		return false
+	}
+
+	for _, videoID := range videoIDs {
+		// 全局预算和该视频的预算都未超出时才恢复
+		if err := translator.CheckBudget(videoID); err != nil {
+			continue
+		}
+
+		savedVideo, err := h.SavedVideoService.GetVideoByVideoID(videoID)
+		if err != nil {
+			h.App.Logger.Errorf("获取视频信息失败: %v", err)
+			continue
+		}
+
+		if savedVideo.Status != "003" {
+			if err := h.TaskStepService.ResumeBudgetPausedSteps(videoID); err != nil {
+				h.App.Logger.Errorf("恢复预算暂停的步骤失败: %v", err)
+			} else {
+				h.App.Logger.Infof("▶️ 预算已恢复，视频 %s 暂停的步骤重新加入重试队列", videoID)
+			}
+			continue
+		}
+
+		if err := h.updateSavedVideoStatus(savedVideo.ID, "002"); err != nil {
+			h.App.Logger.Errorf("更新任务状态为处理中时出错: %v", err)
+			continue
+		}
+		if err := h.TaskStepService.ResumeBudgetPausedSteps(videoID); err != nil {
+			h.App.Logger.Errorf("恢复预算暂停的步骤失败: %v", err)
+		}
+
+		h.App.Logger.Infof("▶️ 预算已恢复，继续执行视频 %s 的任务链", videoID)
+		h.isRunning = true
+		h.ResumeTaskChain(models2.TbVideo{
+			Id:        savedVideo.ID,
+			URL:       savedVideo.URL,
+			Title:     savedVideo.Title,
+			VideoId:   savedVideo.VideoID,
+			Status:    savedVideo.Status,
+			CreatedAt: savedVideo.CreatedAt,
+			UpdatedAt: savedVideo.UpdatedAt,
+		})
+		h.isRunning = false
+		return true
+	}
+	return false
+}
+
+// skipFinishedSteps 从任务链中移除已完成或已跳过的步骤，并用最后完成的步骤保存的结果恢复任务上下文
+// （语音检测、质量门禁等结论由后续步骤读取）
+func (h *ChainTaskHandler) skipFinishedSteps(chain *manager.TaskChain, videoID string) {
+	steps, err := h.TaskStepService.GetTaskStepsByVideoID(videoID)
+	if err != nil {
+		h.App.Logger.Errorf("获取任务步骤失败: %v", err)
+		return
+	}
+
+	finished := map[string]bool{}
+	for _, step := range steps {
+		switch step.Status {
+		case model.TaskStepStatusCompleted:
+			finished[step.StepName] = true
+			restored := map[string]interface{}{}
+			if err := json.Unmarshal([]byte(step.ResultData), &restored); err == nil {
+				chain.Context = restored
+			}
+		case model.TaskStepStatusSkipped:
+			finished[step.StepName] = true
+		}
+	}
+
+	tasks := make([]types.Task, 0, len(chain.Tasks))
+	for _, task := range chain.Tasks {
+		if _, tracked := task.(*TaskStepWrapper); tracked && finished[task.GetName()] {
+			continue
+		}
+		tasks = append(tasks, task)
+	}
+	chain.Tasks = tasks
+}
 func (h *ChainTaskHandler) RunTaskChain(video models2.TbVideo) {
+	h.runTaskChain(video, false)
+}
+
+// ResumeTaskChain 继续执行预算暂停的任务链：跳过已完成或跳过的步骤，并从最后完成的步骤结果中恢复任务上下文
+func (h *ChainTaskHandler) ResumeTaskChain(video models2.TbVideo) {
+	h.runTaskChain(video, true)
+}
+
+func (h *ChainTaskHandler) runTaskChain(video models2.TbVideo, resume bool) {
 
 	currentDir, err := filepath.Abs(h.App.Config.FileUpDir)
 	if err != nil {
@@ -181,11 +301,6 @@ func (h *ChainTaskHandler) RunTaskChain(video models2.TbVideo) {
 
 	}
 
-	// 初始化任务步骤
-	if err := h.TaskStepService.InitTaskSteps(video.VideoId); err != nil {
-		h.App.Logger.Errorf("初始化任务步骤失败: %v", err)
-	}
-
 	stateManager := manager.NewStateManager(video.Id, video.VideoId, currentDir, video.CreatedAt)
 	chain := manager.NewTaskChain()
 
@@ -198,6 +313,10 @@ func (h *ChainTaskHandler) RunTaskChain(video models2.TbVideo) {
 	extractAudioTask := handlers.NewExtractAudio("分离音频", h.App, stateManager, h.App.CosClient)
 	chain.AddTask(h.wrapTaskWithStepTracking(extractAudioTask, video.VideoId))
 
+	// 语音检测: 无语音的视频（音乐、环境音、游戏画面等）跳过字幕、翻译和字幕上传
+	detectSpeechTask := handlers.NewDetectSpeech("语音检测", h.App, stateManager, h.App.CosClient)
+	chain.AddTask(h.wrapTaskWithStepTracking(detectSpeechTask, video.VideoId))
+
 	// 任务3: 使用 B站必剪 转录生成字幕（如果启用）
 	if h.App.Config.WhisperConfig != nil && h.App.Config.WhisperConfig.Enabled {
 		h.App.Logger.Info("✓ B站必剪 已启用，将使用 B站必剪 进行语音转录")
@@ -208,21 +327,62 @@ func (h *ChainTaskHandler) RunTaskChain(video models2.TbVideo) {
 			h.App.CosClient,
 			h.App.Config.WhisperConfig.Language,
 		)
-		chain.AddTask(h.wrapTaskWithStepTracking(whisperTask, video.VideoId))
+		chain.AddTask(h.wrapSpeechDependentTask(whisperTask, video.VideoId))
 	} else {
 		// 备用方案：使用原有的字幕生成方法
 		h.App.Logger.Info("使用默认字幕生成方法")
 		subtitleTask := handlers.NewGenerateSubtitles("生成字幕", h.App, stateManager, h.App.CosClient, h.SavedVideoService)
-		chain.AddTask(h.wrapTaskWithStepTracking(subtitleTask, video.VideoId))
+		chain.AddTask(h.wrapSpeechDependentTask(subtitleTask, video.VideoId))
 	}
+	// 根据转录结果的文本密度修正语音检测结论
+	densityTask := handlers.NewCheckSubtitleDensity("字幕密度检查", h.App, stateManager, h.App.CosClient)
+	chain.AddTask(h.wrapTaskWithStepTracking(densityTask, video.VideoId))
+	// 将滚动式自动字幕重新断句为完整句子，再交给翻译
+	resegmentTask := handlers.NewResegmentSubtitles("字幕断句", h.App, stateManager, h.App.CosClient, h.AIService)
+	chain.AddTask(h.wrapSpeechDependentTask(resegmentTask, video.VideoId))
 	chain.AddTask(handlers.NewDownloadImgHandler("下载封面", h.App, stateManager, h.App.CosClient))
 	// 任务3: 翻译字幕（动态检查配置）
-	translateTask := handlers.NewTranslateSubtitle("翻译字幕", h.App, stateManager, h.App.CosClient, h.Db, "")
-	chain.AddTask(h.wrapTaskWithStepTracking(translateTask, video.VideoId))
+	translateTask := handlers.NewTranslateSubtitle("翻译字幕", h.App, stateManager, h.App.CosClient, h.Db, h.AIService)
+	chain.AddTask(h.wrapSpeechDependentTask(translateTask, video.VideoId))
+	// 中文字幕质检与自动修复
+	qaTask := handlers.NewSubtitleQA("字幕质检", h.App, stateManager, h.App.CosClient, h.Db)
+	chain.AddTask(h.wrapSpeechDependentTask(qaTask, video.VideoId))
+	// 翻译质量门禁：不达标时视频进入待人工审核状态，不会自动上传
+	gateTask := handlers.NewTranslationQualityGate("翻译质量门禁", h.App, stateManager, h.App.CosClient, h.Db, h.AIService)
+	chain.AddTask(h.wrapSpeechDependentTask(gateTask, video.VideoId))
+	// 生成双语字幕（SRT + ASS）
+	bilingualTask := handlers.NewGenerateBilingualSubtitles("生成双语字幕", h.App, stateManager, h.App.CosClient)
+	chain.AddTask(h.wrapSpeechDependentTask(bilingualTask, video.VideoId))
+	// 烧录硬字幕（可选）
+	burnInTask := handlers.NewBurnInSubtitles("烧录字幕", h.App, stateManager, h.App.CosClient)
+	chain.AddTask(h.wrapTaskWithStepTracking(burnInTask, video.VideoId))
+	// 生成配音（可选）：按主语言字幕合成语音，与压低音量的原声混音生成配音版视频
+	dubbingTask := handlers.NewGenerateDubbing("生成配音", h.App, stateManager, h.App.CosClient, h.SavedVideoService)
+	chain.AddTask(h.wrapTaskWithStepTracking(dubbingTask, video.VideoId))
 
 	// 任务4: 生成视频标题和描述（动态检查配置）
-	metadataTask := handlers.NewGenerateMetadata("生成视频元数据", h.App, stateManager, h.App.CosClient, "", h.Db, h.SavedVideoService)
+	metadataTask := handlers.NewGenerateMetadata("生成视频元数据", h.App, stateManager, h.App.CosClient, h.Db, h.SavedVideoService, h.AIService)
 	chain.AddTask(h.wrapTaskWithStepTracking(metadataTask, video.VideoId))
+	// 生成封面（可选）：从视频画面生成B站尺寸的封面并叠加生成的标题
+	coverTask := handlers.NewGenerateCover("生成封面", h.App, stateManager, h.App.CosClient, h.SavedVideoService)
+	chain.AddTask(h.wrapTaskWithStepTracking(coverTask, video.VideoId))
+	// 选择投稿分区（可选）：按来源分类、标签和生成的内容概要确定分区
+	partitionTask := handlers.NewSelectPartition("选择分区", h.App, stateManager, h.App.CosClient, h.SavedVideoService, h.AIService, h.PartitionService)
+	chain.AddTask(h.wrapTaskWithStepTracking(partitionTask, video.VideoId))
+	// 生成章节（可选）：翻译来源视频的章节或根据字幕划分章节，上传时写入简介
+	chaptersTask := handlers.NewGenerateChapters("生成章节", h.App, stateManager, h.App.CosClient, h.Db, h.SavedVideoService, h.AIService)
+	chain.AddTask(h.wrapTaskWithStepTracking(chaptersTask, video.VideoId))
+	// 合规检查（可选）：检查投稿信息和字幕中的违规词、外部链接和引流文字，未通过时不会上传
+	complianceTask := handlers.NewComplianceCheck("合规检查", h.App, stateManager, h.App.CosClient, h.SavedVideoService, h.AIService)
+	chain.AddTask(h.wrapTaskWithStepTracking(complianceTask, video.VideoId))
+
+	// 初始化任务步骤（任务链中所有跟踪状态的步骤）
+	if err := h.TaskStepService.InitTaskSteps(video.VideoId, trackedStepNames(chain)); err != nil {
+		h.App.Logger.Errorf("初始化任务步骤失败: %v", err)
+	}
+	if resume {
+		h.skipFinishedSteps(chain, video.VideoId)
+	}
 
 	// 注意: 上传任务已移至 UploadScheduler 定时执行
 	// - 视频上传: 每小时上传一个视频
@@ -244,15 +404,54 @@ func (h *ChainTaskHandler) RunTaskChain(video models2.TbVideo) {
 		h.App.Logger.Errorf("任务链执行过程中发生错误: %v", errorMsg)
 	}
 
+	// 翻译质量未达标或合规检查未通过时需要人工审核
+	var reviewReasons []string
+	if result["needs_review"] == true {
+		reviewReasons = append(reviewReasons, fmt.Sprintf("%s: %v", qualityReviewReason, result["needs_review_reason"]))
+	}
+	if result["compliance_blocked"] == true {
+		reviewReasons = append(reviewReasons, fmt.Sprintf("%s: %v", complianceReviewReason, result["compliance_reason"]))
+	}
+
 	// 根据执行结果更新任务状态
-	if success {
+	if success && len(reviewReasons) > 0 {
+		// 状态设为待人工审核，审核通过后才进入上传队列
+		reason := strings.Join(reviewReasons, "; ")
+		if err := h.SavedVideoService.MarkNeedsReview(video.Id, reason); err != nil {
+			h.App.Logger.Errorf("更新任务状态为待审核时出错: %v", err)
+		} else {
+			h.App.Logger.Warnf("任务 %s 需要人工审核，状态已更新为待人工审核: %s", video.VideoId, reason)
+		}
+	} else if success && h.reviewRequired(video.Id) {
+		// 该视频（或其频道/播放列表）设置了上传前人工审核
+		if err := h.SavedVideoService.MarkNeedsReview(video.Id, reviewRequiredReason); err != nil {
+			h.App.Logger.Errorf("更新任务状态为待审核时出错: %v", err)
+		} else {
+			h.App.Logger.Infof("任务 %s 执行成功，等待人工审核后上传", video.VideoId)
+		}
+	} else if success {
 		// 任务成功完成，更新状态为完成
 		if err := h.updateSavedVideoStatus(video.Id, "200"); err != nil {
 			h.App.Logger.Errorf("更新任务状态为完成时出错: %v", err)
 		} else {
 			h.App.Logger.Infof("任务 %s 执行成功，状态已更新为完成", video.VideoId)
 		}
+	} else if result["budget_paused"] == true {
+		// AI 费用超出预算：未执行的步骤一并暂停，预算恢复后从暂停的步骤继续执行
+		if err := h.TaskStepService.PauseTaskSteps(video.VideoId, fmt.Sprintf("%v", result["error"])); err != nil {
+			h.App.Logger.Errorf("更新未执行步骤状态失败: %v", err)
+		}
+		if err := h.updateSavedVideoStatus(video.Id, "003"); err != nil {
+			h.App.Logger.Errorf("更新任务状态为预算暂停时出错: %v", err)
+		} else {
+			h.App.Logger.Warnf("⏸️ 任务 %s 因 AI 费用超出预算暂停，预算恢复后继续执行", video.VideoId)
+		}
 	} else {
+		// 任务链中断后未执行的步骤标记为跳过，避免被当作待重试步骤逐个执行
+		if err := h.TaskStepService.SkipPendingTaskSteps(video.VideoId, "前置步骤失败，任务链已中断"); err != nil {
+			h.App.Logger.Errorf("更新未执行步骤状态失败: %v", err)
+		}
+
 		// 任务失败，更新状态为失败
 		if err := h.updateSavedVideoStatus(video.Id, "999"); err != nil {
 			h.App.Logger.Errorf("更新任务状态为失败时出错: %v", err)
@@ -293,6 +492,14 @@ func (h *ChainTaskHandler) RunSingleTaskStep(videoID, stepName string) error {
 	// 创建状态管理器
 	stateManager := manager.NewStateManager(video.Id, video.VideoId, currentDir, video.CreatedAt)
 
+	// 无语音的视频不再执行字幕相关步骤
+	if isSpeechDependentStep(stepName) {
+		if reason := handlers.NoSpeechSkipReason(stateManager); reason != "" {
+			h.App.Logger.Infof("⏭️ 跳过任务步骤 %s: %s", stepName, reason)
+			return h.TaskStepService.SkipTaskStep(videoID, stepName, reason)
+		}
+	}
+
 	// 重置步骤状态
 	if err := h.TaskStepService.ResetTaskStep(videoID, stepName); err != nil {
 		h.App.Logger.Errorf("重置任务步骤失败: %v", err)
@@ -313,6 +520,12 @@ func (h *ChainTaskHandler) RunSingleTaskStep(videoID, stepName string) error {
 		task = handlers.NewDownloadVideo("下载视频", h.App, stateManager, h.App.CosClient, h.SavedVideoService)
 	case "分离音频":
 		task = handlers.NewExtractAudio("分离音频", h.App, stateManager, h.App.CosClient)
+	case "语音检测":
+		task = handlers.NewDetectSpeech("语音检测", h.App, stateManager, h.App.CosClient)
+	case "字幕密度检查":
+		task = handlers.NewCheckSubtitleDensity("字幕密度检查", h.App, stateManager, h.App.CosClient)
+	case "字幕断句":
+		task = handlers.NewResegmentSubtitles("字幕断句", h.App, stateManager, h.App.CosClient, h.AIService)
 	case "Whisper转录":
 		// 从配置中读取 Whisper 参数
 		if h.App.Config.WhisperConfig != nil && h.App.Config.WhisperConfig.Enabled {
@@ -330,10 +543,28 @@ func (h *ChainTaskHandler) RunSingleTaskStep(videoID, stepName string) error {
 		task = handlers.NewGenerateSubtitles("生成字幕", h.App, stateManager, h.App.CosClient, h.SavedVideoService)
 	case "翻译字幕":
 		// 不再在这里检查配置，让任务运行时动态检查最新配置
-		task = handlers.NewTranslateSubtitle("翻译字幕", h.App, stateManager, h.App.CosClient, h.Db, "")
-	case "生成元数据":
+		task = handlers.NewTranslateSubtitle("翻译字幕", h.App, stateManager, h.App.CosClient, h.Db, h.AIService)
+	case "字幕质检":
+		task = handlers.NewSubtitleQA("字幕质检", h.App, stateManager, h.App.CosClient, h.Db)
+	case "翻译质量门禁":
+		task = handlers.NewTranslationQualityGate("翻译质量门禁", h.App, stateManager, h.App.CosClient, h.Db, h.AIService)
+	case "生成双语字幕":
+		task = handlers.NewGenerateBilingualSubtitles("生成双语字幕", h.App, stateManager, h.App.CosClient)
+	case "烧录字幕":
+		task = handlers.NewBurnInSubtitles("烧录字幕", h.App, stateManager, h.App.CosClient)
+	case "生成配音":
+		task = handlers.NewGenerateDubbing("生成配音", h.App, stateManager, h.App.CosClient, h.SavedVideoService)
+	case "生成视频元数据", "生成元数据":
 		// 不再在这里检查配置，让任务运行时动态检查最新配置
-		task = handlers.NewGenerateMetadata("生成元数据", h.App, stateManager, h.App.CosClient, "", h.Db, h.SavedVideoService)
+		task = handlers.NewGenerateMetadata("生成视频元数据", h.App, stateManager, h.App.CosClient, h.Db, h.SavedVideoService, h.AIService)
+	case "生成封面":
+		task = handlers.NewGenerateCover("生成封面", h.App, stateManager, h.App.CosClient, h.SavedVideoService)
+	case "选择分区":
+		task = handlers.NewSelectPartition("选择分区", h.App, stateManager, h.App.CosClient, h.SavedVideoService, h.AIService, h.PartitionService)
+	case "生成章节":
+		task = handlers.NewGenerateChapters("生成章节", h.App, stateManager, h.App.CosClient, h.Db, h.SavedVideoService, h.AIService)
+	case "合规检查":
+		task = handlers.NewComplianceCheck("合规检查", h.App, stateManager, h.App.CosClient, h.SavedVideoService, h.AIService)
 	case "上传到Bilibili":
 		task = handlers.NewUploadToBilibili("上传到Bilibili", h.App, stateManager, h.App.CosClient, h.SavedVideoService)
 	case "上传字幕到Bilibili":
@@ -370,6 +601,19 @@ func (h *ChainTaskHandler) RunSingleTaskStep(videoID, stepName string) error {
 			h.App.Logger.Errorf("更新任务步骤结果失败: %v", err)
 		}
 		h.App.Logger.Infof("任务步骤 %s 执行成功", stepName)
+		if _, checked := result["quality_gate"]; checked && stepName == "翻译质量门禁" {
+			h.syncReviewStatus(savedVideo.ID, savedVideo.Status, result["needs_review"] == true, qualityReviewReason, result["needs_review_reason"])
+		}
+		if _, checked := result["compliance"]; checked && stepName == "合规检查" {
+			h.syncReviewStatus(savedVideo.ID, savedVideo.Status, result["compliance_blocked"] == true, complianceReviewReason, result["compliance_reason"])
+		}
+	} else if result["budget_paused"] == true {
+		// AI 费用超出预算：步骤暂停，预算恢复后重新放入重试队列
+		if err := h.TaskStepService.UpdateTaskStepStatus(videoID, stepName, model.TaskStepStatusBudgetPaused, errorMsg); err != nil {
+			h.App.Logger.Errorf("更新任务步骤状态失败: %v", err)
+		}
+		h.App.Logger.Warnf("⏸️ 任务步骤 %s 因 AI 费用超出预算暂停: %s", stepName, errorMsg)
+		return nil
 	} else {
 		if err := h.TaskStepService.UpdateTaskStepStatus(videoID, stepName, "failed", errorMsg); err != nil {
 			h.App.Logger.Errorf("更新任务步骤状态失败: %v", err)
@@ -392,12 +636,44 @@ func (h *ChainTaskHandler) wrapTaskWithStepTracking(task types.Task, videoID str
 	}
 }
 
+// wrapSpeechDependentTask 包装依赖语音的任务（字幕、翻译），视频无语音时跳过执行
+func (h *ChainTaskHandler) wrapSpeechDependentTask(task types.Task, videoID string) types.Task {
+	return &TaskStepWrapper{
+		task:            task,
+		videoID:         videoID,
+		taskStepService: h.TaskStepService,
+		logger:          h.App.Logger,
+		skipOnNoSpeech:  true,
+	}
+}
+
+// trackedStepNames 返回任务链中跟踪步骤状态的任务名称（按执行顺序）
+func trackedStepNames(chain *manager.TaskChain) []string {
+	var names []string
+	for _, task := range chain.Tasks {
+		if _, ok := task.(*TaskStepWrapper); ok {
+			names = append(names, task.GetName())
+		}
+	}
+	return names
+}
+
+// isSpeechDependentStep 判断步骤是否依赖语音（无语音时跳过）
+func isSpeechDependentStep(stepName string) bool {
+	switch stepName {
+	case "Whisper转录", "B站必剪转录", "生成字幕", "字幕断句", "翻译字幕", "字幕质检", "翻译质量门禁", "生成双语字幕", "上传字幕到Bilibili":
+		return true
+	}
+	return false
+}
+
 // TaskStepWrapper 任务步骤包装器
 type TaskStepWrapper struct {
 	task            types.Task
 	videoID         string
 	taskStepService *services.TaskStepService
 	logger          *zap.SugaredLogger
+	skipOnNoSpeech  bool // 语音检测结果为无语音时跳过
 }
 
 func (w *TaskStepWrapper) GetName() string {
@@ -415,6 +691,18 @@ func (w *TaskStepWrapper) UpdateStatus(status, message string) error {
 func (w *TaskStepWrapper) Execute(context map[string]interface{}) bool {
 	stepName := w.task.GetName()
 
+	// 视频无语音时跳过该步骤，并记录原因
+	if w.skipOnNoSpeech {
+		if presence, _ := context["speech_presence"].(string); presence == handlers.SpeechPresenceNone {
+			reason, _ := context["speech_skip_reason"].(string)
+			w.logger.Infof("⏭️ 跳过任务步骤 %s: %s", stepName, reason)
+			if err := w.taskStepService.SkipTaskStep(w.videoID, stepName, reason); err != nil {
+				w.logger.Errorf("更新任务步骤状态失败: %v", err)
+			}
+			return true
+		}
+	}
+
 	// 更新步骤状态为运行中
 	if err := w.taskStepService.UpdateTaskStepStatus(w.videoID, stepName, "running"); err != nil {
 		w.logger.Errorf("更新任务步骤状态失败: %v", err)
@@ -445,7 +733,11 @@ func (w *TaskStepWrapper) Execute(context map[string]interface{}) bool {
 			errorMsg = fmt.Sprintf("%v", err)
 		}
 
-		if err := w.taskStepService.UpdateTaskStepStatus(w.videoID, stepName, "failed", errorMsg); err != nil {
+		status := model.TaskStepStatusFailed
+		if context["budget_paused"] == true {
+			status = model.TaskStepStatusBudgetPaused
+		}
+		if err := w.taskStepService.UpdateTaskStepStatus(w.videoID, stepName, status, errorMsg); err != nil {
 			w.logger.Errorf("更新任务步骤状态失败: %v", err)
 		}
 	}
@@ -453,6 +745,62 @@ func (w *TaskStepWrapper) Execute(context map[string]interface{}) bool {
 	return success
 }
 
+// syncReviewStatus 单独重新执行翻译质量门禁或合规检查后同步视频状态：未通过的待上传视频转为待人工审核（150），
+// 因该项检查进入待审核、现已通过的视频恢复为准备就绪（200）。reason 为检查项的待审核原因前缀
+func (h *ChainTaskHandler) syncReviewStatus(id uint, status string, failed bool, reason string, detail interface{}) {
+	var err error
+	newStatus := ""
+	if failed {
+		if status == "200" || status == "299" {
+			newStatus = "150"
+			err = h.SavedVideoService.MarkNeedsReview(id, fmt.Sprintf("%s: %v", reason, detail))
+		}
+	} else if status == "150" {
+		// 只有因该项检查进入待审核的视频自动恢复；被审核人退回的视频仍等待审核
+		video, findErr := h.SavedVideoService.GetByID(id)
+		if findErr != nil || (video.ReviewReason != "" && !strings.HasPrefix(video.ReviewReason, reason)) {
+			return
+		}
+		if h.reviewRequired(id) {
+			// 需要上传前人工审核的视频仍然待审核
+			if err := h.SavedVideoService.MarkNeedsReview(id, reviewRequiredReason); err != nil {
+				h.App.Logger.Errorf("更新视频审核原因失败: %v", err)
+			}
+			return
+		}
+		newStatus = "200"
+		err = h.updateSavedVideoStatus(id, newStatus)
+	}
+	if newStatus == "" {
+		return
+	}
+
+	if err != nil {
+		h.App.Logger.Errorf("更新视频审核状态失败: %v", err)
+		return
+	}
+	h.App.Logger.Infof("%s结果已同步，视频状态 %s → %s", reason, status, newStatus)
+}
+
+// 视频进入待人工审核状态的原因
+const (
+	qualityReviewReason    = "翻译质量未达标"
+	complianceReviewReason = "合规检查未通过"
+	reviewRequiredReason   = "上传前需要人工审核"
+)
+
+// reviewRequired 视频上传前是否需要人工审核（全局配置或频道/播放列表的审核设置）
+func (h *ChainTaskHandler) reviewRequired(id uint) bool {
+	if h.ReviewService == nil {
+		return false
+	}
+	video, err := h.SavedVideoService.GetByID(id)
+	if err != nil {
+		return false
+	}
+	return h.ReviewService.Required(video)
+}
+
 // updateSavedVideoStatus 更新 SavedVideo 的状态
 func (h *ChainTaskHandler) updateSavedVideoStatus(id uint, status string) error {
 	return h.SavedVideoService.UpdateStatus(id, status)
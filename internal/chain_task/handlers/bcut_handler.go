@@ -14,6 +14,7 @@ import (
 	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
 	"github.com/difyz9/ytb2bili/internal/core"
 	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
 	"gorm.io/gorm"
 )
 
@@ -317,39 +318,29 @@ func (h *BcutHandler) saveSubtitle(result map[string]interface{}) error {
 		return fmt.Errorf("未找到utterances数据")
 	}
 	
-	// 创建SRT文件
-	outFile, err := os.Create(h.StateManager.OriginalSRT)
-	if err != nil {
-		return fmt.Errorf("创建字幕文件失败: %v", err)
-	}
-	defer outFile.Close()
-	
-	// 写入SRT格式
-	for i, u := range utterances {
+	doc := subtitle.NewDocument("")
+	for _, u := range utterances {
 		utterance := u.(map[string]interface{})
 		text := strings.TrimSpace(utterance["transcript"].(string))
-		
-		// B站ASR返回的时间戳是毫秒，需要转换为秒
+
+		// B站ASR返回的时间戳单位为毫秒
 		startTime := int64(utterance["start_time"].(float64))
 		endTime := int64(utterance["end_time"].(float64))
-		
-		// SRT序号（从1开始）
-		fmt.Fprintf(outFile, "%d\n", i+1)
-		
-		// SRT时间格式: HH:MM:SS,mmm --> HH:MM:SS,mmm
-		fmt.Fprintf(outFile, "%s --> %s\n",
-			formatSRTTimeFromMS(startTime),
-			formatSRTTimeFromMS(endTime))
-		
-		// 字幕文本
-		fmt.Fprintf(outFile, "%s\n\n", text)
+
+		doc.Add(startTime, endTime, text)
 	}
-	
+
 	// 提取语言信息
 	if lang, ok := resultData["language"].(string); ok {
+		doc.Language = lang
 		fmt.Printf("📝 检测到语言: %s\n", lang)
 	}
-	
+
+	// 写入SRT文件
+	if err := subtitle.WriteFile(h.StateManager.OriginalSRT, doc); err != nil {
+		return fmt.Errorf("创建字幕文件失败: %v", err)
+	}
+
 	return nil
 }
 
@@ -402,15 +393,3 @@ func (h *BcutHandler) makeRequest(method, url string, payload interface{}) (map[
 	
 	return result, nil
 }
-
-// formatSRTTimeFromMS 格式化毫秒时间为SRT格式 (HH:MM:SS,mmm)
-func formatSRTTimeFromMS(ms int64) string {
-	totalSeconds := ms / 1000
-	milliseconds := ms % 1000
-	
-	hours := totalSeconds / 3600
-	minutes := (totalSeconds % 3600) / 60
-	seconds := totalSeconds % 60
-	
-	return fmt.Sprintf("%02d:%02d:%02d,%03d", hours, minutes, seconds, milliseconds)
-}
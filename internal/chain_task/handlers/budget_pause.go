@@ -0,0 +1,15 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/difyz9/ytb2bili/pkg/translator"
+)
+
+// markBudgetPaused AI 调用因费用超出预算失败时在任务上下文中设置 budget_paused，
+// 任务链据此暂停视频而不是标记为失败，预算恢复后从该步骤继续执行
+func markBudgetPaused(context map[string]interface{}, err error) {
+	if errors.Is(err, translator.ErrBudgetExceeded) {
+		context["budget_paused"] = true
+	}
+}
@@ -0,0 +1,156 @@
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/chain_task/base"
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+	"github.com/difyz9/ytb2bili/pkg/utils"
+)
+
+// BurnInSubtitles 将字幕烧录进视频（硬字幕），输出到 OutVideoPath，启用后上传烧录后的视频
+type BurnInSubtitles struct {
+	base.BaseTask
+	App *core.AppServer
+}
+
+func NewBurnInSubtitles(name string, app *core.AppServer, stateManager *manager.StateManager, client *cos.CosClient) *BurnInSubtitles {
+	return &BurnInSubtitles{
+		BaseTask: base.BaseTask{
+			Name:         name,
+			StateManager: stateManager,
+			Client:       client,
+		},
+		App: app,
+	}
+}
+
+func (t *BurnInSubtitles) Execute(context map[string]interface{}) bool {
+	cfg := t.App.Config.BurnInConfig
+	if cfg == nil || !cfg.Enabled {
+		t.App.Logger.Info("字幕烧录未启用，跳过")
+		return true
+	}
+
+	if _, err := os.Stat(t.StateManager.InputVideoPath); os.IsNotExist(err) {
+		t.App.Logger.Error("❌ 源视频文件不存在，无法烧录字幕")
+		context["error"] = "源视频文件不存在"
+		return false
+	}
+
+	track := cfg.Track
+	if track == "" {
+		track = "bilingual"
+	}
+
+	ass, err := t.buildBurnInASS(track, cfg.ASSPreset)
+	if err != nil {
+		t.App.Logger.Errorf("❌ 生成烧录字幕失败: %v", err)
+		context["error"] = fmt.Sprintf("生成烧录字幕失败: %v", err)
+		return false
+	}
+	if ass == "" {
+		// 没有字幕（如无语音视频），上传时使用源视频
+		t.App.Logger.Warnf("⚠️  字幕轨道 %s 不存在，跳过字幕烧录", track)
+		os.Remove(t.StateManager.OutVideoPath)
+		return true
+	}
+	if err := os.WriteFile(t.StateManager.BurnInASS, []byte(ass), 0644); err != nil {
+		t.App.Logger.Errorf("❌ 保存烧录字幕失败: %v", err)
+		context["error"] = fmt.Sprintf("保存烧录字幕失败: %v", err)
+		return false
+	}
+
+	t.App.Logger.Infof("🔥 开始烧录字幕: 轨道=%s, 编码器=%s, preset=%s, crf=%d", track, cfg.VideoCodec, cfg.Preset, cfg.CRF)
+	startTime := time.Now()
+
+	// 先输出到临时文件，避免上传未完成的视频
+	partPath := t.StateManager.OutVideoPath + ".part"
+	err = utils.BurnSubtitles(t.StateManager.InputVideoPath, t.StateManager.BurnInASS, partPath, utils.BurnInOptions{
+		VideoCodec:   cfg.VideoCodec,
+		Preset:       cfg.Preset,
+		CRF:          cfg.CRF,
+		AudioBitrate: cfg.AudioBitrate,
+		FontsDir:     cfg.FontsDir,
+	})
+	if err != nil {
+		os.Remove(partPath)
+		t.App.Logger.Errorf("❌ %v", err)
+		context["error"] = "字幕烧录失败，请检查 ffmpeg 是否支持 libass 以及编码器配置"
+		return false
+	}
+	if err := os.Rename(partPath, t.StateManager.OutVideoPath); err != nil {
+		os.Remove(partPath)
+		t.App.Logger.Errorf("❌ 保存烧录后的视频失败: %v", err)
+		context["error"] = fmt.Sprintf("保存烧录后的视频失败: %v", err)
+		return false
+	}
+
+	t.App.Logger.Infof("✅ 字幕烧录完成，耗时 %v: %s", time.Since(startTime).Round(time.Second), t.StateManager.OutVideoPath)
+	context["out_video_path"] = t.StateManager.OutVideoPath
+	return true
+}
+
+// buildBurnInASS 按轨道生成用于烧录的 ASS 字幕，轨道字幕不存在时返回空字符串
+func (t *BurnInSubtitles) buildBurnInASS(track, presetName string) (string, error) {
+	subtitleCfg := t.App.Config.SubtitleConfig
+	if presetName != "" {
+		var cfgCopy types.SubtitleConfig
+		if subtitleCfg != nil {
+			cfgCopy = *subtitleCfg
+		}
+		cfgCopy.ASSPreset = presetName
+		subtitleCfg = &cfgCopy
+	}
+	preset := ResolveASSPreset(subtitleCfg)
+
+	readTrack := func(path string) (*subtitle.Document, error) {
+		if path == "" {
+			return nil, nil
+		}
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return nil, nil
+		}
+		doc, err := subtitle.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		doc.Sort()
+		return doc, nil
+	}
+
+	switch track {
+	case "zh", "original":
+		path := t.StateManager.TranslateSRT
+		if track == "original" {
+			path = t.StateManager.OriginalSubtitlePath()
+		}
+		doc, err := readTrack(path)
+		if err != nil || doc == nil {
+			return "", err
+		}
+		return subtitle.FormatASSDocument(doc, preset.Primary), nil
+	case "bilingual":
+		zhDoc, err := readTrack(t.StateManager.TranslateSRT)
+		if err != nil || zhDoc == nil {
+			return "", err
+		}
+		originalDoc, err := readTrack(t.StateManager.OriginalSubtitlePath())
+		if err != nil {
+			return "", err
+		}
+		if originalDoc == nil {
+			// 没有原文时只烧录中文
+			return subtitle.FormatASSDocument(zhDoc, preset.Primary), nil
+		}
+		return subtitle.FormatBilingualASS(zhDoc, originalDoc, preset), nil
+	default:
+		return "", fmt.Errorf("未知的字幕轨道: %s", track)
+	}
+}
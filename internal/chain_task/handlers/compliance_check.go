@@ -0,0 +1,265 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/chain_task/base"
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/prompt"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+	"github.com/difyz9/ytb2bili/pkg/translator"
+	"github.com/difyz9/ytb2bili/pkg/utils"
+)
+
+// 发送给模型检查的带时间点字幕的最大字符数
+const complianceTranscriptMaxRunes = 20000
+
+// 每个字段最多记录的问题数，避免字幕中的高频词撑大报告
+const complianceMaxFindings = 50
+
+// ComplianceCheck 上传前内容合规检查：按违规词表检查投稿标题、简介、标签和主语言字幕，检查简介中的外部链接和
+// 引流文字，可选由大模型检查字幕内容。报告保存到 compliance.json；有 block 级别的问题时在 context 中标记
+// compliance_blocked，任务链结束后视频状态设为 150（待人工审核），上传步骤也会拒绝上传
+type ComplianceCheck struct {
+	base.BaseTask
+	App               *core.AppServer
+	AIService         *services.AIServiceManager
+	SavedVideoService *services.SavedVideoService
+}
+
+func NewComplianceCheck(name string, app *core.AppServer, stateManager *manager.StateManager, client *cos.CosClient, savedVideoService *services.SavedVideoService, aiService *services.AIServiceManager) *ComplianceCheck {
+	return &ComplianceCheck{
+		BaseTask: base.BaseTask{
+			Name:         name,
+			StateManager: stateManager,
+			Client:       client,
+		},
+		App:               app,
+		AIService:         aiService,
+		SavedVideoService: savedVideoService,
+	}
+}
+
+// modelCompliance 模型返回的检查结论
+type modelCompliance struct {
+	Verdict    string   `json:"verdict"`
+	Categories []string `json:"categories"`
+	Reason     string   `json:"reason"`
+	Issues     []struct {
+		Time  string `json:"time"`
+		Text  string `json:"text"`
+		Issue string `json:"issue"`
+	} `json:"issues"`
+}
+
+func (t *ComplianceCheck) Execute(context map[string]interface{}) bool {
+	cfg := t.App.Config.ComplianceConfig
+	if cfg == nil || !cfg.Enabled {
+		t.App.Logger.Info("合规检查未启用，跳过")
+		return true
+	}
+
+	checker, err := services.NewComplianceChecker(cfg)
+	if err != nil {
+		t.App.Logger.Errorf("❌ %v", err)
+		context["error"] = err.Error()
+		return false
+	}
+
+	video, err := t.SavedVideoService.GetVideoByVideoID(t.StateManager.VideoID)
+	if err != nil {
+		t.App.Logger.Errorf("❌ 获取视频记录失败: %v", err)
+		context["error"] = fmt.Sprintf("获取视频记录失败: %v", err)
+		return false
+	}
+
+	report := &services.ComplianceReport{CheckedAt: time.Now()}
+
+	// 1. 投稿标题、简介和标签：与上传时的选择一致（不含原视频链接后缀）
+	title, desc := t.uploadText(video)
+	report.Findings = append(report.Findings, limitFindings(checker.CheckText(services.ComplianceFieldTitle, title))...)
+	report.Findings = append(report.Findings, limitFindings(checker.CheckText(services.ComplianceFieldDescription, desc))...)
+	report.Findings = append(report.Findings, limitFindings(checker.CheckText(services.ComplianceFieldTags, video.GeneratedTags))...)
+
+	// 2. 主语言字幕
+	language := resolveTargetLanguages(t.App.Config, video)[0]
+	if cfg.CheckSubtitles {
+		report.Findings = append(report.Findings, limitFindings(t.checkSubtitles(checker, language))...)
+	}
+
+	// 3. 由大模型检查字幕内容
+	if cfg.UseLLM {
+		if t.AIService == nil || len(t.AIService.EnabledProviders()) == 0 {
+			t.App.Logger.Warn("⚠️ 没有可用的 AI 服务，跳过大模型合规检查")
+		} else if transcript := timedTranscript(t.StateManager, language, videoDuration(t.StateManager), complianceTranscriptMaxRunes); transcript == "" {
+			t.App.Logger.Warn("⚠️ 没有可用的字幕，跳过大模型合规检查")
+		} else {
+			t.App.Logger.Info("🤖 由大模型检查字幕内容")
+			if err := t.classifyWithLLM(report, transcript, video, title, desc); err != nil {
+				t.App.Logger.Errorf("❌ 大模型合规检查失败: %v", err)
+				context["error"] = fmt.Sprintf("大模型合规检查失败: %v", err)
+				markBudgetPaused(context, err)
+				return false
+			}
+		}
+	}
+
+	// 4. 保存报告：上传步骤根据报告拒绝上传，保存失败时不能继续
+	reasons := report.BlockReasons()
+	report.Passed = len(reasons) == 0
+	if report.Findings == nil {
+		report.Findings = []services.ComplianceFinding{}
+	}
+	if err := services.SaveComplianceReport(t.StateManager.Compliance, report); err != nil {
+		t.App.Logger.Errorf("❌ 保存合规检查报告失败: %v", err)
+		context["error"] = fmt.Sprintf("保存合规检查报告失败: %v", err)
+		return false
+	}
+	if report.PromptVersion != "" {
+		if err := recordPromptVersions(t.StateManager, filepath.Base(t.StateManager.Compliance), []string{report.PromptVersion}); err != nil {
+			t.App.Logger.Warnf("⚠️ 记录提示词版本失败: %v", err)
+		}
+	}
+
+	context["compliance"] = report
+	if !report.Passed {
+		reason := truncateRunes(strings.Join(reasons, "; "), 300)
+		context["compliance_blocked"] = true
+		context["compliance_reason"] = reason
+		t.App.Logger.Warnf("🚫 合规检查未通过，视频需修改并重新检查后才能上传: %s", reason)
+	} else {
+		delete(context, "compliance_blocked")
+		t.App.Logger.Infof("✅ 合规检查通过（%d 条提示）", len(report.Findings))
+	}
+	return true
+}
+
+// uploadText 上传时使用的标题和简介，章节不使用自定义模板时也一并检查
+func (t *ComplianceCheck) uploadText(video *model.SavedVideo) (string, string) {
+	biliConfig := t.App.Config.BilibiliConfig
+	title, _ := uploadTitle(video, biliConfig, t.StateManager.VideoID)
+
+	chapterLines := ""
+	if list, err := utils.LoadChapters(t.StateManager.Chapters); err == nil {
+		chapterLines = utils.FormatChapterLines(list.Chapters)
+	}
+	desc, useTemplate, _ := uploadDescription(video, biliConfig, chapterLines)
+	if chapterLines != "" && !useTemplate {
+		desc += "\n" + chapterLines
+	}
+	return title, desc
+}
+
+// checkSubtitles 按违规词表检查主语言字幕
+func (t *ComplianceCheck) checkSubtitles(checker *services.ComplianceChecker, language string) []services.ComplianceFinding {
+	path := t.StateManager.TranslatedSRTPath(language)
+	if _, err := os.Stat(path); err != nil {
+		t.App.Logger.Warnf("⚠️ 主语言字幕不存在，跳过字幕检查: %s", filepath.Base(path))
+		return nil
+	}
+	doc, err := subtitle.ReadFile(path)
+	if err != nil || doc.Len() == 0 {
+		return nil
+	}
+
+	long := doc.Cues[doc.Len()-1].Start >= 3600*1000
+	var findings []services.ComplianceFinding
+	for _, cue := range doc.Cues {
+		for _, finding := range checker.CheckText(services.ComplianceFieldSubtitles, cue.Text) {
+			finding.Time = utils.FormatChapterTime(float64(cue.Start)/1000, long)
+			findings = append(findings, finding)
+		}
+	}
+	return findings
+}
+
+// classifyWithLLM 渲染合规检查提示词，由模型检查字幕内容，结论和问题写入报告
+func (t *ComplianceCheck) classifyWithLLM(report *services.ComplianceReport, transcript string, video *model.SavedVideo, title, desc string) error {
+	data := services.PromptDataForVideo(video)
+	data.SubtitleText = transcript
+	data.Summary = "标题：" + title
+	if desc != "" {
+		data.Summary += "\n简介：" + truncateRunes(desc, 300)
+	}
+
+	rendered, err := prompt.Render(prompt.Compliance, data)
+	if err != nil {
+		return err
+	}
+
+	ctx := translator.WithUsageScope(context.Background(), t.StateManager.VideoID, t.Name)
+	content, provider, err := t.AIService.ChatCompletionContext(ctx, rendered.System, rendered.User)
+	if err != nil {
+		return err
+	}
+	t.App.Logger.Debugf("%s 原始返回: %s", provider, content)
+
+	var result modelCompliance
+	if err := json.Unmarshal([]byte(trimCodeFence(content)), &result); err != nil {
+		return fmt.Errorf("解析合规检查JSON失败: %v, 内容: %s", err, content)
+	}
+
+	verdict := strings.ToLower(strings.TrimSpace(result.Verdict))
+	report.LLM = &services.ComplianceLLMResult{
+		Provider:   string(provider),
+		Verdict:    verdict,
+		Categories: result.Categories,
+		Reason:     strings.TrimSpace(result.Reason),
+	}
+	report.PromptVersion = rendered.Version
+	if verdict != services.ComplianceBlock && verdict != services.ComplianceWarn {
+		return nil
+	}
+
+	// 模型判定为 block 时，只有 llm_block 开启才阻止上传
+	action := services.ComplianceWarn
+	if verdict == services.ComplianceBlock && t.App.Config.ComplianceConfig.LLMBlock {
+		action = services.ComplianceBlock
+	}
+	finding := services.ComplianceFinding{
+		Field:  services.ComplianceFieldSubtitles,
+		Rule:   services.ComplianceRuleLLM,
+		Action: action,
+		Match:  report.LLM.Reason,
+	}
+	if len(result.Issues) == 0 {
+		report.Findings = append(report.Findings, finding)
+		return nil
+	}
+	for _, issue := range result.Issues {
+		finding.Match = strings.TrimSpace(issue.Issue)
+		finding.Context = strings.TrimSpace(issue.Text)
+		finding.Time = strings.TrimSpace(issue.Time)
+		report.Findings = append(report.Findings, finding)
+	}
+	return nil
+}
+
+// limitFindings 每个字段最多保留 complianceMaxFindings 条，block 级别的问题优先保留
+func limitFindings(findings []services.ComplianceFinding) []services.ComplianceFinding {
+	if len(findings) <= complianceMaxFindings {
+		return findings
+	}
+	limited := make([]services.ComplianceFinding, 0, complianceMaxFindings)
+	for _, finding := range findings {
+		if finding.Action == services.ComplianceBlock && len(limited) < complianceMaxFindings {
+			limited = append(limited, finding)
+		}
+	}
+	for _, finding := range findings {
+		if finding.Action != services.ComplianceBlock && len(limited) < complianceMaxFindings {
+			limited = append(limited, finding)
+		}
+	}
+	return limited
+}
@@ -2,12 +2,17 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/difyz9/ytb2bili/pkg/ratelimit"
+	"github.com/difyz9/ytb2bili/pkg/translator"
 )
 
 // DeepSeekClient DeepSeek API客户端
@@ -17,6 +22,10 @@ type DeepSeekClient struct {
 	Client     *http.Client
 	MaxRetries int
 	RetryDelay time.Duration
+
+	// 用量归属（记录 token 用量和费用时使用，可为空）
+	VideoID string
+	Step    string
 }
 
 // DeepSeekRequest API请求结构
@@ -78,6 +87,10 @@ func NewDeepSeekClient(apiKey string) *DeepSeekClient {
 
 // ChatCompletion 执行对话补全（带重试机制）
 func (c *DeepSeekClient) ChatCompletion(systemPrompt, userPrompt string) (string, error) {
+	if err := translator.CheckBudget(c.VideoID); err != nil {
+		return "", err
+	}
+
 	var lastErr error
 
 	for attempt := 0; attempt <= c.MaxRetries; attempt++ {
@@ -92,6 +105,11 @@ func (c *DeepSeekClient) ChatCompletion(systemPrompt, userPrompt string) (string
 
 		lastErr = err
 
+		// 熔断期间重试没有意义
+		if errors.Is(err, ratelimit.ErrCircuitOpen) {
+			return "", err
+		}
+
 		// 如果是API限制错误，延长等待时间
 		if strings.Contains(err.Error(), "rate limit") || strings.Contains(err.Error(), "429") {
 			time.Sleep(time.Duration(attempt+1) * 5 * time.Second)
@@ -103,7 +121,7 @@ func (c *DeepSeekClient) ChatCompletion(systemPrompt, userPrompt string) (string
 
 // doRequest 执行单次API请求
 func (c *DeepSeekClient) doRequest(systemPrompt, userPrompt string) (string, error) {
-	request := DeepSeekRequest{
+	content, _, err := c.send(DeepSeekRequest{
 		Model: "deepseek-chat",
 		Messages: []DeepSeekMessage{
 			{
@@ -120,51 +138,17 @@ func (c *DeepSeekClient) doRequest(systemPrompt, userPrompt string) (string, err
 			Temperature: 0.3,  // 降低随机性，提高一致性
 			MaxTokens:   4000, // 增加最大token数
 		},
-	}
-
-	jsonData, err := json.Marshal(request)
-	if err != nil {
-		return "", fmt.Errorf("序列化请求失败: %v", err)
-	}
-
-	req, err := http.NewRequest("POST", c.BaseURL, bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("创建请求失败: %v", err)
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.APIKey))
-
-	resp, err := c.Client.Do(req)
-	if err != nil {
-		return "", fmt.Errorf("发送请求失败: %v", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return "", fmt.Errorf("读取响应失败: %v", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API返回错误 (状态码: %d): %s", resp.StatusCode, string(body))
-	}
-
-	var response DeepSeekResponse
-	if err := json.Unmarshal(body, &response); err != nil {
-		return "", fmt.Errorf("解析响应失败: %v", err)
-	}
-
-	if len(response.Choices) == 0 {
-		return "", fmt.Errorf("API响应中没有结果")
-	}
-
-	return response.Choices[0].Message.Content, nil
+	})
+	return content, err
 }
 
 // ChatCompletionWithUsage 执行对话补全并返回使用量统计
 func (c *DeepSeekClient) ChatCompletionWithUsage(systemPrompt, userPrompt string) (string, *DeepSeekUsage, error) {
-	request := DeepSeekRequest{
+	if err := translator.CheckBudget(c.VideoID); err != nil {
+		return "", nil, err
+	}
+
+	return c.send(DeepSeekRequest{
 		Model: "deepseek-chat",
 		Messages: []DeepSeekMessage{
 			{
@@ -177,8 +161,47 @@ func (c *DeepSeekClient) ChatCompletionWithUsage(systemPrompt, userPrompt string
 			},
 		},
 		Stream: false,
+	})
+}
+
+// send 发送单次请求并记录用量，请求受 DeepSeek 提供商的共享限额约束
+func (c *DeepSeekClient) send(request DeepSeekRequest) (string, *DeepSeekUsage, error) {
+	texts := make([]string, 0, len(request.Messages))
+	for _, message := range request.Messages {
+		texts = append(texts, message.Content)
 	}
+	permit, err := ratelimit.Acquire(context.Background(), "deepseek", ratelimit.EstimateTokens(texts...))
+	if err != nil {
+		return "", nil, err
+	}
+
+	start := time.Now()
+	content, usage, err := c.post(request)
+	permit.Done(err)
+
+	record := &translator.UsageRecord{
+		VideoID:  c.VideoID,
+		Step:     c.Step,
+		Provider: "deepseek",
+		Model:    request.Model,
+		Kind:     translator.UsageKindChat,
+		Latency:  time.Since(start),
+		Success:  err == nil,
+	}
+	if usage != nil {
+		record.InputTokens = usage.PromptTokens
+		record.OutputTokens = usage.CompletionTokens
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	translator.RecordUsage(context.Background(), record)
+
+	return content, usage, err
+}
 
+// post 执行 HTTP 请求并解析响应
+func (c *DeepSeekClient) post(request DeepSeekRequest) (string, *DeepSeekUsage, error) {
 	jsonData, err := json.Marshal(request)
 	if err != nil {
 		return "", nil, fmt.Errorf("序列化请求失败: %v", err)
@@ -204,7 +227,7 @@ func (c *DeepSeekClient) ChatCompletionWithUsage(systemPrompt, userPrompt string
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", nil, fmt.Errorf("API返回错误 (状态码: %d): %s", resp.StatusCode, string(body))
+		return "", nil, ratelimit.WithStatus(resp.StatusCode, fmt.Errorf("API返回错误 (状态码: %d): %s", resp.StatusCode, string(body)))
 	}
 
 	var response DeepSeekResponse
@@ -213,7 +236,7 @@ func (c *DeepSeekClient) ChatCompletionWithUsage(systemPrompt, userPrompt string
 	}
 
 	if len(response.Choices) == 0 {
-		return "", nil, fmt.Errorf("API响应中没有结果")
+		return "", &response.Usage, fmt.Errorf("API响应中没有结果")
 	}
 
 	return response.Choices[0].Message.Content, &response.Usage, nil
@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/difyz9/ytb2bili/internal/chain_task/base"
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+	"github.com/difyz9/ytb2bili/pkg/utils"
+)
+
+// 语音存在性分类
+const (
+	SpeechPresenceSpeech = "speech" // 有正常语音
+	SpeechPresenceSparse = "sparse" // 语音稀少（仍然生成字幕）
+	SpeechPresenceNone   = "none"   // 无语音（跳过字幕、翻译、字幕上传）
+)
+
+// 语音检测阈值
+const (
+	speechNoiseDB           = -35.0 // 低于该音量视为静音（dB）
+	speechMinSilence        = 0.5   // 最短静音时长（秒）
+	speechSilentMaxVolume   = -50.0 // 最大音量低于该值视为整段无声
+	speechNoneActiveRatio   = 0.05  // 有声比例低于该值视为无语音
+	speechSparseActiveRatio = 0.30  // 有声比例低于该值视为语音稀少
+	asrNoneCharsPerMinute   = 20.0  // 转录文本每分钟字符数低于该值视为无语音
+	asrSparseCharsPerMinute = 200.0 // 转录文本每分钟字符数低于该值视为语音稀少
+	asrMinCues              = 3     // 转录字幕条数少于该值视为无语音（仅对较长视频生效）
+	asrMinCuesMinutes       = 3.0   // 视频时长达到该值（分钟）才按字幕条数判定，短视频只看文本密度
+)
+
+// SpeechAnalysisResult 语音检测结果，保存到 speech_analysis.json 供后续步骤和上传调度器读取
+type SpeechAnalysisResult struct {
+	Presence       string                    `json:"presence"`
+	Reason         string                    `json:"reason"`
+	Audio          *utils.AudioActivityStats `json:"audio,omitempty"`
+	SubtitleCues   int                       `json:"subtitle_cues"`
+	SubtitleChars  int                       `json:"subtitle_chars"`
+	CharsPerMinute float64                   `json:"chars_per_minute"`
+	AnalyzedAt     time.Time                 `json:"analyzed_at"`
+}
+
+// DetectSpeech 基于 ffmpeg silencedetect/volumedetect 的语音存在性检测
+type DetectSpeech struct {
+	base.BaseTask
+	App *core.AppServer
+}
+
+func NewDetectSpeech(name string, app *core.AppServer, stateManager *manager.StateManager, client *cos.CosClient) *DetectSpeech {
+	return &DetectSpeech{
+		BaseTask: base.BaseTask{
+			Name:         name,
+			StateManager: stateManager,
+			Client:       client,
+		},
+		App: app,
+	}
+}
+
+func (t *DetectSpeech) Execute(context map[string]interface{}) bool {
+	t.App.Logger.Infof("🔊 开始语音检测: VideoID=%s", t.StateManager.VideoID)
+
+	audioPath := t.StateManager.OriginalMP3
+	if ok, _ := utils.CheckAudioFile(audioPath); !ok {
+		audioPath = t.StateManager.InputVideoPath
+	}
+
+	stats, err := utils.AnalyzeAudioActivity(audioPath, speechNoiseDB, speechMinSilence)
+	if err != nil {
+		// 检测失败不影响后续流程，按有语音处理；删除上次运行遗留的检测结果，避免后续步骤误读
+		t.App.Logger.Warnf("⚠️ 语音检测失败，按有语音处理: %v", err)
+		if err := os.Remove(t.StateManager.SpeechAnalysis); err != nil && !os.IsNotExist(err) {
+			t.App.Logger.Warnf("⚠️ 删除旧的语音检测结果失败: %v", err)
+		}
+		applySpeechAnalysis(context, &SpeechAnalysisResult{Presence: SpeechPresenceSpeech})
+		return true
+	}
+
+	result := &SpeechAnalysisResult{
+		Audio:      stats,
+		AnalyzedAt: time.Now(),
+	}
+	result.Presence, result.Reason = classifyAudioActivity(stats)
+
+	t.App.Logger.Infof("🔊 语音检测结果: %s (时长 %.1fs, 有声比例 %.1f%%, 最大音量 %.1fdB)",
+		result.Presence, stats.Duration, stats.ActiveRatio()*100, stats.MaxVolume)
+
+	if err := SaveSpeechAnalysis(t.StateManager, result); err != nil {
+		t.App.Logger.Warnf("⚠️ 保存语音检测结果失败: %v", err)
+	}
+	applySpeechAnalysis(context, result)
+	return true
+}
+
+// CheckSubtitleDensity 根据转录字幕的文本密度修正语音检测结果
+// 音乐、游戏画面等有声音但无人说话的视频，只有转录后才能判断
+type CheckSubtitleDensity struct {
+	base.BaseTask
+	App *core.AppServer
+}
+
+func NewCheckSubtitleDensity(name string, app *core.AppServer, stateManager *manager.StateManager, client *cos.CosClient) *CheckSubtitleDensity {
+	return &CheckSubtitleDensity{
+		BaseTask: base.BaseTask{
+			Name:         name,
+			StateManager: stateManager,
+			Client:       client,
+		},
+		App: app,
+	}
+}
+
+func (t *CheckSubtitleDensity) Execute(context map[string]interface{}) bool {
+	result, err := LoadSpeechAnalysis(t.StateManager)
+	if err != nil {
+		result = &SpeechAnalysisResult{Presence: SpeechPresenceSpeech}
+	}
+
+	// 已判定为无语音时，转录步骤已被跳过，无需再检查
+	if result.Presence == SpeechPresenceNone {
+		applySpeechAnalysis(context, result)
+		return true
+	}
+
+	doc, err := subtitle.ReadFile(t.StateManager.OriginalSRT)
+	if err != nil {
+		t.App.Logger.Warnf("⚠️ 读取转录字幕失败，跳过密度检查: %v", err)
+		return true
+	}
+
+	cues, chars := countSubtitleText(doc)
+	result.SubtitleCues = cues
+	result.SubtitleChars = chars
+
+	minutes := 0.0
+	if result.Audio != nil && result.Audio.Duration > 0 {
+		minutes = result.Audio.Duration / 60
+		result.CharsPerMinute = float64(chars) / minutes
+	}
+
+	switch {
+	case cues == 0:
+		result.Presence = SpeechPresenceNone
+		result.Reason = "转录结果没有字幕，判定为无语音"
+	case minutes >= asrMinCuesMinutes && cues < asrMinCues:
+		result.Presence = SpeechPresenceNone
+		result.Reason = fmt.Sprintf("%.1f 分钟的视频转录结果仅 %d 条字幕，判定为无语音", minutes, cues)
+	case minutes > 0 && result.CharsPerMinute < asrNoneCharsPerMinute:
+		result.Presence = SpeechPresenceNone
+		result.Reason = fmt.Sprintf("转录文本密度过低 (%.1f 字符/分钟)，判定为无语音", result.CharsPerMinute)
+	case minutes > 0 && result.CharsPerMinute < asrSparseCharsPerMinute:
+		result.Presence = SpeechPresenceSparse
+		result.Reason = fmt.Sprintf("转录文本密度较低 (%.1f 字符/分钟)", result.CharsPerMinute)
+	}
+	result.AnalyzedAt = time.Now()
+
+	t.App.Logger.Infof("📝 字幕密度检查: %d 条字幕, %d 字符, %.1f 字符/分钟 → %s",
+		cues, chars, result.CharsPerMinute, result.Presence)
+
+	if err := SaveSpeechAnalysis(t.StateManager, result); err != nil {
+		t.App.Logger.Warnf("⚠️ 保存语音检测结果失败: %v", err)
+	}
+	applySpeechAnalysis(context, result)
+	return true
+}
+
+// classifyAudioActivity 根据音频活动统计分类
+func classifyAudioActivity(stats *utils.AudioActivityStats) (string, string) {
+	ratio := stats.ActiveRatio()
+	switch {
+	case stats.MaxVolume < speechSilentMaxVolume:
+		return SpeechPresenceNone, fmt.Sprintf("音频整体无声 (最大音量 %.1fdB)", stats.MaxVolume)
+	case ratio < speechNoneActiveRatio:
+		return SpeechPresenceNone, fmt.Sprintf("有声比例仅 %.1f%%，判定为无语音", ratio*100)
+	case ratio < speechSparseActiveRatio:
+		return SpeechPresenceSparse, fmt.Sprintf("有声比例 %.1f%%，语音稀少", ratio*100)
+	default:
+		return SpeechPresenceSpeech, ""
+	}
+}
+
+// applySpeechAnalysis 将检测结果写入任务上下文
+func applySpeechAnalysis(context map[string]interface{}, result *SpeechAnalysisResult) {
+	context["speech_presence"] = result.Presence
+	if result.Presence == SpeechPresenceNone {
+		context["speech_skip_reason"] = result.Reason
+	} else {
+		delete(context, "speech_skip_reason")
+	}
+}
+
+// countSubtitleText 统计字幕中的有效条数和文本字符数
+func countSubtitleText(doc *subtitle.Document) (int, int) {
+	cues, chars := 0, 0
+	for _, cue := range doc.Cues {
+		text := strings.TrimSpace(cue.Text)
+		if text == "" {
+			continue
+		}
+		cues++
+		chars += utf8.RuneCountInString(text)
+	}
+	return cues, chars
+}
+
+// SaveSpeechAnalysis 保存语音检测结果
+func SaveSpeechAnalysis(stateManager *manager.StateManager, result *SpeechAnalysisResult) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(stateManager.SpeechAnalysis, data, 0644)
+}
+
+// LoadSpeechAnalysis 读取语音检测结果
+func LoadSpeechAnalysis(stateManager *manager.StateManager) (*SpeechAnalysisResult, error) {
+	data, err := os.ReadFile(stateManager.SpeechAnalysis)
+	if err != nil {
+		return nil, err
+	}
+	var result SpeechAnalysisResult
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// NoSpeechSkipReason 如果视频被判定为无语音，返回跳过原因，否则返回空字符串
+func NoSpeechSkipReason(stateManager *manager.StateManager) string {
+	result, err := LoadSpeechAnalysis(stateManager)
+	if err != nil || result.Presence != SpeechPresenceNone {
+		return ""
+	}
+	if result.Reason == "" {
+		return "视频无语音"
+	}
+	return result.Reason
+}
@@ -295,12 +295,26 @@ func (t *DownloadVideo) executeDownload(ytdlpPath, videoURL string, useProxy boo
 			t.App.Logger.Infof("✓ 原始描述: %s", t.truncateString(metadata.Description, 100))
 		}
 
+		// 来源视频自带的章节，供生成章节步骤翻译使用
+		if len(metadata.Chapters) > 0 {
+			t.saveSourceChapters(metadata)
+		}
+
 		// 保存到数据库
 		if t.SavedVideoService != nil {
 			savedVideo, err := t.SavedVideoService.GetVideoByVideoID(t.StateManager.VideoID)
 			if err == nil {
 				savedVideo.Title = metadata.Title
 				savedVideo.Description = metadata.Description
+				if metadata.ChannelID != "" {
+					savedVideo.ChannelID = metadata.ChannelID // 用于匹配频道级术语表
+				}
+				if metadata.Uploader != "" {
+					savedVideo.ChannelName = metadata.Uploader // 提示词模板中的来源频道
+				}
+				// 来源分类和标签用于选择投稿分区
+				savedVideo.SourceCategory = truncateJoined(metadata.Categories, 200)
+				savedVideo.SourceTags = truncateJoined(metadata.Tags, 1000)
 				if err := t.SavedVideoService.UpdateVideo(savedVideo); err != nil {
 					t.App.Logger.Errorf("❌ 保存原始元数据到数据库失败: %v", err)
 				} else {
@@ -387,7 +401,14 @@ type VideoMetadataInfo struct {
 	Title       string `json:"title"`
 	Description string `json:"description"`
 	Uploader    string `json:"uploader"`
+	ChannelID   string `json:"channel_id"`
 	Duration    int    `json:"duration"`
+	Categories  []string `json:"categories"`
+	Tags        []string `json:"tags"`
+	Chapters    []struct {
+		StartTime float64 `json:"start_time"`
+		Title     string  `json:"title"`
+	} `json:"chapters"`
 }
 
 // getVideoMetadata 使用 yt-dlp 获取视频元数据（带代理回退）
@@ -446,6 +467,19 @@ func (t *DownloadVideo) getVideoMetadata(ytdlpPath string) (*VideoMetadataInfo,
 	return &metadata, nil
 }
 
+// saveSourceChapters 保存来源视频自带的章节（原文标题）
+func (t *DownloadVideo) saveSourceChapters(metadata *VideoMetadataInfo) {
+	list := &utils.ChapterList{Source: utils.ChapterSourceVideo}
+	for _, chapter := range metadata.Chapters {
+		list.Chapters = append(list.Chapters, utils.Chapter{Start: chapter.StartTime, Title: chapter.Title})
+	}
+	if err := utils.SaveChapters(t.StateManager.SourceChapters, list); err != nil {
+		t.App.Logger.Warnf("⚠️ 保存来源章节失败: %v", err)
+		return
+	}
+	t.App.Logger.Infof("✓ 来源视频包含 %d 个章节", len(list.Chapters))
+}
+
 // truncateString 截断字符串用于日志显示
 func (t *DownloadVideo) truncateString(s string, maxLen int) string {
 	runes := []rune(s)
@@ -3,12 +3,18 @@ package handlers
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/pkg/prompt"
+	"github.com/difyz9/ytb2bili/pkg/ratelimit"
+	"github.com/difyz9/ytb2bili/pkg/translator"
 	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/googleapi"
 	"google.golang.org/api/option"
 )
 
@@ -93,78 +99,44 @@ func (g *GeminiClient) WaitForFileProcessing(ctx context.Context, file *genai.Fi
 }
 
 // GenerateMetadataFromVideo 从视频生成元数据（标题、描述、标签）
-func (g *GeminiClient) GenerateMetadataFromVideo(ctx context.Context, videoFile *genai.File) (*VideoMetadata, error) {
-	// 直接使用模型名称，SDK会自动处理
-	model := g.client.GenerativeModel(g.model)
-
-	// 设置生成参数
-	model.SetMaxOutputTokens(int32(g.maxTokens))
-	model.SetTemperature(0.7)
-
-	prompt := `请作为一个专业的 Bilibili UP 主，分析这个视频并生成以下内容：
-
-1. 一个吸引眼球的标题（严格控制在30个字以内，能够准确概括视频主题）
-2. 一个精炼的视频介绍（严格控制在100个字以内，提炼视频的核心内容和亮点）
-3. 3-5个相关的标签
-
-要求：
-- 必须使用中文
-- 标题要简洁有力，吸引观众点击
-- 介绍要精炼，突出重点，严格控制在100字以内
-- 标签要准确反映视频内容
-- 输出格式必须是JSON，格式如下：
-{
-  "title": "视频标题",
-  "description": "视频介绍（100字以内）",
-  "tags": ["标签1", "标签2", "标签3"]
+func (g *GeminiClient) GenerateMetadataFromVideo(ctx context.Context, videoFile *genai.File, data *prompt.Data) (*VideoMetadata, error) {
+	rendered, err := prompt.Render(prompt.MetadataGeminiVideo, data)
+	if err != nil {
+		return nil, err
+	}
+	return g.generateMetadata(ctx, rendered, genai.FileData{URI: videoFile.URI})
 }
 
-请直接返回JSON格式的结果，不要包含任何其他说明文字。`
-
-	resp, err := model.GenerateContent(ctx, genai.FileData{URI: videoFile.URI}, genai.Text(prompt))
+// GenerateMetadataFromFrames 从采样的关键帧和字幕摘录生成元数据，图片内联发送，无需上传视频
+func (g *GeminiClient) GenerateMetadataFromFrames(ctx context.Context, images []services.ImageInput, data *prompt.Data) (*VideoMetadata, error) {
+	rendered, err := prompt.Render(prompt.MetadataFrames, data)
 	if err != nil {
-		return nil, fmt.Errorf("生成内容失败: %v", err)
+		return nil, err
 	}
 
-	if len(resp.Candidates) == 0 || len(resp.Candidates[0].Content.Parts) == 0 {
-		return nil, fmt.Errorf("未生成任何内容")
+	parts := make([]genai.Part, 0, len(images)*2)
+	for _, image := range images {
+		if image.Label != "" {
+			parts = append(parts, genai.Text(image.Label))
+		}
+		parts = append(parts, genai.Blob{MIMEType: image.MIMEType, Data: image.Data})
 	}
-
-	// 提取文本内容
-	content := fmt.Sprintf("%v", resp.Candidates[0].Content.Parts[0])
-
-	return parseMetadataJSON(content)
+	return g.generateMetadata(ctx, rendered, parts...)
 }
 
-// GenerateMetadataFromText 从文本生成元数据（用于字幕）
-func (g *GeminiClient) GenerateMetadataFromText(ctx context.Context, subtitleText string) (*VideoMetadata, error) {
+// generateMetadata 使用渲染后的提示词生成元数据，parts 为提示词之前附加的内容（如视频文件）
+func (g *GeminiClient) generateMetadata(ctx context.Context, rendered *prompt.Rendered, parts ...genai.Part) (*VideoMetadata, error) {
 	// 直接使用模型名称，SDK会自动处理
 	model := g.client.GenerativeModel(g.model)
 
 	// 设置生成参数
 	model.SetMaxOutputTokens(int32(g.maxTokens))
 	model.SetTemperature(0.7)
+	if rendered.System != "" {
+		model.SystemInstruction = genai.NewUserContent(genai.Text(rendered.System))
+	}
 
-	prompt := fmt.Sprintf(`请根据以下视频字幕内容，生成一个吸引人的视频标题、精炼介绍和3-5个相关标签。
-
-字幕内容：
-%s
-
-要求：
-1. 标题要简洁有力，严格控制在30个字以内，能够准确概括视频主题
-2. 介绍要精炼，严格控制在100个字以内，提炼视频的核心内容和亮点
-3. 标签要准确反映视频内容，3-5个即可
-4. 必须使用中文
-5. 输出格式必须是JSON，格式如下：
-{
-  "title": "视频标题",
-  "description": "视频介绍（100字以内）",
-  "tags": ["标签1", "标签2", "标签3"]
-}
-
-请直接返回JSON格式的结果，不要包含任何其他说明文字。`, subtitleText)
-
-	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+	resp, err := g.generateContent(ctx, model, append(parts, genai.Text(rendered.User))...)
 	if err != nil {
 		return nil, fmt.Errorf("生成内容失败: %v", err)
 	}
@@ -176,14 +148,65 @@ func (g *GeminiClient) GenerateMetadataFromText(ctx context.Context, subtitleTex
 	// 提取文本内容
 	content := fmt.Sprintf("%v", resp.Candidates[0].Content.Parts[0])
 
-	return parseMetadataJSON(content)
+	metadata, err := parseMetadataJSON(content)
+	if err != nil {
+		return nil, err
+	}
+	metadata.PromptVersion = rendered.Version
+	return metadata, nil
 }
 
-// parseMetadataJSON 解析 JSON 格式的元数据
-func parseMetadataJSON(content string) (*VideoMetadata, error) {
-	var metadata VideoMetadata
+// generateContent 调用模型生成内容并记录用量（用量归属取自 ctx）
+func (g *GeminiClient) generateContent(ctx context.Context, model *genai.GenerativeModel, parts ...genai.Part) (*genai.GenerateContentResponse, error) {
+	if err := translator.CheckBudget(translator.UsageScopeFromContext(ctx).VideoID); err != nil {
+		return nil, err
+	}
+
+	var texts []string
+	for _, part := range parts {
+		if text, ok := part.(genai.Text); ok {
+			texts = append(texts, string(text))
+		}
+	}
+	permit, err := ratelimit.Acquire(ctx, "gemini", ratelimit.EstimateTokens(texts...))
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := model.GenerateContent(ctx, parts...)
+	permit.Done(geminiStatusError(err))
+
+	record := &translator.UsageRecord{
+		Provider: "gemini",
+		Model:    g.model,
+		Kind:     translator.UsageKindChat,
+		Latency:  time.Since(start),
+		Success:  err == nil,
+	}
+	if resp != nil && resp.UsageMetadata != nil {
+		record.InputTokens = int(resp.UsageMetadata.PromptTokenCount)
+		record.OutputTokens = int(resp.UsageMetadata.CandidatesTokenCount)
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	translator.RecordUsage(ctx, record)
 
-	// 清理可能的 markdown 代码块标记
+	return resp, err
+}
+
+// geminiStatusError 为 REST 接口返回的错误附加 HTTP 状态码，供熔断器判断
+func geminiStatusError(err error) error {
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		return ratelimit.WithStatus(apiErr.Code, err)
+	}
+	return err
+}
+
+// trimCodeFence 清理模型返回内容中可能的 markdown 代码块标记
+func trimCodeFence(content string) string {
 	content = strings.TrimSpace(content)
 	if strings.HasPrefix(content, "```json") {
 		content = strings.TrimPrefix(content, "```json")
@@ -192,7 +215,14 @@ func parseMetadataJSON(content string) (*VideoMetadata, error) {
 		content = strings.TrimPrefix(content, "```")
 		content = strings.TrimSuffix(content, "```")
 	}
-	content = strings.TrimSpace(content)
+	return strings.TrimSpace(content)
+}
+
+// parseMetadataJSON 解析 JSON 格式的元数据
+func parseMetadataJSON(content string) (*VideoMetadata, error) {
+	var metadata VideoMetadata
+
+	content = trimCodeFence(content)
 
 	// 使用 json.Unmarshal 解析
 	if err := json.Unmarshal([]byte(content), &metadata); err != nil {
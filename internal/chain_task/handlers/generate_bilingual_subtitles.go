@@ -0,0 +1,149 @@
+package handlers
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/difyz9/ytb2bili/internal/chain_task/base"
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+)
+
+// GenerateBilingualSubtitles 生成双语字幕（中文在上、原文在下），同时输出 SRT 和带样式的 ASS
+type GenerateBilingualSubtitles struct {
+	base.BaseTask
+	App *core.AppServer
+}
+
+func NewGenerateBilingualSubtitles(name string, app *core.AppServer, stateManager *manager.StateManager, client *cos.CosClient) *GenerateBilingualSubtitles {
+	return &GenerateBilingualSubtitles{
+		BaseTask: base.BaseTask{
+			Name:         name,
+			StateManager: stateManager,
+			Client:       client,
+		},
+		App: app,
+	}
+}
+
+func (t *GenerateBilingualSubtitles) Execute(context map[string]interface{}) bool {
+	cfg := t.App.Config.SubtitleConfig
+	if cfg == nil || !cfg.Bilingual {
+		t.App.Logger.Info("双语字幕未启用，跳过")
+		return true
+	}
+
+	if _, err := os.Stat(t.StateManager.TranslateSRT); os.IsNotExist(err) {
+		t.App.Logger.Warn("⚠️  中文字幕文件不存在，跳过双语字幕生成")
+		return true
+	}
+	originalPath := t.StateManager.OriginalSubtitlePath()
+	if originalPath == "" {
+		t.App.Logger.Warn("⚠️  原文字幕文件不存在，跳过双语字幕生成")
+		return true
+	}
+
+	zhDoc, err := subtitle.ReadFile(t.StateManager.TranslateSRT)
+	if err != nil {
+		t.App.Logger.Errorf("❌ 读取中文字幕失败: %v", err)
+		context["error"] = fmt.Sprintf("读取中文字幕失败: %v", err)
+		return false
+	}
+	originalDoc, err := subtitle.ReadFile(originalPath)
+	if err != nil {
+		t.App.Logger.Errorf("❌ 读取原文字幕失败: %v", err)
+		context["error"] = fmt.Sprintf("读取原文字幕失败: %v", err)
+		return false
+	}
+	zhDoc.Sort()
+	originalDoc.Sort()
+
+	bilingual := subtitle.MergeBilingual(zhDoc, originalDoc)
+	if err := subtitle.WriteFile(t.StateManager.BilingualSRT, bilingual); err != nil {
+		t.App.Logger.Errorf("❌ 保存双语 SRT 字幕失败: %v", err)
+		context["error"] = fmt.Sprintf("保存双语 SRT 字幕失败: %v", err)
+		return false
+	}
+
+	preset := ResolveASSPreset(cfg)
+	ass := subtitle.FormatBilingualASS(zhDoc, originalDoc, preset)
+	if err := os.WriteFile(t.StateManager.BilingualASS, []byte(ass), 0644); err != nil {
+		t.App.Logger.Errorf("❌ 保存双语 ASS 字幕失败: %v", err)
+		context["error"] = fmt.Sprintf("保存双语 ASS 字幕失败: %v", err)
+		return false
+	}
+
+	t.App.Logger.Infof("🌐 双语字幕已生成: %d 条 (ASS 样式: %s)", bilingual.Len(), preset.Name)
+
+	context["bilingual_srt_path"] = t.StateManager.BilingualSRT
+	context["bilingual_ass_path"] = t.StateManager.BilingualASS
+	return true
+}
+
+// ResolveASSPreset 根据配置选择双语 ASS 样式预设：
+// 先查找内置预设，再用同名的自定义预设覆盖（自定义预设以内置同名预设或 default 为基础）
+func ResolveASSPreset(cfg *types.SubtitleConfig) subtitle.ASSPreset {
+	presets := subtitle.BuiltinASSPresets()
+
+	name := subtitle.DefaultASSPresetName
+	if cfg != nil && cfg.ASSPreset != "" {
+		name = cfg.ASSPreset
+	}
+
+	preset, ok := presets[name]
+	if !ok {
+		preset = presets[subtitle.DefaultASSPresetName]
+	}
+
+	if cfg != nil {
+		if custom := cfg.ASSPresets[name]; custom != nil {
+			preset.Name = name
+			applyASSStyleConfig(&preset.Primary, custom.Primary)
+			applyASSStyleConfig(&preset.Secondary, custom.Secondary)
+		}
+	}
+	return preset
+}
+
+// applyASSStyleConfig 用配置中的非零字段覆盖样式
+func applyASSStyleConfig(style *subtitle.ASSStyle, cfg *types.ASSStyleConfig) {
+	if cfg == nil {
+		return
+	}
+	if cfg.FontName != "" {
+		style.FontName = cfg.FontName
+	}
+	if cfg.FontSize > 0 {
+		style.FontSize = cfg.FontSize
+	}
+	if cfg.PrimaryColour != "" {
+		style.PrimaryColour = cfg.PrimaryColour
+	}
+	if cfg.OutlineColour != "" {
+		style.OutlineColour = cfg.OutlineColour
+	}
+	if cfg.BackColour != "" {
+		style.BackColour = cfg.BackColour
+	}
+	if cfg.Bold {
+		style.Bold = true
+	}
+	if cfg.BorderStyle > 0 {
+		style.BorderStyle = cfg.BorderStyle
+	}
+	if cfg.Outline > 0 {
+		style.Outline = cfg.Outline
+	}
+	if cfg.Shadow > 0 {
+		style.Shadow = cfg.Shadow
+	}
+	if cfg.Alignment > 0 {
+		style.Alignment = cfg.Alignment
+	}
+	if cfg.MarginV > 0 {
+		style.MarginV = cfg.MarginV
+	}
+}
@@ -0,0 +1,290 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/difyz9/ytb2bili/internal/chain_task/base"
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/prompt"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+	"github.com/difyz9/ytb2bili/pkg/translator"
+	"github.com/difyz9/ytb2bili/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// 发送给模型的带时间点字幕的最大字符数
+const chapterTranscriptMaxRunes = 20000
+
+// GenerateChapters 生成视频章节：来源视频自带章节时翻译章节标题，否则由大模型根据带时间点的字幕划分章节。
+// 结果保存为 chapters.json，上传时以时间点列表写入简介
+type GenerateChapters struct {
+	base.BaseTask
+	App               *core.AppServer
+	DB                *gorm.DB
+	AIService         *services.AIServiceManager
+	SavedVideoService *services.SavedVideoService
+}
+
+func NewGenerateChapters(name string, app *core.AppServer, stateManager *manager.StateManager, client *cos.CosClient, db *gorm.DB, savedVideoService *services.SavedVideoService, aiService *services.AIServiceManager) *GenerateChapters {
+	return &GenerateChapters{
+		BaseTask: base.BaseTask{
+			Name:         name,
+			StateManager: stateManager,
+			Client:       client,
+		},
+		App:               app,
+		DB:                db,
+		AIService:         aiService,
+		SavedVideoService: savedVideoService,
+	}
+}
+
+// modelChapters 模型返回的章节
+type modelChapters struct {
+	Chapters []struct {
+		Start string `json:"start"`
+		Title string `json:"title"`
+	} `json:"chapters"`
+}
+
+func (t *GenerateChapters) Execute(context map[string]interface{}) bool {
+	cfg := t.App.Config.ChapterConfig
+	if cfg == nil || !cfg.Enabled {
+		t.App.Logger.Info("章节生成未启用，跳过")
+		return true
+	}
+
+	// 重新执行时清除上次的结果，未生成章节时简介中不再包含旧章节
+	os.Remove(t.StateManager.Chapters)
+
+	duration := videoDuration(t.StateManager)
+	if cfg.MinVideoMinutes > 0 && duration > 0 && duration < float64(cfg.MinVideoMinutes*60) {
+		t.App.Logger.Infof("视频时长 %s 短于 %d 分钟，不生成章节", utils.FormatChapterTime(duration, false), cfg.MinVideoMinutes)
+		return true
+	}
+
+	var video *model.SavedVideo
+	if t.SavedVideoService != nil {
+		video, _ = t.SavedVideoService.GetVideoByVideoID(t.StateManager.VideoID)
+	}
+	language := resolveTargetLanguages(t.App.Config, video)[0]
+
+	var list *utils.ChapterList
+	minGap := float64(cfg.MinChapterSeconds)
+	if source, err := utils.LoadChapters(t.StateManager.SourceChapters); err == nil && len(source.Chapters) >= 2 {
+		// 1. 翻译来源视频自带的章节标题（来源章节由作者划分，不按最短时长合并）
+		t.App.Logger.Infof("📑 翻译来源视频的 %d 个章节标题", len(source.Chapters))
+		list, err = t.translateSourceChapters(source.Chapters, language, video)
+		if err != nil {
+			t.App.Logger.Errorf("❌ 翻译章节标题失败: %v", err)
+			context["error"] = fmt.Sprintf("翻译章节标题失败: %v", err)
+			markBudgetPaused(context, err)
+			return false
+		}
+		minGap = 0
+	} else if cfg.UseLLM {
+		// 2. 由大模型根据带时间点的字幕划分章节
+		if t.AIService == nil || len(t.AIService.EnabledProviders()) == 0 {
+			t.App.Logger.Warn("⚠️ 没有可用的 AI 服务，跳过章节生成")
+			return true
+		}
+		transcript := timedTranscript(t.StateManager, language, duration, chapterTranscriptMaxRunes)
+		if transcript == "" {
+			t.App.Logger.Warn("⚠️ 没有可用的字幕，跳过章节生成")
+			return true
+		}
+		t.App.Logger.Info("📑 根据字幕生成章节")
+		list, err = t.generateWithLLM(transcript, language, video, cfg.MaxChapters)
+		if err != nil {
+			t.App.Logger.Errorf("❌ 生成章节失败: %v", err)
+			context["error"] = fmt.Sprintf("生成章节失败: %v", err)
+			markBudgetPaused(context, err)
+			return false
+		}
+	} else {
+		t.App.Logger.Info("来源视频没有章节，且未启用大模型生成，跳过")
+		return true
+	}
+
+	// 3. 整理并保存
+	chapters := utils.NormalizeChapters(list.Chapters, duration, minGap, cfg.MaxChapters)
+	if chapters == nil {
+		t.App.Logger.Warn("⚠️ 有效章节不足 2 个，不写入章节")
+		return true
+	}
+	list.Chapters = chapters
+	if err := utils.SaveChapters(t.StateManager.Chapters, list); err != nil {
+		t.App.Logger.Errorf("❌ 保存章节失败: %v", err)
+		context["error"] = fmt.Sprintf("保存章节失败: %v", err)
+		return false
+	}
+	if list.PromptVersion != "" {
+		if err := recordPromptVersions(t.StateManager, filepath.Base(t.StateManager.Chapters), []string{list.PromptVersion}); err != nil {
+			t.App.Logger.Warnf("⚠️ 记录提示词版本失败: %v", err)
+		}
+	}
+	context["chapters"] = chapters
+
+	t.App.Logger.Infof("✓ 已生成 %d 个章节（%s）:\n%s", len(chapters), list.Source, utils.FormatChapterLines(chapters))
+	return true
+}
+
+// videoDuration 视频时长（秒）：优先读取视频文件，失败时取字幕最后一条的结束时间
+func videoDuration(stateManager *manager.StateManager) float64 {
+	if duration, err := utils.GetMediaDuration(stateManager.InputVideoPath); err == nil && duration > 0 {
+		return duration
+	}
+	if path := stateManager.OriginalSubtitlePath(); path != "" {
+		if doc, err := subtitle.ReadFile(path); err == nil && doc.Len() > 0 {
+			return float64(doc.Cues[doc.Len()-1].End) / 1000
+		}
+	}
+	return 0
+}
+
+// translateSourceChapters 用字幕翻译服务翻译来源章节标题（同样使用术语表）
+func (t *GenerateChapters) translateSourceChapters(chapters []utils.Chapter, language string, video *model.SavedVideo) (*utils.ChapterList, error) {
+	targetLang := translator.NormalizeLangCode(language)
+	titles := make([]string, len(chapters))
+	for i, chapter := range chapters {
+		titles[i] = chapter.Title
+	}
+
+	var glossary []translator.GlossaryTerm
+	if t.DB != nil {
+		if terms, err := services.NewGlossaryService(t.DB).EffectiveTranslatorTerms(video, targetLang); err == nil {
+			glossary = translator.FilterGlossary(terms, titles)
+		}
+	}
+
+	promptData := services.PromptDataForVideo(video)
+	translatorManager := translator.NewTranslatorManager(t.App.Config)
+	ctx := translator.WithUsageScope(context.Background(), t.StateManager.VideoID, t.Name)
+	result, err := translatorManager.BatchTranslate(ctx, &translator.BatchTranslationRequest{
+		Texts:           titles,
+		SourceLang:      "auto",
+		TargetLang:      targetLang,
+		TextType:        "title",
+		Glossary:        glossary,
+		GlossaryVersion: translator.GlossaryVersion(glossary),
+		VideoTitle:      promptData.SourceTitle,
+		Channel:         promptData.Channel,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Results) != len(chapters) {
+		return nil, fmt.Errorf("翻译结果数量不匹配: 期望 %d，实际 %d", len(chapters), len(result.Results))
+	}
+
+	list := &utils.ChapterList{Source: utils.ChapterSourceVideo, Provider: result.Provider, PromptVersion: result.PromptVersion}
+	for i, chapter := range chapters {
+		title := strings.TrimSpace(result.Results[i].TranslatedText)
+		if title == "" {
+			title = chapter.Title
+		}
+		list.Chapters = append(list.Chapters, utils.Chapter{Start: chapter.Start, Title: title})
+	}
+	return list, nil
+}
+
+// generateWithLLM 渲染章节提示词，由模型根据带时间点的字幕划分章节
+func (t *GenerateChapters) generateWithLLM(transcript, language string, video *model.SavedVideo, maxChapters int) (*utils.ChapterList, error) {
+	data := services.PromptDataForVideo(video)
+	data.SubtitleText = transcript
+	data.TargetLanguage = translator.LanguageName(language)
+	data.MaxChapters = maxChapters
+
+	rendered, err := prompt.Render(prompt.Chapters, data)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := translator.WithUsageScope(context.Background(), t.StateManager.VideoID, t.Name)
+	content, provider, err := t.AIService.ChatCompletionContext(ctx, rendered.System, rendered.User)
+	if err != nil {
+		return nil, err
+	}
+	t.App.Logger.Debugf("%s 原始返回: %s", provider, content)
+
+	var result modelChapters
+	if err := json.Unmarshal([]byte(trimCodeFence(content)), &result); err != nil {
+		return nil, fmt.Errorf("解析章节JSON失败: %v, 内容: %s", err, content)
+	}
+
+	list := &utils.ChapterList{Source: utils.ChapterSourceLLM, Provider: string(provider), PromptVersion: rendered.Version}
+	for _, chapter := range result.Chapters {
+		start, err := utils.ParseChapterTime(chapter.Start)
+		if err != nil {
+			t.App.Logger.Warnf("⚠️ 忽略时间无效的章节 %q: %v", chapter.Title, err)
+			continue
+		}
+		list.Chapters = append(list.Chapters, utils.Chapter{Start: start, Title: chapter.Title})
+	}
+	if len(list.Chapters) == 0 {
+		return nil, errors.New("模型没有返回有效的章节")
+	}
+	return list, nil
+}
+
+// timedTranscript 将主语言字幕（不存在时使用原文字幕）按时间窗口合并为 "[mm:ss] 文本" 行，
+// 长视频加大时间窗口并截断每段文本，总长度控制在约 maxRunes 个字符
+func timedTranscript(stateManager *manager.StateManager, language string, duration float64, maxRunes int) string {
+	path := stateManager.TranslatedSRTPath(language)
+	if _, err := os.Stat(path); err != nil {
+		path = stateManager.OriginalSubtitlePath()
+	}
+	if path == "" {
+		return ""
+	}
+	doc, err := subtitle.ReadFile(path)
+	if err != nil || doc.Len() == 0 {
+		return ""
+	}
+
+	window := int64(30000)
+	if byDuration := int64(duration * 1000 / 300); byDuration > window {
+		window = byDuration
+	}
+
+	type block struct {
+		start int64
+		texts []string
+	}
+	var blocks []block
+	for _, cue := range doc.Cues {
+		text := strings.TrimSpace(strings.ReplaceAll(cue.Text, "\n", " "))
+		if text == "" {
+			continue
+		}
+		if len(blocks) == 0 || cue.Start >= blocks[len(blocks)-1].start+window {
+			blocks = append(blocks, block{start: cue.Start})
+		}
+		blocks[len(blocks)-1].texts = append(blocks[len(blocks)-1].texts, text)
+	}
+	if len(blocks) == 0 {
+		return ""
+	}
+
+	long := duration >= 3600
+	perBlock := maxRunes / len(blocks)
+	lines := make([]string, 0, len(blocks))
+	for _, b := range blocks {
+		text := strings.Join(b.texts, " ")
+		if runes := []rune(text); len(runes) > perBlock {
+			text = string(runes[:perBlock]) + "…"
+		}
+		lines = append(lines, fmt.Sprintf("[%s] %s", utils.FormatChapterTime(float64(b.start)/1000, long), text))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,175 @@
+package handlers
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/difyz9/ytb2bili/internal/chain_task/base"
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/utils"
+)
+
+// GenerateCover 从视频中挑选画面生成B站封面：场景检测取候选帧，按清晰度和亮度打分（排除黑场），
+// 裁剪缩放为封面尺寸并按模板叠加AI生成的标题。得分最高的画面 + 第一个模板 + 第一个尺寸作为默认封面（cover.jpg），
+// 其余候选保存在 covers 目录中供手动选择
+type GenerateCover struct {
+	base.BaseTask
+	App               *core.AppServer
+	SavedVideoService *services.SavedVideoService
+}
+
+func NewGenerateCover(name string, app *core.AppServer, stateManager *manager.StateManager, client *cos.CosClient, savedVideoService *services.SavedVideoService) *GenerateCover {
+	return &GenerateCover{
+		BaseTask: base.BaseTask{
+			Name:         name,
+			StateManager: stateManager,
+			Client:       client,
+		},
+		App:               app,
+		SavedVideoService: savedVideoService,
+	}
+}
+
+func (t *GenerateCover) Execute(context map[string]interface{}) bool {
+	cfg := t.App.Config.CoverConfig
+	if cfg == nil || !cfg.Enabled {
+		t.App.Logger.Info("自动生成封面未启用，跳过")
+		return true
+	}
+
+	if _, err := os.Stat(t.StateManager.InputVideoPath); os.IsNotExist(err) {
+		t.App.Logger.Error("❌ 源视频文件不存在，无法生成封面")
+		context["error"] = "源视频文件不存在"
+		return false
+	}
+
+	sizes, templates, err := coverLayouts(cfg.Sizes, cfg.Templates)
+	if err != nil {
+		t.App.Logger.Errorf("❌ 封面配置无效: %v", err)
+		context["error"] = fmt.Sprintf("封面配置无效: %v", err)
+		return false
+	}
+	candidates := cfg.Candidates
+	if candidates <= 0 {
+		candidates = 4
+	}
+
+	// 1. 采样候选帧（多取几倍，打分后去掉黑场和模糊的画面），重新生成时先清空上次的候选封面
+	os.RemoveAll(t.StateManager.CoversDir)
+	framesDir := filepath.Join(t.StateManager.CoversDir, "frames")
+	opts := utils.FrameSampleOptions{Count: candidates * 3, MaxWidth: 1920}
+	if sampler := t.App.Config.FrameSamplerConfig; sampler != nil {
+		opts.SceneThreshold = sampler.SceneThreshold
+	}
+	t.App.Logger.Infof("🖼️ 采样封面候选画面: %d 帧", opts.Count)
+	frames, err := utils.SampleFrames(t.StateManager.InputVideoPath, framesDir, opts)
+	if err != nil {
+		t.App.Logger.Errorf("❌ 采样候选画面失败: %v", err)
+		context["error"] = fmt.Sprintf("采样封面候选画面失败: %v", err)
+		return false
+	}
+
+	// 2. 打分排序
+	ranked, qualities := utils.RankFrames(frames)
+	if len(ranked) == 0 {
+		t.App.Logger.Warn("⚠️ 没有可用的候选画面（均为黑场/白场），使用视频缩略图作为封面")
+		return true
+	}
+	if len(ranked) > candidates {
+		ranked, qualities = ranked[:candidates], qualities[:candidates]
+	}
+
+	// 3. 叠加的标题
+	title := ""
+	if cfg.OverlayTitle {
+		title = t.generatedTitle(context)
+		if title == "" {
+			t.App.Logger.Info("没有AI生成的标题，封面不叠加文字")
+		}
+	}
+
+	// 4. 生成候选封面
+	index := &utils.CoverIndex{}
+	for i, frame := range ranked {
+		for _, template := range templates {
+			for _, size := range sizes {
+				name := fmt.Sprintf("cover_%d_%s_%s.jpg", i+1, template, strings.ReplaceAll(size.Name, ":", "x"))
+				if err := utils.RenderCover(frame.Path, filepath.Join(t.StateManager.CoversDir, name), size, template, title, cfg.FontFile, cfg.Font); err != nil {
+					t.App.Logger.Errorf("❌ 生成封面失败: %v", err)
+					context["error"] = fmt.Sprintf("生成封面失败: %v", err)
+					return false
+				}
+				index.Candidates = append(index.Candidates, utils.CoverCandidate{
+					Name:     name,
+					Frame:    frame.Timestamp,
+					Score:    qualities[i].Score,
+					Template: template,
+					Size:     size.Name,
+					Title:    title,
+				})
+			}
+		}
+	}
+	if err := utils.SaveCoverIndex(t.StateManager.CoversDir, index); err != nil {
+		t.App.Logger.Errorf("❌ 保存封面列表失败: %v", err)
+		context["error"] = fmt.Sprintf("保存封面列表失败: %v", err)
+		return false
+	}
+
+	// 5. 第一个候选（得分最高的画面、第一个模板和尺寸）作为默认封面
+	selected := index.Candidates[0].Name
+	if err := utils.SelectCover(t.StateManager.CoversDir, selected, t.StateManager.ImageCover); err != nil {
+		t.App.Logger.Errorf("❌ 设置默认封面失败: %v", err)
+		context["error"] = fmt.Sprintf("设置默认封面失败: %v", err)
+		return false
+	}
+	context["cover_image_path"] = t.StateManager.ImageCover
+
+	t.App.Logger.Infof("✓ 已生成 %d 个候选封面，默认使用: %s", len(index.Candidates), selected)
+	return true
+}
+
+// generatedTitle AI生成的标题：优先取本次任务链中生成元数据步骤的结果，其次读取数据库
+func (t *GenerateCover) generatedTitle(context map[string]interface{}) string {
+	if title, ok := context["video_title"].(string); ok && title != "" {
+		return title
+	}
+	if t.SavedVideoService == nil {
+		return ""
+	}
+	video, err := t.SavedVideoService.GetVideoByVideoID(t.StateManager.VideoID)
+	if err != nil {
+		return ""
+	}
+	return video.GeneratedTitle
+}
+
+// coverLayouts 校验配置中的封面尺寸和模板，未配置时使用 16:10 + bottom_bar
+func coverLayouts(sizeNames, templateNames []string) ([]utils.CoverSize, []string, error) {
+	if len(sizeNames) == 0 {
+		sizeNames = []string{"16:10"}
+	}
+	if len(templateNames) == 0 {
+		templateNames = []string{"bottom_bar"}
+	}
+
+	sizes := make([]utils.CoverSize, 0, len(sizeNames))
+	for _, name := range sizeNames {
+		size, ok := utils.CoverSizes[name]
+		if !ok {
+			return nil, nil, fmt.Errorf("不支持的封面比例: %s", name)
+		}
+		sizes = append(sizes, size)
+	}
+	for _, name := range templateNames {
+		if _, ok := utils.CoverTemplates[name]; !ok {
+			return nil, nil, fmt.Errorf("未知的封面模板: %s", name)
+		}
+	}
+	return sizes, templateNames, nil
+}
@@ -0,0 +1,367 @@
+package handlers
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/chain_task/base"
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+	"github.com/difyz9/ytb2bili/pkg/tts"
+	"github.com/difyz9/ytb2bili/pkg/utils"
+)
+
+// 单条字幕合成失败时的最大尝试次数
+const dubbingMaxAttempts = 3
+
+// 可用时长短于该值（毫秒）的字幕不配音
+const dubbingMinSlotMs = 200
+
+// 去掉字幕中的 HTML 和 ASS 标签
+var dubbingTagPattern = regexp.MustCompile(`<[^>]+>|\{[^}]*\}`)
+
+// GenerateDubbing 按主语言字幕逐条合成配音：语音长于字幕时加速（最多 max_tempo 倍，仍然过长时截断），
+// 按字幕时间拼接为配音音轨（TranslateMP3），再与压低音量的原声混音生成配音版视频（DubbedVideoPath）。
+// 启用字幕烧录时在烧录后的视频上配音；合成的语音按文本和音色缓存，修改字幕后重新执行只合成改动的条目
+type GenerateDubbing struct {
+	base.BaseTask
+	App               *core.AppServer
+	SavedVideoService *services.SavedVideoService
+}
+
+func NewGenerateDubbing(name string, app *core.AppServer, stateManager *manager.StateManager, client *cos.CosClient, savedVideoService *services.SavedVideoService) *GenerateDubbing {
+	return &GenerateDubbing{
+		BaseTask: base.BaseTask{
+			Name:         name,
+			StateManager: stateManager,
+			Client:       client,
+		},
+		App:               app,
+		SavedVideoService: savedVideoService,
+	}
+}
+
+// dubbingStats 配音音轨的拼接结果
+type dubbingStats struct {
+	Spoken    int // 配音的条目数
+	Stretched int // 加速的条目数
+	Truncated int // 加速后仍然过长被截断的条目数
+	Skipped   int // 可用时长过短未配音的条目数
+}
+
+func (t *GenerateDubbing) Execute(context map[string]interface{}) bool {
+	cfg := t.App.Config.DubbingConfig
+	if cfg == nil || !cfg.Enabled {
+		t.App.Logger.Info("配音未启用，跳过")
+		return true
+	}
+
+	// 1. 配音的视频：启用字幕烧录时使用烧录后的视频
+	sourcePath := t.StateManager.InputVideoPath
+	if burnIn := t.App.Config.BurnInConfig; burnIn != nil && burnIn.Enabled {
+		if _, err := os.Stat(t.StateManager.OutVideoPath); err == nil {
+			sourcePath = t.StateManager.OutVideoPath
+		}
+	}
+	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
+		t.App.Logger.Error("❌ 源视频文件不存在，无法生成配音")
+		context["error"] = "源视频文件不存在"
+		return false
+	}
+
+	// 2. 主语言字幕
+	video, err := t.SavedVideoService.GetVideoByVideoID(t.StateManager.VideoID)
+	if err != nil {
+		t.App.Logger.Errorf("❌ 获取视频记录失败: %v", err)
+		context["error"] = fmt.Sprintf("获取视频记录失败: %v", err)
+		return false
+	}
+	language := resolveTargetLanguages(t.App.Config, video)[0]
+	cfg = dubbingConfigFor(cfg, video.Settings())
+	srtPath := t.StateManager.TranslatedSRTPath(language)
+	if _, err := os.Stat(srtPath); os.IsNotExist(err) {
+		// 没有字幕（如无语音视频），上传时使用原视频
+		t.App.Logger.Warnf("⚠️ 主语言字幕不存在，跳过配音: %s", filepath.Base(srtPath))
+		os.Remove(t.StateManager.DubbedVideoPath)
+		return true
+	}
+	doc, err := subtitle.ReadFile(srtPath)
+	if err != nil {
+		t.App.Logger.Errorf("❌ 读取字幕失败: %v", err)
+		context["error"] = fmt.Sprintf("读取字幕失败: %v", err)
+		return false
+	}
+	doc.Sort()
+
+	provider, err := tts.NewProvider(cfg)
+	if err != nil {
+		t.App.Logger.Errorf("❌ %v", err)
+		context["error"] = err.Error()
+		return false
+	}
+	if err := os.MkdirAll(t.StateManager.DubbingDir, 0755); err != nil {
+		t.App.Logger.Errorf("❌ 创建配音目录失败: %v", err)
+		context["error"] = fmt.Sprintf("创建配音目录失败: %v", err)
+		return false
+	}
+
+	// 3. 逐条合成语音
+	t.App.Logger.Infof("🎙️ 开始合成配音: 提供商=%s, 语言=%s, 音色=%s, %d 条字幕", provider.Name(), language, dubbingVoiceLabel(cfg), doc.Len())
+	startTime := time.Now()
+	clips, err := t.synthesizeCues(provider, cfg, language, doc)
+	if err != nil {
+		t.App.Logger.Errorf("❌ 合成配音失败: %v", err)
+		context["error"] = fmt.Sprintf("合成配音失败: %v", err)
+		return false
+	}
+
+	// 4. 按字幕时间拼接配音音轨
+	durationMs := doc.Duration()
+	if duration, err := utils.GetMediaDuration(sourcePath); err == nil && duration > 0 {
+		durationMs = int64(duration * 1000)
+	}
+	stats, err := t.buildVoiceTrack(cfg, doc, clips, durationMs)
+	if err != nil {
+		t.App.Logger.Errorf("❌ 生成配音音轨失败: %v", err)
+		context["error"] = fmt.Sprintf("生成配音音轨失败: %v", err)
+		return false
+	}
+	t.App.Logger.Infof("🔊 配音音轨已生成: 配音 %d 条，加速 %d 条，截断 %d 条，跳过 %d 条", stats.Spoken, stats.Stretched, stats.Truncated, stats.Skipped)
+
+	// 5. 与原声混音，先输出到临时文件，避免上传未完成的视频
+	partPath := t.StateManager.DubbedVideoPath + ".part"
+	err = utils.MixDubbing(sourcePath, t.StateManager.TranslateMP3, partPath, utils.DubbingMixOptions{
+		OriginalVolume: cfg.OriginalVolume,
+		DuckRatio:      cfg.DuckRatio,
+		VoiceVolume:    cfg.VoiceVolume,
+		AudioBitrate:   cfg.AudioBitrate,
+	})
+	if err != nil {
+		os.Remove(partPath)
+		t.App.Logger.Errorf("❌ %v", err)
+		context["error"] = "配音混音失败，请检查 ffmpeg 是否支持 sidechaincompress 滤镜和 aac 编码器"
+		return false
+	}
+	if err := os.Rename(partPath, t.StateManager.DubbedVideoPath); err != nil {
+		os.Remove(partPath)
+		t.App.Logger.Errorf("❌ 保存配音版视频失败: %v", err)
+		context["error"] = fmt.Sprintf("保存配音版视频失败: %v", err)
+		return false
+	}
+
+	t.App.Logger.Infof("✅ 配音完成，耗时 %v: %s", time.Since(startTime).Round(time.Second), t.StateManager.DubbedVideoPath)
+	context["dubbed_video_path"] = t.StateManager.DubbedVideoPath
+	return true
+}
+
+// synthesizeCues 并发合成每条字幕的语音，返回与字幕一一对应的音频文件路径（空文本为空字符串）。
+// 音频按提供商、音色、语速和文本缓存在配音目录中
+func (t *GenerateDubbing) synthesizeCues(provider tts.Provider, cfg *types.DubbingConfig, language string, doc *subtitle.Document) ([]string, error) {
+	clips := make([]string, doc.Len())
+	errs := make([]error, doc.Len())
+
+	workers := cfg.Concurrency
+	if workers <= 0 {
+		workers = 1
+	}
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				clips[i], errs[i] = t.synthesizeCue(provider, cfg, language, doc.Cues[i].Text)
+			}
+		}()
+	}
+
+	for i := range doc.Cues {
+		indexes <- i
+	}
+	close(indexes)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("第 %d 条字幕: %v", i+1, err)
+		}
+	}
+	return clips, nil
+}
+
+// synthesizeCue 合成一条字幕的语音，已缓存时直接返回缓存文件
+func (t *GenerateDubbing) synthesizeCue(provider tts.Provider, cfg *types.DubbingConfig, language, text string) (string, error) {
+	text = strings.Join(strings.Fields(dubbingTagPattern.ReplaceAllString(text, " ")), " ")
+	if text == "" {
+		return "", nil
+	}
+
+	sum := sha1.Sum([]byte(strings.Join([]string{provider.Name(), cfg.Model, cfg.Tier, cfg.VoiceName, cfg.Gender, fmt.Sprintf("%.2f", cfg.VoiceSpeed), language, text}, "|")))
+	key := hex.EncodeToString(sum[:])[:16]
+	if cached, _ := filepath.Glob(filepath.Join(t.StateManager.DubbingDir, key+".*")); len(cached) > 0 {
+		return cached[0], nil
+	}
+
+	req := &tts.Request{
+		Text:     text,
+		Language: language,
+		Voice:    cfg.VoiceName,
+		Gender:   strings.ToLower(cfg.Gender),
+		Speed:    cfg.VoiceSpeed,
+	}
+	var lastErr error
+	for attempt := 1; attempt <= dubbingMaxAttempts; attempt++ {
+		audio, err := provider.Synthesize(context.Background(), req)
+		if err == nil {
+			// 先写临时文件，避免中断后留下不完整的缓存
+			path := filepath.Join(t.StateManager.DubbingDir, key+"."+audio.Format)
+			tmpPath := filepath.Join(t.StateManager.DubbingDir, "."+key+".tmp")
+			if err := os.WriteFile(tmpPath, audio.Data, 0644); err != nil {
+				return "", err
+			}
+			if err := os.Rename(tmpPath, path); err != nil {
+				os.Remove(tmpPath)
+				return "", err
+			}
+			return path, nil
+		}
+		lastErr = err
+		t.App.Logger.Warnf("⚠️ 合成语音失败（第 %d/%d 次）: %v", attempt, dubbingMaxAttempts, err)
+		if attempt < dubbingMaxAttempts {
+			time.Sleep(time.Duration(attempt) * 2 * time.Second)
+		}
+	}
+	return "", lastErr
+}
+
+// buildVoiceTrack 按字幕开始时间拼接配音音轨并编码为 TranslateMP3。每条语音最多占用到下一条字幕开始
+// （最后一条到视频结束），超出时加速，加速到 max_tempo 仍然过长时截断
+func (t *GenerateDubbing) buildVoiceTrack(cfg *types.DubbingConfig, doc *subtitle.Document, clips []string, durationMs int64) (*dubbingStats, error) {
+	maxTempo := cfg.MaxTempo
+	if maxTempo < 1 {
+		maxTempo = 1.5
+	}
+
+	wavPath := filepath.Join(t.StateManager.DubbingDir, "voice.wav")
+	writer, err := utils.CreateWave(wavPath, utils.DubbingSampleRate)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(wavPath)
+
+	stats := &dubbingStats{}
+	for i, cue := range doc.Cues {
+		if clips[i] == "" {
+			continue
+		}
+
+		// 可用时长：从字幕开始（或上一条语音结束）到下一条字幕开始
+		position := writer.PositionMs()
+		start := cue.Start
+		if start < position {
+			start = position
+		}
+		limit := cue.End
+		if i+1 < len(doc.Cues) {
+			if next := doc.Cues[i+1].Start; next > limit {
+				limit = next
+			}
+		} else if durationMs > limit {
+			limit = durationMs
+		}
+		available := limit - start
+		if available < dubbingMinSlotMs {
+			stats.Skipped++
+			continue
+		}
+
+		clipSeconds, err := utils.GetMediaDuration(clips[i])
+		if err != nil {
+			writer.Close()
+			return nil, fmt.Errorf("第 %d 条字幕: %v", i+1, err)
+		}
+		clipMs := clipSeconds * 1000
+		tempo := 1.0
+		if clipMs > float64(available) {
+			tempo = clipMs / float64(available)
+			if tempo > maxTempo {
+				tempo = maxTempo
+				stats.Truncated++
+			}
+			stats.Stretched++
+		}
+
+		pcm, err := utils.FitAudioClip(clips[i], tempo, float64(available)/1000)
+		if err != nil {
+			writer.Close()
+			return nil, fmt.Errorf("第 %d 条字幕: %v", i+1, err)
+		}
+		if err := writer.WriteSilence(start - position); err != nil {
+			writer.Close()
+			return nil, err
+		}
+		if err := writer.Write(pcm); err != nil {
+			writer.Close()
+			return nil, err
+		}
+		stats.Spoken++
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	if stats.Spoken == 0 {
+		return nil, fmt.Errorf("没有可配音的字幕")
+	}
+
+	if err := utils.EncodeMP3(wavPath, t.StateManager.TranslateMP3); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
+
+// dubbingConfigFor 视频提交时设置的音色（性别、音质档位、音色名称、语速）覆盖全局配音配置
+func dubbingConfigFor(cfg *types.DubbingConfig, settings model.TranslationSettings) *types.DubbingConfig {
+	merged := *cfg
+	if settings.Gender != "" {
+		merged.Gender = settings.Gender
+		// 只指定了性别时按性别选择默认音色，不再使用全局配置的音色名称
+		if settings.VoiceName == "" && !strings.EqualFold(settings.Gender, cfg.Gender) {
+			merged.VoiceName = ""
+		}
+	}
+	if settings.Tier != "" {
+		merged.Tier = settings.Tier
+	}
+	if settings.VoiceName != "" {
+		merged.VoiceName = settings.VoiceName
+	}
+	if settings.VoiceSpeed > 0 {
+		merged.VoiceSpeed = settings.VoiceSpeed
+	}
+	return &merged
+}
+
+// dubbingVoiceLabel 日志中显示的音色
+func dubbingVoiceLabel(cfg *types.DubbingConfig) string {
+	if cfg.VoiceName != "" {
+		return cfg.VoiceName
+	}
+	if cfg.Gender != "" {
+		return "默认" + cfg.Gender
+	}
+	return "默认"
+}
@@ -14,18 +14,21 @@ import (
 	"github.com/difyz9/ytb2bili/internal/core"
 	"github.com/difyz9/ytb2bili/internal/core/services"
 	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/prompt"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+	"github.com/difyz9/ytb2bili/pkg/translator"
 	"gorm.io/gorm"
 )
 
 type GenerateMetadata struct {
 	base.BaseTask
 	App               *core.AppServer
-	DeepSeekClient    *DeepSeekClient
-	GeminiClient      *GeminiClient
+	AIService         *services.AIServiceManager // 按首选服务和故障转移顺序调用对话接口
 	SavedVideoService *services.SavedVideoService
 }
 
-func NewGenerateMetadata(name string, app *core.AppServer, stateManager *manager.StateManager, client *cos.CosClient, apiKey string, db *gorm.DB, savedVideoService *services.SavedVideoService) *GenerateMetadata {
+func NewGenerateMetadata(name string, app *core.AppServer, stateManager *manager.StateManager, client *cos.CosClient, db *gorm.DB, savedVideoService *services.SavedVideoService, aiService *services.AIServiceManager) *GenerateMetadata {
 	return &GenerateMetadata{
 		BaseTask: base.BaseTask{
 			Name:         name,
@@ -33,29 +36,48 @@ func NewGenerateMetadata(name string, app *core.AppServer, stateManager *manager
 			Client:       client,
 		},
 		App:               app,
-		DeepSeekClient:    nil, // 不再固化客户端，运行时动态创建
+		AIService:         aiService,
 		SavedVideoService: savedVideoService,
 	}
 }
 
-// getCurrentDeepSeekClient 获取当前的DeepSeek客户端（使用最新配置）
-func (g *GenerateMetadata) getCurrentDeepSeekClient() (*DeepSeekClient, error) {
-	if g.App.Config.DeepSeekTransConfig == nil || !g.App.Config.DeepSeekTransConfig.Enabled {
-		return nil, fmt.Errorf("DeepSeek 翻译服务未启用")
+// loadVideo 查询当前视频记录，查询失败时返回 nil
+func (g *GenerateMetadata) loadVideo() *model.SavedVideo {
+	if g.SavedVideoService == nil {
+		return nil
 	}
-
-	apiKey := g.App.Config.DeepSeekTransConfig.ApiKey
-	if apiKey == "" {
-		return nil, fmt.Errorf("DeepSeek API Key 未配置")
+	video, err := g.SavedVideoService.GetVideoByVideoID(g.StateManager.VideoID)
+	if err != nil {
+		return nil
 	}
+	return video
+}
+
+// promptData 构建元数据提示词的模板变量
+func (g *GenerateMetadata) promptData(subtitleText string) *prompt.Data {
+	data := services.PromptDataForVideo(g.loadVideo())
+	data.SubtitleText = subtitleText
+	return data
+}
 
-	return NewDeepSeekClient(apiKey), nil
+// primarySubtitlePath 返回主语言（第一个目标语言）的译文字幕，不存在时回退到 zh.srt
+func (g *GenerateMetadata) primarySubtitlePath() string {
+	language := resolveTargetLanguages(g.App.Config, g.loadVideo())[0]
+	path := g.StateManager.TranslatedSRTPath(language)
+	if _, err := os.Stat(path); err != nil {
+		return g.StateManager.TranslateSRT
+	}
+	if language != subtitle.LanguageSimplifiedChinese {
+		g.App.Logger.Infof("🌍 使用主语言 %s 字幕生成元数据", language)
+	}
+	return path
 }
 
 type VideoMetadata struct {
-	Title       string   `json:"title"`
-	Description string   `json:"description"`
-	Tags        []string `json:"tags"`
+	Title         string   `json:"title"`
+	Description   string   `json:"description"`
+	Tags          []string `json:"tags"`
+	PromptVersion string   `json:"prompt_version,omitempty"` // 生成时使用的提示词模板版本
 }
 
 func (g *GenerateMetadata) Execute(context map[string]interface{}) bool {
@@ -63,66 +85,56 @@ func (g *GenerateMetadata) Execute(context map[string]interface{}) bool {
 	g.App.Logger.Infof("开始生成视频标题和描述: VideoID=%s", g.StateManager.VideoID)
 	g.App.Logger.Info("========================================")
 
-	// 0. 检查是否使用 Gemini
-	useGemini := false
-	if g.App.Config.GeminiConfig != nil && g.App.Config.GeminiConfig.Enabled && g.App.Config.GeminiConfig.UseForMetadata {
-		useGemini = true
-		g.App.Logger.Info("🤖 使用 Gemini 多模态服务生成元数据")
-
-		// 如果配置了视频分析，尝试使用视频文件
-		if g.App.Config.GeminiConfig.AnalyzeVideo {
+	// 1. Gemini 多模态分析视频画面（不经过对话接口），失败时回退
+	if cfg := g.App.Config.GeminiConfig; cfg != nil && cfg.Enabled && cfg.UseForMetadata && cfg.AnalyzeVideo {
+		if cfg.VideoSampleFrames > 0 {
+			g.App.Logger.Infof("🤖 使用 Gemini 分析 %d 个关键帧生成元数据", cfg.VideoSampleFrames)
+			if success := g.executeWithGeminiFrames(context, cfg.VideoSampleFrames); success {
+				return true
+			}
+			g.App.Logger.Warn("⚠️ Gemini 关键帧分析失败，尝试其他方式")
+		} else {
+			g.App.Logger.Info("🤖 使用 Gemini 多模态服务生成元数据")
 			if success := g.executeWithGeminiVideo(context); success {
 				return true
 			}
-			g.App.Logger.Warn("⚠️ Gemini 视频分析失败，回退到文本模式")
+			g.App.Logger.Warn("⚠️ Gemini 视频分析失败，尝试其他方式")
 		}
+	}
 
-		// 使用 Gemini 处理字幕文本
-		if success := g.executeWithGeminiText(context); success {
+	// 2. 支持图像输入的 OpenAI 兼容模型分析关键帧
+	if g.AIService != nil && g.AIService.VisionFrames() > 0 {
+		frames := g.AIService.VisionFrames()
+		g.App.Logger.Infof("🤖 使用 OpenAI 兼容视觉模型分析 %d 个关键帧生成元数据", frames)
+		if success := g.executeWithVisionFrames(context, frames); success {
 			return true
 		}
-		g.App.Logger.Warn("⚠️ Gemini 文本分析失败，回退到 DeepSeek")
-		useGemini = false
+		g.App.Logger.Warn("⚠️ 视觉模型分析失败，回退到字幕文本模式")
 	}
 
-	// 使用 DeepSeek（默认或回退）
-	if !useGemini {
-		return g.executeWithDeepSeek(context)
-	}
-
-	return false
+	// 3. 根据字幕文本生成，按首选 AI 服务和故障转移顺序调用
+	return g.executeWithChat(context)
 }
 
-// executeWithDeepSeek 使用 DeepSeek 生成元数据
-func (g *GenerateMetadata) executeWithDeepSeek(context map[string]interface{}) bool {
-	// 0. 动态获取最新的DeepSeek客户端
-	client, err := g.getCurrentDeepSeekClient()
-	if err != nil {
-		g.App.Logger.Errorf("❌ %v", err)
-		// 使用默认值而不是失败
-		context["video_title"] = g.StateManager.VideoID
-		context["video_description"] = "包含字幕的视频"
-		return true
+// executeWithChat 读取主语言字幕，通过 AIServiceManager 生成元数据
+func (g *GenerateMetadata) executeWithChat(context map[string]interface{}) bool {
+	if g.AIService == nil || len(g.AIService.EnabledProviders()) == 0 {
+		g.App.Logger.Errorf("❌ %v", services.ErrNoAIService)
+		context["error"] = services.ErrNoAIService.Error()
+		return false
 	}
 
-	g.App.Logger.Infof("🔑 使用 DeepSeek 配置生成元数据")
-	// 更新当前使用的客户端
-	g.DeepSeekClient = client
-
-	// 1. 检查中文字幕文件是否存在
-	zhSRTPath := filepath.Join(g.StateManager.CurrentDir, "zh.srt")
+	// 1. 检查主语言字幕文件是否存在（无语音的视频没有字幕，上传时使用原标题）
+	zhSRTPath := g.primarySubtitlePath()
 	if _, err := os.Stat(zhSRTPath); os.IsNotExist(err) {
-		g.App.Logger.Warn("⚠️  中文字幕文件不存在，使用默认标题和描述")
-		// 使用默认值
-		context["video_title"] = g.StateManager.VideoID
-		context["video_description"] = fmt.Sprintf("包含字幕的视频")
-		return true // 没有字幕文件不算失败
+		g.App.Logger.Warn("⚠️  译文字幕文件不存在，跳过生成，上传时将使用原视频标题和描述")
+		return true
 	}
 
-	// 2. 读取中文字幕内容
+	// 2. 读取字幕内容
 	srtContent, err := os.ReadFile(zhSRTPath)
 	if err != nil {
-		g.App.Logger.Errorf("❌ 读取中文字幕文件失败: %v", err)
+		g.App.Logger.Errorf("❌ 读取译文字幕文件失败: %v", err)
 		context["error"] = "读取翻译字幕失败，请确保字幕翻译步骤已完成"
 		return false
 	}
@@ -130,9 +142,7 @@ func (g *GenerateMetadata) executeWithDeepSeek(context map[string]interface{}) b
 	// 3. 解析字幕提取文本
 	subtitleText := g.extractTextFromSRT(string(srtContent))
 	if subtitleText == "" {
-		g.App.Logger.Warn("⚠️  字幕内容为空，使用默认标题和描述")
-		context["video_title"] = g.StateManager.VideoID
-		context["video_description"] = fmt.Sprintf("包含字幕的视频")
+		g.App.Logger.Warn("⚠️  字幕内容为空，跳过生成，上传时将使用原视频标题和描述")
 		return true
 	}
 
@@ -144,156 +154,51 @@ func (g *GenerateMetadata) executeWithDeepSeek(context map[string]interface{}) b
 		subtitleText = subtitleText[:maxLength] + "..."
 	}
 
-	// 5. 调用 DeepSeek API 生成标题和描述
-	g.App.Logger.Info("🤖 调用 DeepSeek API 生成标题和描述...")
-	metadata, err := g.generateMetadataFromDeepSeek(subtitleText)
+	// 5. 调用 AI 服务生成标题和描述
+	metadata, err := g.generateMetadataWithChat(subtitleText)
 	if err != nil {
 		g.App.Logger.Errorf("❌ 生成标题和描述失败: %v", err)
-		g.App.Logger.Warn("⚠️  将使用默认标题和描述，不影响视频上传")
-		// 使用默认值
-		context["video_title"] = g.StateManager.VideoID
-		context["video_description"] = fmt.Sprintf("包含字幕的视频")
-		return true // API调用失败不算整个任务失败
-	}
-
-	// 6. 验证标题长度（Bilibili限制80字符）
-	if len([]rune(metadata.Title)) > 80 {
-		runes := []rune(metadata.Title)
-		metadata.Title = string(runes[:77]) + "..."
-		g.App.Logger.Warnf("⚠️  标题过长，已截断为80字符")
-	}
-
-	// 7. 保存到 context
-	context["video_title"] = metadata.Title
-	context["video_description"] = metadata.Description
-	context["video_tags"] = metadata.Tags
-
-	// 8. 保存到 meta.json 文件
-	g.App.Logger.Info("💾 保存元数据到 meta.json 文件...")
-	if err := g.saveMetadataToFile(metadata); err != nil {
-		g.App.Logger.Errorf("❌ 保存 meta.json 文件失败: %v", err)
-		// 不影响任务继续执行
-	} else {
-		g.App.Logger.Info("✅ meta.json 文件已保存")
-	}
-
-	// 9. 保存到数据库
-	g.App.Logger.Info("💾 保存生成的元数据到数据库...")
-	savedVideo, err := g.SavedVideoService.GetVideoByVideoID(g.StateManager.VideoID)
-	if err != nil {
-		g.App.Logger.Errorf("❌ 获取视频记录失败: %v", err)
-		// 不影响任务继续执行
-	} else {
-		// 更新生成的元数据
-		savedVideo.GeneratedTitle = metadata.Title
-		savedVideo.GeneratedDesc = metadata.Description
-		savedVideo.GeneratedTags = strings.Join(metadata.Tags, ",")
-
-		if err := g.SavedVideoService.UpdateVideo(savedVideo); err != nil {
-			g.App.Logger.Errorf("❌ 保存元数据到数据库失败: %v", err)
-		} else {
-			g.App.Logger.Info("✅ 元数据已保存到数据库")
-		}
+		context["error"] = fmt.Sprintf("生成视频元数据失败: %v", err)
+		markBudgetPaused(context, err)
+		return false
 	}
 
-	// 10. 输出生成结果
-	g.App.Logger.Info("========================================")
-	g.App.Logger.Info("✅ 视频元数据生成成功！")
-	g.App.Logger.Infof("📌 标题: %s", metadata.Title)
-	g.App.Logger.Infof("📝 描述: %s", g.truncateString(metadata.Description, 100))
-	g.App.Logger.Infof("🏷️  标签: %v", metadata.Tags)
-	g.App.Logger.Info("========================================")
-
-	return true
+	// 6. 保存结果
+	return g.saveMetadataResults(metadata, context)
 }
 
 // extractTextFromSRT 从SRT内容中提取纯文本
 func (g *GenerateMetadata) extractTextFromSRT(srtContent string) string {
-	lines := strings.Split(srtContent, "\n")
-	var textLines []string
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		// 跳过空行、序号行、时间码行
-		if line == "" || isNumber(line) || strings.Contains(line, "-->") {
-			continue
-		}
-		textLines = append(textLines, line)
-	}
-
-	return strings.Join(textLines, " ")
-}
-
-// isNumber 检查字符串是否为数字
-func isNumber(s string) bool {
-	for _, c := range s {
-		if c < '0' || c > '9' {
-			return false
-		}
+	doc, err := subtitle.Parse([]byte(srtContent), subtitle.FormatSRT)
+	if err != nil {
+		g.App.Logger.Warnf("⚠️ 解析字幕失败: %v", err)
+		return ""
 	}
-	return len(s) > 0
-}
-
-// generateMetadataFromDeepSeek 调用 DeepSeek API 生成标题和描述
-func (g *GenerateMetadata) generateMetadataFromDeepSeek(subtitleText string) (*VideoMetadata, error) {
-	prompt := fmt.Sprintf(`请根据以下视频字幕内容，生成一个吸引人的视频标题、详细描述和3-5个相关标签。
-
-字幕内容：
-%s
-
-要求：
-1. 标题要简洁有力，严格控制在30个字以内（B站限制80字，但建议30字以内更易读），能够准确概括视频主题，吸引观众点击
-2. 描述要详细但不要过长，严格控制在600-800字以内，包含视频的主要内容和亮点（注意：B站简介限制2000字，需要预留约200字给原视频链接和分隔线）
-3. 标签要准确反映视频内容，3-5个即可
-4. 必须使用中文
-5. 输出格式必须是JSON，格式如下：
-{
-  "title": "视频标题",
-  "description": "视频描述",
-  "tags": ["标签1", "标签2", "标签3"]
+	return doc.PlainText(" ")
 }
 
-请直接返回JSON格式的结果，不要包含任何其他说明文字。`, subtitleText)
-
-	// 使用 DeepSeekClient 调用 API
-	content, usage, err := g.DeepSeekClient.ChatCompletionWithUsage("你是一个专业的视频内容分析助手，擅长根据视频字幕生成吸引人的标题和描述。", prompt)
+// generateMetadataWithChat 渲染元数据提示词并通过 AIServiceManager 生成标题、描述和标签
+func (g *GenerateMetadata) generateMetadataWithChat(subtitleText string) (*VideoMetadata, error) {
+	rendered, err := prompt.Render(prompt.Metadata, g.promptData(subtitleText))
 	if err != nil {
-		return nil, fmt.Errorf("调用 DeepSeek API 失败: %v", err)
+		return nil, err
 	}
 
-	g.App.Logger.Debugf("DeepSeek 原始返回: %s", content)
-
-	// 提取JSON部分（可能包含在代码块中）
-	content = strings.TrimSpace(content)
-	if strings.HasPrefix(content, "```json") {
-		content = strings.TrimPrefix(content, "```json")
-		content = strings.TrimSuffix(content, "```")
-	} else if strings.HasPrefix(content, "```") {
-		content = strings.TrimPrefix(content, "```")
-		content = strings.TrimSuffix(content, "```")
-	}
-	content = strings.TrimSpace(content)
-
-	// 解析JSON
-	var metadata VideoMetadata
-	if err := json.Unmarshal([]byte(content), &metadata); err != nil {
-		return nil, fmt.Errorf("解析元数据JSON失败: %v, 内容: %s", err, content)
+	ctx := translator.WithUsageScope(context.Background(), g.StateManager.VideoID, g.Name)
+	content, provider, err := g.AIService.ChatCompletionContext(ctx, rendered.System, rendered.User)
+	if err != nil {
+		return nil, err
 	}
 
-	// 验证必填字段
-	if metadata.Title == "" {
-		return nil, fmt.Errorf("生成的标题为空")
-	}
+	g.App.Logger.Debugf("%s 原始返回: %s", provider, content)
 
-	// Token使用情况
-	if usage != nil {
-		g.App.Logger.Infof("💰 Token使用: 输入=%d, 输出=%d, 总计=%d",
-			usage.PromptTokens,
-			usage.CompletionTokens,
-			usage.TotalTokens)
+	metadata, err := parseMetadataJSON(content)
+	if err != nil {
+		return nil, err
 	}
-
-	return &metadata, nil
+	metadata.PromptVersion = rendered.Version
+	g.App.Logger.Infof("🤖 元数据由 %s 生成", provider)
+	return metadata, nil
 }
 
 // saveMetadataToFile 保存元数据到 meta.json 文件
@@ -309,6 +214,9 @@ func (g *GenerateMetadata) saveMetadataToFile(metadata *VideoMetadata) error {
 		"tags":         metadata.Tags,
 		"generated_at": time.Now().Format("2006-01-02 15:04:05"),
 	}
+	if metadata.PromptVersion != "" {
+		fileMetadata["prompt_version"] = metadata.PromptVersion
+	}
 
 	// 转换为格式化的JSON
 	jsonData, err := json.MarshalIndent(fileMetadata, "", "  ")
@@ -322,6 +230,10 @@ func (g *GenerateMetadata) saveMetadataToFile(metadata *VideoMetadata) error {
 	}
 
 	g.App.Logger.Infof("📁 meta.json 文件已保存: %s", metaFilePath)
+
+	if err := recordPromptVersions(g.StateManager, filepath.Base(metaFilePath), []string{metadata.PromptVersion}); err != nil {
+		g.App.Logger.Warnf("⚠️ 记录提示词版本失败: %v", err)
+	}
 	return nil
 }
 
@@ -361,7 +273,7 @@ func (g *GenerateMetadata) executeWithGeminiVideo(taskContext map[string]interfa
 	g.App.Logger.Infof("📹 找到视频文件: %s", filepath.Base(videoPath))
 
 	// 3. 上传视频到 Gemini
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(g.App.Config.GeminiConfig.Timeout)*time.Second)
+	ctx, cancel := context.WithTimeout(translator.WithUsageScope(context.Background(), g.StateManager.VideoID, g.Name), time.Duration(g.App.Config.GeminiConfig.Timeout)*time.Second)
 	defer cancel()
 
 	g.App.Logger.Info("⏫ 上传视频到 Gemini...")
@@ -382,7 +294,7 @@ func (g *GenerateMetadata) executeWithGeminiVideo(taskContext map[string]interfa
 
 	// 5. 生成元数据
 	g.App.Logger.Info("🤖 调用 Gemini 生成元数据...")
-	metadata, err := client.GenerateMetadataFromVideo(ctx, uploadedFile)
+	metadata, err := client.GenerateMetadataFromVideo(ctx, uploadedFile, g.promptData(""))
 	if err != nil {
 		g.App.Logger.Errorf("❌ 生成元数据失败: %v", err)
 		return false
@@ -392,67 +304,88 @@ func (g *GenerateMetadata) executeWithGeminiVideo(taskContext map[string]interfa
 	return g.saveMetadataResults(metadata, taskContext)
 }
 
-// executeWithGeminiText 使用 Gemini 分析字幕文本生成元数据
-func (g *GenerateMetadata) executeWithGeminiText(taskContext map[string]interface{}) bool {
-	g.App.Logger.Info("📝 使用 Gemini 分析字幕文本...")
-
-	// 1. 检查中文字幕文件
-	zhSRTPath := filepath.Join(g.StateManager.CurrentDir, "zh.srt")
-	if _, err := os.Stat(zhSRTPath); os.IsNotExist(err) {
-		g.App.Logger.Warn("⚠️ 中文字幕文件不存在")
+// executeWithGeminiFrames 采样关键帧，连同字幕摘录发送给 Gemini 生成元数据
+func (g *GenerateMetadata) executeWithGeminiFrames(taskContext map[string]interface{}, count int) bool {
+	frames, err := g.sampleFrames(count)
+	if err != nil {
+		g.App.Logger.Errorf("❌ 关键帧采样失败: %v", err)
 		return false
 	}
 
-	// 2. 读取字幕内容
-	srtContent, err := os.ReadFile(zhSRTPath)
+	cfg := g.App.Config.GeminiConfig
+	client, err := NewGeminiClient(cfg.ApiKey, cfg.Model, cfg.Timeout, cfg.MaxTokens)
 	if err != nil {
-		g.App.Logger.Errorf("❌ 读取字幕文件失败: %v", err)
+		g.App.Logger.Errorf("❌ 创建 Gemini 客户端失败: %v", err)
 		return false
 	}
+	defer client.Close()
 
-	// 3. 提取文本
-	subtitleText := g.extractTextFromSRT(string(srtContent))
-	if subtitleText == "" {
-		g.App.Logger.Warn("⚠️ 字幕内容为空")
+	ctx, cancel := context.WithTimeout(translator.WithUsageScope(context.Background(), g.StateManager.VideoID, g.Name), time.Duration(cfg.Timeout)*time.Second)
+	defer cancel()
+
+	metadata, err := client.GenerateMetadataFromFrames(ctx, frames.Images, g.promptData(frames.Excerpts))
+	if err != nil {
+		g.App.Logger.Errorf("❌ 生成元数据失败: %v", err)
 		return false
 	}
+	return g.saveMetadataResults(metadata, taskContext)
+}
 
-	g.App.Logger.Infof("📝 提取到字幕文本，总长度: %d 字符", len(subtitleText))
-
-	// 4. 截取文本（避免token过多）
-	maxLength := 2000
-	if len(subtitleText) > maxLength {
-		subtitleText = subtitleText[:maxLength] + "..."
+// executeWithVisionFrames 采样关键帧，连同字幕摘录发送给 OpenAI 兼容的视觉模型生成元数据
+func (g *GenerateMetadata) executeWithVisionFrames(taskContext map[string]interface{}, count int) bool {
+	frames, err := g.sampleFrames(count)
+	if err != nil {
+		g.App.Logger.Errorf("❌ 关键帧采样失败: %v", err)
+		return false
 	}
 
-	// 5. 创建 Gemini 客户端
-	client, err := NewGeminiClient(
-		g.App.Config.GeminiConfig.ApiKey,
-		g.App.Config.GeminiConfig.Model,
-		g.App.Config.GeminiConfig.Timeout,
-		g.App.Config.GeminiConfig.MaxTokens,
-	)
+	rendered, err := prompt.Render(prompt.MetadataFrames, g.promptData(frames.Excerpts))
 	if err != nil {
-		g.App.Logger.Errorf("❌ 创建 Gemini 客户端失败: %v", err)
+		g.App.Logger.Errorf("❌ 渲染提示词失败: %v", err)
 		return false
 	}
-	defer client.Close()
 
-	// 6. 生成元数据
-	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(g.App.Config.GeminiConfig.Timeout)*time.Second)
-	defer cancel()
-
-	g.App.Logger.Info("🤖 调用 Gemini 生成元数据...")
-	metadata, err := client.GenerateMetadataFromText(ctx, subtitleText)
+	ctx := translator.WithUsageScope(context.Background(), g.StateManager.VideoID, g.Name)
+	content, err := g.AIService.VisionCompletionContext(ctx, rendered.System, rendered.User, frames.Images)
 	if err != nil {
 		g.App.Logger.Errorf("❌ 生成元数据失败: %v", err)
 		return false
 	}
 
-	// 7. 保存结果
+	metadata, err := parseMetadataJSON(content)
+	if err != nil {
+		g.App.Logger.Errorf("❌ 解析元数据失败: %v", err)
+		return false
+	}
+	metadata.PromptVersion = rendered.Version
 	return g.saveMetadataResults(metadata, taskContext)
 }
 
+// sampleFrames 从原视频采样关键帧，并摘录主语言字幕（没有字幕时只发送画面）
+func (g *GenerateMetadata) sampleFrames(count int) (*VideoFrames, error) {
+	videoPath := g.StateManager.InputVideoPath
+	if _, err := os.Stat(videoPath); err != nil {
+		videoFiles := g.findVideoFiles()
+		if len(videoFiles) == 0 {
+			return nil, fmt.Errorf("未找到视频文件")
+		}
+		videoPath = videoFiles[0]
+	}
+
+	subtitlePath := g.primarySubtitlePath()
+	if _, err := os.Stat(subtitlePath); err != nil {
+		subtitlePath = ""
+	}
+
+	g.App.Logger.Infof("🎞️ 从 %s 采样 %d 个关键帧...", filepath.Base(videoPath), count)
+	frames, err := sampleVideoFrames(g.StateManager, g.App.Config.FrameSamplerConfig, videoPath, subtitlePath, count)
+	if err != nil {
+		return nil, err
+	}
+	g.App.Logger.Infof("✓ 采样完成: %d 帧", len(frames.Images))
+	return frames, nil
+}
+
 // saveMetadataResults 保存元数据结果到context和数据库
 func (g *GenerateMetadata) saveMetadataResults(metadata *VideoMetadata, taskContext map[string]interface{}) bool {
 	// 1. 验证标题长度
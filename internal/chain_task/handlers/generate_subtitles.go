@@ -7,12 +7,12 @@ import (
 	"github.com/difyz9/ytb2bili/internal/core/services"
 	"github.com/difyz9/ytb2bili/pkg/cos"
 	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
 	"github.com/difyz9/ytb2bili/pkg/utils"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 )
 
 type GenerateSubtitles struct {
@@ -33,35 +33,17 @@ func NewGenerateSubtitles(name string, app *core.AppServer, stateManager *manage
 	}
 }
 
-// formatTime 将秒数转换为 SRT 时间格式 (HH:MM:SS,mmm)
-func (t *GenerateSubtitles) formatTime(seconds float64) string {
-	hours := int(seconds / 3600)
-	minutes := int((seconds - float64(hours*3600)) / 60)
-	secs := int(seconds - float64(hours*3600) - float64(minutes*60))
-	milliseconds := int((seconds - float64(int(seconds))) * 1000)
-
-	return fmt.Sprintf("%02d:%02d:%02d,%03d", hours, minutes, secs, milliseconds)
-}
-
-// generateSRT 生成 SRT 格式字幕内容
-func (t *GenerateSubtitles) generateSRT(subtitles []model.SavedVideoSubtitle) string {
-	var srtContent strings.Builder
-
-	for i, subtitle := range subtitles {
-		// SRT 序号（从1开始）
-		srtContent.WriteString(fmt.Sprintf("%d\n", i+1))
-
-		// 时间轴
-		startTime := t.formatTime(subtitle.Offset)
-		endTime := t.formatTime(subtitle.Offset + subtitle.Duration)
-		srtContent.WriteString(fmt.Sprintf("%s --> %s\n", startTime, endTime))
-
-		// 字幕文本
-		srtContent.WriteString(subtitle.Text)
-		srtContent.WriteString("\n\n")
+// buildSubtitleDocument 将用户提交的字幕条目转换为统一字幕模型
+func (t *GenerateSubtitles) buildSubtitleDocument(subtitles []model.SavedVideoSubtitle) *subtitle.Document {
+	doc := subtitle.NewDocument("")
+	for _, item := range subtitles {
+		if doc.Language == "" {
+			doc.Language = item.Lang
+		}
+		start := subtitle.SecondsToMS(item.Offset)
+		doc.Add(start, start+subtitle.SecondsToMS(item.Duration), item.Text)
 	}
-
-	return srtContent.String()
+	return doc
 }
 
 func (t *GenerateSubtitles) Execute(context map[string]interface{}) bool {
@@ -105,8 +87,8 @@ func (t *GenerateSubtitles) Execute(context map[string]interface{}) bool {
 
 	t.App.Logger.Infof("📝 找到 %d 条字幕", len(subtitles))
 
-	// 4. 生成 SRT 内容
-	srtContent := t.generateSRT(subtitles)
+	// 4. 转换为统一字幕模型
+	doc := t.buildSubtitleDocument(subtitles)
 
 	// 5. 确保输出目录存在
 	if err := os.MkdirAll(t.StateManager.CurrentDir, 0755); err != nil {
@@ -120,7 +102,7 @@ func (t *GenerateSubtitles) Execute(context map[string]interface{}) bool {
 	srtFilePath := filepath.Join(t.StateManager.CurrentDir, srtFileName)
 
 	// 7. 写入 SRT 文件
-	if err := os.WriteFile(srtFilePath, []byte(srtContent), 0644); err != nil {
+	if err := subtitle.WriteFile(srtFilePath, doc); err != nil {
 		t.App.Logger.Errorf("❌ 写入字幕文件失败: %v", err)
 		context["error"] = fmt.Sprintf("写入字幕文件失败: %v", err)
 		return false
@@ -2,12 +2,17 @@ package handlers
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"strings"
 	"time"
+
+	"github.com/difyz9/ytb2bili/pkg/ratelimit"
+	"github.com/difyz9/ytb2bili/pkg/translator"
 )
 
 // OpenAICompatibleClient OpenAI兼容API客户端
@@ -27,6 +32,10 @@ type OpenAICompatibleClient struct {
 	RetryDelay  time.Duration
 	Temperature float64
 	MaxTokens   int
+
+	// 用量归属（记录 token 用量和费用时使用，可为空）
+	VideoID string
+	Step    string
 }
 
 // OpenAIRequest OpenAI格式请求结构
@@ -144,6 +153,11 @@ func (c *OpenAICompatibleClient) ChatCompletion(systemPrompt, userPrompt string)
 
 		lastErr = err
 
+		// 熔断期间重试没有意义
+		if errors.Is(err, ratelimit.ErrCircuitOpen) {
+			return "", err
+		}
+
 		// 如果是API限制错误，延长等待时间
 		if strings.Contains(err.Error(), "rate limit") || strings.Contains(err.Error(), "429") {
 			time.Sleep(time.Duration(attempt+1) * 5 * time.Second)
@@ -199,6 +213,46 @@ func (c *OpenAICompatibleClient) doRequest(systemPrompt, userPrompt string) (str
 
 // doRequestWithMessages 执行带消息列表的请求
 func (c *OpenAICompatibleClient) doRequestWithMessages(messages []OpenAIMessage) (*OpenAIResponse, error) {
+	if err := translator.CheckBudget(c.VideoID); err != nil {
+		return nil, err
+	}
+
+	texts := make([]string, 0, len(messages))
+	for _, message := range messages {
+		texts = append(texts, message.Content)
+	}
+	permit, err := ratelimit.Acquire(context.Background(), "openai", ratelimit.EstimateTokens(texts...))
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	response, err := c.post(messages)
+	permit.Done(err)
+
+	record := &translator.UsageRecord{
+		VideoID:  c.VideoID,
+		Step:     c.Step,
+		Provider: "openai",
+		Model:    c.Model,
+		Kind:     translator.UsageKindChat,
+		Latency:  time.Since(start),
+		Success:  err == nil,
+	}
+	if response != nil {
+		record.InputTokens = response.Usage.PromptTokens
+		record.OutputTokens = response.Usage.CompletionTokens
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	translator.RecordUsage(context.Background(), record)
+
+	return response, err
+}
+
+// post 执行 HTTP 请求并解析响应
+func (c *OpenAICompatibleClient) post(messages []OpenAIMessage) (*OpenAIResponse, error) {
 	request := OpenAIRequest{
 		Model:       c.Model,
 		Messages:    messages,
@@ -245,11 +299,11 @@ func (c *OpenAICompatibleClient) doRequestWithMessages(messages []OpenAIMessage)
 
 	// 检查API错误
 	if response.Error != nil {
-		return nil, fmt.Errorf("API错误 [%s]: %s", response.Error.Type, response.Error.Message)
+		return nil, ratelimit.WithStatus(resp.StatusCode, fmt.Errorf("API错误 [%s]: %s", response.Error.Type, response.Error.Message))
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API返回错误 (状态码: %d): %s", resp.StatusCode, string(body))
+		return nil, ratelimit.WithStatus(resp.StatusCode, fmt.Errorf("API返回错误 (状态码: %d): %s", resp.StatusCode, string(body)))
 	}
 
 	return &response, nil
@@ -0,0 +1,36 @@
+package handlers
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
+)
+
+// PromptVersionRecord 某个产物生成时使用的提示词模板版本
+type PromptVersionRecord struct {
+	Versions    []string  `json:"versions"` // 如 translation@v2、subtitle_fix@builtin；为空表示未使用大模型提示词
+	GeneratedAt time.Time `json:"generated_at"`
+}
+
+// recordPromptVersions 在 prompt_versions.json 中记录产物（按文件名）使用的提示词模板版本，
+// 产物重新生成时覆盖该产物的旧记录
+func recordPromptVersions(stateManager *manager.StateManager, artifact string, versions []string) error {
+	records := map[string]PromptVersionRecord{}
+	if data, err := os.ReadFile(stateManager.PromptVersions); err == nil {
+		// 文件损坏时重新生成
+		_ = json.Unmarshal(data, &records)
+	}
+
+	if versions == nil {
+		versions = []string{}
+	}
+	records[artifact] = PromptVersionRecord{Versions: versions, GeneratedAt: time.Now()}
+
+	data, err := json.MarshalIndent(records, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(stateManager.PromptVersions, data, 0644)
+}
@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	stdcontext "context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/chain_task/base"
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+	"github.com/difyz9/ytb2bili/pkg/translator"
+	"github.com/difyz9/ytb2bili/pkg/utils"
+)
+
+// punctuationSystemPrompt 标点恢复提示词
+const punctuationSystemPrompt = `你是语音转录文本的标点恢复助手。
+请为用户给出的英文（或其他语言）转录文本添加标点符号并修正句首大小写。
+严格要求：
+1. 不要增加、删除、替换或调整任何单词的顺序
+2. 只能在单词后面添加标点，或修改单词的大小写
+3. 只输出处理后的文本，不要任何解释`
+
+// ResegmentSubtitles 字幕重新断句（去除滚动字幕重复、合并碎片为完整句子）
+// 在翻译之前执行，使翻译器看到完整的句子
+type ResegmentSubtitles struct {
+	base.BaseTask
+	App       *core.AppServer
+	AIService *services.AIServiceManager
+}
+
+func NewResegmentSubtitles(name string, app *core.AppServer, stateManager *manager.StateManager, client *cos.CosClient, aiService *services.AIServiceManager) *ResegmentSubtitles {
+	return &ResegmentSubtitles{
+		BaseTask: base.BaseTask{
+			Name:         name,
+			StateManager: stateManager,
+			Client:       client,
+		},
+		App:       app,
+		AIService: aiService,
+	}
+}
+
+func (t *ResegmentSubtitles) Execute(context map[string]interface{}) bool {
+	cfg := t.App.Config.SubtitleConfig
+	if cfg == nil || !cfg.Resegment {
+		t.App.Logger.Info("字幕重新断句未启用，跳过")
+		return true
+	}
+
+	// 翻译步骤读取 <videoID>.srt，B站必剪和 Whisper 只生成 en.srt
+	translateInput := filepath.Join(t.StateManager.CurrentDir, fmt.Sprintf("%s.srt", t.StateManager.VideoID))
+
+	// 保留原始字幕，重试时总是基于原始字幕重新断句；重新转录或重新生成字幕后刷新备份
+	if err := t.backupRawSRT(translateInput, t.StateManager.OriginalSRT); err != nil {
+		t.App.Logger.Errorf("❌ 备份原始字幕失败: %v", err)
+		context["error"] = fmt.Sprintf("备份原始字幕失败: %v", err)
+		return false
+	}
+	if _, err := os.Stat(t.StateManager.RawSRT); os.IsNotExist(err) {
+		t.App.Logger.Warn("⚠️  字幕文件不存在，跳过重新断句")
+		return true
+	}
+
+	doc, err := subtitle.ReadFile(t.StateManager.RawSRT)
+	if err != nil {
+		t.App.Logger.Errorf("❌ 读取原始字幕失败: %v", err)
+		context["error"] = fmt.Sprintf("读取原始字幕失败: %v", err)
+		return false
+	}
+	if doc.Len() == 0 {
+		t.App.Logger.Warn("⚠️  字幕内容为空，跳过重新断句")
+		return true
+	}
+
+	opts := subtitle.ResegmentOptions{
+		MaxLineChars: cfg.MaxLineChars,
+		MaxLines:     cfg.MaxLines,
+		MaxDuration:  int64(cfg.MaxDurationMs),
+		MinDuration:  int64(cfg.MinDurationMs),
+		MinGap:       int64(cfg.MinGapMs),
+	}
+	if cfg.RestorePunctuation {
+		if len(t.AIService.EnabledProviders()) == 0 {
+			t.App.Logger.Warnf("⚠️ 无法进行标点恢复: %v", services.ErrNoAIService)
+		} else {
+			ctx := translator.WithUsageScope(stdcontext.Background(), t.StateManager.VideoID, t.Name)
+			opts.Punctuate = func(text string) (string, error) {
+				result, _, err := t.AIService.ChatCompletionContext(ctx, punctuationSystemPrompt, text)
+				if err != nil {
+					return "", err
+				}
+				return strings.TrimSpace(result), nil
+			}
+		}
+	}
+
+	resegmented, stats, err := subtitle.Resegment(doc, opts)
+	if err != nil {
+		// 标点恢复失败时退回到不使用标点恢复
+		t.App.Logger.Warnf("⚠️ 标点恢复失败，仅按时间断句: %v", err)
+		opts.Punctuate = nil
+		resegmented, stats, err = subtitle.Resegment(doc, opts)
+		if err != nil {
+			context["error"] = fmt.Sprintf("字幕重新断句失败: %v", err)
+			return false
+		}
+	}
+
+	// 同时写入翻译输入和原始语言字幕（上传字幕时使用）
+	for _, path := range []string{translateInput, t.StateManager.OriginalSRT} {
+		if err := subtitle.WriteFile(path, resegmented); err != nil {
+			t.App.Logger.Errorf("❌ 保存断句结果失败: %v", err)
+			context["error"] = fmt.Sprintf("保存断句结果失败: %v", err)
+			return false
+		}
+	}
+
+	// 断句结果比备份新，将备份时间更新为当前时间，此后只有重新生成的源字幕才会比备份新
+	now := time.Now()
+	if err := os.Chtimes(t.StateManager.RawSRT, now, now); err != nil {
+		t.App.Logger.Warnf("⚠️ 更新原始字幕备份时间失败: %v", err)
+	}
+
+	t.App.Logger.Infof("✂️ 字幕重新断句完成: %d 条 → %d 条 (去除重复词 %d 个, 标点恢复: %v)",
+		stats.InputCues, stats.OutputCues, stats.DroppedRepeats, stats.Punctuated)
+
+	context["resegment_stats"] = stats
+	return true
+}
+
+// backupRawSRT 将最新的源字幕备份为重新断句前的原始字幕：备份不存在，
+// 或源字幕比备份新（重新转录、重新下载了字幕）时刷新备份
+func (t *ResegmentSubtitles) backupRawSRT(sources ...string) error {
+	var sourcePath string
+	var sourceTime time.Time
+	for _, path := range sources {
+		if info, err := os.Stat(path); err == nil && info.ModTime().After(sourceTime) {
+			sourcePath, sourceTime = path, info.ModTime()
+		}
+	}
+	if sourcePath == "" {
+		return nil
+	}
+
+	if info, err := os.Stat(t.StateManager.RawSRT); err == nil {
+		if !sourceTime.After(info.ModTime()) {
+			return nil
+		}
+		t.App.Logger.Infof("🔄 源字幕已重新生成，刷新原始字幕备份: %s", filepath.Base(sourcePath))
+	}
+	return utils.CopyFile(sourcePath, t.StateManager.RawSRT)
+}
@@ -0,0 +1,289 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/difyz9/bilibili-go-sdk/bilibili"
+	"github.com/difyz9/ytb2bili/internal/chain_task/base"
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/prompt"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/translator"
+)
+
+// SelectPartition 根据来源视频的分类、标签和AI生成的内容概要选择B站投稿分区：
+// 先按配置的映射规则匹配，未命中时由大模型在缓存的分区列表中选择，结果和依据保存到视频记录，上传时使用
+type SelectPartition struct {
+	base.BaseTask
+	App               *core.AppServer
+	AIService         *services.AIServiceManager
+	PartitionService  *services.BiliPartitionService
+	SavedVideoService *services.SavedVideoService
+}
+
+func NewSelectPartition(name string, app *core.AppServer, stateManager *manager.StateManager, client *cos.CosClient, savedVideoService *services.SavedVideoService, aiService *services.AIServiceManager, partitionService *services.BiliPartitionService) *SelectPartition {
+	return &SelectPartition{
+		BaseTask: base.BaseTask{
+			Name:         name,
+			StateManager: stateManager,
+			Client:       client,
+		},
+		App:               app,
+		AIService:         aiService,
+		PartitionService:  partitionService,
+		SavedVideoService: savedVideoService,
+	}
+}
+
+// partitionChoice 模型返回的分区选择
+type partitionChoice struct {
+	Tid    int    `json:"tid"`
+	Reason string `json:"reason"`
+}
+
+func (t *SelectPartition) Execute(context map[string]interface{}) bool {
+	cfg := t.App.Config.PartitionConfig
+	if cfg == nil || !cfg.Enabled {
+		t.App.Logger.Info("自动选择分区未启用，使用配置的默认分区")
+		return true
+	}
+
+	video, err := t.SavedVideoService.GetVideoByVideoID(t.StateManager.VideoID)
+	if err != nil {
+		t.App.Logger.Errorf("❌ 获取视频记录失败: %v", err)
+		context["error"] = fmt.Sprintf("获取视频记录失败: %v", err)
+		return false
+	}
+
+	// 分区列表用于校验规则和模型选择的分区，获取失败时规则仍然生效
+	var partitions []bilibili.PartitionType
+	if t.PartitionService != nil {
+		partitions, err = t.PartitionService.Partitions()
+		if err != nil {
+			t.App.Logger.Warnf("⚠️ 获取B站分区列表失败: %v", err)
+		}
+	}
+
+	tid, reason := 0, ""
+
+	// 1. 映射规则
+	if ruleTid, ruleReason, ok := matchPartitionRule(cfg.Rules, video); ok {
+		if len(partitions) > 0 {
+			if _, child := services.FindPartition(partitions, ruleTid); child == nil {
+				t.App.Logger.Warnf("⚠️ 规则中的分区 %d 不在B站分区列表中，忽略该规则", ruleTid)
+			} else {
+				tid, reason = ruleTid, ruleReason
+			}
+		} else {
+			tid, reason = ruleTid, ruleReason
+		}
+	}
+
+	// 2. 由大模型在分区列表中选择
+	if tid == 0 && cfg.UseLLM {
+		switch {
+		case len(partitions) == 0:
+			t.App.Logger.Warn("⚠️ 没有可用的B站分区列表，跳过模型选择")
+		case t.AIService == nil || len(t.AIService.EnabledProviders()) == 0:
+			t.App.Logger.Warn("⚠️ 没有可用的 AI 服务，跳过模型选择")
+		default:
+			choice, provider, err := t.chooseWithLLM(video, partitions)
+			if err != nil {
+				t.App.Logger.Errorf("❌ 模型选择分区失败: %v", err)
+				context["error"] = fmt.Sprintf("模型选择分区失败: %v", err)
+				markBudgetPaused(context, err)
+				return false
+			}
+			if _, child := services.FindPartition(partitions, choice.Tid); child == nil {
+				t.App.Logger.Warnf("⚠️ 模型返回的分区 %d 不在B站分区列表中，使用默认分区", choice.Tid)
+			} else {
+				tid, reason = choice.Tid, fmt.Sprintf("模型选择（%s）：%s", provider, strings.TrimSpace(choice.Reason))
+			}
+		}
+	}
+
+	if tid == 0 {
+		reason = "未匹配到分区，使用配置的默认分区"
+	} else if parent, child := services.FindPartition(partitions, tid); child != nil {
+		reason = fmt.Sprintf("%s/%s，%s", parent.Name, child.Name, reason)
+	}
+
+	// 3. 保存到视频记录（重新执行时覆盖上次的选择）
+	video.Tid = tid
+	video.TidReason = truncateRunes(reason, 500)
+	if err := t.SavedVideoService.UpdateVideo(video); err != nil {
+		t.App.Logger.Errorf("❌ 保存分区到数据库失败: %v", err)
+		context["error"] = fmt.Sprintf("保存分区失败: %v", err)
+		return false
+	}
+	context["tid"] = tid
+
+	if tid == 0 {
+		t.App.Logger.Info("ℹ️ 未能确定分区，上传时使用配置的默认分区")
+	} else {
+		t.App.Logger.Infof("✓ 投稿分区: %d（%s）", tid, video.TidReason)
+	}
+	return true
+}
+
+// chooseWithLLM 渲染分区提示词，让模型从分区列表中选择分区
+func (t *SelectPartition) chooseWithLLM(video *model.SavedVideo, partitions []bilibili.PartitionType) (*partitionChoice, services.AIProvider, error) {
+	data := services.PromptDataForVideo(video)
+	data.Summary = partitionSummary(video)
+	data.Partitions = formatPartitions(partitions)
+
+	rendered, err := prompt.Render(prompt.Partition, data)
+	if err != nil {
+		return nil, "", err
+	}
+
+	ctx := translator.WithUsageScope(context.Background(), t.StateManager.VideoID, t.Name)
+	content, provider, err := t.AIService.ChatCompletionContext(ctx, rendered.System, rendered.User)
+	if err != nil {
+		return nil, "", err
+	}
+	t.App.Logger.Debugf("%s 原始返回: %s", provider, content)
+
+	var choice partitionChoice
+	if err := json.Unmarshal([]byte(trimCodeFence(content)), &choice); err != nil {
+		return nil, "", fmt.Errorf("解析分区JSON失败: %v, 内容: %s", err, content)
+	}
+	return &choice, provider, nil
+}
+
+// matchPartitionRule 按顺序匹配映射规则，返回第一个命中规则的分区和命中的条件
+func matchPartitionRule(rules []types.PartitionRule, video *model.SavedVideo) (int, string, bool) {
+	categories := splitList(video.SourceCategory)
+	keywordText := strings.ToLower(strings.Join([]string{video.SourceTags, video.Title, video.GeneratedTags}, " "))
+
+	for _, rule := range rules {
+		if rule.Tid <= 0 || (len(rule.Categories) == 0 && len(rule.Keywords) == 0 && len(rule.Channels) == 0) {
+			continue
+		}
+
+		var matched []string
+		if len(rule.Categories) > 0 {
+			hit := firstMatch(rule.Categories, func(value string) bool { return containsFold(categories, value) })
+			if hit == "" {
+				continue
+			}
+			matched = append(matched, "YouTube 分类 "+hit)
+		}
+		if len(rule.Keywords) > 0 {
+			hit := firstMatch(rule.Keywords, func(value string) bool { return strings.Contains(keywordText, strings.ToLower(value)) })
+			if hit == "" {
+				continue
+			}
+			matched = append(matched, "关键词 "+hit)
+		}
+		if len(rule.Channels) > 0 {
+			hit := firstMatch(rule.Channels, func(value string) bool {
+				return strings.EqualFold(value, video.ChannelID) || strings.EqualFold(value, video.ChannelName)
+			})
+			if hit == "" {
+				continue
+			}
+			matched = append(matched, "频道 "+hit)
+		}
+		return rule.Tid, "规则匹配：" + strings.Join(matched, "，"), true
+	}
+	return 0, "", false
+}
+
+// firstMatch 返回第一个满足条件的非空值
+func firstMatch(values []string, match func(string) bool) string {
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value != "" && match(value) {
+			return value
+		}
+	}
+	return ""
+}
+
+// containsFold 列表中是否包含 value（不区分大小写）
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// splitList 拆分逗号分隔的列表
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// truncateJoined 用逗号连接列表，超出 maxLen 个字符的条目丢弃
+func truncateJoined(items []string, maxLen int) string {
+	result := ""
+	for _, item := range items {
+		item = strings.TrimSpace(strings.ReplaceAll(item, ",", " "))
+		if item == "" {
+			continue
+		}
+		next := item
+		if result != "" {
+			next = result + "," + item
+		}
+		if len([]rune(next)) > maxLen {
+			break
+		}
+		result = next
+	}
+	return result
+}
+
+// truncateRunes 按字符数截断字符串
+func truncateRunes(s string, maxLen int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLen {
+		return s
+	}
+	return string(runes[:maxLen])
+}
+
+// partitionSummary 用AI生成的标题、简介和标签作为内容概要
+func partitionSummary(video *model.SavedVideo) string {
+	var lines []string
+	if video.GeneratedTitle != "" {
+		lines = append(lines, "标题："+video.GeneratedTitle)
+	}
+	if video.GeneratedDesc != "" {
+		lines = append(lines, "简介："+truncateRunes(video.GeneratedDesc, 300))
+	}
+	if video.GeneratedTags != "" {
+		lines = append(lines, "标签："+video.GeneratedTags)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// formatPartitions 将分区列表格式化为提示词中的候选列表（只列出可投稿的二级分区）
+func formatPartitions(partitions []bilibili.PartitionType) string {
+	var builder strings.Builder
+	for _, parent := range partitions {
+		for _, child := range parent.Children {
+			fmt.Fprintf(&builder, "%d %s/%s", child.ID, parent.Name, child.Name)
+			if desc := strings.TrimSpace(child.Desc); desc != "" {
+				fmt.Fprintf(&builder, " - %s", truncateRunes(desc, 40))
+			}
+			builder.WriteString("\n")
+		}
+	}
+	return strings.TrimSpace(builder.String())
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/chain_task/base"
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+	"github.com/difyz9/ytb2bili/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// latinMaxCPS 按字母计数的语言（英文等）每秒最大字符数
+const latinMaxCPS = 17
+
+// SubtitleQAReport 单个译文轨道的质检报告
+type SubtitleQAReport struct {
+	Language  string             `json:"language"`
+	File      string             `json:"file"`
+	AutoFixed bool               `json:"auto_fixed"`
+	Before    *subtitle.QAReport `json:"before"`
+	After     *subtitle.QAReport `json:"after"`
+	Actions   map[string]int     `json:"actions,omitempty"`
+	Options   subtitle.QAOptions `json:"options"`
+}
+
+// SubtitleQAResult 字幕质检结果，保存到 subtitle_qa.json，在视频详情中展示
+type SubtitleQAResult struct {
+	Tracks    []*SubtitleQAReport `json:"tracks"`
+	CheckedAt time.Time           `json:"checked_at"`
+}
+
+// SubtitleQA 译文字幕质检：对每个目标语言检查重叠、时长、阅读速度、行长和超出视频结尾，
+// 并自动修复可安全修复的问题（只调整时间和折行，条目与原文保持一一对应）
+type SubtitleQA struct {
+	base.BaseTask
+	App *core.AppServer
+	DB  *gorm.DB
+}
+
+func NewSubtitleQA(name string, app *core.AppServer, stateManager *manager.StateManager, client *cos.CosClient, db *gorm.DB) *SubtitleQA {
+	return &SubtitleQA{
+		BaseTask: base.BaseTask{
+			Name:         name,
+			StateManager: stateManager,
+			Client:       client,
+		},
+		App: app,
+		DB:  db,
+	}
+}
+
+func (t *SubtitleQA) Execute(context map[string]interface{}) bool {
+	cfg := t.App.Config.SubtitleConfig
+	if cfg == nil || !cfg.QualityCheck {
+		t.App.Logger.Info("字幕质检未启用，跳过")
+		return true
+	}
+
+	var mediaDuration int64
+	if duration, err := utils.GetMediaDuration(t.StateManager.InputVideoPath); err != nil {
+		t.App.Logger.Warnf("⚠️ 获取视频时长失败，跳过超出结尾检查: %v", err)
+	} else {
+		mediaDuration = subtitle.SecondsToMS(duration)
+	}
+
+	var video *model.SavedVideo
+	if t.DB != nil {
+		if v, err := services.NewSavedVideoService(t.DB).GetVideoByVideoID(t.StateManager.VideoID); err == nil {
+			video = v
+		}
+	}
+
+	result := &SubtitleQAResult{CheckedAt: time.Now()}
+	for _, language := range resolveTargetLanguages(t.App.Config, video) {
+		srtPath := t.StateManager.TranslatedSRTPath(language)
+		if _, err := os.Stat(srtPath); os.IsNotExist(err) {
+			t.App.Logger.Warnf("⚠️  [%s] 译文字幕不存在，跳过字幕质检", language)
+			continue
+		}
+
+		report, err := t.checkTrack(srtPath, language, cfg, mediaDuration)
+		if err != nil {
+			t.App.Logger.Errorf("❌ [%s] 字幕质检失败: %v", language, err)
+			context["error"] = fmt.Sprintf("[%s] 字幕质检失败: %v", language, err)
+			return false
+		}
+		result.Tracks = append(result.Tracks, report)
+
+		t.App.Logger.Infof("🔍 [%s] 字幕质检完成: %d 条字幕, 修复前 %d 错误/%d 警告, 修复后 %d 错误/%d 警告",
+			language, report.After.TotalCues, report.Before.Errors, report.Before.Warnings, report.After.Errors, report.After.Warnings)
+	}
+
+	if len(result.Tracks) == 0 {
+		t.App.Logger.Warn("⚠️  没有可质检的译文字幕，跳过字幕质检")
+		return true
+	}
+
+	if err := SaveSubtitleQAReport(t.StateManager, result); err != nil {
+		t.App.Logger.Warnf("⚠️ 保存字幕质检报告失败: %v", err)
+	}
+
+	context["subtitle_qa"] = result
+	return true
+}
+
+// checkTrack 质检单个译文轨道，开启自动修复时原地写回修复结果
+func (t *SubtitleQA) checkTrack(srtPath, language string, cfg *types.SubtitleConfig, mediaDuration int64) (*SubtitleQAReport, error) {
+	doc, err := subtitle.ReadFile(srtPath)
+	if err != nil {
+		return nil, fmt.Errorf("读取字幕失败: %w", err)
+	}
+
+	opts := qaOptionsFor(cfg, language)
+	opts.MediaDuration = mediaDuration
+
+	report := &SubtitleQAReport{
+		Language:  language,
+		File:      srtPath,
+		AutoFixed: cfg.AutoFix,
+		Options:   opts,
+	}
+
+	if !cfg.AutoFix {
+		report.Before = subtitle.CheckQuality(doc, opts)
+		report.After = report.Before
+		return report, nil
+	}
+
+	fixed, result := subtitle.AutoFix(doc, opts)
+	report.Before = result.Before
+	report.After = result.After
+	report.Actions = result.Actions
+
+	if len(result.Actions) > 0 {
+		if err := subtitle.WriteFile(srtPath, fixed); err != nil {
+			return nil, fmt.Errorf("保存修复后的字幕失败: %w", err)
+		}
+		t.App.Logger.Infof("🔧 [%s] 字幕自动修复: %v", language, result.Actions)
+	}
+	return report, nil
+}
+
+// qaOptionsFor 按语言选择质检参数：中日韩文字按字计数，使用中文字幕的阅读速度和行长；
+// 其它语言按字母计数，使用原文字幕的行长
+func qaOptionsFor(cfg *types.SubtitleConfig, language string) subtitle.QAOptions {
+	opts := subtitle.QAOptions{
+		MaxCPS:       cfg.MaxCPS,
+		MaxLineChars: cfg.TargetMaxLineChars,
+		MaxLines:     cfg.MaxLines,
+		MinDuration:  int64(cfg.MinDurationMs),
+		MaxDuration:  int64(cfg.MaxDurationMs),
+		MinGap:       int64(cfg.MinGapMs),
+	}
+	if !utils.IsCJKLanguage(language) {
+		opts.MaxCPS = latinMaxCPS
+		opts.MaxLineChars = cfg.MaxLineChars
+	}
+	return opts
+}
+
+// SaveSubtitleQAReport 保存字幕质检结果
+func SaveSubtitleQAReport(stateManager *manager.StateManager, result *SubtitleQAResult) error {
+	data, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(stateManager.SubtitleQA, data, 0644)
+}
@@ -1,30 +1,40 @@
 package handlers
 
 import (
+	stdcontext "context"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
 	"github.com/difyz9/ytb2bili/internal/chain_task/base"
 	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
 	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/internal/core/types"
 	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+	"github.com/difyz9/ytb2bili/pkg/translator"
 	"github.com/difyz9/ytb2bili/pkg/utils"
 	"gorm.io/gorm"
 )
 
+// TranslateSubtitle 通过 pkg/translator 的 TranslatorManager 翻译字幕，
+// 提供商、备选提供商、重试次数和超时由 TranslatorConfig 控制
 type TranslateSubtitle struct {
 	base.BaseTask
 	App        *core.AppServer
 	DB         *gorm.DB
-	APIKey     string
 	GroupSize  int
-	MaxWorkers int // 最大并发数
+	MaxWorkers int                        // 最大并发数
+	AIService  *services.AIServiceManager // 字幕校验后修复问题条目使用的对话服务
 }
 
-func NewTranslateSubtitle(name string, app *core.AppServer, stateManager *manager.StateManager, client *cos.CosClient, db *gorm.DB, apiKey string) *TranslateSubtitle {
+func NewTranslateSubtitle(name string, app *core.AppServer, stateManager *manager.StateManager, client *cos.CosClient, db *gorm.DB, aiService *services.AIServiceManager) *TranslateSubtitle {
 	return &TranslateSubtitle{
 		BaseTask: base.BaseTask{
 			Name:         name,
@@ -33,215 +43,194 @@ func NewTranslateSubtitle(name string, app *core.AppServer, stateManager *manage
 		},
 		App:        app,
 		DB:         db,
-		APIKey:     "", // 不再固化API Key，运行时动态获取
-		GroupSize:  25, // 每组25句，减少API调用次数
-		MaxWorkers: 3,  // 最多3个并发，避免API限制
+		GroupSize:  20, // 每组20句，与 DeepSeek 翻译器的单次批量大小一致
+		MaxWorkers: 3,  // 单个视频内的并发组数；提供商的请求频率和并发由 ratelimit 在所有视频间统一限制
+		AIService:  aiService,
 	}
 }
 
-// getCurrentAPIKey 获取当前的DeepSeek API Key（实时从配置中读取）
-func (t *TranslateSubtitle) getCurrentAPIKey() (string, error) {
-	if t.App.Config.DeepSeekTransConfig == nil || !t.App.Config.DeepSeekTransConfig.Enabled {
-		return "", fmt.Errorf("DeepSeek 翻译服务未启用")
-	}
-
-	apiKey := t.App.Config.DeepSeekTransConfig.ApiKey
-	if apiKey == "" {
-		return "", fmt.Errorf("DeepSeek API Key 未配置")
-	}
-
-	return apiKey, nil
-}
-
-// SRTEntry SRT字幕条目
-type SRTEntry struct {
-	Index    int
-	TimeCode string
-	Text     string
-}
-
 func (t *TranslateSubtitle) Execute(context map[string]interface{}) bool {
 	t.App.Logger.Info("========================================")
 	t.App.Logger.Infof("开始翻译字幕: VideoID=%s", t.StateManager.VideoID)
 	t.App.Logger.Info("========================================")
 
-	// 0. 动态获取最新的API Key配置
-	currentAPIKey, err := t.getCurrentAPIKey()
-	if err != nil {
-		t.App.Logger.Errorf("❌ %v", err)
-		context["error"] = t.getTranslationError(err)
-		return false
+	// 0. 每次执行都按最新配置创建翻译管理器，配置热更新后立即生效
+	translatorManager := translator.NewTranslatorManager(t.App.Config)
+	translatorManager.SetMemory(services.NewTranslationMemoryService(t.DB))
+	t.App.Logger.Infof("🌐 翻译提供商: %s", strings.Join(translatorManager.ProviderChain(), " -> "))
+	if cfg := t.App.Config.TranslatorConfig; cfg != nil && cfg.EnableCache {
+		t.App.Logger.Info("♻️  已启用翻译记忆，命中的句子不再调用翻译服务")
 	}
 
-	t.App.Logger.Infof("🔑 使用DeepSeek API Key: %s", maskAPIKey(currentAPIKey))
-	// 更新当前使用的API Key
-	t.APIKey = currentAPIKey
+	// 0.1 确定目标语言（视频单独设置优先，否则使用全局配置），第一个为主语言
+	video := t.loadVideo()
+	languages := resolveTargetLanguages(t.App.Config, video)
+	t.App.Logger.Infof("🌍 目标语言: %s（主语言: %s）", strings.Join(languages, ", "), languages[0])
 
 	// 1. 检查英文字幕文件是否存在（由 GenerateSubtitles 任务生成）
-	enSRTPath := filepath.Join(t.StateManager.CurrentDir, fmt.Sprintf("%s.srt", t.StateManager.VideoID))
+	enSRTPath := t.StateManager.TranslationSourceSRT()
 	if _, err := os.Stat(enSRTPath); os.IsNotExist(err) {
 		t.App.Logger.Warn("⚠️  英文字幕文件不存在，跳过翻译")
 		return true // 没有字幕文件不算失败
 	}
 
 	// 2. 读取并解析英文字幕文件
-	srtContent, err := os.ReadFile(enSRTPath)
-	if err != nil {
-		t.App.Logger.Errorf("❌ 读取英文字幕文件失败: %v", err)
-		context["error"] = "字幕文件读取失败，请确认字幕生成步骤已完成"
-		return false
-	}
-
-	srtEntries, err := t.parseSRTContent(string(srtContent))
+	srtDoc, err := subtitle.ReadFile(enSRTPath)
 	if err != nil {
 		t.App.Logger.Errorf("❌ 解析SRT文件失败: %v", err)
 		context["error"] = "字幕文件格式错误，无法解析SRT内容"
 		return false
 	}
 
-	if len(srtEntries) == 0 {
+	if srtDoc.Len() == 0 {
 		t.App.Logger.Warn("⚠️  字幕内容为空，跳过翻译")
 		return true
 	}
 
-	t.App.Logger.Infof("📝 找到 %d 条字幕", len(srtEntries))
-
-	// 3. 提取文本进行翻译
-	var texts []string
-	for _, entry := range srtEntries {
-		texts = append(texts, entry.Text)
-	}
-
-	// 4. 执行并发翻译
-	totalGroups := (len(texts) + t.GroupSize - 1) / t.GroupSize
-	t.App.Logger.Infof("� 开始并发翻译，每组 %d 句，共 %d 组，并发数: %d", t.GroupSize, totalGroups, t.MaxWorkers)
-
-	translatedTexts, err := t.translateTextsInGroupsConcurrent(texts)
-	if err != nil {
-		t.App.Logger.Errorf("❌ 翻译失败: %v", err)
-		context["error"] = t.getTranslationError(err)
-		return false
-	}
+	t.App.Logger.Infof("📝 找到 %d 条字幕", srtDoc.Len())
 
-	// 5. 生成中文字幕SRT
-	translatedSRT := t.generateTranslatedSRTContent(srtEntries, translatedTexts)
-
-	// 6. 保存中文字幕文件
-	zhSRTPath := filepath.Join(t.StateManager.CurrentDir, "zh.srt")
-	if err := os.WriteFile(zhSRTPath, []byte(translatedSRT), 0644); err != nil {
-		t.App.Logger.Errorf("❌ 保存中文字幕失败: %v", err)
-		context["error"] = "保存翻译字幕文件失败，请检查磁盘空间和文件权限"
-		return false
-	}
+	// 3. 逐个目标语言翻译，每个语言生成一条字幕轨道
+	tracks := make(map[string]string, len(languages))
+	var failedLanguages, allProviders, glossaryViolations []string
+	translatedCount := 0
+	for i, language := range languages {
+		result, err := t.translateTrack(translatorManager, srtDoc, enSRTPath, language, video)
+		if err != nil {
+			if i == 0 {
+				// 主语言翻译失败时整个步骤失败（元数据生成依赖主语言字幕）
+				t.App.Logger.Errorf("❌ 主语言 %s 翻译失败: %v", language, err)
+				context["error"] = t.getTranslationError(err)
+				markBudgetPaused(context, err)
+				return false
+			}
+			t.App.Logger.Errorf("❌ %s 翻译失败，跳过该语言: %v", language, err)
+			failedLanguages = append(failedLanguages, language)
+			continue
+		}
 
-	// 7. 字幕质量校验和优化
-	optimizedPath, validationResult, err := t.validateAndOptimizeSubtitles(enSRTPath, zhSRTPath)
-	if err != nil {
-		t.App.Logger.Warnf("⚠️  字幕校验失败，使用原始翻译: %v", err)
-	} else {
-		if validationResult.MissingEntries > 0 {
-			t.App.Logger.Infof("🔧 检测到 %d 个问题条目，已尝试修复 %d 个",
-				validationResult.MissingEntries, len(validationResult.FixedEntries))
-
-			if optimizedPath != "" {
-				// 使用优化后的文件替换原文件
-				if err := os.Rename(optimizedPath, zhSRTPath); err == nil {
-					t.App.Logger.Info("✨ 已应用字幕优化结果")
+		tracks[language] = result.Path
+		allProviders = append(allProviders, result.Providers...)
+		if err := recordPromptVersions(t.StateManager, filepath.Base(result.Path), uniqueSorted(result.PromptVersions)); err != nil {
+			t.App.Logger.Warnf("⚠️  记录提示词版本失败: %v", err)
+		}
+		if i == 0 {
+			translatedCount = result.Count
+			if result.Validation != nil {
+				context["validation_result"] = map[string]interface{}{
+					"total_entries":   result.Validation.TotalEntries,
+					"valid_entries":   result.Validation.ValidEntries,
+					"missing_entries": result.Validation.MissingEntries,
+					"fixed_entries":   len(result.Validation.FixedEntries),
+					"glossary_issues": len(result.Validation.GlossaryIssues),
 				}
 			}
 		}
+		// 非主语言的术语问题带上语言前缀，便于区分
+		for _, violation := range result.GlossaryViolations {
+			if i > 0 {
+				violation = language + " " + violation
+			}
+			glossaryViolations = append(glossaryViolations, violation)
+		}
 	}
 
-	// 8. 保存文件路径到 context
+	// 4. 保存文件路径到 context
 	context["en_srt_path"] = enSRTPath
-	context["zh_srt_path"] = zhSRTPath
-	context["translated_count"] = len(translatedTexts)
-
-	// 添加校验结果信息
-	if validationResult != nil {
-		context["validation_result"] = map[string]interface{}{
-			"total_entries":   validationResult.TotalEntries,
-			"valid_entries":   validationResult.ValidEntries,
-			"missing_entries": validationResult.MissingEntries,
-			"fixed_entries":   len(validationResult.FixedEntries),
-		}
+	if path, ok := tracks[subtitle.LanguageSimplifiedChinese]; ok {
+		context["zh_srt_path"] = path
+	}
+	context["primary_language"] = languages[0]
+	context["translated_tracks"] = tracks
+	context["translated_count"] = translatedCount
+	context["translate_providers"] = uniqueSorted(allProviders)
+	if len(failedLanguages) > 0 {
+		context["translate_failed_languages"] = failedLanguages
+	}
+	if len(glossaryViolations) > 0 {
+		context["glossary_violations"] = glossaryViolations
 	}
 
-	t.App.Logger.Infof("✓ 中文字幕已保存: %s", zhSRTPath)
-	t.App.Logger.Infof("✓ 翻译完成: %d/%d 条字幕", len(translatedTexts), len(texts))
+	t.App.Logger.Infof("✓ 翻译完成: %d 条字幕，%d/%d 个语言 (提供商: %s)",
+		len(srtDoc.Texts()), len(tracks), len(languages), strings.Join(uniqueSorted(allProviders), ", "))
 	t.App.Logger.Info("========================================")
 
 	return true
 }
 
-// parseSRTContent 解析SRT文件内容
-func (t *TranslateSubtitle) parseSRTContent(content string) ([]SRTEntry, error) {
-	lines := strings.Split(content, "\n")
-	var entries []SRTEntry
-	var currentEntry SRTEntry
-	var textLines []string
-	stage := 0 // 0=等待序号, 1=等待时间码, 2=读取文本
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-
-		if line == "" {
-			// 空行表示一个条目结束
-			if stage == 2 && len(textLines) > 0 {
-				currentEntry.Text = strings.Join(textLines, "\n")
-				entries = append(entries, currentEntry)
-				textLines = nil
-				stage = 0
-			}
-			continue
-		}
+// translatedTrack 单个目标语言的翻译结果
+type translatedTrack struct {
+	Path               string
+	Count              int
+	Providers          []string
+	PromptVersions     []string // 生成该轨道使用的提示词模板版本（翻译记忆命中和机器翻译不使用提示词）
+	Validation         *utils.ValidationResult
+	GlossaryViolations []string
+}
 
-		switch stage {
-		case 0: // 读取序号
-			var index int
-			if _, err := fmt.Sscanf(line, "%d", &index); err == nil {
-				currentEntry = SRTEntry{Index: index}
-				stage = 1
-			}
-		case 1: // 读取时间码
-			if strings.Contains(line, "-->") {
-				currentEntry.TimeCode = line
-				stage = 2
-			}
-		case 2: // 读取文本
-			textLines = append(textLines, line)
-		}
-	}
+// translateTrack 将原文字幕翻译为指定语言并保存为该语言的字幕轨道
+func (t *TranslateSubtitle) translateTrack(translatorManager *translator.TranslatorManager, srtDoc *subtitle.Document, enSRTPath, language string, video *model.SavedVideo) (*translatedTrack, error) {
+	targetLang := translator.NormalizeLangCode(language)
+	texts := srtDoc.Texts()
 
-	// 处理最后一个条目（如果文件末尾没有空行）
-	if stage == 2 && len(textLines) > 0 {
-		currentEntry.Text = strings.Join(textLines, "\n")
-		entries = append(entries, currentEntry)
-	}
+	// 加载该语言生效的术语表（全局 + 频道 + 播放列表 + 视频级）
+	glossary := t.loadGlossary(video, targetLang)
 
-	return entries, nil
-}
+	totalGroups := (len(texts) + t.GroupSize - 1) / t.GroupSize
+	t.App.Logger.Infof("🚀 [%s] 开始并发翻译，每组 %d 句，共 %d 组，并发数: %d", language, t.GroupSize, totalGroups, t.MaxWorkers)
 
-// generateTranslatedSRTContent 生成翻译后的SRT内容（保持原时间轴）
-func (t *TranslateSubtitle) generateTranslatedSRTContent(entries []SRTEntry, translatedTexts []string) string {
-	var builder strings.Builder
+	translatedTexts, providers, promptVersions, err := t.translateTextsInGroupsConcurrent(translatorManager, texts, targetLang, glossary, video)
+	if err != nil {
+		return nil, err
+	}
 
-	for i, entry := range entries {
-		builder.WriteString(fmt.Sprintf("%d\n", entry.Index))
-		builder.WriteString(fmt.Sprintf("%s\n", entry.TimeCode))
+	// 生成译文字幕（保持原时间轴）并按语言代码保存
+	trackPath := t.StateManager.TranslatedSRTPath(language)
+	if err := subtitle.WriteFile(trackPath, srtDoc.WithTexts(language, translatedTexts)); err != nil {
+		return nil, fmt.Errorf("保存翻译字幕文件失败，请检查磁盘空间和文件权限: %w", err)
+	}
 
-		if i < len(translatedTexts) {
-			builder.WriteString(fmt.Sprintf("%s\n\n", translatedTexts[i]))
+	result := &translatedTrack{
+		Path:           trackPath,
+		Count:          len(translatedTexts),
+		Providers:      providers,
+		PromptVersions: promptVersions,
+	}
+
+	// 字幕校验修复基于中文提示词，只对简体中文轨道执行
+	if language == subtitle.LanguageSimplifiedChinese {
+		optimizedPath, validationResult, err := t.validateAndOptimizeSubtitles(enSRTPath, trackPath, glossary, video)
+		if err != nil {
+			t.App.Logger.Warnf("⚠️  字幕校验失败，使用原始翻译: %v", err)
 		} else {
-			builder.WriteString(fmt.Sprintf("%s\n\n", entry.Text))
+			result.Validation = validationResult
+			// 修复后会重新统计，问题全部修复时 MissingEntries 为 0，因此同时看 FixedEntries
+			if validationResult.MissingEntries > 0 || len(validationResult.FixedEntries) > 0 {
+				t.App.Logger.Infof("🔧 剩余 %d 个问题条目，已修复 %d 个",
+					validationResult.MissingEntries, len(validationResult.FixedEntries))
+
+				if optimizedPath != "" {
+					// 使用优化后的文件替换原文件
+					if err := os.Rename(optimizedPath, trackPath); err == nil {
+						t.App.Logger.Info("✨ 已应用字幕优化结果")
+						result.PromptVersions = append(result.PromptVersions, validationResult.PromptVersion)
+					}
+				}
+			}
 		}
 	}
 
-	return builder.String()
+	// 最终术语检查：仍未按术语表翻译的条目记录下来供人工审核
+	if violations := t.checkGlossary(trackPath, texts, glossary); len(violations) > 0 {
+		t.App.Logger.Warnf("⚠️  [%s] %d 条字幕未按术语表翻译", language, len(violations))
+		result.GlossaryViolations = violations
+	}
+
+	t.App.Logger.Infof("✓ [%s] 字幕已保存: %s", language, trackPath)
+	return result, nil
 }
 
-// translateTextsInGroupsConcurrent 并发分组翻译文本
-func (t *TranslateSubtitle) translateTextsInGroupsConcurrent(texts []string) ([]string, error) {
+// translateTextsInGroupsConcurrent 并发分组翻译文本，返回译文、实际使用的提供商和提示词模板版本
+func (t *TranslateSubtitle) translateTextsInGroupsConcurrent(translatorManager *translator.TranslatorManager, texts []string, targetLang string, glossary []translator.GlossaryTerm, video *model.SavedVideo) ([]string, []string, []string, error) {
 	totalGroups := (len(texts) + t.GroupSize - 1) / t.GroupSize
 	results := make([][]string, totalGroups)
 
@@ -253,9 +242,11 @@ func (t *TranslateSubtitle) translateTextsInGroupsConcurrent(texts []string) ([]
 
 	taskChannel := make(chan translateTask, totalGroups)
 	resultChannel := make(chan struct {
-		groupIndex int
-		result     []string
-		err        error
+		groupIndex    int
+		result        []string
+		provider      string
+		promptVersion string
+		err           error
 	}, totalGroups)
 
 	// 启动工作者
@@ -275,17 +266,20 @@ func (t *TranslateSubtitle) translateTextsInGroupsConcurrent(texts []string) ([]
 				t.App.Logger.Infof("⏳ 工作者 %d 处理第 %d/%d 组 (%d句)",
 					workerID, task.groupIndex+1, totalGroups, len(task.texts))
 
-				// 使用简化的翻译方法
-				translated, err := t.translateGroupSimple(task.texts)
+				translated, provider, promptVersion, err := t.translateGroup(translatorManager, task.texts, targetLang, glossary, video)
 
 				resultChannel <- struct {
-					groupIndex int
-					result     []string
-					err        error
+					groupIndex    int
+					result        []string
+					provider      string
+					promptVersion string
+					err           error
 				}{
-					groupIndex: task.groupIndex,
-					result:     translated,
-					err:        err,
+					groupIndex:    task.groupIndex,
+					result:        translated,
+					provider:      provider,
+					promptVersion: promptVersion,
+					err:           err,
 				}
 			}
 		}(i)
@@ -315,6 +309,8 @@ func (t *TranslateSubtitle) translateTextsInGroupsConcurrent(texts []string) ([]
 
 	// 处理结果
 	var lastErr error
+	usedProviders := map[string]bool{}
+	var promptVersions []string
 	for result := range resultChannel {
 		if result.err != nil {
 			t.App.Logger.Errorf("❌ 第 %d 组翻译失败: %v", result.groupIndex+1, result.err)
@@ -322,10 +318,12 @@ func (t *TranslateSubtitle) translateTextsInGroupsConcurrent(texts []string) ([]
 			continue
 		}
 		results[result.groupIndex] = result.result
+		usedProviders[result.provider] = true
+		promptVersions = append(promptVersions, result.promptVersion)
 	}
 
 	if lastErr != nil {
-		return nil, lastErr
+		return nil, nil, nil, lastErr
 	}
 
 	// 合并结果
@@ -334,222 +332,135 @@ func (t *TranslateSubtitle) translateTextsInGroupsConcurrent(texts []string) ([]
 		allTranslated = append(allTranslated, groupResult...)
 	}
 
-	return allTranslated, nil
-}
-
-// translateGroupSimple 简化的组翻译（无上下文，更快速）
-func (t *TranslateSubtitle) translateGroupSimple(texts []string) ([]string, error) {
-	if len(texts) == 0 {
-		return []string{}, nil
+	providers := make([]string, 0, len(usedProviders))
+	for provider := range usedProviders {
+		providers = append(providers, provider)
 	}
 
-	// 直接组合文本
-	combinedText := strings.Join(texts, "\n###SENTENCE_BREAK###\n")
-
-	// 简化的系统提示
-	systemPrompt := fmt.Sprintf(`你是一个专业的视频字幕翻译专家。将给出的 %d 句英文字幕翻译成中文。
-
-翻译要求：
-1. 自然流畅：使用口语化表达，符合中文字幕习惯
-2. 准确传神：忠实原文含义，保持语气和情感
-3. 简洁明了：字幕需要快速阅读，避免冗长
-4. 数量严格：必须输出 %d 句翻译，不多不少
-5. 分隔符：每句翻译用"###SENTENCE_BREAK###"分隔
-
-输入格式：句子用"###SENTENCE_BREAK###"分隔
-输出格式：只返回中文翻译，用"###SENTENCE_BREAK###"分隔
-
-注意：只返回翻译的中文文本，不要添加序号、解释或其他内容。`, len(texts), len(texts))
+	return allTranslated, uniqueSorted(providers), uniqueSorted(promptVersions), nil
+}
 
-	translatedText, err := t.callDeepSeekAPI(systemPrompt, combinedText)
+// translateGroup 通过翻译管理器翻译一组字幕（失败时自动重试并切换备选提供商），
+// 返回译文、提供商和使用的提示词模板版本
+func (t *TranslateSubtitle) translateGroup(translatorManager *translator.TranslatorManager, texts []string, targetLang string, glossary []translator.GlossaryTerm, video *model.SavedVideo) ([]string, string, string, error) {
+	if len(texts) == 0 {
+		return []string{}, "", "", nil
+	}
+
+	// 批量翻译按行对应，多行字幕先合并为一行
+	flattened := make([]string, len(texts))
+	for i, text := range texts {
+		flattened[i] = strings.Join(strings.Fields(text), " ")
+	}
+
+	// 只注入本组出现的术语；翻译记忆按本组术语版本区分，无关术语变化不会让缓存失效
+	relevant := translator.FilterGlossary(glossary, flattened)
+	promptData := services.PromptDataForVideo(video)
+	ctx := translator.WithUsageScope(stdcontext.Background(), t.StateManager.VideoID, t.Name)
+	result, err := translatorManager.BatchTranslate(ctx, &translator.BatchTranslationRequest{
+		Texts:           flattened,
+		SourceLang:      "auto",
+		TargetLang:      targetLang,
+		TextType:        "subtitle",
+		Glossary:        relevant,
+		GlossaryVersion: translator.GlossaryVersion(relevant),
+		VideoTitle:      promptData.SourceTitle,
+		Channel:         promptData.Channel,
+	})
 	if err != nil {
-		return nil, err
-	}
-
-	translatedSentences := strings.Split(translatedText, "###SENTENCE_BREAK###")
-
-	// 清理和验证
-	for i := range translatedSentences {
-		translatedSentences[i] = strings.TrimSpace(translatedSentences[i])
+		return nil, "", "", err
 	}
 
-	// 确保数量匹配
-	if len(translatedSentences) != len(texts) {
-		t.App.Logger.Warnf("⚠️  翻译结果数量不匹配: 期望%d句，实际%d句，正在修正...", len(texts), len(translatedSentences))
-		for len(translatedSentences) < len(texts) {
-			translatedSentences = append(translatedSentences, "[翻译缺失]")
+	// 翻译管理器已对空译文重试并切换提供商，仍为空时不写占位符，整组失败，避免缺失内容混入字幕
+	translated := make([]string, len(result.Results))
+	var missing []int
+	cached := 0
+	for i, item := range result.Results {
+		if item.Cached {
+			cached++
 		}
-		if len(translatedSentences) > len(texts) {
-			translatedSentences = translatedSentences[:len(texts)]
+		translated[i] = strings.TrimSpace(item.TranslatedText)
+		if translated[i] == "" {
+			missing = append(missing, i+1)
 		}
 	}
-
-	return translatedSentences, nil
-}
-
-// translateTextsInGroups 分组翻译文本（带上下文）- 保留原方法作为备用
-func (t *TranslateSubtitle) translateTextsInGroups(texts []string) ([]string, error) {
-	var translatedTexts []string
-	totalGroups := (len(texts) + t.GroupSize - 1) / t.GroupSize
-
-	for i := 0; i < len(texts); i += t.GroupSize {
-		groupNum := (i / t.GroupSize) + 1
-		end := i + t.GroupSize
-		if end > len(texts) {
-			end = len(texts)
-		}
-
-		currentGroup := texts[i:end]
-
-		// 准备上下文窗口
-		var prevContext, nextContext []string
-		contextSize := 2 // 前后各取2句作为上下文
-
-		// 获取前置上下文
-		if i > 0 {
-			prevStart := i - contextSize
-			if prevStart < 0 {
-				prevStart = 0
-			}
-			prevContext = texts[prevStart:i]
-		}
-
-		// 获取后置上下文
-		if end < len(texts) {
-			nextEnd := end + contextSize
-			if nextEnd > len(texts) {
-				nextEnd = len(texts)
-			}
-			nextContext = texts[end:nextEnd]
-		}
-
-		t.App.Logger.Infof("⏳ 翻译第 %d/%d 组 (上下文: 前%d句, 当前%d句, 后%d句)",
-			groupNum, totalGroups, len(prevContext), len(currentGroup), len(nextContext))
-
-		// 带上下文翻译
-		groupTranslated, err := t.translateGroupWithContext(currentGroup, prevContext, nextContext)
-		if err != nil {
-			return nil, fmt.Errorf("翻译第 %d 组失败: %v", groupNum, err)
-		}
-
-		translatedTexts = append(translatedTexts, groupTranslated...)
-
-		// 移除组间延迟，改为根据需要动态调整
-		// 如果遇到API限制，可以在错误处理中添加重试和延迟
+	if len(missing) > 0 {
+		return nil, "", "", fmt.Errorf("%s 返回的译文缺失 %d 条（组内第 %v 条）", result.Provider, len(missing), missing)
+	}
+	if cached > 0 {
+		t.App.Logger.Infof("♻️  翻译记忆命中 %d/%d 句", cached, len(texts))
 	}
 
-	return translatedTexts, nil
+	return translated, result.Provider, result.PromptVersion, nil
 }
 
-// translateGroupWithContext 带上下文翻译一组文本
-func (t *TranslateSubtitle) translateGroupWithContext(texts []string, prevContext []string, nextContext []string) ([]string, error) {
-	// 构建包含上下文的完整文本
-	var fullTexts []string
-	targetStartIndex := 0
-
-	// 添加前置上下文
-	if len(prevContext) > 0 {
-		fullTexts = append(fullTexts, prevContext...)
-		targetStartIndex = len(fullTexts)
+// loadVideo 查询当前视频记录（用于读取目标语言、频道等设置），查询失败时返回 nil
+func (t *TranslateSubtitle) loadVideo() *model.SavedVideo {
+	if t.DB == nil {
+		return nil
 	}
 
-	// 添加目标翻译文本
-	fullTexts = append(fullTexts, texts...)
-	targetEndIndex := len(fullTexts)
-
-	// 添加后置上下文
-	if len(nextContext) > 0 {
-		fullTexts = append(fullTexts, nextContext...)
+	video, err := services.NewSavedVideoService(t.DB).GetVideoByVideoID(t.StateManager.VideoID)
+	if err != nil {
+		t.App.Logger.Warnf("⚠️  查询视频信息失败，使用全局设置: %v", err)
+		return nil
 	}
+	return video
+}
 
-	combinedText := strings.Join(fullTexts, "\n###SENTENCE_BREAK###\n")
-
-	// 构建系统提示
-	contextInfo := ""
-	if len(prevContext) > 0 || len(nextContext) > 0 {
-		contextInfo = fmt.Sprintf(`
-
-上下文信息：
-- 前置上下文：%d 句（仅供参考，不需要翻译）
-- 目标翻译：%d 句（位于第 %d-%d 句，需要全部翻译）
-- 后置上下文：%d 句（仅供参考，不需要翻译）
-
-请只翻译目标部分（第 %d-%d 句），但要充分考虑前后文的连贯性。`,
-			len(prevContext), len(texts), targetStartIndex+1, targetEndIndex,
-			len(nextContext), targetStartIndex+1, targetEndIndex)
+// loadGlossary 加载视频在目标语言下生效的术语表，加载失败时不使用术语表继续翻译
+func (t *TranslateSubtitle) loadGlossary(video *model.SavedVideo, targetLang string) []translator.GlossaryTerm {
+	if t.DB == nil {
+		return nil
 	}
 
-	systemPrompt := fmt.Sprintf(`你是一个专业的视频字幕翻译专家。我将给你一段连续的英文字幕，其中包含 %d 句需要翻译的内容。%s
-
-翻译要求：
-1. 自然流畅：使用口语化表达，符合中文字幕习惯
-2. 上下文连贯：理解整体语境，确保翻译前后呼应
-3. 准确传神：忠实原文含义，保持语气和情感
-4. 简洁明了：字幕需要快速阅读，避免冗长
-5. 数量严格：必须输出 %d 句翻译，不多不少
-6. 分隔符：每句翻译用"###SENTENCE_BREAK###"分隔
-
-输入格式：句子用"###SENTENCE_BREAK###"分隔
-输出格式：只返回目标部分的中文翻译，用"###SENTENCE_BREAK###"分隔
-
-注意：只返回翻译的中文文本，不要添加序号、解释或其他内容。`, len(texts), contextInfo, len(texts))
-
-	translatedText, err := t.callDeepSeekAPI(systemPrompt, combinedText)
+	glossary, err := services.NewGlossaryService(t.DB).EffectiveTranslatorTerms(video, targetLang)
 	if err != nil {
-		return nil, err
+		t.App.Logger.Warnf("⚠️  加载术语表失败，不使用术语表: %v", err)
+		return nil
 	}
-
-	translatedSentences := strings.Split(translatedText, "###SENTENCE_BREAK###")
-
-	// 清理和验证
-	for i := range translatedSentences {
-		translatedSentences[i] = strings.TrimSpace(translatedSentences[i])
+	if len(glossary) > 0 {
+		t.App.Logger.Infof("📖 已加载 %d 条术语", len(glossary))
 	}
-
-	// 确保数量匹配
-	if len(translatedSentences) != len(texts) {
-		t.App.Logger.Warnf("⚠️  翻译结果数量不匹配: 期望%d句，实际%d句，正在修正...", len(texts), len(translatedSentences))
-		for len(translatedSentences) < len(texts) {
-			translatedSentences = append(translatedSentences, "[翻译缺失]")
-		}
-		if len(translatedSentences) > len(texts) {
-			translatedSentences = translatedSentences[:len(texts)]
-		}
-	}
-
-	return translatedSentences, nil
+	return glossary
 }
 
-// callDeepSeekAPI 调用DeepSeek API（实时获取最新的API Key）
-func (t *TranslateSubtitle) callDeepSeekAPI(systemPrompt, userPrompt string) (string, error) {
-	// 实时从配置中获取最新的API Key
-	currentAPIKey, err := t.getCurrentAPIKey()
-	if err != nil {
-		return "", err
+// checkGlossary 检查最终译文字幕是否遵守术语表，返回 "序号: 术语 → 规定译法" 形式的问题列表
+func (t *TranslateSubtitle) checkGlossary(trackPath string, texts []string, glossary []translator.GlossaryTerm) []string {
+	if len(glossary) == 0 {
+		return nil
 	}
 
-	// 添加调试日志，显示当前使用的API Key（用于验证热更新是否生效）
-	t.App.Logger.Debugf("🔑 当前使用API Key: %s", maskAPIKey(currentAPIKey))
-
-	client := NewDeepSeekClient(currentAPIKey)
-	response, err := client.ChatCompletion(systemPrompt, userPrompt)
+	translatedDoc, err := subtitle.ReadFile(trackPath)
 	if err != nil {
-		return "", fmt.Errorf("调用DeepSeek API失败: %v", err)
+		t.App.Logger.Warnf("⚠️  读取译文字幕失败，跳过术语检查: %v", err)
+		return nil
 	}
+	translated := translatedDoc.Texts()
 
-	return response, nil
+	var violations []string
+	for i := 0; i < len(texts) && i < len(translated); i++ {
+		for _, term := range translator.CheckGlossary(texts[i], translated[i], glossary) {
+			violations = append(violations, fmt.Sprintf("%d: %s → %s", i+1, term.Source, term.ExpectedTarget()))
+		}
+	}
+	return violations
 }
 
 // getTranslationError 将翻译错误转换为用户友好的错误信息
 func (t *TranslateSubtitle) getTranslationError(err error) string {
+	if errors.Is(err, translator.ErrBudgetExceeded) {
+		return fmt.Sprintf("翻译暂停：%v，请提高预算上限或等待预算周期重置后重试", err)
+	}
+
 	errorStr := err.Error()
 
-	if strings.Contains(errorStr, "DeepSeek API Key 未配置") {
-		return "翻译失败：DeepSeek API Key未配置，请在设置中配置API Key"
+	if noTranslatorAvailable(errorStr) {
+		return "翻译失败：没有可用的翻译服务，请在设置中启用并配置翻译提供商（TranslatorConfig）"
 	}
 
 	if strings.Contains(errorStr, "401") || strings.Contains(errorStr, "unauthorized") {
-		return "翻译失败：DeepSeek API Key无效或已过期，请检查API Key设置"
+		return "翻译失败：翻译服务 API Key无效或已过期，请检查API Key设置"
 	}
 
 	if strings.Contains(errorStr, "429") || strings.Contains(errorStr, "rate limit") {
@@ -557,7 +468,7 @@ func (t *TranslateSubtitle) getTranslationError(err error) string {
 	}
 
 	if strings.Contains(errorStr, "insufficient_quota") || strings.Contains(errorStr, "quota") {
-		return "翻译失败：DeepSeek账户余额不足，请充值后重试"
+		return "翻译失败：翻译服务账户余额不足，请充值后重试"
 	}
 
 	if strings.Contains(errorStr, "timeout") || strings.Contains(errorStr, "deadline exceeded") {
@@ -584,27 +495,39 @@ func (t *TranslateSubtitle) getTranslationError(err error) string {
 	return "翻译失败：AI翻译服务暂时不可用，请稍后重试"
 }
 
-// maskAPIKey 隐藏API Key的敏感信息用于日志显示
-func maskAPIKey(apiKey string) string {
-	if apiKey == "" {
-		return ""
+// noTranslatorAvailable 所有提供商都因未启用或配置错误而无法创建
+func noTranslatorAvailable(errorStr string) bool {
+	if !strings.HasPrefix(errorStr, "all translators failed: ") {
+		return false
 	}
-	if len(apiKey) > 10 {
-		return apiKey[:6] + "..." + apiKey[len(apiKey)-4:]
+	for _, part := range strings.Split(strings.TrimPrefix(errorStr, "all translators failed: "), "; ") {
+		if !strings.Contains(part, "failed to get translator") {
+			return false
+		}
 	}
-	return "***"
+	return true
 }
 
 // validateAndOptimizeSubtitles 校验和优化字幕质量
-func (t *TranslateSubtitle) validateAndOptimizeSubtitles(originalPath, translatedPath string) (string, *utils.ValidationResult, error) {
-	// 获取当前API Key用于修复
-	apiKey, err := t.getCurrentAPIKey()
-	if err != nil {
-		return "", nil, fmt.Errorf("无法获取API Key进行校验: %v", err)
+func (t *TranslateSubtitle) validateAndOptimizeSubtitles(originalPath, translatedPath string, glossary []translator.GlossaryTerm, video *model.SavedVideo) (string, *utils.ValidationResult, error) {
+	// 修复问题条目通过 AIServiceManager 调用（按首选服务和故障转移顺序）
+	if t.AIService == nil {
+		return "", nil, services.ErrNoAIService
+	}
+	if len(t.AIService.EnabledProviders()) == 0 {
+		return "", nil, fmt.Errorf("无法进行校验修复: %w", services.ErrNoAIService)
 	}
 
 	// 创建校验器
-	validator := utils.NewSubtitleValidator(t.App.Logger, apiKey)
+	validator := utils.NewSubtitleValidator(t.App.Logger, "")
+	validator.SetChat(func(ctx stdcontext.Context, systemPrompt, userPrompt string) (string, error) {
+		content, _, err := t.AIService.ChatCompletionContext(ctx, systemPrompt, userPrompt)
+		return content, err
+	})
+	validator.SetUsageScope(t.StateManager.VideoID, t.Name)
+	validator.SetGlossary(glossary)
+	promptData := services.PromptDataForVideo(video)
+	validator.SetPromptContext(promptData.SourceTitle, promptData.Channel)
 
 	// 生成优化后的文件路径
 	optimizedPath := filepath.Join(t.StateManager.CurrentDir, "zh_optimized.srt")
@@ -623,3 +546,34 @@ func (t *TranslateSubtitle) validateAndOptimizeSubtitles(originalPath, translate
 	// 没有问题或无法修复，返回空路径
 	return "", result, nil
 }
+
+// resolveTargetLanguages 确定视频的字幕翻译目标语言：视频单独设置优先，其次全局配置，
+// 都未设置时为简体中文。返回的语言代码已规范化，第一个为主语言
+func resolveTargetLanguages(config *types.AppConfig, video *model.SavedVideo) []string {
+	if video != nil && video.TargetLanguages != "" {
+		if languages := subtitle.NormalizeLanguages(strings.Split(video.TargetLanguages, ",")); len(languages) > 0 {
+			return languages
+		}
+	}
+	if config != nil && config.TranslatorConfig != nil {
+		if languages := subtitle.NormalizeLanguages(config.TranslatorConfig.TargetLanguages); len(languages) > 0 {
+			return languages
+		}
+	}
+	return []string{subtitle.LanguageSimplifiedChinese}
+}
+
+// uniqueSorted 去重并排序
+func uniqueSorted(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, value := range values {
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		result = append(result, value)
+	}
+	sort.Strings(result)
+	return result
+}
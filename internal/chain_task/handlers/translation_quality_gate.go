@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	stdcontext "context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/chain_task/base"
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/utils"
+	"gorm.io/gorm"
+)
+
+// QualityGateReport 翻译质量门禁报告，保存到 quality_gate.json，在视频详情中展示
+type QualityGateReport struct {
+	Passed     bool                        `json:"passed"`
+	Reasons    []string                    `json:"reasons,omitempty"`
+	Tracks     []*utils.TranslationQuality `json:"tracks"`
+	Thresholds types.QualityGateConfig     `json:"thresholds"`
+	CheckedAt  time.Time                   `json:"checked_at"`
+}
+
+// TranslationQualityGate 翻译质量门禁：统计各目标语言译文的覆盖率、英文残留和长度比异常，
+// 可选由 LLM 抽样打分。不达标时在 context 中标记 needs_review，任务链结束后视频状态设为 150（待人工审核），
+// 不会进入上传队列
+type TranslationQualityGate struct {
+	base.BaseTask
+	App       *core.AppServer
+	DB        *gorm.DB
+	AIService *services.AIServiceManager // LLM 抽样评分使用的对话服务
+}
+
+func NewTranslationQualityGate(name string, app *core.AppServer, stateManager *manager.StateManager, client *cos.CosClient, db *gorm.DB, aiService *services.AIServiceManager) *TranslationQualityGate {
+	return &TranslationQualityGate{
+		BaseTask: base.BaseTask{
+			Name:         name,
+			StateManager: stateManager,
+			Client:       client,
+		},
+		App:       app,
+		DB:        db,
+		AIService: aiService,
+	}
+}
+
+func (t *TranslationQualityGate) Execute(context map[string]interface{}) bool {
+	cfg := t.App.Config.QualityGateConfig
+	if cfg == nil || !cfg.Enabled {
+		t.App.Logger.Info("翻译质量门禁未启用，跳过")
+		return true
+	}
+
+	// 与翻译步骤使用同一份原文（字幕断句后的 <videoID>.srt）
+	originalPath := t.StateManager.TranslationSourceSRT()
+	if _, err := os.Stat(originalPath); os.IsNotExist(err) {
+		t.App.Logger.Warn("⚠️  原文字幕文件不存在，跳过翻译质量门禁")
+		return true
+	}
+
+	var video *model.SavedVideo
+	if t.DB != nil {
+		if v, err := services.NewSavedVideoService(t.DB).GetVideoByVideoID(t.StateManager.VideoID); err == nil {
+			video = v
+		}
+	}
+	languages := resolveTargetLanguages(t.App.Config, video)
+
+	report := &QualityGateReport{
+		Passed:     true,
+		Thresholds: *cfg,
+		CheckedAt:  time.Now(),
+	}
+
+	validator := utils.NewSubtitleValidator(t.App.Logger, "")
+	validator.SetChat(func(ctx stdcontext.Context, systemPrompt, userPrompt string) (string, error) {
+		content, _, err := t.AIService.ChatCompletionContext(ctx, systemPrompt, userPrompt)
+		return content, err
+	})
+	validator.SetUsageScope(t.StateManager.VideoID, t.Name)
+	for i, language := range languages {
+		trackPath := t.StateManager.TranslatedSRTPath(language)
+		if _, err := os.Stat(trackPath); os.IsNotExist(err) {
+			// 主语言译文缺失说明翻译未完成；其他语言翻译失败时已在翻译步骤记录，不会上传
+			if i == 0 {
+				report.Reasons = append(report.Reasons, fmt.Sprintf("[%s] 译文字幕不存在", language))
+			}
+			continue
+		}
+
+		quality, entries, err := validator.AssessTranslation(originalPath, trackPath, utils.TranslationQualityOptions{
+			Language:       language,
+			MinLengthRatio: cfg.MinLengthRatio,
+			MaxLengthRatio: cfg.MaxLengthRatio,
+		})
+		if err != nil {
+			t.App.Logger.Errorf("❌ 评估译文质量失败 [%s]: %v", language, err)
+			context["error"] = fmt.Sprintf("评估译文质量失败 [%s]: %v", language, err)
+			return false
+		}
+
+		// LLM 抽样打分只针对主语言，控制调用成本
+		if i == 0 && cfg.LLMSample {
+			if sample, err := validator.ScoreTranslationSample(entries, cfg.SampleSize); err != nil {
+				t.App.Logger.Warnf("⚠️ LLM 抽样评分失败，跳过该项检查: %v", err)
+			} else {
+				quality.Sample = sample
+			}
+		}
+
+		report.Tracks = append(report.Tracks, quality)
+		report.Reasons = append(report.Reasons, checkQualityThresholds(quality, cfg)...)
+
+		t.App.Logger.Infof("📏 译文质量 [%s]: 覆盖率 %.1f%%, 英文残留 %.1f%%, 长度比异常 %.1f%%",
+			language, quality.Coverage*100, quality.EnglishRatio*100, quality.LengthAnomalyRatio*100)
+	}
+	report.Passed = len(report.Reasons) == 0
+
+	if err := SaveQualityGateReport(t.StateManager, report); err != nil {
+		t.App.Logger.Warnf("⚠️ 保存翻译质量门禁报告失败: %v", err)
+	}
+
+	context["quality_gate"] = report
+	if !report.Passed {
+		reason := strings.Join(report.Reasons, "; ")
+		context["needs_review"] = true
+		context["needs_review_reason"] = reason
+		t.App.Logger.Warnf("🚧 翻译质量未达标，视频需人工审核后才能上传: %s", reason)
+	} else {
+		delete(context, "needs_review")
+		t.App.Logger.Info("✅ 翻译质量门禁通过")
+	}
+	return true
+}
+
+// checkQualityThresholds 对照门禁阈值检查译文质量，返回未达标的原因
+func checkQualityThresholds(quality *utils.TranslationQuality, cfg *types.QualityGateConfig) []string {
+	var reasons []string
+	if cfg.MinCoverage > 0 && quality.Coverage < cfg.MinCoverage {
+		reasons = append(reasons, fmt.Sprintf("[%s] 覆盖率 %.1f%% 低于 %.1f%%（缺失 %d 条）",
+			quality.Language, quality.Coverage*100, cfg.MinCoverage*100, len(quality.MissingEntries)))
+	}
+	if cfg.MaxEnglishRatio > 0 && quality.EnglishRatio > cfg.MaxEnglishRatio {
+		reasons = append(reasons, fmt.Sprintf("[%s] 英文残留 %.1f%% 超过 %.1f%%（%d 条）",
+			quality.Language, quality.EnglishRatio*100, cfg.MaxEnglishRatio*100, len(quality.EnglishEntries)))
+	}
+	if cfg.MaxLengthAnomalyRatio > 0 && quality.LengthAnomalyRatio > cfg.MaxLengthAnomalyRatio {
+		reasons = append(reasons, fmt.Sprintf("[%s] 长度比异常 %.1f%% 超过 %.1f%%（%d 条）",
+			quality.Language, quality.LengthAnomalyRatio*100, cfg.MaxLengthAnomalyRatio*100, len(quality.LengthAnomalies)))
+	}
+	if quality.Sample != nil && cfg.MinSampleScore > 0 && quality.Sample.Average < cfg.MinSampleScore {
+		reasons = append(reasons, fmt.Sprintf("[%s] 抽样评分 %.1f 低于 %.1f",
+			quality.Language, quality.Sample.Average, cfg.MinSampleScore))
+	}
+	return reasons
+}
+
+// SaveQualityGateReport 保存翻译质量门禁报告
+func SaveQualityGateReport(stateManager *manager.StateManager, report *QualityGateReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(stateManager.QualityGate, data, 0644)
+}
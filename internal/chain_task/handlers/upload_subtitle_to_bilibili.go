@@ -8,6 +8,7 @@ import (
 	"github.com/difyz9/ytb2bili/internal/storage"
 	"github.com/difyz9/bilibili-go-sdk/bilibili"
 	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
 	"os"
 	"path/filepath"
 )
@@ -36,11 +37,14 @@ func (t *UploadSubtitleToBilibili) Execute(context map[string]interface{}) bool
 	t.App.Logger.Info("========================================")
 
 	// 1. 检查是否有BVID（视频已上传成功）
+	savedVideo, err := t.SavedVideoService.GetVideoByVideoID(t.StateManager.VideoID)
+	if err != nil {
+		savedVideo = nil
+	}
 	bvid, exists := context["bili_bvid"].(string)
 	if !exists || bvid == "" {
 		// 尝试从数据库获取BVID
-		savedVideo, err := t.SavedVideoService.GetVideoByVideoID(t.StateManager.VideoID)
-		if err != nil || savedVideo.BiliBVID == "" {
+		if savedVideo == nil || savedVideo.BiliBVID == "" {
 			t.App.Logger.Warn("⚠️  没有找到BVID，跳过字幕上传")
 			return true // 不算失败，只是跳过
 		}
@@ -65,7 +69,7 @@ func (t *UploadSubtitleToBilibili) Execute(context map[string]interface{}) bool
 	}
 
 	// 3. 查找字幕文件
-	subtitleFiles := t.findSubtitleFiles()
+	subtitleFiles := t.findSubtitleFiles(savedVideo)
 	if len(subtitleFiles) == 0 {
 		t.App.Logger.Warn("⚠️  未找到字幕文件，跳过字幕上传")
 		return true // 不算失败，只是跳过
@@ -113,31 +117,58 @@ type SubtitleFileInfo struct {
 	Language string
 }
 
-// findSubtitleFiles 查找字幕文件
-func (t *UploadSubtitleToBilibili) findSubtitleFiles() []SubtitleFileInfo {
+// findSubtitleFiles 按配置的字幕轨道查找要上传的字幕文件
+// 轨道: zh=译文字幕（视频的每个目标语言各一条）, original=原文字幕, bilingual=双语字幕；同一语言代码只上传第一个找到的轨道
+func (t *UploadSubtitleToBilibili) findSubtitleFiles(savedVideo *model.SavedVideo) []SubtitleFileInfo {
 	var subtitleFiles []SubtitleFileInfo
 
-	// 检查常见的字幕文件
-	subtitleFilesToCheck := []struct {
-		filename string
-		language string
-	}{
-		{"zh_optimized.srt", "zh-Hans"}, // 中文简体
-		{"en.srt", "en"},                // 英文
-		//{"zh-cn.srt", "zh-Hans"}, // 中文简体
-		//{"zh-tw.srt", "zh-Hant"}, // 中文繁体
-		//{"ja.srt", "ja"},         // 日文
-		//{"ko.srt", "ko"},         // 韩文
+	tracks := []string{"zh", "original"}
+	originalLanguage := "en"
+	bilingualLanguage := "zh-CN" // B站每个语言代码只保留一条字幕，双语字幕不能占用译文的 zh-Hans
+	if cfg := t.App.Config.SubtitleConfig; cfg != nil {
+		if len(cfg.UploadTracks) > 0 {
+			tracks = cfg.UploadTracks
+		}
+		if cfg.OriginalLanguage != "" {
+			originalLanguage = cfg.OriginalLanguage
+		}
+		if cfg.BilingualLanguage != "" {
+			bilingualLanguage = cfg.BilingualLanguage
+		}
 	}
 
-	for _, item := range subtitleFilesToCheck {
-		fullPath := filepath.Join(t.StateManager.CurrentDir, item.filename)
-		if _, err := os.Stat(fullPath); err == nil {
-			subtitleFiles = append(subtitleFiles, SubtitleFileInfo{
-				Path:     fullPath,
-				Language: item.language,
-			})
-			t.App.Logger.Infof("🎯 找到字幕文件: %s (%s)", item.filename, item.language)
+	usedLanguages := make(map[string]string)
+	for _, track := range tracks {
+		var candidates []SubtitleFileInfo
+		switch track {
+		case "zh":
+			for _, language := range resolveTargetLanguages(t.App.Config, savedVideo) {
+				candidates = append(candidates, SubtitleFileInfo{Path: t.StateManager.TranslatedSRTPath(language), Language: language})
+			}
+		case "original":
+			candidates = append(candidates, SubtitleFileInfo{Path: t.StateManager.OriginalSubtitlePath(), Language: originalLanguage})
+		case "bilingual":
+			candidates = append(candidates, SubtitleFileInfo{Path: t.StateManager.BilingualSRT, Language: bilingualLanguage})
+		default:
+			t.App.Logger.Warnf("⚠️  未知的字幕轨道: %s", track)
+			continue
+		}
+
+		for _, candidate := range candidates {
+			if candidate.Path == "" {
+				continue
+			}
+			if _, err := os.Stat(candidate.Path); err != nil {
+				continue
+			}
+			if previous, ok := usedLanguages[candidate.Language]; ok {
+				t.App.Logger.Warnf("⚠️  字幕轨道 %s 与 %s 使用相同的语言代码 %s，跳过", track, previous, candidate.Language)
+				continue
+			}
+			usedLanguages[candidate.Language] = track
+
+			subtitleFiles = append(subtitleFiles, candidate)
+			t.App.Logger.Infof("🎯 找到字幕文件: %s (%s, %s)", filepath.Base(candidate.Path), track, candidate.Language)
 		}
 	}
 
@@ -14,8 +14,10 @@ import (
 	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
 	"github.com/difyz9/ytb2bili/internal/core"
 	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/internal/core/types"
 	"github.com/difyz9/ytb2bili/internal/storage"
 	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
 	"github.com/difyz9/ytb2bili/pkg/utils"
 )
 
@@ -157,16 +159,41 @@ func (t *UploadToBilibili) Execute(context map[string]interface{}) bool {
 		t.App.Logger.Warnf("⚠️ 无法从数据库获取视频信息: %v", err)
 	}
 
-	// 3. 查找下载的视频文件
-	videoFiles := t.findVideoFiles()
-	if len(videoFiles) == 0 {
-		errMsg := "未找到视频文件"
-		t.App.Logger.Error("❌ " + errMsg)
-		context["error"] = errMsg
+	// 合规检查发现 block 级别的问题时不上传（审核时修改后需重新执行合规检查）
+	if reasons := t.complianceBlockReasons(); len(reasons) > 0 {
+		t.App.Logger.Errorf("🚫 合规检查未通过，不上传: %s", strings.Join(reasons, "; "))
+		context["error"] = "合规检查未通过: " + strings.Join(reasons, "; ")
 		return false
 	}
 
-	videoPath := videoFiles[0] // 使用第一个视频文件
+	// 3. 查找下载的视频文件（启用配音时优先使用配音版视频，其次是启用字幕烧录时烧录后的视频）
+	videoPath := ""
+	if dubbing := t.App.Config.DubbingConfig; dubbing != nil && dubbing.Enabled && dubbing.UseForUpload {
+		if _, err := os.Stat(t.StateManager.DubbedVideoPath); err == nil {
+			videoPath = t.StateManager.DubbedVideoPath
+			t.App.Logger.Info("🎙️ 使用配音版视频")
+		} else {
+			t.App.Logger.Warn("⚠️ 已启用配音，但未找到配音版视频")
+		}
+	}
+	if burnIn := t.App.Config.BurnInConfig; videoPath == "" && burnIn != nil && burnIn.Enabled {
+		if _, err := os.Stat(t.StateManager.OutVideoPath); err == nil {
+			videoPath = t.StateManager.OutVideoPath
+			t.App.Logger.Info("🔥 使用烧录字幕后的视频")
+		} else {
+			t.App.Logger.Warn("⚠️ 已启用字幕烧录，但未找到烧录后的视频，将上传源视频")
+		}
+	}
+	if videoPath == "" {
+		videoFiles := t.findVideoFiles()
+		if len(videoFiles) == 0 {
+			errMsg := "未找到视频文件"
+			t.App.Logger.Error("❌ " + errMsg)
+			context["error"] = errMsg
+			return false
+		}
+		videoPath = videoFiles[0] // 使用第一个视频文件
+	}
 	t.App.Logger.Infof("📹 找到视频文件: %s", filepath.Base(videoPath))
 
 	// 4. 创建上传客户端
@@ -188,7 +215,7 @@ func (t *UploadToBilibili) Execute(context map[string]interface{}) bool {
 
 	// 6. 上传封面 (如果有)
 	coverURL := ""
-	if coverImagePath, ok := context["cover_image_path"].(string); ok && coverImagePath != "" {
+	if coverImagePath := t.coverImagePath(context); coverImagePath != "" {
 		t.App.Logger.Infof("📸 找到封面图片: %s", filepath.Base(coverImagePath))
 		t.App.Logger.Info("⏫ 开始上传封面...")
 		
@@ -280,7 +307,7 @@ func (t *UploadToBilibili) Execute(context map[string]interface{}) bool {
 	return true
 }
 
-// findVideoFiles 查找下载目录中的视频文件
+// findVideoFiles 查找下载目录中的视频文件（不含烧录字幕后的视频和配音版视频）
 func (t *UploadToBilibili) findVideoFiles() []string {
 	var videoFiles []string
 	videoExtensions := []string{".mp4", ".flv", ".mkv", ".webm", ".avi", ".mov"}
@@ -300,6 +327,9 @@ func (t *UploadToBilibili) findVideoFiles() []string {
 		for _, videoExt := range videoExtensions {
 			if ext == videoExt {
 				fullPath := filepath.Join(t.StateManager.CurrentDir, file.Name())
+				if fullPath == t.StateManager.OutVideoPath || fullPath == t.StateManager.DubbedVideoPath {
+					break
+				}
 				videoFiles = append(videoFiles, fullPath)
 				break
 			}
@@ -309,6 +339,27 @@ func (t *UploadToBilibili) findVideoFiles() []string {
 	return videoFiles
 }
 
+// chapterLines 生成章节步骤保存的章节，格式化为简介中的时间点列表，没有章节时返回空字符串。
+// 当前使用的 bilibili-go-sdk 没有提交视频分段章节的接口，章节只写入简介
+func (t *UploadToBilibili) chapterLines() string {
+	list, err := utils.LoadChapters(t.StateManager.Chapters)
+	if err != nil {
+		return ""
+	}
+	return utils.FormatChapterLines(list.Chapters)
+}
+
+// coverImagePath 封面图片：任务链中指定的封面，其次是生成封面步骤保存的 cover.jpg（上传由调度器单独执行时）
+func (t *UploadToBilibili) coverImagePath(context map[string]interface{}) string {
+	if path, ok := context["cover_image_path"].(string); ok && path != "" {
+		return path
+	}
+	if _, err := os.Stat(t.StateManager.ImageCover); err == nil {
+		return t.StateManager.ImageCover
+	}
+	return ""
+}
+
 // buildStudioInfo 构建投稿信息
 func (t *UploadToBilibili) buildStudioInfo(video *bilibili.Video, coverURL string, context map[string]interface{}) *bilibili.Studio {
 	// 默认值
@@ -323,47 +374,15 @@ func (t *UploadToBilibili) buildStudioInfo(video *bilibili.Video, coverURL strin
 	} else {
 		// 此处不再重复调用 fetchAndSaveMetadata，已在 Execute 中处理
 
-		// 清理标题中的标签（#hashtag）
-		cleanTitle := func(title string) string {
-			// 使用正则表达式移除 #标签
-			re := regexp.MustCompile(`\s*#[^\s#]+`)
-			cleaned := re.ReplaceAllString(title, "")
-			// 清理多余的空格
-			cleaned = strings.TrimSpace(cleaned)
-			// 将多个连续空格替换为单个空格
-			re2 := regexp.MustCompile(`\s+`)
-			cleaned = re2.ReplaceAllString(cleaned, " ")
-			return cleaned
-		}
-
 		// 根据配置选择标题来源
 		biliConfig := t.App.Config.BilibiliConfig
-		if biliConfig != nil && biliConfig.CustomTitleTemplate != "" {
-			// 使用自定义标题模板
-			title = biliConfig.CustomTitleTemplate
-			// 清理原标题中的标签
-			cleanedOriginalTitle := cleanTitle(savedVideo.Title)
-			title = strings.ReplaceAll(title, "{original_title}", cleanedOriginalTitle)
-			title = strings.ReplaceAll(title, "{ai_title}", savedVideo.GeneratedTitle)
-			t.App.Logger.Infof("✓ 使用自定义标题模板: %s", title)
-		} else if biliConfig != nil && !biliConfig.UseOriginalTitle {
-			// 配置为使用AI生成标题
-			if savedVideo.GeneratedTitle != "" {
-				title = savedVideo.GeneratedTitle
-				t.App.Logger.Infof("✓ 使用AI生成的标题: %s", title)
-			} else if savedVideo.Title != "" {
-				title = cleanTitle(savedVideo.Title)
-				t.App.Logger.Infof("✓ AI标题不存在，回退使用原始标题（已清理标签）: %s", title)
-			}
-		} else {
-			// 默认使用原始标题（YouTube原标题）
-			if savedVideo.Title != "" {
-				title = cleanTitle(savedVideo.Title)
-				t.App.Logger.Infof("✓ 使用YouTube原始标题（已清理标签）: %s", title)
-			} else if savedVideo.GeneratedTitle != "" {
-				title = savedVideo.GeneratedTitle
-				t.App.Logger.Infof("✓ 原始标题不存在，回退使用AI标题: %s", title)
-			}
+		checker := t.complianceChecker()
+		var source string
+		title, source = uploadTitle(savedVideo, biliConfig, title)
+		t.App.Logger.Infof("✓ %s: %s", source, title)
+		if checker != nil {
+			// 合规检查：替换 replace 级别的违规词，按配置删除外部链接
+			title = checker.Sanitize(services.ComplianceFieldTitle, title)
 		}
 
 		// B站标题长度限制（80个字符）
@@ -375,76 +394,19 @@ func (t *UploadToBilibili) buildStudioInfo(video *bilibili.Video, coverURL strin
 		}
 		t.App.Logger.Infof("📝 标题长度: %d/%d 字符", len([]rune(title)), maxTitleLength)
 
-		// 过滤无效的描述（YouTube的默认描述）
-		isValidDescription := func(desc string) bool {
-			if desc == "" {
-				return false
-			}
-			// 过滤YouTube的默认描述
-			invalidDescriptions := []string{
-				"YouTube",
-				"自动上传的视频",
-				"Uploaded by",
-				"Auto-generated",
-			}
-			for _, invalid := range invalidDescriptions {
-				if strings.Contains(desc, invalid) && len(desc) < 50 {
-					return false
-				}
-			}
-			return true
-		}
+		// 生成章节步骤的章节时间点：自定义模板通过 {chapters} 引用，其他情况放在原视频链接之前
+		chapterLines := t.chapterLines()
+		chapterSuffix := ""
 
-		// 根据配置选择描述来源
-		if biliConfig != nil && biliConfig.CustomDescTemplate != "" {
-			// 使用自定义模板
-			desc = biliConfig.CustomDescTemplate
-			desc = strings.ReplaceAll(desc, "{original_desc}", savedVideo.Description)
-			desc = strings.ReplaceAll(desc, "{ai_desc}", savedVideo.GeneratedDesc)
-			t.App.Logger.Infof("✓ 使用自定义描述模板")
-		} else if biliConfig != nil && biliConfig.UseOriginalDesc {
-			// 配置为使用原始描述
-			if isValidDescription(savedVideo.Description) {
-				desc = savedVideo.Description
-				t.App.Logger.Infof("✓ 使用YouTube原始描述")
-			} else if savedVideo.GeneratedDesc != "" {
-				desc = savedVideo.GeneratedDesc
-				t.App.Logger.Infof("✓ 原始描述无效，回退使用AI描述")
-			} else {
-				desc = ""
-				t.App.Logger.Info("✓ 无有效描述，仅使用原视频链接")
-			}
-		} else {
-			// 默认使用AI生成的描述 + 原视频简介
-			aiIntro := ""
-			originalDesc := ""
-
-			// 获取AI生成的精炼介绍（100字以内）
-			if savedVideo.GeneratedDesc != "" {
-				aiIntro = savedVideo.GeneratedDesc
-				t.App.Logger.Infof("✓ AI生成的精炼介绍: %s", aiIntro)
-			}
-
-			// 获取原视频简介
-			if isValidDescription(savedVideo.Description) {
-				originalDesc = savedVideo.Description
-				t.App.Logger.Infof("✓ 原视频简介长度: %d 字符", len([]rune(originalDesc)))
-			}
-
-			// 拼接描述：AI介绍 + 分隔线 + 原视频简介
-			if aiIntro != "" && originalDesc != "" {
-				desc = fmt.Sprintf("%s\n\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n📄 原视频简介：\n%s", aiIntro, originalDesc)
-				t.App.Logger.Info("✓ 使用AI介绍 + 原视频简介")
-			} else if aiIntro != "" {
-				desc = aiIntro
-				t.App.Logger.Info("✓ 仅使用AI介绍")
-			} else if originalDesc != "" {
-				desc = originalDesc
-				t.App.Logger.Info("✓ 仅使用原视频简介")
-			} else {
-				desc = ""
-				t.App.Logger.Info("✓ 无有效描述，仅使用原视频链接")
-			}
+		// 根据配置选择描述来源（审核时修改过的简介优先）
+		var useDescTemplate bool
+		desc, useDescTemplate, source = uploadDescription(savedVideo, biliConfig, chapterLines)
+		t.App.Logger.Infof("✓ %s", source)
+		if checker != nil {
+			desc = checker.Sanitize(services.ComplianceFieldDescription, desc)
+		}
+		if chapterLines != "" && !useDescTemplate {
+			chapterSuffix = "\n\n📑 章节：\n" + chapterLines
 		}
 
 		// 使用AI生成的标签
@@ -452,6 +414,9 @@ func (t *UploadToBilibili) buildStudioInfo(video *bilibili.Video, coverURL strin
 			tags = savedVideo.GeneratedTags
 			t.App.Logger.Infof("✓ 使用数据库中AI生成的标签: %s", tags)
 		}
+		if checker != nil {
+			tags = checker.Sanitize(services.ComplianceFieldTags, tags)
+		}
 
 		// B站简介字数限制（2000字）
 		const maxDescLength = 2000
@@ -461,6 +426,11 @@ func (t *UploadToBilibili) buildStudioInfo(video *bilibili.Video, coverURL strin
 		if savedVideo.URL != "" {
 			linkSuffix = fmt.Sprintf("\n\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n📺 原视频链接：%s\n🔄 本视频为转载内容，仅供学习交流使用", savedVideo.URL)
 		}
+		// 章节与链接一样预留长度，不会被截断
+		if chapterSuffix != "" {
+			linkSuffix = chapterSuffix + linkSuffix
+			t.App.Logger.Info("✓ 已添加章节到描述")
+		}
 
 		// 计算链接后缀的长度（字符数）
 		linkSuffixLength := len([]rune(linkSuffix))
@@ -557,6 +527,12 @@ func (t *UploadToBilibili) buildStudioInfo(video *bilibili.Video, coverURL strin
 		upCloseReward = t.App.Config.BilibiliConfig.UpCloseReward
 	}
 
+	// 选择分区步骤为视频确定的分区优先于全局配置
+	if savedVideo != nil && savedVideo.Tid > 0 {
+		tid = savedVideo.Tid
+		t.App.Logger.Infof("✓ 使用自动选择的分区: %d（%s）", tid, savedVideo.TidReason)
+	}
+
 	// 如果是转载且没有提供来源，使用视频URL作为来源
 	if copyright == 2 && source == "" {
 		if savedVideo != nil {
@@ -697,3 +673,131 @@ func (t *UploadToBilibili) getUserFriendlyError(err error, operation string) str
 	// 如果是未知错误，返回简化的错误信息
 	return fmt.Sprintf("%s失败：发生未知错误，请重试或联系技术支持", operation)
 }
+
+// uploadTitle 按配置选择投稿标题：审核修改 > 自定义模板 > AI标题或原标题（互为回退），都没有时使用 fallback。
+// 返回标题和来源说明
+func uploadTitle(savedVideo *model.SavedVideo, biliConfig *types.BilibiliConfig, fallback string) (string, string) {
+	if savedVideo.ReviewedTitle != "" {
+		// 审核时修改过的标题优先
+		return savedVideo.ReviewedTitle, "使用审核时修改的标题"
+	}
+	if biliConfig != nil && biliConfig.CustomTitleTemplate != "" {
+		// 使用自定义标题模板（原标题中的标签已清理）
+		title := strings.ReplaceAll(biliConfig.CustomTitleTemplate, "{original_title}", cleanTitleTags(savedVideo.Title))
+		title = strings.ReplaceAll(title, "{ai_title}", savedVideo.GeneratedTitle)
+		return title, "使用自定义标题模板"
+	}
+	if biliConfig != nil && !biliConfig.UseOriginalTitle {
+		// 配置为使用AI生成标题
+		if savedVideo.GeneratedTitle != "" {
+			return savedVideo.GeneratedTitle, "使用AI生成的标题"
+		}
+		if savedVideo.Title != "" {
+			return cleanTitleTags(savedVideo.Title), "AI标题不存在，回退使用原始标题（已清理标签）"
+		}
+		return fallback, "没有可用的标题，使用视频ID"
+	}
+	// 默认使用原始标题（YouTube原标题）
+	if savedVideo.Title != "" {
+		return cleanTitleTags(savedVideo.Title), "使用YouTube原始标题（已清理标签）"
+	}
+	if savedVideo.GeneratedTitle != "" {
+		return savedVideo.GeneratedTitle, "原始标题不存在，回退使用AI标题"
+	}
+	return fallback, "没有可用的标题，使用视频ID"
+}
+
+// uploadDescription 按配置选择投稿简介（不含章节和原视频链接后缀）：审核修改 > 自定义模板 > 原始描述或AI描述。
+// useTemplate 表示使用了自定义模板（模板通过 {chapters} 引用章节），source 为来源说明
+func uploadDescription(savedVideo *model.SavedVideo, biliConfig *types.BilibiliConfig, chapterLines string) (desc string, useTemplate bool, source string) {
+	if savedVideo.ReviewedDesc != "" {
+		return savedVideo.ReviewedDesc, false, "使用审核时修改的描述"
+	}
+	if biliConfig != nil && biliConfig.CustomDescTemplate != "" {
+		// 使用自定义模板
+		desc = biliConfig.CustomDescTemplate
+		desc = strings.ReplaceAll(desc, "{original_desc}", savedVideo.Description)
+		desc = strings.ReplaceAll(desc, "{ai_desc}", savedVideo.GeneratedDesc)
+		desc = strings.ReplaceAll(desc, "{chapters}", chapterLines)
+		return desc, true, "使用自定义描述模板"
+	}
+	if biliConfig != nil && biliConfig.UseOriginalDesc {
+		// 配置为使用原始描述
+		if isValidDescription(savedVideo.Description) {
+			return savedVideo.Description, false, "使用YouTube原始描述"
+		}
+		if savedVideo.GeneratedDesc != "" {
+			return savedVideo.GeneratedDesc, false, "原始描述无效，回退使用AI描述"
+		}
+		return "", false, "无有效描述，仅使用原视频链接"
+	}
+
+	// 默认使用AI生成的精炼介绍 + 原视频简介
+	aiIntro := savedVideo.GeneratedDesc
+	originalDesc := ""
+	if isValidDescription(savedVideo.Description) {
+		originalDesc = savedVideo.Description
+	}
+	switch {
+	case aiIntro != "" && originalDesc != "":
+		// 拼接描述：AI介绍 + 分隔线 + 原视频简介
+		return fmt.Sprintf("%s\n\n━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n📄 原视频简介：\n%s", aiIntro, originalDesc), false, "使用AI介绍 + 原视频简介"
+	case aiIntro != "":
+		return aiIntro, false, "仅使用AI介绍"
+	case originalDesc != "":
+		return originalDesc, false, "仅使用原视频简介"
+	default:
+		return "", false, "无有效描述，仅使用原视频链接"
+	}
+}
+
+// cleanTitleTags 清理标题中的标签（#hashtag）和多余的空格
+func cleanTitleTags(title string) string {
+	cleaned := regexp.MustCompile(`\s*#[^\s#]+`).ReplaceAllString(title, "")
+	return regexp.MustCompile(`\s+`).ReplaceAllString(strings.TrimSpace(cleaned), " ")
+}
+
+// isValidDescription 过滤无效的描述（YouTube的默认描述）
+func isValidDescription(desc string) bool {
+	if desc == "" {
+		return false
+	}
+	invalidDescriptions := []string{
+		"YouTube",
+		"自动上传的视频",
+		"Uploaded by",
+		"Auto-generated",
+	}
+	for _, invalid := range invalidDescriptions {
+		if strings.Contains(desc, invalid) && len(desc) < 50 {
+			return false
+		}
+	}
+	return true
+}
+
+// complianceChecker 启用合规检查时返回检查器，违规词表无效时返回 nil（合规检查步骤会报错）
+func (t *UploadToBilibili) complianceChecker() *services.ComplianceChecker {
+	cfg := t.App.Config.ComplianceConfig
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	checker, err := services.NewComplianceChecker(cfg)
+	if err != nil {
+		t.App.Logger.Warnf("⚠️ %v，上传时不做合规替换", err)
+		return nil
+	}
+	return checker
+}
+
+// complianceBlockReasons 启用合规检查时读取检查报告，返回 block 级别问题的说明（未检查时返回空）
+func (t *UploadToBilibili) complianceBlockReasons() []string {
+	if cfg := t.App.Config.ComplianceConfig; cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	report, err := services.LoadComplianceReport(t.StateManager.Compliance)
+	if err != nil || report.Passed {
+		return nil
+	}
+	return report.BlockReasons()
+}
@@ -0,0 +1,133 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+	"github.com/difyz9/ytb2bili/pkg/utils"
+)
+
+// frameIndex frames.json 的内容：采样参数相同时直接复用已采样的关键帧
+type frameIndex struct {
+	Video   string                   `json:"video"`
+	Options utils.FrameSampleOptions `json:"options"`
+	Frames  []utils.SampledFrame     `json:"frames"`
+}
+
+// VideoFrames 采样得到的关键帧图片和对应时间点的字幕摘录
+type VideoFrames struct {
+	Images   []services.ImageInput
+	Excerpts string // 每行一条：[时间点] 字幕文本
+}
+
+// sampleVideoFrames 从视频中采样 count 个关键帧（缓存在 FramesDir 中），并摘录每帧前后的字幕
+func sampleVideoFrames(stateManager *manager.StateManager, cfg *types.FrameSamplerConfig, videoPath, subtitlePath string, count int) (*VideoFrames, error) {
+	opts := utils.FrameSampleOptions{Count: count}
+	excerptSeconds := 10
+	if cfg != nil {
+		opts.Mode = cfg.Mode
+		opts.SceneThreshold = cfg.SceneThreshold
+		opts.MaxWidth = cfg.MaxWidth
+		if cfg.ExcerptSeconds > 0 {
+			excerptSeconds = cfg.ExcerptSeconds
+		}
+	}
+
+	frames, err := loadSampledFrames(stateManager, videoPath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &VideoFrames{Images: make([]services.ImageInput, 0, len(frames))}
+	for i, frame := range frames {
+		data, err := os.ReadFile(frame.Path)
+		if err != nil {
+			return nil, fmt.Errorf("读取关键帧失败: %v", err)
+		}
+		result.Images = append(result.Images, services.ImageInput{
+			Label:    fmt.Sprintf("第%d帧 [%s]", i+1, formatClock(frame.Timestamp)),
+			MIMEType: "image/jpeg",
+			Data:     data,
+		})
+	}
+
+	if subtitlePath != "" {
+		if content, err := os.ReadFile(subtitlePath); err == nil {
+			if doc, err := subtitle.ParseSRT(string(content)); err == nil {
+				result.Excerpts = subtitleExcerpts(doc, frames, int64(excerptSeconds)*1000)
+			}
+		}
+	}
+	return result, nil
+}
+
+// loadSampledFrames 读取 frames.json 中的采样结果，视频或采样参数变化、图片缺失时重新采样
+func loadSampledFrames(stateManager *manager.StateManager, videoPath string, opts utils.FrameSampleOptions) ([]utils.SampledFrame, error) {
+	indexPath := filepath.Join(stateManager.FramesDir, "frames.json")
+	if data, err := os.ReadFile(indexPath); err == nil {
+		var index frameIndex
+		if json.Unmarshal(data, &index) == nil && index.Video == filepath.Base(videoPath) && index.Options == opts && framesExist(index.Frames) {
+			return index.Frames, nil
+		}
+	}
+
+	frames, err := utils.SampleFrames(videoPath, stateManager.FramesDir, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.MarshalIndent(frameIndex{Video: filepath.Base(videoPath), Options: opts, Frames: frames}, "", "  ")
+	if err == nil {
+		_ = os.WriteFile(indexPath, data, 0644)
+	}
+	return frames, nil
+}
+
+// framesExist 检查采样的图片是否都存在
+func framesExist(frames []utils.SampledFrame) bool {
+	if len(frames) == 0 {
+		return false
+	}
+	for _, frame := range frames {
+		if _, err := os.Stat(frame.Path); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// subtitleExcerpts 摘录每个关键帧前后 window 毫秒内的字幕，相邻关键帧重叠的字幕只保留一次
+func subtitleExcerpts(doc *subtitle.Document, frames []utils.SampledFrame, window int64) string {
+	var builder strings.Builder
+	used := make(map[int]bool)
+	for _, frame := range frames {
+		at := int64(frame.Timestamp * 1000)
+		var texts []string
+		for i, cue := range doc.Cues {
+			if cue.End < at-window || cue.Start > at+window || used[i] {
+				continue
+			}
+			used[i] = true
+			if text := strings.TrimSpace(strings.ReplaceAll(cue.Text, "\n", " ")); text != "" {
+				texts = append(texts, text)
+			}
+		}
+		if len(texts) > 0 {
+			fmt.Fprintf(&builder, "[%s] %s\n", formatClock(frame.Timestamp), strings.Join(texts, " "))
+		}
+	}
+	return strings.TrimSpace(builder.String())
+}
+
+// formatClock 将秒数格式化为 HH:MM:SS
+func formatClock(seconds float64) string {
+	total := int(seconds)
+	return fmt.Sprintf("%02d:%02d:%02d", total/3600, total%3600/60, total%60)
+}
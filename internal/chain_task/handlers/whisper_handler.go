@@ -10,6 +10,7 @@ import (
 	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
 	"github.com/difyz9/ytb2bili/internal/core"
 	"github.com/difyz9/ytb2bili/pkg/cos"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
 	whisper "github.com/ggerganov/whisper.cpp/bindings/go/pkg/whisper"
 	"gorm.io/gorm"
 )
@@ -45,35 +46,35 @@ func NewWhisperHandler(name string, app *core.AppServer, stateManager *manager.S
 }
 
 func (h *WhisperHandler) Execute(context map[string]interface{}) bool {
-	fmt.Println("开始使用 Whisper 转录音频")
+	h.App.Logger.Info("开始使用 Whisper 转录音频")
 	
 	// 检查 WAV 音频文件是否存在
 	if _, err := os.Stat(h.StateManager.OriginalWAV); os.IsNotExist(err) {
-		fmt.Printf("错误: WAV 音频文件不存在: %s\n", h.StateManager.OriginalWAV)
+		h.App.Logger.Errorf("❌ WAV 音频文件不存在: %s", h.StateManager.OriginalWAV)
 		context["error"] = fmt.Sprintf("WAV 音频文件不存在: %s", h.StateManager.OriginalWAV)
 		return false
 	}
 	
 	// 检查模型文件是否存在
 	if _, err := os.Stat(h.ModelPath); os.IsNotExist(err) {
-		fmt.Printf("错误: Whisper 模型文件不存在: %s\n", h.ModelPath)
+		h.App.Logger.Errorf("❌ Whisper 模型文件不存在: %s", h.ModelPath)
 		context["error"] = fmt.Sprintf("Whisper 模型文件不存在: %s", h.ModelPath)
 		return false
 	}
 	
-	fmt.Printf("📝 使用 Whisper 转录: %s\n", h.StateManager.OriginalWAV)
-	fmt.Printf("   模型: %s\n", h.ModelPath)
-	fmt.Printf("   语言: %s\n", h.Language)
-	fmt.Printf("   线程: %d\n", h.Threads)
+	h.App.Logger.Infof("📝 使用 Whisper 转录: %s", h.StateManager.OriginalWAV)
+	h.App.Logger.Infof("   模型: %s", h.ModelPath)
+	h.App.Logger.Infof("   语言: %s", h.Language)
+	h.App.Logger.Infof("   线程: %d", h.Threads)
 	
 	// 执行转录，生成 SRT 字幕文件
 	if err := h.transcribe(h.ModelPath, h.StateManager.OriginalWAV, h.Language, h.Threads, true, h.StateManager.OriginalSRT); err != nil {
-		fmt.Printf("❌ Whisper 转录失败: %v\n", err)
+		h.App.Logger.Errorf("❌ Whisper 转录失败: %v", err)
 		context["error"] = fmt.Sprintf("Whisper 转录失败: %v", err)
 		return false
 	}
 	
-	fmt.Printf("✅ Whisper 转录完成，字幕文件保存至: %s\n", h.StateManager.OriginalSRT)
+	h.App.Logger.Infof("✅ Whisper 转录完成，字幕文件保存至: %s", h.StateManager.OriginalSRT)
 	context["subtitle_path"] = h.StateManager.OriginalSRT
 	return true
 }
@@ -117,13 +118,6 @@ func (h *WhisperHandler) transcribe(modelPath, wavPath, language string, threads
 		return fmt.Errorf("处理音频失败: %v", err)
 	}
 
-	// 创建输出文件
-	outFile, err := os.Create(outputPath)
-	if err != nil {
-		return fmt.Errorf("创建输出文件失败: %v", err)
-	}
-	defer outFile.Close()
-
 	// 收集所有片段
 	var segments []whisper.Segment
 	for {
@@ -136,42 +130,36 @@ func (h *WhisperHandler) transcribe(modelPath, wavPath, language string, threads
 
 	// 根据格式输出
 	if outputSRT {
-		// 输出SRT格式
-		for i, segment := range segments {
-			// SRT序号（从1开始）
-			fmt.Fprintf(outFile, "%d\n", i+1)
-
-			// SRT时间格式: HH:MM:SS,mmm --> HH:MM:SS,mmm
-			fmt.Fprintf(outFile, "%s --> %s\n",
-				formatSRTTime(segment.Start),
-				formatSRTTime(segment.End))
-
-			// 字幕文本
-			fmt.Fprintf(outFile, "%s\n\n", strings.TrimSpace(segment.Text))
-
-			// 同时输出到控制台
-			fmt.Printf("[%6s --> %6s]  %s\n",
-				segment.Start.Truncate(time.Millisecond),
-				segment.End.Truncate(time.Millisecond),
-				segment.Text)
+		// 通过统一字幕模型写出 SRT
+		docLanguage := language
+		if docLanguage == "auto" {
+			docLanguage = ""
 		}
-	} else {
-		// 输出纯文本格式
+		doc := subtitle.NewDocument(docLanguage)
 		for _, segment := range segments {
-			// 带时间戳的文本
-			fmt.Fprintf(outFile, "[%s --> %s]  %s\n",
-				segment.Start.Truncate(time.Millisecond),
-				segment.End.Truncate(time.Millisecond),
-				segment.Text)
-
-			// 同时输出到控制台
-			fmt.Printf("[%6s --> %6s]  %s\n",
-				segment.Start.Truncate(time.Millisecond),
-				segment.End.Truncate(time.Millisecond),
-				segment.Text)
+			text := strings.TrimSpace(segment.Text)
+			if text == "" {
+				continue
+			}
+			doc.Add(segment.Start.Milliseconds(), segment.End.Milliseconds(), text)
 		}
+		if err := subtitle.WriteFileAs(outputPath, doc, subtitle.FormatSRT); err != nil {
+			return fmt.Errorf("写入字幕文件失败: %v", err)
+		}
+		return nil
 	}
 
+	// 输出带时间戳的纯文本格式
+	var builder strings.Builder
+	for _, segment := range segments {
+		fmt.Fprintf(&builder, "[%s --> %s]  %s\n",
+			segment.Start.Truncate(time.Millisecond),
+			segment.End.Truncate(time.Millisecond),
+			segment.Text)
+	}
+	if err := os.WriteFile(outputPath, []byte(builder.String()), 0644); err != nil {
+		return fmt.Errorf("写入输出文件失败: %v", err)
+	}
 	return nil
 }
 
@@ -202,13 +190,3 @@ func readWAVFile(wavPath string) ([]float32, error) {
 
 	return samples, nil
 }
-
-// formatSRTTime 格式化时间为SRT格式 (HH:MM:SS,mmm)
-func formatSRTTime(d time.Duration) string {
-	hours := int(d.Hours())
-	minutes := int(d.Minutes()) % 60
-	seconds := int(d.Seconds()) % 60
-	milliseconds := int(d.Milliseconds()) % 1000
-
-	return fmt.Sprintf("%02d:%02d:%02d,%03d", hours, minutes, seconds, milliseconds)
-}
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/difyz9/ytb2bili/internal/core/models"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
 )
 
 // StateManager 任务状态管理器
@@ -21,18 +22,33 @@ type StateManager struct {
 	InputVideoPath  string
 	NoviceVideoPath string
 	OutVideoPath    string
+	DubbedVideoPath string // 配音版视频
 	ImageCover      string
 	OriginalMP3     string
 	OriginalWAV     string // WAV音频文件（用于Whisper）
-	TranslateMP3    string
+	TranslateMP3    string // 合成的配音音轨
 	OriginalJSON    string
 	TranslateJSON   string
 	OriginalSRT     string
+	RawSRT          string // 重新断句前的原始字幕
 	M3u8FileName    string
 	M3u8FileDir     string
 	TranslateSRT    string
 	TranslateVtt    string
 	TranslateTXT    string
+	BilingualSRT    string // 双语字幕（中文在上，原文在下）
+	BilingualASS    string // 双语 ASS 字幕
+	BurnInASS       string // 烧录用的 ASS 字幕
+	SpeechAnalysis  string // 语音检测结果（JSON）
+	SubtitleQA      string // 字幕质检报告（JSON）
+	QualityGate     string // 翻译质量门禁报告（JSON）
+	PromptVersions  string // 各产物使用的提示词模板版本（JSON）
+	FramesDir       string // 关键帧采样目录（图片和 frames.json）
+	CoversDir       string // 候选封面目录（图片和 covers.json）
+	DubbingDir      string // 配音目录（逐条合成的语音，按文本缓存）
+	SourceChapters  string // 来源视频自带的章节（JSON，下载时保存）
+	Chapters        string // 生成章节步骤的结果（JSON）
+	Compliance      string // 合规检查报告（JSON）
 	// 目录路径
 	AudioDir       string
 	SaveUrlService *services.TbVideoService
@@ -55,21 +71,37 @@ func NewStateManager(Id uint, videoID, projectRoot string, createTim time.Time)
 	//os.MkdirAll(m8u3Dir, os.ModePerm)
 
 	return &StateManager{
-		Id:             Id,
-		VideoID:        videoID,
-		ProjectRoot:    projectRoot,
-		CurrentDir:     currentDir,
-		InputVideoPath: filepath.Join(currentDir, videoID+".mp4"),
-		OutVideoPath:   filepath.Join(currentDir, videoID+"out.mp4"),
-		OriginalWAV:    filepath.Join(currentDir, videoID+".wav"),
-		OriginalMP3:    filepath.Join(currentDir, videoID+".mp3"),
-		ImageCover:     filepath.Join(currentDir, "cover.jpg"),
-		OriginalSRT:    filepath.Join(currentDir, "en.srt"),
-		OriginalJSON:   filepath.Join(currentDir, "en.json"),
-		TranslateJSON:  filepath.Join(currentDir, "zh.json"),
-		TranslateSRT:   filepath.Join(currentDir, "zh.srt"),
-		TranslateVtt:   filepath.Join(currentDir, "zh.vtt"),
-		TranslateTXT:   filepath.Join(currentDir, videoID+"_trans.txt"),
+		Id:              Id,
+		VideoID:         videoID,
+		ProjectRoot:     projectRoot,
+		CurrentDir:      currentDir,
+		InputVideoPath:  filepath.Join(currentDir, videoID+".mp4"),
+		OutVideoPath:    filepath.Join(currentDir, videoID+"out.mp4"),
+		DubbedVideoPath: filepath.Join(currentDir, videoID+"_dubbed.mp4"),
+		OriginalWAV:     filepath.Join(currentDir, videoID+".wav"),
+		OriginalMP3:     filepath.Join(currentDir, videoID+".mp3"),
+		TranslateMP3:    filepath.Join(currentDir, videoID+"_dub.mp3"),
+		ImageCover:      filepath.Join(currentDir, "cover.jpg"),
+		OriginalSRT:     filepath.Join(currentDir, "en.srt"),
+		RawSRT:          filepath.Join(currentDir, videoID+".raw.srt"),
+		OriginalJSON:    filepath.Join(currentDir, "en.json"),
+		TranslateJSON:   filepath.Join(currentDir, "zh.json"),
+		TranslateSRT:    filepath.Join(currentDir, "zh.srt"),
+		TranslateVtt:    filepath.Join(currentDir, "zh.vtt"),
+		TranslateTXT:    filepath.Join(currentDir, videoID+"_trans.txt"),
+		BilingualSRT:    filepath.Join(currentDir, "bilingual.srt"),
+		BilingualASS:    filepath.Join(currentDir, "bilingual.ass"),
+		BurnInASS:       filepath.Join(currentDir, "burnin.ass"),
+		SpeechAnalysis:  filepath.Join(currentDir, "speech_analysis.json"),
+		SubtitleQA:      filepath.Join(currentDir, "subtitle_qa.json"),
+		QualityGate:     filepath.Join(currentDir, "quality_gate.json"),
+		PromptVersions:  filepath.Join(currentDir, "prompt_versions.json"),
+		FramesDir:       filepath.Join(currentDir, "frames"),
+		CoversDir:       filepath.Join(currentDir, "covers"),
+		DubbingDir:      filepath.Join(currentDir, "dubbing"),
+		SourceChapters:  filepath.Join(currentDir, "source_chapters.json"),
+		Chapters:        filepath.Join(currentDir, "chapters.json"),
+		Compliance:      filepath.Join(currentDir, "compliance.json"),
 		//AudioDir:       audioDir,
 		//M3u8FileDir:    m8u3Dir,
 		//M3u8FileName:   filepath.Join(m8u3Dir, "output.m3u8"),
@@ -101,6 +133,31 @@ func (s *StateManager) UpdateTBVideo(item *models.TbVideo) error {
 	return nil
 }
 
+// OriginalSubtitlePath 返回原文字幕路径：优先 en.srt，其次 <videoID>.srt，都不存在时返回空字符串
+func (s *StateManager) OriginalSubtitlePath() string {
+	for _, path := range []string{s.OriginalSRT, filepath.Join(s.CurrentDir, s.VideoID+".srt")} {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return ""
+}
+
+// TranslationSourceSRT 返回翻译使用的原文字幕路径 <videoID>.srt（字幕断句后的结果）
+func (s *StateManager) TranslationSourceSRT() string {
+	return filepath.Join(s.CurrentDir, s.VideoID+".srt")
+}
+
+// TranslatedSRTPath 返回指定目标语言的译文字幕路径：简体中文沿用 zh.srt，
+// 其他语言为 translated.<语言代码>.srt（避免与原文 en.srt 冲突）
+func (s *StateManager) TranslatedSRTPath(language string) string {
+	language = subtitle.NormalizeLanguage(language)
+	if language == "" || language == subtitle.LanguageSimplifiedChinese {
+		return s.TranslateSRT
+	}
+	return filepath.Join(s.CurrentDir, "translated."+language+".srt")
+}
+
 // GetCurrentDateYYYYMMDD 返回当前日期的yyyymmdd格式字符串
 func GetCurrentDateYYYYMMDD(time2 time.Time) string {
 	return time2.Format("2006-01-02")
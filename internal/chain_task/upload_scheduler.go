@@ -205,6 +205,14 @@ func (s *UploadScheduler) executeUploadTask(videoID, taskName string) error {
 	// 创建状态管理器
 	stateManager := manager.NewStateManager(savedVideo.ID, savedVideo.VideoID, currentDir, savedVideo.CreatedAt)
 
+	// 无语音的视频没有字幕可上传，直接跳过
+	if taskName == "上传字幕到Bilibili" {
+		if reason := handlers.NoSpeechSkipReason(stateManager); reason != "" {
+			s.logger.Infof("⏭️ 跳过字幕上传 (VideoID: %s): %s", videoID, reason)
+			return s.TaskStepService.SkipTaskStep(videoID, taskName, reason)
+		}
+	}
+
 	// 更新步骤状态为运行中
 	if err := s.TaskStepService.UpdateTaskStepStatus(videoID, taskName, "running"); err != nil {
 		s.logger.Errorf("更新任务步骤状态失败: %v", err)
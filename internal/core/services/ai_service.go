@@ -2,7 +2,10 @@ package services
 
 import (
 	"bytes"
+	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,6 +14,8 @@ import (
 	"time"
 
 	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/ratelimit"
+	"github.com/difyz9/ytb2bili/pkg/translator"
 	"go.uber.org/zap"
 )
 
@@ -23,6 +28,19 @@ const (
 	AIProviderGemini           AIProvider = "gemini"            // Gemini（原生）
 )
 
+// defaultProviderOrder 未设置首选服务时的调用顺序
+var defaultProviderOrder = []AIProvider{
+	AIProviderOpenAICompatible,
+	AIProviderDeepSeek,
+	AIProviderGemini,
+}
+
+// geminiOpenAIEndpoint Gemini 的 OpenAI 兼容对话接口
+const geminiOpenAIEndpoint = "https://generativelanguage.googleapis.com/v1beta/openai/chat/completions"
+
+// ErrNoAIService 没有启用任何AI服务
+var ErrNoAIService = errors.New("没有可用的AI服务，请先配置OpenAI兼容API、DeepSeek或Gemini")
+
 // AIServiceStatus AI服务状态
 type AIServiceStatus struct {
 	Provider    AIProvider `json:"provider"`
@@ -142,62 +160,47 @@ func (m *AIServiceManager) RefreshConfig(config *types.AppConfig) {
 // GetPreferredProvider 获取首选的AI服务提供商
 // 优先使用用户选择的首选服务，如果未设置则按默认优先级
 func (m *AIServiceManager) GetPreferredProvider() (AIProvider, error) {
-	m.mu.RLock()
-	defer m.mu.RUnlock()
+	providers := m.EnabledProviders()
+	if len(providers) == 0 {
+		return "", ErrNoAIService
+	}
+	return providers[0], nil
+}
 
-	// 1. 首先检查用户选择的首选服务
-	if m.config.PrimaryAIService != "" {
-		provider := AIProvider(m.config.PrimaryAIService)
-		if status, ok := m.statusMap[provider]; ok && status.Enabled {
+// GetAvailableProvider 获取可用的AI服务提供商（带故障转移）
+// 如果首选服务不可用（熔断中），自动切换到备选服务
+func (m *AIServiceManager) GetAvailableProvider() (AIProvider, error) {
+	providers := m.EnabledProviders()
+	for _, provider := range providers {
+		if available, _ := ratelimit.Available(rateLimitKey(provider)); available {
 			return provider, nil
 		}
-		// 用户选择的服务未启用，继续查找其他可用服务
-	}
-
-	// 2. 按默认优先级查找：OpenAI兼容API > DeepSeek > Gemini
-	if status := m.statusMap[AIProviderOpenAICompatible]; status.Enabled {
-		return AIProviderOpenAICompatible, nil
-	}
-
-	if status := m.statusMap[AIProviderDeepSeek]; status.Enabled {
-		return AIProviderDeepSeek, nil
 	}
 
-	if status := m.statusMap[AIProviderGemini]; status.Enabled {
-		return AIProviderGemini, nil
+	// 都在熔断中时返回首选服务
+	if len(providers) > 0 {
+		return providers[0], nil
 	}
-
-	return "", fmt.Errorf("没有可用的AI服务，请先配置AI服务")
+	return "", ErrNoAIService
 }
 
-// GetAvailableProvider 获取可用的AI服务提供商（带故障转移）
-// 如果首选服务不可用，自动切换到备选服务
-func (m *AIServiceManager) GetAvailableProvider() (AIProvider, error) {
+// EnabledProviders 按调用顺序返回已启用的服务：首选服务（PrimaryAIService）在前，
+// 其余按 OpenAI兼容API > DeepSeek > Gemini。每次调用读取最新配置，配置修改后立即生效
+func (m *AIServiceManager) EnabledProviders() []AIProvider {
 	m.mu.RLock()
-	defer m.mu.RUnlock()
+	primary := AIProvider(m.config.PrimaryAIService)
+	m.mu.RUnlock()
 
-	providers := []AIProvider{
-		AIProviderOpenAICompatible,
-		AIProviderDeepSeek,
-		AIProviderGemini,
+	order := make([]AIProvider, 0, len(defaultProviderOrder))
+	if primary != "" && m.isProviderEnabled(primary) {
+		order = append(order, primary)
 	}
-
-	for _, provider := range providers {
-		status := m.statusMap[provider]
-		if status.Enabled && status.Available {
-			return provider, nil
+	for _, provider := range defaultProviderOrder {
+		if provider != primary && m.isProviderEnabled(provider) {
+			order = append(order, provider)
 		}
 	}
-
-	// 如果没有已验证可用的，返回第一个启用的
-	for _, provider := range providers {
-		status := m.statusMap[provider]
-		if status.Enabled {
-			return provider, nil
-		}
-	}
-
-	return "", fmt.Errorf("没有可用的AI服务")
+	return order
 }
 
 // GetAllStatus 获取所有AI服务状态
@@ -207,10 +210,11 @@ func (m *AIServiceManager) GetAllStatus() []*AIServiceStatus {
 
 	// 按优先级顺序返回
 	result := make([]*AIServiceStatus, 0, 3)
-	for _, provider := range []AIProvider{AIProviderOpenAICompatible, AIProviderDeepSeek, AIProviderGemini} {
+	for _, provider := range defaultProviderOrder {
 		if status, ok := m.statusMap[provider]; ok {
 			// 复制一份避免并发问题
 			statusCopy := *status
+			applyCircuitState(&statusCopy)
 			result = append(result, &statusCopy)
 		}
 	}
@@ -224,12 +228,13 @@ func (m *AIServiceManager) GetStatus(provider AIProvider) *AIServiceStatus {
 
 	if status, ok := m.statusMap[provider]; ok {
 		statusCopy := *status
+		applyCircuitState(&statusCopy)
 		return &statusCopy
 	}
 	return nil
 }
 
-// SetAvailable 设置服务可用状态
+// SetAvailable 设置服务可用状态（errMsg 为最近一次错误，可用时也会保留，传空字符串表示清除）
 func (m *AIServiceManager) SetAvailable(provider AIProvider, available bool, errMsg string) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -237,12 +242,24 @@ func (m *AIServiceManager) SetAvailable(provider AIProvider, available bool, err
 	if status, ok := m.statusMap[provider]; ok {
 		status.Available = available
 		status.LastChecked = time.Now()
-		if !available {
-			status.LastError = errMsg
-		} else {
-			status.LastError = ""
-		}
+		status.LastError = errMsg
+	}
+}
+
+// applyCircuitState 熔断中的服务显示为不可用
+func applyCircuitState(status *AIServiceStatus) {
+	if available, reason := ratelimit.Available(rateLimitKey(status.Provider)); !available {
+		status.Available = false
+		status.LastError = reason
+	}
+}
+
+// rateLimitKey 服务对应的限流/熔断提供商名称（与用量记录的提供商名称一致）
+func rateLimitKey(provider AIProvider) string {
+	if provider == AIProviderOpenAICompatible {
+		return "openai"
 	}
+	return string(provider)
 }
 
 // GetOpenAICompatibleConfig 获取OpenAI兼容API配置
@@ -284,23 +301,68 @@ func (m *AIServiceManager) IsGeminiEnabled() bool {
 	return cfg != nil && cfg.Enabled && cfg.ApiKey != ""
 }
 
+// VisionFrames OpenAI兼容API的模型每次分析的视频关键帧数，0 表示模型不支持图像输入
+func (m *AIServiceManager) VisionFrames() int {
+	cfg := m.GetOpenAICompatibleConfig()
+	if cfg == nil || !m.IsOpenAICompatibleEnabled() {
+		return 0
+	}
+	return cfg.VisionFrames
+}
+
+// VisionCompletionContext 使用OpenAI兼容API的视觉模型分析图片和文本（只有该服务支持图像输入，不切换备选服务）
+func (m *AIServiceManager) VisionCompletionContext(ctx context.Context, systemPrompt, userPrompt string, images []ImageInput) (string, error) {
+	if m.VisionFrames() <= 0 {
+		return "", fmt.Errorf("OpenAI兼容API未启用或未配置 vision_frames")
+	}
+	if err := translator.CheckBudget(translator.UsageScopeFromContext(ctx).VideoID); err != nil {
+		return "", err
+	}
+	if available, reason := ratelimit.Available(rateLimitKey(AIProviderOpenAICompatible)); !available {
+		return "", fmt.Errorf("%s 已熔断: %s", m.getProviderName(AIProviderOpenAICompatible), reason)
+	}
+
+	client := m.createOpenAICompatibleClient(m.GetOpenAICompatibleConfig())
+	content, err := client.ChatCompletionWithImages(ctx, systemPrompt, userPrompt, images)
+	if err != nil {
+		available, _ := ratelimit.Available(rateLimitKey(AIProviderOpenAICompatible))
+		m.SetAvailable(AIProviderOpenAICompatible, available, err.Error())
+		return "", err
+	}
+	m.SetAvailable(AIProviderOpenAICompatible, true, "")
+	return content, nil
+}
+
 // ChatCompletion 执行对话补全（自动选择AI服务）
-// 优先使用首选服务，失败后自动切换到备选服务
+// 按 EnabledProviders 的顺序调用，失败后自动切换到备选服务
 func (m *AIServiceManager) ChatCompletion(systemPrompt, userPrompt string) (string, AIProvider, error) {
-	providers := []AIProvider{
-		AIProviderOpenAICompatible,
-		AIProviderDeepSeek,
+	return m.ChatCompletionContext(context.Background(), systemPrompt, userPrompt)
+}
+
+// ChatCompletionContext 同 ChatCompletion，用量按 ctx 中的用量归属（translator.WithUsageScope）记录
+func (m *AIServiceManager) ChatCompletionContext(ctx context.Context, systemPrompt, userPrompt string) (string, AIProvider, error) {
+	scope := translator.UsageScopeFromContext(ctx)
+	if err := translator.CheckBudget(scope.VideoID); err != nil {
+		return "", "", err
+	}
+
+	providers := m.EnabledProviders()
+	if len(providers) == 0 {
+		return "", "", ErrNoAIService
 	}
 
 	var lastErr error
 	for _, provider := range providers {
-		if !m.isProviderEnabled(provider) {
+		if available, reason := ratelimit.Available(rateLimitKey(provider)); !available {
+			m.SetAvailable(provider, false, reason)
+			lastErr = fmt.Errorf("%s 已熔断: %s", m.getProviderName(provider), reason)
+			m.logger.Warnf("⚠️ %s 已熔断，尝试下一个服务...", m.getProviderName(provider))
 			continue
 		}
 
 		m.logger.Infof("🤖 尝试使用 %s 进行AI对话...", m.getProviderName(provider))
 
-		result, err := m.chatWithProvider(provider, systemPrompt, userPrompt)
+		result, err := m.chatWithProvider(ctx, provider, systemPrompt, userPrompt)
 		if err == nil {
 			m.SetAvailable(provider, true, "")
 			m.logger.Infof("✅ %s 调用成功", m.getProviderName(provider))
@@ -308,7 +370,9 @@ func (m *AIServiceManager) ChatCompletion(systemPrompt, userPrompt string) (stri
 		}
 
 		lastErr = err
-		m.SetAvailable(provider, false, err.Error())
+		// 可用状态跟随熔断器：偶发失败不标记为不可用，连续 429/5xx 熔断后才不可用
+		available, _ := ratelimit.Available(rateLimitKey(provider))
+		m.SetAvailable(provider, available, err.Error())
 		m.logger.Warnf("⚠️ %s 调用失败: %v，尝试下一个服务...", m.getProviderName(provider), err)
 	}
 
@@ -316,30 +380,32 @@ func (m *AIServiceManager) ChatCompletion(systemPrompt, userPrompt string) (stri
 }
 
 // chatWithProvider 使用指定提供商进行对话
-func (m *AIServiceManager) chatWithProvider(provider AIProvider, systemPrompt, userPrompt string) (string, error) {
+func (m *AIServiceManager) chatWithProvider(ctx context.Context, provider AIProvider, systemPrompt, userPrompt string) (string, error) {
 	switch provider {
 	case AIProviderOpenAICompatible:
-		return m.chatWithOpenAICompatible(systemPrompt, userPrompt)
+		return m.chatWithOpenAICompatible(ctx, systemPrompt, userPrompt)
 	case AIProviderDeepSeek:
-		return m.chatWithDeepSeek(systemPrompt, userPrompt)
+		return m.chatWithDeepSeek(ctx, systemPrompt, userPrompt)
+	case AIProviderGemini:
+		return m.chatWithGemini(ctx, systemPrompt, userPrompt)
 	default:
 		return "", fmt.Errorf("不支持的AI提供商: %s", provider)
 	}
 }
 
 // chatWithOpenAICompatible 使用OpenAI兼容API进行对话
-func (m *AIServiceManager) chatWithOpenAICompatible(systemPrompt, userPrompt string) (string, error) {
+func (m *AIServiceManager) chatWithOpenAICompatible(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
 	cfg := m.GetOpenAICompatibleConfig()
 	if cfg == nil || !cfg.Enabled {
 		return "", fmt.Errorf("OpenAI兼容API未启用")
 	}
 
 	client := m.createOpenAICompatibleClient(cfg)
-	return client.ChatCompletion(systemPrompt, userPrompt)
+	return client.ChatCompletionContext(ctx, systemPrompt, userPrompt)
 }
 
 // chatWithDeepSeek 使用DeepSeek进行对话
-func (m *AIServiceManager) chatWithDeepSeek(systemPrompt, userPrompt string) (string, error) {
+func (m *AIServiceManager) chatWithDeepSeek(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
 	cfg := m.GetDeepSeekConfig()
 	if cfg == nil || !cfg.Enabled {
 		return "", fmt.Errorf("DeepSeek未启用")
@@ -347,12 +413,42 @@ func (m *AIServiceManager) chatWithDeepSeek(systemPrompt, userPrompt string) (st
 
 	// 使用OpenAI兼容客户端调用DeepSeek
 	client := m.createOpenAICompatibleClientFromDeepSeek(cfg)
-	return client.ChatCompletion(systemPrompt, userPrompt)
+	return client.ChatCompletionContext(ctx, systemPrompt, userPrompt)
+}
+
+// chatWithGemini 使用Gemini进行对话（通过 Gemini 的 OpenAI 兼容接口，多模态分析仍使用原生SDK）
+func (m *AIServiceManager) chatWithGemini(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+	cfg := m.GetGeminiConfig()
+	if cfg == nil || !cfg.Enabled {
+		return "", fmt.Errorf("Gemini未启用")
+	}
+
+	model := cfg.Model
+	if model == "" {
+		model = "gemini-1.5-pro"
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 120
+	}
+
+	client := NewOpenAICompatibleClient(&OpenAIClientConfig{
+		Provider:    "gemini",
+		APIKey:      cfg.ApiKey,
+		BaseURL:     geminiOpenAIEndpoint,
+		Model:       model,
+		Timeout:     timeout,
+		MaxRetries:  3,
+		Temperature: 0.7,
+		MaxTokens:   cfg.MaxTokens,
+	})
+	return client.ChatCompletionContext(ctx, systemPrompt, userPrompt)
 }
 
 // createOpenAICompatibleClient 创建OpenAI兼容客户端
 func (m *AIServiceManager) createOpenAICompatibleClient(cfg *types.OpenAICompatibleConfig) *OpenAICompatibleClient {
 	return NewOpenAICompatibleClient(&OpenAIClientConfig{
+		Provider:    "openai",
 		APIKey:      cfg.APIKey,
 		BaseURL:     cfg.BaseURL,
 		Model:       cfg.Model,
@@ -371,6 +467,7 @@ func (m *AIServiceManager) createOpenAICompatibleClientFromDeepSeek(cfg *types.D
 	}
 
 	return NewOpenAICompatibleClient(&OpenAIClientConfig{
+		Provider:    "deepseek",
 		APIKey:      cfg.ApiKey,
 		BaseURL:     baseURL,
 		Model:       cfg.Model,
@@ -411,6 +508,7 @@ type OpenAICompatibleClient = openAICompatibleClientWrapper
 
 // openAICompatibleClientWrapper 包装器，避免循环引用
 type openAICompatibleClientWrapper struct {
+	provider    string
 	apiKey      string
 	baseURL     string
 	model       string
@@ -422,6 +520,7 @@ type openAICompatibleClientWrapper struct {
 
 // OpenAIClientConfig 客户端配置
 type OpenAIClientConfig struct {
+	Provider    string // 用量记录中的提供商名称（对应价格表的键）
 	APIKey      string
 	BaseURL     string
 	Model       string
@@ -433,7 +532,12 @@ type OpenAIClientConfig struct {
 
 // NewOpenAICompatibleClient 创建客户端
 func NewOpenAICompatibleClient(config *OpenAIClientConfig) *openAICompatibleClientWrapper {
+	provider := config.Provider
+	if provider == "" {
+		provider = "openai"
+	}
 	return &openAICompatibleClientWrapper{
+		provider:    provider,
 		apiKey:      config.APIKey,
 		baseURL:     config.BaseURL,
 		model:       config.Model,
@@ -446,35 +550,97 @@ func NewOpenAICompatibleClient(config *OpenAIClientConfig) *openAICompatibleClie
 
 // ChatCompletion 执行对话
 func (c *openAICompatibleClientWrapper) ChatCompletion(systemPrompt, userPrompt string) (string, error) {
-	// 构建请求
+	return c.ChatCompletionContext(context.Background(), systemPrompt, userPrompt)
+}
+
+// ImageInput 随对话发送的图片（如视频关键帧）
+type ImageInput struct {
+	Label    string // 图片前附加的说明文字（如时间点），可为空
+	MIMEType string // 默认 image/jpeg
+	Data     []byte
+}
+
+// chatMessage 请求消息，Content 为字符串或多模态内容列表（[]chatContentPart）
+type chatMessage struct {
+	Role    string      `json:"role"`
+	Content interface{} `json:"content"`
+}
+
+// chatContentPart 多模态消息内容（文本或图片）
+type chatContentPart struct {
+	Type     string        `json:"type"` // text / image_url
+	Text     string        `json:"text,omitempty"`
+	ImageURL *chatImageURL `json:"image_url,omitempty"`
+}
+
+type chatImageURL struct {
+	URL string `json:"url"`
+}
+
+// ChatCompletionContext 执行对话，每次收到响应的请求都按 ctx 的用量归属记录用量
+func (c *openAICompatibleClientWrapper) ChatCompletionContext(ctx context.Context, systemPrompt, userPrompt string) (string, error) {
+	return c.complete(ctx, []chatMessage{
+		{Role: "system", Content: systemPrompt},
+		{Role: "user", Content: userPrompt},
+	}, systemPrompt, userPrompt)
+}
+
+// ChatCompletionWithImages 发送图片和文本进行对话（需要模型支持图像输入），图片以 data URL 内联发送
+func (c *openAICompatibleClientWrapper) ChatCompletionWithImages(ctx context.Context, systemPrompt, userPrompt string, images []ImageInput) (string, error) {
+	parts := make([]chatContentPart, 0, len(images)*2+1)
+	for _, image := range images {
+		if image.Label != "" {
+			parts = append(parts, chatContentPart{Type: "text", Text: image.Label})
+		}
+		mimeType := image.MIMEType
+		if mimeType == "" {
+			mimeType = "image/jpeg"
+		}
+		parts = append(parts, chatContentPart{
+			Type:     "image_url",
+			ImageURL: &chatImageURL{URL: "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(image.Data)},
+		})
+	}
+	parts = append(parts, chatContentPart{Type: "text", Text: userPrompt})
+
+	messages := []chatMessage{{Role: "user", Content: parts}}
+	if systemPrompt != "" {
+		messages = append([]chatMessage{{Role: "system", Content: systemPrompt}}, messages...)
+	}
+	return c.complete(ctx, messages, systemPrompt, userPrompt)
+}
+
+// complete 发送对话请求（带重试），texts 用于估算限流的 token 数
+func (c *openAICompatibleClientWrapper) complete(ctx context.Context, messages []chatMessage, texts ...string) (string, error) {
 	type Message struct {
 		Role    string `json:"role"`
 		Content string `json:"content"`
 	}
 	type Request struct {
-		Model       string    `json:"model"`
-		Messages    []Message `json:"messages"`
-		Stream      bool      `json:"stream"`
-		Temperature float64   `json:"temperature,omitempty"`
-		MaxTokens   int       `json:"max_tokens,omitempty"`
+		Model       string        `json:"model"`
+		Messages    []chatMessage `json:"messages"`
+		Stream      bool          `json:"stream"`
+		Temperature float64       `json:"temperature,omitempty"`
+		MaxTokens   int           `json:"max_tokens,omitempty"`
 	}
 	type Choice struct {
 		Message Message `json:"message"`
 	}
 	type Response struct {
 		Choices []Choice `json:"choices"`
-		Error   *struct {
+		Usage   *struct {
+			PromptTokens     int `json:"prompt_tokens"`
+			CompletionTokens int `json:"completion_tokens"`
+		} `json:"usage,omitempty"`
+		Error *struct {
 			Message string `json:"message"`
 			Type    string `json:"type"`
 		} `json:"error,omitempty"`
 	}
 
 	request := Request{
-		Model: c.model,
-		Messages: []Message{
-			{Role: "system", Content: systemPrompt},
-			{Role: "user", Content: userPrompt},
-		},
+		Model:       c.model,
+		Messages:    messages,
 		Stream:      false,
 		Temperature: c.temperature,
 		MaxTokens:   c.maxTokens,
@@ -513,9 +679,17 @@ func (c *openAICompatibleClientWrapper) ChatCompletion(systemPrompt, userPrompt
 		req.Header.Set("Content-Type", "application/json")
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
 
+		permit, err := ratelimit.Acquire(ctx, c.provider, ratelimit.EstimateTokens(texts...))
+		if err != nil {
+			// 熔断期间重试没有意义，由上层切换到备选服务
+			return "", err
+		}
+
+		startTime := time.Now()
 		resp, err := client.Do(req)
 		if err != nil {
 			lastErr = fmt.Errorf("发送请求失败: %v", err)
+			permit.Done(lastErr)
 			continue
 		}
 
@@ -523,17 +697,36 @@ func (c *openAICompatibleClientWrapper) ChatCompletion(systemPrompt, userPrompt
 		resp.Body.Close()
 		if err != nil {
 			lastErr = fmt.Errorf("读取响应失败: %v", err)
+			permit.Done(lastErr)
 			continue
 		}
 
 		var response Response
 		if err := json.Unmarshal(body, &response); err != nil {
-			lastErr = fmt.Errorf("解析响应失败: %v", err)
+			lastErr = ratelimit.WithStatus(resp.StatusCode, fmt.Errorf("解析响应失败: %v", err))
+			permit.Done(lastErr)
 			continue
 		}
 
+		record := &translator.UsageRecord{
+			Provider: c.provider,
+			Model:    c.model,
+			Kind:     translator.UsageKindChat,
+			Latency:  time.Since(startTime),
+			Success:  response.Error == nil && resp.StatusCode == http.StatusOK && len(response.Choices) > 0,
+		}
+		if response.Usage != nil {
+			record.InputTokens = response.Usage.PromptTokens
+			record.OutputTokens = response.Usage.CompletionTokens
+		}
+		if response.Error != nil {
+			record.Error = response.Error.Message
+		}
+		translator.RecordUsage(ctx, record)
+
 		if response.Error != nil {
-			lastErr = fmt.Errorf("API错误: %s", response.Error.Message)
+			lastErr = ratelimit.WithStatus(resp.StatusCode, fmt.Errorf("API错误: %s", response.Error.Message))
+			permit.Done(lastErr)
 			if strings.Contains(response.Error.Message, "rate limit") {
 				time.Sleep(5 * time.Second * time.Duration(attempt+1))
 			}
@@ -541,15 +734,18 @@ func (c *openAICompatibleClientWrapper) ChatCompletion(systemPrompt, userPrompt
 		}
 
 		if resp.StatusCode != http.StatusOK {
-			lastErr = fmt.Errorf("API返回错误 (状态码: %d): %s", resp.StatusCode, string(body))
+			lastErr = ratelimit.WithStatus(resp.StatusCode, fmt.Errorf("API返回错误 (状态码: %d): %s", resp.StatusCode, string(body)))
+			permit.Done(lastErr)
 			continue
 		}
 
 		if len(response.Choices) == 0 {
 			lastErr = fmt.Errorf("API响应中没有结果")
+			permit.Done(lastErr)
 			continue
 		}
 
+		permit.Done(nil)
 		return response.Choices[0].Message.Content, nil
 	}
 
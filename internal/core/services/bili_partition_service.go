@@ -0,0 +1,152 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/difyz9/bilibili-go-sdk/bilibili"
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/internal/storage"
+)
+
+// partitionCacheFile 分区列表缓存文件名（位于 DataPath 下）
+const partitionCacheFile = "bili_partitions.json"
+
+// partitionCache 分区列表缓存文件内容
+type partitionCache struct {
+	FetchedAt  time.Time                `json:"fetched_at"`
+	Partitions []bilibili.PartitionType `json:"partitions"`
+}
+
+// BiliPartitionService B站投稿分区列表缓存：分区列表接口需要登录，
+// 获取后保存到文件，选择分区步骤在缓存过期前不再请求接口
+type BiliPartitionService struct {
+	Config *types.AppConfig
+	mu     sync.Mutex
+	cache  *partitionCache
+}
+
+func NewBiliPartitionService(config *types.AppConfig) *BiliPartitionService {
+	return &BiliPartitionService{
+		Config: config,
+	}
+}
+
+// cachePath 缓存文件路径
+func (s *BiliPartitionService) cachePath() string {
+	dataPath := s.Config.DataPath
+	if dataPath == "" {
+		dataPath = "./data"
+	}
+	return filepath.Join(dataPath, partitionCacheFile)
+}
+
+// cacheTTL 缓存有效期，未配置时为 24 小时
+func (s *BiliPartitionService) cacheTTL() time.Duration {
+	if cfg := s.Config.PartitionConfig; cfg != nil && cfg.CacheHours > 0 {
+		return time.Duration(cfg.CacheHours) * time.Hour
+	}
+	return 24 * time.Hour
+}
+
+// loadCache 读取缓存（内存中没有时从文件读取），调用方需持有锁
+func (s *BiliPartitionService) loadCache() *partitionCache {
+	if s.cache != nil {
+		return s.cache
+	}
+	data, err := os.ReadFile(s.cachePath())
+	if err != nil {
+		return nil
+	}
+	var cache partitionCache
+	if err := json.Unmarshal(data, &cache); err != nil || len(cache.Partitions) == 0 {
+		return nil
+	}
+	s.cache = &cache
+	return s.cache
+}
+
+// Store 保存分区列表（分区列表接口返回时调用，顺便刷新缓存）
+func (s *BiliPartitionService) Store(partitions []bilibili.PartitionType) error {
+	if len(partitions) == 0 {
+		return fmt.Errorf("分区列表为空")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cache := &partitionCache{FetchedAt: time.Now(), Partitions: partitions}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.cachePath()), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(s.cachePath(), data, 0644); err != nil {
+		return err
+	}
+	s.cache = cache
+	return nil
+}
+
+// Partitions 返回分区列表：缓存未过期时直接返回，否则使用已保存的登录信息重新获取，
+// 获取失败时回退到过期的缓存
+func (s *BiliPartitionService) Partitions() ([]bilibili.PartitionType, error) {
+	s.mu.Lock()
+	cache := s.loadCache()
+	s.mu.Unlock()
+
+	if cache != nil && time.Since(cache.FetchedAt) < s.cacheTTL() {
+		return cache.Partitions, nil
+	}
+
+	partitions, err := s.fetch()
+	if err != nil {
+		if cache != nil {
+			return cache.Partitions, nil
+		}
+		return nil, err
+	}
+	if err := s.Store(partitions); err != nil {
+		return nil, fmt.Errorf("保存分区列表失败: %v", err)
+	}
+	return partitions, nil
+}
+
+// fetch 使用已保存的登录信息请求分区列表
+func (s *BiliPartitionService) fetch() ([]bilibili.PartitionType, error) {
+	loginStore := storage.GetDefaultStore()
+	if !loginStore.IsValid() {
+		return nil, fmt.Errorf("未登录 Bilibili，无法获取分区列表")
+	}
+	loginInfo, err := loginStore.Load()
+	if err != nil {
+		return nil, fmt.Errorf("加载登录信息失败: %v", err)
+	}
+
+	archiveData, err := bilibili.NewClient().GetArchivePre(loginInfo.GetCookieString())
+	if err != nil {
+		return nil, fmt.Errorf("获取分区列表失败: %v", err)
+	}
+	if len(archiveData.TypeList) == 0 {
+		return nil, fmt.Errorf("分区列表为空")
+	}
+	return archiveData.TypeList, nil
+}
+
+// FindPartition 在分区列表中查找投稿分区（二级分区），返回其所属的一级分区和分区本身
+func FindPartition(partitions []bilibili.PartitionType, tid int) (*bilibili.PartitionType, *bilibili.PartitionType) {
+	for i := range partitions {
+		for j := range partitions[i].Children {
+			if partitions[i].Children[j].ID == tid {
+				return &partitions[i], &partitions[i].Children[j]
+			}
+		}
+	}
+	return nil, nil
+}
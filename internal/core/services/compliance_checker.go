@@ -0,0 +1,266 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+)
+
+// 合规检查的处理方式
+const (
+	ComplianceBlock   = "block"   // 阻止上传
+	ComplianceReplace = "replace" // 上传时替换
+	ComplianceWarn    = "warn"    // 只提示
+)
+
+// 合规检查的字段
+const (
+	ComplianceFieldTitle       = "title"
+	ComplianceFieldDescription = "description"
+	ComplianceFieldTags        = "tags"
+	ComplianceFieldSubtitles   = "subtitles"
+)
+
+// 内置检查规则的名称（违规词的规则名称为词语本身）
+const (
+	ComplianceRuleLink   = "link"
+	ComplianceRuleQRCode = "qrcode"
+	ComplianceRuleLLM    = "llm"
+)
+
+var (
+	// 带协议或 www 的链接，以及常见顶级域名的裸域名
+	complianceLinkPattern = regexp.MustCompile(`(?i)(?:https?://|www\.)[^\s<>"'，。、）)\]]+|\b[a-z0-9][a-z0-9-]*(?:\.[a-z0-9-]+)*\.(?:com|net|org|cn|io|me|tv|cc|co|ly|gg|xyz|top|link|app|info)\b(?:/[^\s<>"'，。、）)\]]*)?`)
+	// 二维码、扫码、加微信等引流文字
+	complianceQRCodePattern = regexp.MustCompile(`(?i)二维码|扫码|扫一扫|qr\s*code|加微信|微信号|加\s*[vV]信|[vV][xX]\s*[:：]`)
+)
+
+// ComplianceFinding 一条合规检查结果
+type ComplianceFinding struct {
+	Field       string `json:"field"`                 // title / description / tags / subtitles
+	Rule        string `json:"rule"`                  // 命中的违规词，或 link / qrcode / llm
+	Action      string `json:"action"`                // block / replace / warn
+	Match       string `json:"match"`                 // 命中的文本
+	Replacement string `json:"replacement,omitempty"` // action 为 replace 时上传使用的文本
+	Context     string `json:"context,omitempty"`     // 命中位置前后的文本
+	Time        string `json:"time,omitempty"`        // 字幕时间点
+}
+
+// ComplianceLLMResult 大模型检查字幕的结论
+type ComplianceLLMResult struct {
+	Provider   string   `json:"provider"`
+	Verdict    string   `json:"verdict"` // pass / warn / block
+	Categories []string `json:"categories,omitempty"`
+	Reason     string   `json:"reason,omitempty"`
+}
+
+// ComplianceReport 合规检查报告，保存到 compliance.json，在视频详情和审核详情中展示
+type ComplianceReport struct {
+	Passed        bool                 `json:"passed"` // 没有 block 级别的问题
+	Findings      []ComplianceFinding  `json:"findings"`
+	LLM           *ComplianceLLMResult `json:"llm,omitempty"`
+	PromptVersion string               `json:"prompt_version,omitempty"`
+	CheckedAt     time.Time            `json:"checked_at"`
+}
+
+// BlockReasons block 级别问题的说明
+func (r *ComplianceReport) BlockReasons() []string {
+	var reasons []string
+	for _, finding := range r.Findings {
+		if finding.Action == ComplianceBlock {
+			reasons = append(reasons, fmt.Sprintf("[%s] %s: %s", finding.Field, finding.Rule, finding.Match))
+		}
+	}
+	return reasons
+}
+
+// LoadComplianceReport 读取合规检查报告
+func LoadComplianceReport(path string) (*ComplianceReport, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var report ComplianceReport
+	if err := json.Unmarshal(data, &report); err != nil {
+		return nil, fmt.Errorf("解析合规检查报告失败: %v", err)
+	}
+	return &report, nil
+}
+
+// SaveComplianceReport 保存合规检查报告
+func SaveComplianceReport(path string, report *ComplianceReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// complianceTerm 编译后的违规词
+type complianceTerm struct {
+	types.ComplianceTerm
+	pattern *regexp.Regexp
+}
+
+// ComplianceChecker 按配置的违规词表、链接和引流文字规则检查文本
+type ComplianceChecker struct {
+	Config *types.ComplianceConfig
+	terms  []complianceTerm
+}
+
+// NewComplianceChecker 编译违规词表，正则表达式无效时返回错误
+func NewComplianceChecker(cfg *types.ComplianceConfig) (*ComplianceChecker, error) {
+	checker := &ComplianceChecker{Config: cfg}
+	for _, term := range cfg.Terms {
+		if strings.TrimSpace(term.Term) == "" {
+			continue
+		}
+		expr := regexp.QuoteMeta(strings.TrimSpace(term.Term))
+		if term.Regex {
+			expr = term.Term
+		}
+		pattern, err := regexp.Compile("(?i)" + expr)
+		if err != nil {
+			return nil, fmt.Errorf("违规词 %q 不是有效的正则表达式: %v", term.Term, err)
+		}
+		term.Action = normalizeComplianceAction(term.Action)
+		checker.terms = append(checker.terms, complianceTerm{ComplianceTerm: term, pattern: pattern})
+	}
+	return checker, nil
+}
+
+// CheckText 检查一个字段的文本：违规词；标题和简介还检查外部链接，简介检查引流文字
+func (c *ComplianceChecker) CheckText(field, text string) []ComplianceFinding {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+
+	var findings []ComplianceFinding
+	for _, term := range c.terms {
+		action := term.Action
+		if action == ComplianceReplace && field == ComplianceFieldSubtitles {
+			action = ComplianceWarn
+		}
+		for _, loc := range term.pattern.FindAllStringIndex(text, -1) {
+			finding := ComplianceFinding{
+				Field:   field,
+				Rule:    term.Term,
+				Action:  action,
+				Match:   text[loc[0]:loc[1]],
+				Context: complianceContext(text, loc),
+			}
+			if action == ComplianceReplace {
+				finding.Replacement = term.Replacement
+			}
+			findings = append(findings, finding)
+		}
+	}
+
+	if field == ComplianceFieldTitle || field == ComplianceFieldDescription {
+		action := normalizeComplianceAction(c.Config.LinkAction)
+		for _, loc := range complianceLinkPattern.FindAllStringIndex(text, -1) {
+			if c.allowedLink(text[loc[0]:loc[1]]) {
+				continue
+			}
+			findings = append(findings, ComplianceFinding{
+				Field:   field,
+				Rule:    ComplianceRuleLink,
+				Action:  action,
+				Match:   text[loc[0]:loc[1]],
+				Context: complianceContext(text, loc),
+			})
+		}
+	}
+
+	if field == ComplianceFieldDescription {
+		action := normalizeComplianceAction(c.Config.QRCodeAction)
+		if action == ComplianceReplace {
+			action = ComplianceWarn
+		}
+		for _, loc := range complianceQRCodePattern.FindAllStringIndex(text, -1) {
+			findings = append(findings, ComplianceFinding{
+				Field:   field,
+				Rule:    ComplianceRuleQRCode,
+				Action:  action,
+				Match:   text[loc[0]:loc[1]],
+				Context: complianceContext(text, loc),
+			})
+		}
+	}
+	return findings
+}
+
+// Sanitize 上传前处理文本：link_action 为 replace 时删除外部链接，再替换 replace 级别的违规词
+func (c *ComplianceChecker) Sanitize(field, text string) string {
+	if (field == ComplianceFieldTitle || field == ComplianceFieldDescription) && normalizeComplianceAction(c.Config.LinkAction) == ComplianceReplace {
+		text = complianceLinkPattern.ReplaceAllStringFunc(text, func(link string) string {
+			if c.allowedLink(link) {
+				return link
+			}
+			return ""
+		})
+	}
+	for _, term := range c.terms {
+		if term.Action == ComplianceReplace {
+			text = term.pattern.ReplaceAllLiteralString(text, term.Replacement)
+		}
+	}
+	if field == ComplianceFieldTags {
+		// 替换为空的标签直接去掉
+		var tags []string
+		for _, tag := range strings.Split(text, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				tags = append(tags, tag)
+			}
+		}
+		return strings.Join(tags, ",")
+	}
+	return strings.TrimSpace(text)
+}
+
+// allowedLink 链接的域名是否在允许列表中（含子域名）
+func (c *ComplianceChecker) allowedLink(link string) bool {
+	host := strings.ToLower(link)
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	if i := strings.IndexAny(host, "/?#:"); i >= 0 {
+		host = host[:i]
+	}
+	host = strings.TrimPrefix(host, "www.")
+	for _, domain := range c.Config.AllowedDomains {
+		domain = strings.ToLower(strings.TrimSpace(domain))
+		if domain != "" && (host == domain || strings.HasSuffix(host, "."+domain)) {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeComplianceAction 未知的处理方式按 warn 处理
+func normalizeComplianceAction(action string) string {
+	switch action = strings.ToLower(strings.TrimSpace(action)); action {
+	case ComplianceBlock, ComplianceReplace:
+		return action
+	default:
+		return ComplianceWarn
+	}
+}
+
+// complianceContext 命中位置前后各 20 个字符
+func complianceContext(text string, loc []int) string {
+	before := []rune(text[:loc[0]])
+	after := []rune(text[loc[1]:])
+	if len(before) > 20 {
+		before = before[len(before)-20:]
+	}
+	if len(after) > 20 {
+		after = after[:20]
+	}
+	return strings.TrimSpace(strings.ReplaceAll(string(before)+text[loc[0]:loc[1]]+string(after), "\n", " "))
+}
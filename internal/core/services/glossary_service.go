@@ -0,0 +1,316 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/translator"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrGlossaryTermExists 同一作用域下已存在相同的原文术语
+	ErrGlossaryTermExists = errors.New("该作用域下已存在相同的术语")
+	// ErrInvalidGlossaryTerm 术语字段不合法
+	ErrInvalidGlossaryTerm = errors.New("术语无效")
+)
+
+// 作用域优先级：视频 > 播放列表 > 频道 > 全局，优先级高的覆盖同名术语
+var glossaryScopePriority = map[string]int{
+	model.GlossaryScopeGlobal:   0,
+	model.GlossaryScopeChannel:  1,
+	model.GlossaryScopePlaylist: 2,
+	model.GlossaryScopeVideo:    3,
+}
+
+// GlossaryService 术语表服务
+type GlossaryService struct {
+	DB *gorm.DB
+}
+
+// NewGlossaryService 创建术语表服务实例
+func NewGlossaryService(db *gorm.DB) *GlossaryService {
+	return &GlossaryService{
+		DB: db,
+	}
+}
+
+// GlossaryFilter 术语筛选条件
+type GlossaryFilter struct {
+	Scope      string
+	ScopeID    string
+	TargetLang string
+	Status     string
+	Keyword    string
+}
+
+// IsValidGlossaryScope 检查作用域是否合法
+func IsValidGlossaryScope(scope string) bool {
+	_, ok := glossaryScopePriority[scope]
+	return ok
+}
+
+// ListTerms 查询术语（按作用域、原文排序）
+func (s *GlossaryService) ListTerms(filter GlossaryFilter) ([]model.GlossaryTerm, error) {
+	db := s.DB.Model(&model.GlossaryTerm{})
+	if filter.Scope != "" {
+		db = db.Where("scope = ?", filter.Scope)
+	}
+	if filter.ScopeID != "" {
+		db = db.Where("scope_id = ?", filter.ScopeID)
+	}
+	if filter.TargetLang != "" {
+		db = db.Where("target_lang = ?", translator.NormalizeLangCode(filter.TargetLang))
+	}
+	if filter.Status != "" {
+		db = db.Where("status = ?", filter.Status)
+	}
+	if filter.Keyword != "" {
+		like := "%" + filter.Keyword + "%"
+		db = db.Where("(source_term LIKE ? OR target_term LIKE ?)", like, like)
+	}
+
+	var terms []model.GlossaryTerm
+	err := db.Order("scope ASC, scope_id ASC, source_term ASC").Find(&terms).Error
+	return terms, err
+}
+
+// GetTerm 获取单个术语
+func (s *GlossaryService) GetTerm(id uint) (*model.GlossaryTerm, error) {
+	var term model.GlossaryTerm
+	if err := s.DB.First(&term, id).Error; err != nil {
+		return nil, err
+	}
+	return &term, nil
+}
+
+// CreateTerm 创建术语，同一作用域、目标语言下原文术语（不区分大小写）不能重复
+func (s *GlossaryService) CreateTerm(term *model.GlossaryTerm) error {
+	normalizeGlossaryTerm(term)
+	if err := validateGlossaryTerm(term); err != nil {
+		return err
+	}
+
+	exists, err := s.termExists(term, 0)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrGlossaryTermExists
+	}
+	return s.DB.Create(term).Error
+}
+
+// UpdateTerm 更新术语
+func (s *GlossaryService) UpdateTerm(term *model.GlossaryTerm) error {
+	normalizeGlossaryTerm(term)
+	if err := validateGlossaryTerm(term); err != nil {
+		return err
+	}
+
+	exists, err := s.termExists(term, term.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrGlossaryTermExists
+	}
+	return s.DB.Save(term).Error
+}
+
+// SetStatus 修改术语状态（审核建议术语）
+func (s *GlossaryService) SetStatus(id uint, status string) (*model.GlossaryTerm, error) {
+	term, err := s.GetTerm(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.DB.Model(term).Update("status", status).Error; err != nil {
+		return nil, err
+	}
+	term.Status = status
+	return term, nil
+}
+
+// DeleteTerm 删除术语
+func (s *GlossaryService) DeleteTerm(id uint) error {
+	result := s.DB.Unscoped().Delete(&model.GlossaryTerm{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteTermsByVideoID 删除视频级术语
+func (s *GlossaryService) DeleteTermsByVideoID(videoID string) error {
+	return s.DB.Unscoped().
+		Where("scope = ? AND scope_id = ?", model.GlossaryScopeVideo, videoID).
+		Delete(&model.GlossaryTerm{}).Error
+}
+
+// EffectiveTerms 获取视频实际生效的术语：合并全局、频道、播放列表和视频级术语，
+// 同一原文术语以优先级高的作用域为准
+func (s *GlossaryService) EffectiveTerms(video *model.SavedVideo, targetLang string) ([]model.GlossaryTerm, error) {
+	db := s.DB.Where("status = ? AND target_lang = ?", model.GlossaryStatusActive, translator.NormalizeLangCode(targetLang))
+
+	conds := []string{"scope = ?"}
+	args := []interface{}{model.GlossaryScopeGlobal}
+	if video != nil {
+		if video.ChannelID != "" {
+			conds = append(conds, "(scope = ? AND scope_id = ?)")
+			args = append(args, model.GlossaryScopeChannel, video.ChannelID)
+		}
+		if video.PlaylistID != "" {
+			conds = append(conds, "(scope = ? AND scope_id = ?)")
+			args = append(args, model.GlossaryScopePlaylist, video.PlaylistID)
+		}
+		conds = append(conds, "(scope = ? AND scope_id = ?)")
+		args = append(args, model.GlossaryScopeVideo, video.VideoID)
+	}
+	db = db.Where("("+strings.Join(conds, " OR ")+")", args...)
+
+	var terms []model.GlossaryTerm
+	if err := db.Order("id ASC").Find(&terms).Error; err != nil {
+		return nil, fmt.Errorf("查询术语表失败: %v", err)
+	}
+
+	merged := make(map[string]model.GlossaryTerm, len(terms))
+	var order []string
+	for _, term := range terms {
+		key := strings.ToLower(term.SourceTerm)
+		existing, exists := merged[key]
+		if !exists {
+			order = append(order, key)
+		} else if glossaryScopePriority[existing.Scope] > glossaryScopePriority[term.Scope] {
+			continue
+		}
+		merged[key] = term
+	}
+
+	result := make([]model.GlossaryTerm, 0, len(order))
+	for _, key := range order {
+		result = append(result, merged[key])
+	}
+	return result, nil
+}
+
+// EffectiveTranslatorTerms 获取视频生效的术语并转换为翻译器使用的格式
+func (s *GlossaryService) EffectiveTranslatorTerms(video *model.SavedVideo, targetLang string) ([]translator.GlossaryTerm, error) {
+	terms, err := s.EffectiveTerms(video, targetLang)
+	if err != nil {
+		return nil, err
+	}
+	return ToTranslatorTerms(terms), nil
+}
+
+// SaveSuggestions 保存大模型建议的术语（状态为待审核），已存在或不完整的术语会被跳过
+func (s *GlossaryService) SaveSuggestions(scope, scopeID, targetLang, provider string, suggestions []translator.SuggestedTerm) ([]model.GlossaryTerm, error) {
+	var created []model.GlossaryTerm
+	for _, suggestion := range suggestions {
+		term := &model.GlossaryTerm{
+			Scope:          scope,
+			ScopeID:        scopeID,
+			TargetLang:     targetLang,
+			SourceTerm:     suggestion.Source,
+			TargetTerm:     suggestion.Target,
+			Forms:          strings.Join(suggestion.Forms, ","),
+			DoNotTranslate: suggestion.DoNotTranslate,
+			Status:         model.GlossaryStatusSuggested,
+			Note:           suggestion.Note,
+			CreatedBy:      provider,
+		}
+		if err := s.CreateTerm(term); err != nil {
+			if errors.Is(err, ErrGlossaryTermExists) || errors.Is(err, ErrInvalidGlossaryTerm) {
+				continue
+			}
+			return created, err
+		}
+		created = append(created, *term)
+	}
+	return created, nil
+}
+
+// ToTranslatorTerms 转换为翻译器使用的术语格式
+func ToTranslatorTerms(terms []model.GlossaryTerm) []translator.GlossaryTerm {
+	result := make([]translator.GlossaryTerm, 0, len(terms))
+	for _, term := range terms {
+		result = append(result, translator.GlossaryTerm{
+			Source:         term.SourceTerm,
+			Target:         term.TargetTerm,
+			Forms:          splitGlossaryForms(term.Forms),
+			CaseSensitive:  term.CaseSensitive,
+			DoNotTranslate: term.DoNotTranslate,
+		})
+	}
+	return result
+}
+
+// termExists 检查同一作用域、目标语言下是否已有相同原文术语（excludeID 为更新时排除自身）
+func (s *GlossaryService) termExists(term *model.GlossaryTerm, excludeID uint) (bool, error) {
+	var count int64
+	db := s.DB.Model(&model.GlossaryTerm{}).
+		Where("scope = ? AND scope_id = ? AND target_lang = ? AND LOWER(source_term) = ?",
+			term.Scope, term.ScopeID, term.TargetLang, strings.ToLower(term.SourceTerm))
+	if excludeID > 0 {
+		db = db.Where("id <> ?", excludeID)
+	}
+	if err := db.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func normalizeGlossaryTerm(term *model.GlossaryTerm) {
+	term.SourceTerm = strings.TrimSpace(term.SourceTerm)
+	term.TargetTerm = strings.TrimSpace(term.TargetTerm)
+	term.Forms = strings.Join(splitGlossaryForms(term.Forms), ",")
+	term.TargetLang = translator.NormalizeLangCode(term.TargetLang)
+	if term.TargetLang == "auto" {
+		term.TargetLang = "zh"
+	}
+	if term.Scope == "" {
+		term.Scope = model.GlossaryScopeGlobal
+	}
+	if term.Scope == model.GlossaryScopeGlobal {
+		term.ScopeID = ""
+	}
+	if term.Status == "" {
+		term.Status = model.GlossaryStatusActive
+	}
+}
+
+func validateGlossaryTerm(term *model.GlossaryTerm) error {
+	if !IsValidGlossaryScope(term.Scope) {
+		return fmt.Errorf("%w：无效的作用域: %s", ErrInvalidGlossaryTerm, term.Scope)
+	}
+	if term.Scope != model.GlossaryScopeGlobal && term.ScopeID == "" {
+		return fmt.Errorf("%w：作用域 %s 需要指定 scope_id", ErrInvalidGlossaryTerm, term.Scope)
+	}
+	if term.SourceTerm == "" {
+		return fmt.Errorf("%w：原文术语不能为空", ErrInvalidGlossaryTerm)
+	}
+	if !term.DoNotTranslate && term.TargetTerm == "" {
+		return fmt.Errorf("%w：请填写译法，或将术语标记为不翻译", ErrInvalidGlossaryTerm)
+	}
+	switch term.Status {
+	case model.GlossaryStatusActive, model.GlossaryStatusSuggested, model.GlossaryStatusRejected:
+	default:
+		return fmt.Errorf("%w：无效的状态: %s", ErrInvalidGlossaryTerm, term.Status)
+	}
+	return nil
+}
+
+func splitGlossaryForms(forms string) []string {
+	var result []string
+	for _, form := range strings.Split(forms, ",") {
+		if form = strings.TrimSpace(form); form != "" {
+			result = append(result, form)
+		}
+	}
+	return result
+}
@@ -0,0 +1,335 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/translator"
+
+	"gorm.io/gorm"
+)
+
+// LLMUsageService LLM 用量与费用服务，实现 translator.UsageRecorder
+type LLMUsageService struct {
+	DB     *gorm.DB
+	Config *types.AppConfig
+}
+
+// NewLLMUsageService 创建 LLM 用量服务实例
+func NewLLMUsageService(db *gorm.DB, config *types.AppConfig) *LLMUsageService {
+	return &LLMUsageService{
+		DB:     db,
+		Config: config,
+	}
+}
+
+// LLMUsageTotals 用量汇总
+type LLMUsageTotals struct {
+	Calls        int64   `json:"calls"`
+	FailedCalls  int64   `json:"failed_calls"`
+	InputTokens  int64   `json:"input_tokens"`
+	OutputTokens int64   `json:"output_tokens"`
+	Characters   int64   `json:"characters"`
+	LatencyMs    int64   `json:"latency_ms"`
+	Cost         float64 `json:"cost"`
+}
+
+// LLMUsageBreakdown 按步骤/提供商/模型汇总的用量
+type LLMUsageBreakdown struct {
+	Step     string `json:"step"`
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+	LLMUsageTotals
+}
+
+// VideoLLMUsage 单个视频的用量
+type VideoLLMUsage struct {
+	VideoID   string              `json:"video_id"`
+	Currency  string              `json:"currency"`
+	Totals    LLMUsageTotals      `json:"totals"`
+	Breakdown []LLMUsageBreakdown `json:"breakdown"`
+}
+
+// LLMSpend 某一天（或某个月）某个提供商的费用
+type LLMSpend struct {
+	Period   string `json:"period"` // 2006-01-02 或 2006-01
+	Provider string `json:"provider"`
+	LLMUsageTotals
+}
+
+// LLMBudgetStatus 预算使用情况
+type LLMBudgetStatus struct {
+	Currency      string  `json:"currency"`
+	DailyBudget   float64 `json:"daily_budget"`
+	DailySpent    float64 `json:"daily_spent"`
+	MonthlyBudget float64 `json:"monthly_budget"`
+	MonthlySpent  float64 `json:"monthly_spent"`
+	VideoBudget   float64 `json:"video_budget"`
+	Exceeded      bool    `json:"exceeded"` // 每日或每月预算已用尽，AI 步骤暂停
+	Reason        string  `json:"reason,omitempty"`
+}
+
+// RecordUsage 保存一次调用的用量，提供商未返回费用时按价格表估算
+func (s *LLMUsageService) RecordUsage(ctx context.Context, record *translator.UsageRecord) {
+	cfg := s.usageConfig()
+	if cfg == nil || !cfg.Enabled {
+		return
+	}
+
+	cost := record.Cost
+	if cost == 0 {
+		cost = EstimateLLMCost(cfg, record.Provider, record.Model, record.InputTokens, record.OutputTokens, record.Characters)
+	}
+
+	errMsg := record.Error
+	if runes := []rune(errMsg); len(runes) > 500 {
+		errMsg = string(runes[:500])
+	}
+
+	usage := &model.LLMUsage{
+		VideoID:      record.VideoID,
+		Step:         record.Step,
+		Provider:     record.Provider,
+		Model:        record.Model,
+		Kind:         record.Kind,
+		InputTokens:  record.InputTokens,
+		OutputTokens: record.OutputTokens,
+		Characters:   record.Characters,
+		LatencyMs:    record.Latency.Milliseconds(),
+		Cost:         cost,
+		Success:      record.Success,
+		Error:        errMsg,
+	}
+	// 不使用调用方的 ctx：请求超时或取消时仍然需要记录已经产生的用量
+	if err := s.DB.Create(usage).Error; err != nil {
+		log.Printf("Failed to record LLM usage: %v", err)
+	}
+}
+
+// EstimateLLMCost 按价格表估算费用，价格表优先匹配 "提供商/模型"，其次匹配 "提供商"
+func EstimateLLMCost(cfg *types.LLMUsageConfig, provider, modelName string, inputTokens, outputTokens, characters int) float64 {
+	if cfg == nil || len(cfg.Prices) == 0 {
+		return 0
+	}
+
+	price := cfg.Prices[provider+"/"+modelName]
+	if price == nil {
+		price = cfg.Prices[provider]
+	}
+	if price == nil {
+		return 0
+	}
+
+	return (float64(inputTokens)*price.InputPerMillion +
+		float64(outputTokens)*price.OutputPerMillion +
+		float64(characters)*price.CharsPerMillion) / 1e6
+}
+
+// CheckBudget 检查每日、每月以及指定视频的费用是否已达到上限
+func (s *LLMUsageService) CheckBudget(videoID string) error {
+	cfg := s.usageConfig()
+	if cfg == nil || !cfg.Enabled {
+		return nil
+	}
+
+	status, err := s.BudgetStatus()
+	if err != nil {
+		// 统计失败时不阻塞 AI 调用
+		log.Printf("Failed to check LLM budget: %v", err)
+		return nil
+	}
+	if status.Exceeded {
+		return fmt.Errorf("%w: %s", translator.ErrBudgetExceeded, status.Reason)
+	}
+
+	if videoID != "" && cfg.VideoBudget > 0 {
+		spent, err := s.sumCost(s.DB.Where("video_id = ?", videoID))
+		if err != nil {
+			log.Printf("Failed to check LLM video budget: %v", err)
+			return nil
+		}
+		if spent >= cfg.VideoBudget {
+			return fmt.Errorf("%w: 视频 %s 费用 %.4f %s 已达到单视频上限 %.4f",
+				translator.ErrBudgetExceeded, videoID, spent, cfg.Currency, cfg.VideoBudget)
+		}
+	}
+	return nil
+}
+
+// BudgetStatus 返回今日、本月的费用和预算上限
+func (s *LLMUsageService) BudgetStatus() (*LLMBudgetStatus, error) {
+	cfg := s.usageConfig()
+	status := &LLMBudgetStatus{}
+	if cfg != nil {
+		status.Currency = cfg.Currency
+		status.DailyBudget = cfg.DailyBudget
+		status.MonthlyBudget = cfg.MonthlyBudget
+		status.VideoBudget = cfg.VideoBudget
+	}
+
+	now := time.Now()
+	dayStart := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	monthStart := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+
+	var err error
+	if status.DailySpent, err = s.sumCost(s.DB.Where("created_at >= ?", dayStart)); err != nil {
+		return nil, err
+	}
+	if status.MonthlySpent, err = s.sumCost(s.DB.Where("created_at >= ?", monthStart)); err != nil {
+		return nil, err
+	}
+
+	if status.DailyBudget > 0 && status.DailySpent >= status.DailyBudget {
+		status.Exceeded = true
+		status.Reason = fmt.Sprintf("今日费用 %.4f %s 已达到每日上限 %.4f", status.DailySpent, status.Currency, status.DailyBudget)
+	} else if status.MonthlyBudget > 0 && status.MonthlySpent >= status.MonthlyBudget {
+		status.Exceeded = true
+		status.Reason = fmt.Sprintf("本月费用 %.4f %s 已达到每月上限 %.4f", status.MonthlySpent, status.Currency, status.MonthlyBudget)
+	}
+	return status, nil
+}
+
+// GetVideoUsage 获取单个视频的用量汇总，按步骤/提供商/模型分组
+func (s *LLMUsageService) GetVideoUsage(videoID string) (*VideoLLMUsage, error) {
+	var rows []struct {
+		Step         string
+		Provider     string
+		Model        string
+		Calls        int64
+		FailedCalls  int64
+		InputTokens  int64
+		OutputTokens int64
+		Characters   int64
+		LatencyMs    int64
+		Cost         float64
+	}
+	err := s.DB.Model(&model.LLMUsage{}).
+		Select("step, provider, model, COUNT(*) AS calls, "+
+			"SUM(CASE WHEN success THEN 0 ELSE 1 END) AS failed_calls, "+
+			"SUM(input_tokens) AS input_tokens, SUM(output_tokens) AS output_tokens, "+
+			"SUM(characters) AS characters, SUM(latency_ms) AS latency_ms, SUM(cost) AS cost").
+		Where("video_id = ?", videoID).
+		Group("step, provider, model").
+		Order("step, provider, model").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	result := &VideoLLMUsage{
+		VideoID:   videoID,
+		Breakdown: make([]LLMUsageBreakdown, 0, len(rows)),
+	}
+	if cfg := s.usageConfig(); cfg != nil {
+		result.Currency = cfg.Currency
+	}
+	for _, row := range rows {
+		totals := LLMUsageTotals{
+			Calls:        row.Calls,
+			FailedCalls:  row.FailedCalls,
+			InputTokens:  row.InputTokens,
+			OutputTokens: row.OutputTokens,
+			Characters:   row.Characters,
+			LatencyMs:    row.LatencyMs,
+			Cost:         row.Cost,
+		}
+		result.Breakdown = append(result.Breakdown, LLMUsageBreakdown{
+			Step:           row.Step,
+			Provider:       row.Provider,
+			Model:          row.Model,
+			LLMUsageTotals: totals,
+		})
+		result.Totals.add(totals)
+	}
+	return result, nil
+}
+
+// GetSpend 按天（period=day）或按月（period=month）统计 [from, to) 内各提供商的费用
+func (s *LLMUsageService) GetSpend(period string, from, to time.Time) ([]LLMSpend, error) {
+	layout := "2006-01-02"
+	if period == "month" {
+		layout = "2006-01"
+	}
+
+	// 按日期分组的 SQL 在 MySQL/PostgreSQL 下写法不同，这里取出明细后在内存中汇总
+	var records []model.LLMUsage
+	err := s.DB.Select("created_at, provider, success, input_tokens, output_tokens, characters, latency_ms, cost").
+		Where("created_at >= ? AND created_at < ?", from, to).
+		Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	type spendKey struct{ period, provider string }
+	buckets := make(map[spendKey]*LLMSpend)
+	for _, record := range records {
+		key := spendKey{period: record.CreatedAt.In(from.Location()).Format(layout), provider: record.Provider}
+		bucket, ok := buckets[key]
+		if !ok {
+			bucket = &LLMSpend{Period: key.period, Provider: key.provider}
+			buckets[key] = bucket
+		}
+		bucket.add(LLMUsageTotals{
+			Calls:        1,
+			FailedCalls:  boolToInt64(!record.Success),
+			InputTokens:  int64(record.InputTokens),
+			OutputTokens: int64(record.OutputTokens),
+			Characters:   int64(record.Characters),
+			LatencyMs:    record.LatencyMs,
+			Cost:         record.Cost,
+		})
+	}
+
+	spend := make([]LLMSpend, 0, len(buckets))
+	for _, bucket := range buckets {
+		spend = append(spend, *bucket)
+	}
+	sort.Slice(spend, func(i, j int) bool {
+		if spend[i].Period != spend[j].Period {
+			return spend[i].Period < spend[j].Period
+		}
+		return spend[i].Provider < spend[j].Provider
+	})
+	return spend, nil
+}
+
+// DeleteUsageByVideoID 删除视频的用量记录
+func (s *LLMUsageService) DeleteUsageByVideoID(videoID string) error {
+	return s.DB.Where("video_id = ?", videoID).Delete(&model.LLMUsage{}).Error
+}
+
+func (s *LLMUsageService) usageConfig() *types.LLMUsageConfig {
+	if s.Config == nil {
+		return nil
+	}
+	return s.Config.LLMUsageConfig
+}
+
+// sumCost 汇总满足条件的记录费用
+func (s *LLMUsageService) sumCost(query *gorm.DB) (float64, error) {
+	var total float64
+	err := query.Model(&model.LLMUsage{}).Select("COALESCE(SUM(cost), 0)").Scan(&total).Error
+	return total, err
+}
+
+func (t *LLMUsageTotals) add(other LLMUsageTotals) {
+	t.Calls += other.Calls
+	t.FailedCalls += other.FailedCalls
+	t.InputTokens += other.InputTokens
+	t.OutputTokens += other.OutputTokens
+	t.Characters += other.Characters
+	t.LatencyMs += other.LatencyMs
+	t.Cost += other.Cost
+}
+
+func boolToInt64(value bool) int64 {
+	if value {
+		return 1
+	}
+	return 0
+}
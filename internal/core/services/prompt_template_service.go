@@ -0,0 +1,249 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/difyz9/ytb2bili/pkg/prompt"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrUnknownPrompt 提示词名称不存在
+	ErrUnknownPrompt = errors.New("提示词不存在")
+	// ErrInvalidPromptTemplate 模板无法解析或渲染
+	ErrInvalidPromptTemplate = errors.New("提示词模板无效")
+)
+
+// PromptTemplateService 提示词模板服务：版本化存储在数据库中，实现 prompt.Store 供翻译和元数据生成读取
+type PromptTemplateService struct {
+	DB *gorm.DB
+
+	// 启用版本的缓存（值为 nil 表示使用内置模板），写操作后清空
+	cache map[string]*prompt.Template
+	mu    sync.RWMutex
+}
+
+// NewPromptTemplateService 创建提示词模板服务实例
+func NewPromptTemplateService(db *gorm.DB) *PromptTemplateService {
+	return &PromptTemplateService{
+		DB:    db,
+		cache: make(map[string]*prompt.Template),
+	}
+}
+
+// PromptSummary 提示词概览
+type PromptSummary struct {
+	prompt.Definition
+	ActiveVersion int    `json:"active_version"` // 0 表示使用内置模板
+	ActiveLabel   string `json:"active_label"`
+	LatestVersion int    `json:"latest_version"`
+}
+
+// ActiveTemplate 返回启用的模板版本，没有启用的版本时返回 nil（实现 prompt.Store）
+func (s *PromptTemplateService) ActiveTemplate(name string) (*prompt.Template, error) {
+	s.mu.RLock()
+	tmpl, ok := s.cache[name]
+	s.mu.RUnlock()
+	if ok {
+		return tmpl, nil
+	}
+
+	var record model.PromptTemplate
+	err := s.DB.Where("name = ? AND active = ?", name, true).Order("version DESC").First(&record).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		tmpl = nil
+	case err != nil:
+		return nil, err
+	default:
+		tmpl = toPromptTemplate(&record)
+	}
+
+	s.mu.Lock()
+	s.cache[name] = tmpl
+	s.mu.Unlock()
+	return tmpl, nil
+}
+
+// ListPrompts 列出所有可配置的提示词及其启用版本
+func (s *PromptTemplateService) ListPrompts() ([]PromptSummary, error) {
+	var latest []struct {
+		Name    string
+		Version int
+	}
+	if err := s.DB.Model(&model.PromptTemplate{}).
+		Select("name, MAX(version) AS version").
+		Group("name").
+		Scan(&latest).Error; err != nil {
+		return nil, err
+	}
+	latestVersions := make(map[string]int, len(latest))
+	for _, item := range latest {
+		latestVersions[item.Name] = item.Version
+	}
+
+	definitions := prompt.Definitions()
+	summaries := make([]PromptSummary, 0, len(definitions))
+	for _, definition := range definitions {
+		active, err := s.ActiveTemplate(definition.Name)
+		if err != nil {
+			return nil, err
+		}
+		summary := PromptSummary{
+			Definition:    definition,
+			LatestVersion: latestVersions[definition.Name],
+		}
+		if active == nil {
+			active = &definition.Builtin
+		}
+		summary.ActiveVersion = active.Version
+		summary.ActiveLabel = active.Label()
+		summaries = append(summaries, summary)
+	}
+	return summaries, nil
+}
+
+// ListVersions 列出提示词的所有版本（新版本在前）
+func (s *PromptTemplateService) ListVersions(name string) ([]model.PromptTemplate, error) {
+	if _, ok := prompt.Lookup(name); !ok {
+		return nil, ErrUnknownPrompt
+	}
+	var versions []model.PromptTemplate
+	err := s.DB.Where("name = ?", name).Order("version DESC").Find(&versions).Error
+	return versions, err
+}
+
+// GetTemplate 获取指定版本的模板，版本 0 为内置模板
+func (s *PromptTemplateService) GetTemplate(name string, version int) (*prompt.Template, error) {
+	builtin, ok := prompt.Builtin(name)
+	if !ok {
+		return nil, ErrUnknownPrompt
+	}
+	if version == 0 {
+		return builtin, nil
+	}
+
+	var record model.PromptTemplate
+	if err := s.DB.Where("name = ? AND version = ?", name, version).First(&record).Error; err != nil {
+		return nil, err
+	}
+	return toPromptTemplate(&record), nil
+}
+
+// CreateVersion 保存新版本的模板，activate 为 true 时同时启用
+func (s *PromptTemplateService) CreateVersion(name, system, user, note string, activate bool) (*model.PromptTemplate, error) {
+	if _, ok := prompt.Lookup(name); !ok {
+		return nil, ErrUnknownPrompt
+	}
+	if err := prompt.Validate(&prompt.Template{Name: name, System: system, User: user}); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPromptTemplate, err)
+	}
+
+	record := &model.PromptTemplate{
+		Name:   name,
+		System: system,
+		User:   user,
+		Note:   note,
+		Active: activate,
+	}
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&model.PromptTemplate{}).
+			Where("name = ?", name).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+		record.Version = latest + 1
+
+		if activate {
+			if err := tx.Model(&model.PromptTemplate{}).
+				Where("name = ? AND active = ?", name, true).
+				Update("active", false).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Create(record).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidate(name)
+	return record, nil
+}
+
+// Activate 启用指定版本，版本 0 表示恢复为内置模板
+func (s *PromptTemplateService) Activate(name string, version int) error {
+	if _, ok := prompt.Lookup(name); !ok {
+		return ErrUnknownPrompt
+	}
+
+	err := s.DB.Transaction(func(tx *gorm.DB) error {
+		if version > 0 {
+			var count int64
+			if err := tx.Model(&model.PromptTemplate{}).
+				Where("name = ? AND version = ?", name, version).
+				Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return gorm.ErrRecordNotFound
+			}
+		}
+
+		if err := tx.Model(&model.PromptTemplate{}).
+			Where("name = ? AND active = ?", name, true).
+			Update("active", false).Error; err != nil {
+			return err
+		}
+		if version == 0 {
+			return nil
+		}
+		return tx.Model(&model.PromptTemplate{}).
+			Where("name = ? AND version = ?", name, version).
+			Update("active", true).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	s.invalidate(name)
+	return nil
+}
+
+// invalidate 清除启用版本的缓存
+func (s *PromptTemplateService) invalidate(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.cache, name)
+}
+
+func toPromptTemplate(record *model.PromptTemplate) *prompt.Template {
+	return &prompt.Template{
+		Name:    record.Name,
+		Version: record.Version,
+		System:  record.System,
+		User:    record.User,
+	}
+}
+
+// PromptDataForVideo 根据视频记录填充模板变量中的原视频标题和来源频道
+func PromptDataForVideo(video *model.SavedVideo) *prompt.Data {
+	data := &prompt.Data{}
+	if video == nil {
+		return data
+	}
+	data.SourceTitle = video.Title
+	data.Channel = video.ChannelName
+	if data.Channel == "" {
+		data.Channel = video.ChannelID
+	}
+	data.SourceCategory = video.SourceCategory
+	data.SourceTags = video.SourceTags
+	return data
+}
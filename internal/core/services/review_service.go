@@ -0,0 +1,145 @@
+package services
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidReviewPolicy 审核设置字段不合法
+var ErrInvalidReviewPolicy = errors.New("审核设置无效")
+
+// reviewSendBackReason 审核人退回重新执行的视频的待审核原因前缀
+const reviewSendBackReason = "审核退回重新执行"
+
+// ReviewService 上传前人工审核：审核设置（全局配置 + 频道/播放列表）和审核记录
+type ReviewService struct {
+	DB     *gorm.DB
+	Config *types.AppConfig
+}
+
+// NewReviewService 创建审核服务实例
+func NewReviewService(db *gorm.DB, config *types.AppConfig) *ReviewService {
+	return &ReviewService{
+		DB:     db,
+		Config: config,
+	}
+}
+
+// Required 视频上传前是否需要人工审核：播放列表设置优先，其次频道设置，都没有时使用全局配置
+func (s *ReviewService) Required(video *model.SavedVideo) bool {
+	if video != nil {
+		for _, scope := range []struct{ name, id string }{
+			{model.ReviewScopePlaylist, video.PlaylistID},
+			{model.ReviewScopeChannel, video.ChannelID},
+		} {
+			if scope.id == "" {
+				continue
+			}
+			var policy model.ReviewPolicy
+			err := s.DB.Where("scope = ? AND scope_id = ?", scope.name, scope.id).First(&policy).Error
+			if err == nil {
+				return policy.Required
+			}
+		}
+	}
+	return s.Config.ReviewConfig != nil && s.Config.ReviewConfig.Required
+}
+
+// ListPolicies 查询所有频道/播放列表的审核设置
+func (s *ReviewService) ListPolicies() ([]model.ReviewPolicy, error) {
+	var policies []model.ReviewPolicy
+	err := s.DB.Order("scope ASC, scope_id ASC").Find(&policies).Error
+	return policies, err
+}
+
+// SavePolicy 保存审核设置，同一频道/播放列表已有设置时覆盖
+func (s *ReviewService) SavePolicy(policy *model.ReviewPolicy) error {
+	policy.ScopeID = strings.TrimSpace(policy.ScopeID)
+	if (policy.Scope != model.ReviewScopeChannel && policy.Scope != model.ReviewScopePlaylist) || policy.ScopeID == "" {
+		return ErrInvalidReviewPolicy
+	}
+
+	var existing model.ReviewPolicy
+	err := s.DB.Where("scope = ? AND scope_id = ?", policy.Scope, policy.ScopeID).First(&existing).Error
+	if err == nil {
+		existing.Required = policy.Required
+		existing.Note = policy.Note
+		if err := s.DB.Save(&existing).Error; err != nil {
+			return err
+		}
+		*policy = existing
+		return nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return s.DB.Create(policy).Error
+}
+
+// DeletePolicy 删除审核设置（恢复使用全局配置）
+func (s *ReviewService) DeletePolicy(id uint) error {
+	result := s.DB.Unscoped().Delete(&model.ReviewPolicy{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// AddRecord 保存一条审核记录
+func (s *ReviewService) AddRecord(record *model.ReviewRecord) error {
+	return s.DB.Create(record).Error
+}
+
+// ListRecords 查询视频的审核记录（按时间倒序）
+func (s *ReviewService) ListRecords(videoID string) ([]model.ReviewRecord, error) {
+	var records []model.ReviewRecord
+	err := s.DB.Where("video_id = ?", videoID).Order("id DESC").Find(&records).Error
+	return records, err
+}
+
+// Approve 审核通过：视频恢复为准备就绪（200）进入上传队列，并记录审核意见
+func (s *ReviewService) Approve(video *model.SavedVideo, reviewer, comment string) error {
+	return s.finish(video, "200", &model.ReviewRecord{Action: model.ReviewActionApprove, Reviewer: reviewer, Comment: comment})
+}
+
+// Reject 驳回：视频设为已驳回（160），不再上传
+func (s *ReviewService) Reject(video *model.SavedVideo, reviewer, comment string) error {
+	return s.finish(video, "160", &model.ReviewRecord{Action: model.ReviewActionReject, Reviewer: reviewer, Comment: comment})
+}
+
+// SendBack 退回到指定步骤重新执行：视频保持待审核（150），步骤完成后重新审核
+func (s *ReviewService) SendBack(video *model.SavedVideo, steps []string, reviewer, comment string) error {
+	step := strings.Join(steps, ",")
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.SavedVideo{}).Where("id = ?", video.ID).
+			Updates(map[string]interface{}{"status": "150", "review_reason": reviewSendBackReason + ": " + step}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&model.ReviewRecord{
+			VideoID:  video.VideoID,
+			Action:   model.ReviewActionSendBack,
+			Step:     step,
+			Reviewer: reviewer,
+			Comment:  comment,
+		}).Error
+	})
+}
+
+// finish 更新视频状态并保存审核记录
+func (s *ReviewService) finish(video *model.SavedVideo, status string, record *model.ReviewRecord) error {
+	record.VideoID = video.VideoID
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&model.SavedVideo{}).Where("id = ?", video.ID).Update("status", status).Error; err != nil {
+			return err
+		}
+		return tx.Create(record).Error
+	})
+}
@@ -54,6 +54,16 @@ func (s *SavedVideoService) UpdateStatus(id uint, status string) error {
 		Update("status", status).Error
 }
 
+// MarkNeedsReview 将视频状态设为待人工审核（150）并记录原因（超过 500 个字符时截断）
+func (s *SavedVideoService) MarkNeedsReview(id uint, reason string) error {
+	if runes := []rune(reason); len(runes) > 500 {
+		reason = string(runes[:500])
+	}
+	return s.DB.Model(&model.SavedVideo{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"status": "150", "review_reason": reason}).Error
+}
+
 // UpdateVideo 更新视频信息
 func (s *SavedVideoService) UpdateVideo(video *model.SavedVideo) error {
 	return s.DB.Save(video).Error
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+
+	"gorm.io/gorm"
+)
+
+// ErrRevisionConflict 保存修订时基础版本已不是最新版本（其他人已修改）
+var ErrRevisionConflict = errors.New("字幕已被其他人修改，请刷新后重试")
+
+// SubtitleRevisionService 字幕修订服务
+type SubtitleRevisionService struct {
+	DB *gorm.DB
+}
+
+// NewSubtitleRevisionService 创建字幕修订服务实例
+func NewSubtitleRevisionService(db *gorm.DB) *SubtitleRevisionService {
+	return &SubtitleRevisionService{
+		DB: db,
+	}
+}
+
+// GetLatestVersion 获取最新版本号，没有修订记录时返回 0
+func (s *SubtitleRevisionService) GetLatestVersion(videoID, track string) (int, error) {
+	return latestRevisionVersion(s.DB, videoID, track)
+}
+
+// ListRevisions 获取修订列表（不含内容和差异，按版本倒序）
+func (s *SubtitleRevisionService) ListRevisions(videoID, track string) ([]model.SubtitleRevision, error) {
+	var revisions []model.SubtitleRevision
+	err := s.DB.Omit("content", "diff").
+		Where("video_id = ? AND track = ?", videoID, track).
+		Order("version DESC").
+		Find(&revisions).Error
+	return revisions, err
+}
+
+// GetRevision 获取指定版本
+func (s *SubtitleRevisionService) GetRevision(videoID, track string, version int) (*model.SubtitleRevision, error) {
+	var revision model.SubtitleRevision
+	err := s.DB.Where("video_id = ? AND track = ? AND version = ?", videoID, track, version).
+		First(&revision).Error
+	if err != nil {
+		return nil, err
+	}
+	return &revision, nil
+}
+
+// CreateRevision 以乐观锁方式保存新版本：baseVersion 必须等于当前最新版本，新版本号为 baseVersion+1。
+// apply 不为空时在同一事务内执行（如写入字幕文件），失败则回滚修订记录
+func (s *SubtitleRevisionService) CreateRevision(revision *model.SubtitleRevision, baseVersion int, apply func() error) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		latest, err := latestRevisionVersion(tx, revision.VideoID, revision.Track)
+		if err != nil {
+			return err
+		}
+		if latest != baseVersion {
+			return ErrRevisionConflict
+		}
+
+		revision.Version = baseVersion + 1
+		if err := tx.Create(revision).Error; err != nil {
+			// 并发保存时由唯一索引兜底
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return ErrRevisionConflict
+			}
+			return fmt.Errorf("保存字幕修订失败: %v", err)
+		}
+		if apply != nil {
+			return apply()
+		}
+		return nil
+	})
+}
+
+// DeleteRevisionsByVideoID 删除视频的所有修订记录
+// 使用物理删除，避免重新添加同一视频时与唯一索引冲突
+func (s *SubtitleRevisionService) DeleteRevisionsByVideoID(videoID string) error {
+	return s.DB.Unscoped().Where("video_id = ?", videoID).Delete(&model.SubtitleRevision{}).Error
+}
+
+func latestRevisionVersion(db *gorm.DB, videoID, track string) (int, error) {
+	var latest int
+	err := db.Model(&model.SubtitleRevision{}).
+		Where("video_id = ? AND track = ?", videoID, track).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&latest).Error
+	return latest, err
+}
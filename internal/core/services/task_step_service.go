@@ -23,48 +23,38 @@ func NewTaskStepService(db *gorm.DB) *TaskStepService {
 	}
 }
 
-// InitTaskSteps 初始化视频的任务步骤
-func (s *TaskStepService) InitTaskSteps(videoID string) error {
-	// 定义标准任务步骤
-	steps := []struct {
-		Name     string
-		Order    int
-		CanRetry bool
-	}{
-		{"下载视频", 1, true},
-		{"生成字幕", 2, true},
-		{"翻译字幕", 3, true},
-		{"生成元数据", 4, true},
-		{"上传到Bilibili", 5, true},
-		// {"上传字幕到Bilibili", 6, true},
-	}
-
-	// 检查是否已经初始化过
-	var count int64
-	if err := s.DB.Model(&model.TaskStep{}).Where("video_id = ?", videoID).Count(&count).Error; err != nil {
-		return err
-	}
-
-	if count > 0 {
-		return nil // 已经初始化过，跳过
-	}
+// InitTaskSteps 按任务链顺序初始化视频的任务步骤：创建缺失的步骤记录，已有记录只调整顺序
+func (s *TaskStepService) InitTaskSteps(videoID string, stepNames []string) error {
+	return s.DB.Transaction(func(tx *gorm.DB) error {
+		for i, stepName := range stepNames {
+			var count int64
+			if err := tx.Model(&model.TaskStep{}).
+				Where("video_id = ? AND step_name = ?", videoID, stepName).
+				Count(&count).Error; err != nil {
+				return err
+			}
 
-	// 创建任务步骤记录
-	for _, step := range steps {
-		taskStep := &model.TaskStep{
-			VideoID:   videoID,
-			StepName:  step.Name,
-			StepOrder: step.Order,
-			Status:    model.TaskStepStatusPending,
-			CanRetry:  step.CanRetry,
-		}
+			if count > 0 {
+				if err := tx.Model(&model.TaskStep{}).
+					Where("video_id = ? AND step_name = ?", videoID, stepName).
+					Update("step_order", i+1).Error; err != nil {
+					return err
+				}
+				continue
+			}
 
-		if err := s.DB.Create(taskStep).Error; err != nil {
-			return err
+			if err := tx.Create(&model.TaskStep{
+				VideoID:   videoID,
+				StepName:  stepName,
+				StepOrder: i + 1,
+				Status:    model.TaskStepStatusPending,
+				CanRetry:  true,
+			}).Error; err != nil {
+				return err
+			}
 		}
-	}
-
-	return nil
+		return nil
+	})
 }
 
 // GetTaskStepsByVideoID 根据视频ID获取任务步骤列表
@@ -104,9 +94,7 @@ func (s *TaskStepService) UpdateTaskStepStatus(videoID, stepName, status string,
 		updates["error_msg"] = errorMsg[0]
 	}
 
-	return s.DB.Model(&model.TaskStep{}).
-		Where("video_id = ? AND step_name = ?", videoID, stepName).
-		Updates(updates).Error
+	return s.updateTaskStep(videoID, stepName, updates)
 }
 
 // UpdateTaskStepResult 更新任务步骤执行结果
@@ -123,6 +111,103 @@ func (s *TaskStepService) UpdateTaskStepResult(videoID, stepName string, resultD
 		Update("result_data", jsonData).Error
 }
 
+// SkipTaskStep 将任务步骤标记为跳过，并记录跳过原因
+func (s *TaskStepService) SkipTaskStep(videoID, stepName, reason string) error {
+	now := time.Now()
+	resultData, _ := json.Marshal(map[string]interface{}{
+		"skipped":     true,
+		"skip_reason": reason,
+	})
+
+	updates := map[string]interface{}{
+		"status":      model.TaskStepStatusSkipped,
+		"end_time":    &now,
+		"error_msg":   reason,
+		"result_data": string(resultData),
+	}
+
+	return s.updateTaskStep(videoID, stepName, updates)
+}
+
+// SkipPendingTaskSteps 将视频所有待执行的步骤标记为跳过（任务链中断后未执行的步骤不应被当作待重试步骤）
+func (s *TaskStepService) SkipPendingTaskSteps(videoID, reason string) error {
+	now := time.Now()
+	resultData, _ := json.Marshal(map[string]interface{}{
+		"skipped":     true,
+		"skip_reason": reason,
+	})
+
+	return s.DB.Model(&model.TaskStep{}).
+		Where("video_id = ? AND status = ?", videoID, model.TaskStepStatusPending).
+		Updates(map[string]interface{}{
+			"status":      model.TaskStepStatusSkipped,
+			"end_time":    &now,
+			"error_msg":   reason,
+			"result_data": string(resultData),
+		}).Error
+}
+
+// PauseTaskSteps 将视频所有待执行的步骤标记为预算暂停（AI 费用超出预算，预算恢复后从暂停的步骤继续执行）
+func (s *TaskStepService) PauseTaskSteps(videoID, reason string) error {
+	return s.DB.Model(&model.TaskStep{}).
+		Where("video_id = ? AND status = ?", videoID, model.TaskStepStatusPending).
+		Updates(map[string]interface{}{
+			"status":    model.TaskStepStatusBudgetPaused,
+			"error_msg": reason,
+		}).Error
+}
+
+// ResumeBudgetPausedSteps 将视频预算暂停的步骤恢复为待执行
+func (s *TaskStepService) ResumeBudgetPausedSteps(videoID string) error {
+	return s.DB.Model(&model.TaskStep{}).
+		Where("video_id = ? AND status = ?", videoID, model.TaskStepStatusBudgetPaused).
+		Updates(map[string]interface{}{
+			"status":    model.TaskStepStatusPending,
+			"error_msg": "",
+		}).Error
+}
+
+// GetBudgetPausedVideoIDs 获取有预算暂停步骤的视频ID（不含已删除的视频）
+func (s *TaskStepService) GetBudgetPausedVideoIDs() ([]string, error) {
+	var videoIDs []string
+	err := s.DB.Table("tb_task_steps").
+		Joins("INNER JOIN tb_saved_videos ON tb_task_steps.video_id = tb_saved_videos.video_id").
+		Where("tb_task_steps.status = ?", model.TaskStepStatusBudgetPaused).
+		Where("tb_task_steps.deleted_at IS NULL").
+		Where("tb_saved_videos.deleted_at IS NULL").
+		Distinct("tb_task_steps.video_id").
+		Pluck("tb_task_steps.video_id", &videoIDs).Error
+	return videoIDs, err
+}
+
+// updateTaskStep 更新任务步骤，步骤记录不存在时先创建（排在已有步骤之后）再更新。
+// Updates 同时更新 updated_at，影响行数为 0 即表示记录不存在
+func (s *TaskStepService) updateTaskStep(videoID, stepName string, updates map[string]interface{}) error {
+	result := s.DB.Model(&model.TaskStep{}).
+		Where("video_id = ? AND step_name = ?", videoID, stepName).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	if err := s.createTaskStep(videoID, stepName); err != nil {
+		return fmt.Errorf("创建任务步骤失败: %v", err)
+	}
+	result = s.DB.Model(&model.TaskStep{}).
+		Where("video_id = ? AND step_name = ?", videoID, stepName).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("任务步骤不存在: %s - %s", videoID, stepName)
+	}
+	return nil
+}
+
 // ResetTaskStep 重置任务步骤（用于重新执行）
 func (s *TaskStepService) ResetTaskStep(videoID, stepName string) error {
 	updates := map[string]interface{}{
@@ -139,6 +224,40 @@ func (s *TaskStepService) ResetTaskStep(videoID, stepName string) error {
 		Updates(updates).Error
 }
 
+// RequeueTaskStep 将任务步骤重置为待执行，步骤记录不存在时创建（排在已有步骤之后）
+func (s *TaskStepService) RequeueTaskStep(videoID, stepName string) error {
+	var count int64
+	if err := s.DB.Model(&model.TaskStep{}).
+		Where("video_id = ? AND step_name = ?", videoID, stepName).
+		Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return s.ResetTaskStep(videoID, stepName)
+	}
+
+	return s.createTaskStep(videoID, stepName)
+}
+
+// createTaskStep 创建待执行的任务步骤记录，排在已有步骤之后
+func (s *TaskStepService) createTaskStep(videoID, stepName string) error {
+	var maxOrder int
+	if err := s.DB.Model(&model.TaskStep{}).
+		Where("video_id = ?", videoID).
+		Select("COALESCE(MAX(step_order), 0)").
+		Scan(&maxOrder).Error; err != nil {
+		return err
+	}
+
+	return s.DB.Create(&model.TaskStep{
+		VideoID:   videoID,
+		StepName:  stepName,
+		StepOrder: maxOrder + 1,
+		Status:    model.TaskStepStatusPending,
+		CanRetry:  true,
+	}).Error
+}
+
 // GetTaskStepByName 根据视频ID和步骤名称获取特定步骤
 func (s *TaskStepService) GetTaskStepByName(videoID, stepName string) (*model.TaskStep, error) {
 	var step model.TaskStep
@@ -159,6 +278,8 @@ func (s *TaskStepService) GetTaskProgress(videoID string) (map[string]interface{
 	totalSteps := len(steps)
 	completedSteps := 0
 	failedSteps := 0
+	skippedSteps := 0
+	pausedSteps := 0
 	currentStep := ""
 
 	for _, step := range steps {
@@ -167,6 +288,10 @@ func (s *TaskStepService) GetTaskProgress(videoID string) (map[string]interface{
 			completedSteps++
 		case model.TaskStepStatusFailed:
 			failedSteps++
+		case model.TaskStepStatusSkipped:
+			skippedSteps++
+		case model.TaskStepStatusBudgetPaused:
+			pausedSteps++
 		case model.TaskStepStatusRunning:
 			currentStep = step.StepName
 		}
@@ -176,12 +301,15 @@ func (s *TaskStepService) GetTaskProgress(videoID string) (map[string]interface{
 		"total_steps":      totalSteps,
 		"completed_steps":  completedSteps,
 		"failed_steps":     failedSteps,
+		"skipped_steps":    skippedSteps,
+		"paused_steps":     pausedSteps,
 		"current_step":     currentStep,
 		"progress_percent": 0,
 	}
 
 	if totalSteps > 0 {
-		progress["progress_percent"] = (completedSteps * 100) / totalSteps
+		// 跳过的步骤视为已处理
+		progress["progress_percent"] = ((completedSteps + skippedSteps) * 100) / totalSteps
 	}
 
 	return progress, nil
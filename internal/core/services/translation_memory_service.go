@@ -0,0 +1,344 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/translator"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 进程内的命中/未命中计数（翻译任务每次执行都会新建服务实例，因此放在包级别）
+var (
+	translationMemoryHits   atomic.Int64
+	translationMemoryMisses atomic.Int64
+)
+
+// 单次 IN 查询的最大哈希数量
+const translationMemoryQueryChunk = 500
+
+// TranslationMemoryService 翻译记忆服务，实现 translator.TranslationMemory
+type TranslationMemoryService struct {
+	DB *gorm.DB
+}
+
+// NewTranslationMemoryService 创建翻译记忆服务实例
+func NewTranslationMemoryService(db *gorm.DB) *TranslationMemoryService {
+	return &TranslationMemoryService{
+		DB: db,
+	}
+}
+
+// TranslationMemoryFilter 翻译记忆筛选条件
+type TranslationMemoryFilter struct {
+	Keyword    string    // 原文或译文包含的关键字
+	Provider   string    // 提供商
+	SourceLang string    // 源语言
+	TargetLang string    // 目标语言
+	Before     time.Time // 只匹配此时间之前更新的条目（零值不限制）
+}
+
+// TranslationMemoryProviderStats 按提供商/模型统计
+type TranslationMemoryProviderStats struct {
+	Provider string `json:"provider"`
+	Model    string `json:"model"`
+	Entries  int64  `json:"entries"`
+	Hits     int64  `json:"hits"`
+}
+
+// TranslationMemoryStats 翻译记忆统计
+type TranslationMemoryStats struct {
+	TotalEntries   int64                            `json:"total_entries"`
+	TotalHits      int64                            `json:"total_hits"`     // 所有条目累计命中次数
+	SessionHits    int64                            `json:"session_hits"`   // 本次启动以来命中的句数
+	SessionMisses  int64                            `json:"session_misses"` // 本次启动以来未命中的句数
+	SessionHitRate float64                          `json:"session_hit_rate"`
+	Providers      []TranslationMemoryProviderStats `json:"providers"`
+}
+
+// Lookup 按原文哈希查找译文，多个提供商都命中时按 query.Providers 的顺序取优先级最高的
+func (s *TranslationMemoryService) Lookup(ctx context.Context, query *translator.MemoryLookup) (map[int]*translator.MemoryRecord, error) {
+	hashIndexes := make(map[string][]int)
+	var hashes []string
+	for i, text := range query.Texts {
+		if translator.NormalizeSourceText(text) == "" {
+			continue
+		}
+		hash := translator.SourceTextHash(text)
+		if _, exists := hashIndexes[hash]; !exists {
+			hashes = append(hashes, hash)
+		}
+		hashIndexes[hash] = append(hashIndexes[hash], i)
+	}
+	if len(hashes) == 0 || len(query.Providers) == 0 {
+		return nil, nil
+	}
+
+	priority := make(map[string]int, len(query.Providers))
+	var providerConds []string
+	var providerArgs []interface{}
+	for i, p := range query.Providers {
+		priority[p.Provider+"\x00"+p.Model+"\x00"+p.PromptVersion] = i
+		if p.Provider == translator.MemoryProviderHuman {
+			// 人工译文不区分模型
+			providerConds = append(providerConds, "provider = ?")
+			providerArgs = append(providerArgs, p.Provider)
+			continue
+		}
+		providerConds = append(providerConds, "(provider = ? AND model = ? AND prompt_version = ?)")
+		providerArgs = append(providerArgs, p.Provider, p.Model, p.PromptVersion)
+	}
+
+	best := make(map[string]*model.TranslationMemory)
+	bestPriority := make(map[string]int)
+	for start := 0; start < len(hashes); start += translationMemoryQueryChunk {
+		end := start + translationMemoryQueryChunk
+		if end > len(hashes) {
+			end = len(hashes)
+		}
+
+		db := s.DB.WithContext(ctx).
+			Where("source_hash IN ?", hashes[start:end]).
+			Where("target_lang = ?", translator.NormalizeLangCode(query.TargetLang)).
+			Where("("+strings.Join(providerConds, " OR ")+")", providerArgs...).
+			Where("(provider = ? OR glossary_version = ?)", translator.MemoryProviderHuman, query.GlossaryVersion)
+		if sourceLang := translator.NormalizeLangCode(query.SourceLang); sourceLang != "auto" {
+			db = db.Where("source_lang IN ?", []string{sourceLang, "auto"})
+		}
+		if query.MaxAge > 0 {
+			db = db.Where("(provider = ? OR updated_at >= ?)", translator.MemoryProviderHuman, time.Now().Add(-query.MaxAge))
+		}
+
+		var entries []model.TranslationMemory
+		if err := db.Find(&entries).Error; err != nil {
+			return nil, fmt.Errorf("查询翻译记忆失败: %v", err)
+		}
+
+		for i := range entries {
+			entry := &entries[i]
+			p, ok := priority[entry.Provider+"\x00"+entry.Model+"\x00"+entry.PromptVersion]
+			if !ok {
+				continue
+			}
+			if current, exists := bestPriority[entry.SourceHash]; exists && current <= p {
+				continue
+			}
+			best[entry.SourceHash] = entry
+			bestPriority[entry.SourceHash] = p
+		}
+	}
+
+	hits := make(map[int]*translator.MemoryRecord)
+	hitIDs := make([]uint, 0, len(best))
+	for hash, entry := range best {
+		hitIDs = append(hitIDs, entry.ID)
+		for _, idx := range hashIndexes[hash] {
+			hits[idx] = &translator.MemoryRecord{
+				SourceText:      entry.SourceText,
+				TranslatedText:  entry.TranslatedText,
+				SourceLang:      entry.SourceLang,
+				TargetLang:      entry.TargetLang,
+				Provider:        entry.Provider,
+				Model:           entry.Model,
+				GlossaryVersion: entry.GlossaryVersion,
+				PromptVersion:   entry.PromptVersion,
+			}
+		}
+	}
+
+	lookups := 0
+	for _, indexes := range hashIndexes {
+		lookups += len(indexes)
+	}
+	translationMemoryHits.Add(int64(len(hits)))
+	translationMemoryMisses.Add(int64(lookups - len(hits)))
+
+	if len(hitIDs) > 0 {
+		// UpdateColumns 不更新 updated_at，避免命中刷新过期时间
+		err := s.DB.WithContext(ctx).Model(&model.TranslationMemory{}).
+			Where("id IN ?", hitIDs).
+			UpdateColumns(map[string]interface{}{
+				"hit_count":   gorm.Expr("hit_count + ?", 1),
+				"last_hit_at": time.Now(),
+			}).Error
+		if err != nil {
+			log.Printf("更新翻译记忆命中次数失败: %v", err)
+		}
+	}
+
+	return hits, nil
+}
+
+// Save 保存翻译结果，相同键的条目覆盖译文
+func (s *TranslationMemoryService) Save(ctx context.Context, records []*translator.MemoryRecord) error {
+	entries := make([]model.TranslationMemory, 0, len(records))
+	seen := make(map[string]bool, len(records))
+	for _, record := range records {
+		sourceText := translator.NormalizeSourceText(record.SourceText)
+		translatedText := strings.TrimSpace(record.TranslatedText)
+		if sourceText == "" || translatedText == "" {
+			continue
+		}
+
+		entry := model.TranslationMemory{
+			SourceHash:      translator.SourceTextHash(sourceText),
+			SourceLang:      translator.NormalizeLangCode(record.SourceLang),
+			TargetLang:      translator.NormalizeLangCode(record.TargetLang),
+			Provider:        record.Provider,
+			Model:           record.Model,
+			GlossaryVersion: record.GlossaryVersion,
+			PromptVersion:   record.PromptVersion,
+			SourceText:      sourceText,
+			TranslatedText:  translatedText,
+		}
+		if entry.Provider == translator.MemoryProviderHuman {
+			entry.Model = ""
+			entry.GlossaryVersion = ""
+			entry.PromptVersion = ""
+		}
+
+		key := strings.Join([]string{entry.SourceHash, entry.SourceLang, entry.TargetLang, entry.Provider, entry.Model, entry.GlossaryVersion, entry.PromptVersion}, "\x00")
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		entries = append(entries, entry)
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+
+	err := s.DB.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{
+			{Name: "source_hash"}, {Name: "source_lang"}, {Name: "target_lang"},
+			{Name: "provider"}, {Name: "model"}, {Name: "glossary_version"}, {Name: "prompt_version"},
+		},
+		DoUpdates: clause.AssignmentColumns([]string{"translated_text", "updated_at"}),
+	}).CreateInBatches(&entries, 200).Error
+	if err != nil {
+		return fmt.Errorf("保存翻译记忆失败: %v", err)
+	}
+	return nil
+}
+
+// ListEntries 分页查询翻译记忆条目（按更新时间倒序）
+func (s *TranslationMemoryService) ListEntries(filter TranslationMemoryFilter, offset, limit int) ([]model.TranslationMemory, int64, error) {
+	var entries []model.TranslationMemory
+	var total int64
+
+	db := s.applyFilter(s.DB.Model(&model.TranslationMemory{}), filter)
+	if err := db.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	err := db.Order("updated_at DESC").Offset(offset).Limit(limit).Find(&entries).Error
+	return entries, total, err
+}
+
+// ExportEntries 导出符合条件的所有条目
+func (s *TranslationMemoryService) ExportEntries(filter TranslationMemoryFilter) ([]model.TranslationMemory, error) {
+	var entries []model.TranslationMemory
+	err := s.applyFilter(s.DB.Model(&model.TranslationMemory{}), filter).
+		Order("id ASC").
+		Find(&entries).Error
+	return entries, err
+}
+
+// ImportUnits 导入 TMX 翻译单元，provider 为空时按人工译文保存
+func (s *TranslationMemoryService) ImportUnits(ctx context.Context, units []translator.TMXUnit, provider string) (int, error) {
+	records := make([]*translator.MemoryRecord, 0, len(units))
+	for _, unit := range units {
+		record := &translator.MemoryRecord{
+			SourceText:     unit.SourceText,
+			TranslatedText: unit.TranslatedText,
+			SourceLang:     unit.SourceLang,
+			TargetLang:     unit.TargetLang,
+			Provider:       translator.MemoryProviderHuman,
+		}
+		if provider != "" && provider != translator.MemoryProviderHuman {
+			record.Provider = provider
+			record.Model = unit.Model
+		}
+		records = append(records, record)
+	}
+	if err := s.Save(ctx, records); err != nil {
+		return 0, err
+	}
+	return len(records), nil
+}
+
+// PurgeEntries 物理删除符合条件的条目，返回删除数量
+func (s *TranslationMemoryService) PurgeEntries(filter TranslationMemoryFilter) (int64, error) {
+	db := s.applyFilter(s.DB.Unscoped().Model(&model.TranslationMemory{}), filter)
+	if filter == (TranslationMemoryFilter{}) {
+		// 无筛选条件时清空全部，需要显式的 WHERE 条件绕过 GORM 的全表删除保护
+		db = db.Where("1 = 1")
+	}
+	result := db.Delete(&model.TranslationMemory{})
+	return result.RowsAffected, result.Error
+}
+
+// DeleteEntry 删除单个条目
+func (s *TranslationMemoryService) DeleteEntry(id uint) error {
+	result := s.DB.Unscoped().Delete(&model.TranslationMemory{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetStats 获取翻译记忆统计
+func (s *TranslationMemoryService) GetStats() (*TranslationMemoryStats, error) {
+	stats := &TranslationMemoryStats{
+		SessionHits:   translationMemoryHits.Load(),
+		SessionMisses: translationMemoryMisses.Load(),
+	}
+	if total := stats.SessionHits + stats.SessionMisses; total > 0 {
+		stats.SessionHitRate = float64(stats.SessionHits) / float64(total)
+	}
+
+	err := s.DB.Model(&model.TranslationMemory{}).
+		Select("provider, model, COUNT(*) AS entries, COALESCE(SUM(hit_count), 0) AS hits").
+		Group("provider, model").
+		Order("entries DESC").
+		Scan(&stats.Providers).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range stats.Providers {
+		stats.TotalEntries += p.Entries
+		stats.TotalHits += p.Hits
+	}
+	return stats, nil
+}
+
+// applyFilter 应用筛选条件
+func (s *TranslationMemoryService) applyFilter(db *gorm.DB, filter TranslationMemoryFilter) *gorm.DB {
+	if filter.Keyword != "" {
+		like := "%" + filter.Keyword + "%"
+		db = db.Where("(source_text LIKE ? OR translated_text LIKE ?)", like, like)
+	}
+	if filter.Provider != "" {
+		db = db.Where("provider = ?", filter.Provider)
+	}
+	if filter.SourceLang != "" {
+		db = db.Where("source_lang = ?", translator.NormalizeLangCode(filter.SourceLang))
+	}
+	if filter.TargetLang != "" {
+		db = db.Where("target_lang = ?", translator.NormalizeLangCode(filter.TargetLang))
+	}
+	if !filter.Before.IsZero() {
+		db = db.Where("updated_at < ?", filter.Before)
+	}
+	return db
+}
@@ -22,11 +22,15 @@ type AppConfig struct {
 	DataPath    string        `toml:"data_path"`   // 数据存储路径（用于 cookies 等）
 	YtDlpPath   string        `toml:"yt_dlp_path"` // yt-dlp 安装路径
 
-	PrimaryAIService         string                    `toml:"primary_ai_service"`          // 首选AI服务提供商
+	PrimaryAIService         string                    `toml:"primary_ai_service"`          // 首选AI服务提供商（openai_compatible / deepseek / gemini），失败时按默认顺序回退
 	TenCosConfig             *TencentCosConfig         `toml:"TenCosConfig"`                // 腾讯云 COS 存储配置
 	OpenAICompatibleConfig   *OpenAICompatibleConfig   `toml:"OpenAICompatibleConfig"`      // OpenAI兼容API配置
 	BaiduTransConfig    *BaiduTransConfig    `toml:"BaiduTransConfig"`    // 百度翻译服务配置
 	DeepSeekTransConfig *DeepSeekTransConfig `toml:"DeepSeekTransConfig"` // DeepSeek翻译服务配置
+	OllamaTransConfig   *OllamaTransConfig   `toml:"OllamaTransConfig"`   // Ollama 本地大模型翻译配置
+	GoogleTransConfig   *GoogleTransConfig   `toml:"GoogleTransConfig"`   // Google Cloud Translation 配置
+	MicrosoftTransConfig *MicrosoftTransConfig `toml:"MicrosoftTransConfig"` // Microsoft Translator 配置
+	TencentTransConfig  *TencentTransConfig  `toml:"TencentTransConfig"`  // 腾讯云机器翻译配置
 	GeminiConfig        *GeminiConfig        `toml:"GeminiConfig"`        // Gemini多模态服务配置
 	TranslatorConfig    *TranslatorConfig    `toml:"TranslatorConfig"`    // 翻译器总配置
 	ProxyConfig         *ProxyConfig         `toml:"ProxyConfig"`         // 代理配置
@@ -34,6 +38,18 @@ type AppConfig struct {
 	BilibiliConfig      *BilibiliConfig      `toml:"BilibiliConfig"`      // Bilibili上传配置
 	WhisperConfig       *WhisperConfig       `toml:"WhisperConfig"`       // Whisper 语音识别配置
 	FirebaseConfig      *FirebaseConfig      `toml:"FirebaseConfig"`      // Firebase Backend配置
+	SubtitleConfig      *SubtitleConfig      `toml:"SubtitleConfig"`      // 字幕处理配置
+	BurnInConfig        *BurnInConfig        `toml:"BurnInConfig"`        // 字幕烧录（硬字幕）配置
+	QualityGateConfig   *QualityGateConfig   `toml:"QualityGateConfig"`   // 翻译质量门禁配置
+	LLMUsageConfig      *LLMUsageConfig      `toml:"LLMUsageConfig"`      // LLM 用量、费用与预算配置
+	RateLimitConfig     *RateLimitConfig     `toml:"RateLimitConfig"`     // AI/翻译提供商限流与熔断配置
+	FrameSamplerConfig  *FrameSamplerConfig  `toml:"FrameSamplerConfig"`  // 视频关键帧采样配置（多模态元数据生成）
+	CoverConfig         *CoverConfig         `toml:"CoverConfig"`         // 自动生成封面配置
+	PartitionConfig     *PartitionConfig     `toml:"PartitionConfig"`     // 投稿分区自动选择配置
+	ChapterConfig       *ChapterConfig       `toml:"ChapterConfig"`       // 视频章节生成配置
+	ReviewConfig        *ReviewConfig        `toml:"ReviewConfig"`        // 上传前人工审核配置
+	ComplianceConfig    *ComplianceConfig    `toml:"ComplianceConfig"`    // 上传前内容合规检查配置
+	DubbingConfig       *DubbingConfig       `toml:"DubbingConfig"`       // 语音配音配置
 }
 
 // BilibiliConfig Bilibili上传配置
@@ -44,7 +60,7 @@ type BilibiliConfig struct {
 	UseOriginalTitle    bool   `toml:"use_original_title"`    // true=使用原视频标题, false=使用AI生成标题
 	UseOriginalDesc     bool   `toml:"use_original_desc"`     // true=使用原视频描述, false=使用AI生成描述
 	CustomTitleTemplate string `toml:"custom_title_template"` // 自定义标题模板，支持变量: {original_title}, {ai_title}
-	CustomDescTemplate  string `toml:"custom_desc_template"`  // 自定义描述模板，支持变量: {original_desc}, {ai_desc}
+	CustomDescTemplate  string `toml:"custom_desc_template"`  // 自定义描述模板，支持变量: {original_desc}, {ai_desc}, {chapters}
 
 	// 新增配置项
 	Tid              int    `toml:"tid"`                // 分区ID（默认122，可自定义）
@@ -146,6 +162,43 @@ type DeepSeekTransConfig struct {
 	MaxTokens int    `toml:"max_tokens"` // 最大token数
 }
 
+// OllamaTransConfig Ollama 本地大模型翻译配置（/api/chat 接口）
+type OllamaTransConfig struct {
+	Enabled     bool    `toml:"enabled"`     // 是否启用
+	Endpoint    string  `toml:"endpoint"`    // 服务地址，默认为 http://localhost:11434
+	Model       string  `toml:"model"`       // 使用的模型，如 qwen2.5:7b
+	Timeout     int     `toml:"timeout"`     // 超时时间（秒）
+	Temperature float64 `toml:"temperature"` // 温度参数
+}
+
+// GoogleTransConfig Google Cloud Translation v2 配置
+type GoogleTransConfig struct {
+	Enabled  bool   `toml:"enabled"`  // 是否启用
+	ApiKey   string `toml:"api_key"`  // API密钥
+	Endpoint string `toml:"endpoint"` // API端点，默认为 https://translation.googleapis.com/language/translate/v2
+	Timeout  int    `toml:"timeout"`  // 超时时间（秒）
+}
+
+// MicrosoftTransConfig Microsoft Translator v3 配置
+type MicrosoftTransConfig struct {
+	Enabled  bool   `toml:"enabled"`  // 是否启用
+	ApiKey   string `toml:"api_key"`  // 订阅密钥
+	Region   string `toml:"region"`   // 资源区域，如 eastasia（全局资源可留空）
+	Endpoint string `toml:"endpoint"` // API端点，默认为 https://api.cognitive.microsofttranslator.com
+	Timeout  int    `toml:"timeout"`  // 超时时间（秒）
+}
+
+// TencentTransConfig 腾讯云机器翻译（TMT）配置
+type TencentTransConfig struct {
+	Enabled   bool   `toml:"enabled"`    // 是否启用
+	SecretId  string `toml:"secret_id"`  // 腾讯云 SecretId
+	SecretKey string `toml:"secret_key"` // 腾讯云 SecretKey
+	Region    string `toml:"region"`     // 地域，默认为 ap-guangzhou
+	Endpoint  string `toml:"endpoint"`   // API端点，默认为 https://tmt.tencentcloudapi.com
+	ProjectId int64  `toml:"project_id"` // 项目ID
+	Timeout   int    `toml:"timeout"`    // 超时时间（秒）
+}
+
 // GeminiConfig Gemini多模态服务配置
 type GeminiConfig struct {
 	Enabled           bool   `toml:"enabled"`             // 是否启用Gemini服务
@@ -153,19 +206,20 @@ type GeminiConfig struct {
 	Model             string `toml:"model"`               // 使用的模型，默认为 gemini-1.5-pro
 	Timeout           int    `toml:"timeout"`             // 超时时间（秒）
 	MaxTokens         int    `toml:"max_tokens"`          // 最大输出token数
-	UseForMetadata    bool   `toml:"use_for_metadata"`    // 是否使用Gemini生成元数据（优先于DeepSeek）
+	UseForMetadata    bool   `toml:"use_for_metadata"`    // 是否使用Gemini分析视频画面生成元数据（需同时开启 analyze_video，字幕文本模式按首选AI服务调用）
 	AnalyzeVideo      bool   `toml:"analyze_video"`       // 是否分析视频文件（true=多模态，false=仅文本）
-	VideoSampleFrames int    `toml:"video_sample_frames"` // 视频采样帧数（0=上传完整视频）
+	VideoSampleFrames int    `toml:"video_sample_frames"` // 视频采样帧数（0=上传完整视频，>0 时只发送采样的关键帧和对应字幕）
 }
 
 // TranslatorConfig 翻译器总配置
 type TranslatorConfig struct {
 	DefaultProvider   string   `toml:"default_provider"`   // 默认翻译提供商
 	FallbackProviders []string `toml:"fallback_providers"` // 备选翻译提供商
-	MaxRetries        int      `toml:"max_retries"`        // 最大重试次数
-	Timeout           int      `toml:"timeout"`            // 超时时间（秒）
-	EnableCache       bool     `toml:"enable_cache"`       // 是否启用缓存
-	CacheExpiry       int      `toml:"cache_expiry"`       // 缓存过期时间（秒）
+	MaxRetries        int      `toml:"max_retries"`        // 每个提供商的最大重试次数
+	Timeout           int      `toml:"timeout"`            // 单次请求超时时间（秒）
+	EnableCache       bool     `toml:"enable_cache"`       // 是否启用翻译记忆（数据库缓存）
+	CacheExpiry       int      `toml:"cache_expiry"`       // 机器译文的过期时间（秒），0 表示不过期
+	TargetLanguages   []string `toml:"target_languages"`   // 字幕翻译目标语言（第一个为主语言），视频未单独设置时使用
 }
 
 // ProxyConfig 代理配置
@@ -194,14 +248,15 @@ type WhisperConfig struct {
 
 // OpenAICompatibleConfig OpenAI兼容API配置
 type OpenAICompatibleConfig struct {
-	Enabled     bool    `toml:"enabled"`     // 是否启用
-	Provider    string  `toml:"provider"`    // 提供商: openai, deepseek, qwen, zhipu, gemini等
-	APIKey      string  `toml:"api_key"`     // API密钥
-	BaseURL     string  `toml:"base_url"`    // API基础URL
-	Model       string  `toml:"model"`       // 使用的模型
-	Timeout     int     `toml:"timeout"`     // 超时时间（秒）
-	MaxTokens   int     `toml:"max_tokens"`  // 最大token数
-	Temperature float64 `toml:"temperature"` // 温度参数（0-2）
+	Enabled      bool    `toml:"enabled"`       // 是否启用
+	Provider     string  `toml:"provider"`      // 提供商: openai, deepseek, qwen, zhipu, gemini等
+	APIKey       string  `toml:"api_key"`       // API密钥
+	BaseURL      string  `toml:"base_url"`      // API基础URL
+	Model        string  `toml:"model"`         // 使用的模型
+	Timeout      int     `toml:"timeout"`       // 超时时间（秒）
+	MaxTokens    int     `toml:"max_tokens"`    // 最大token数
+	Temperature  float64 `toml:"temperature"`   // 温度参数（0-2）
+	VisionFrames int     `toml:"vision_frames"` // 模型支持图像输入时，生成元数据发送的视频关键帧数（0=不发送图片）
 }
 
 // FirebaseConfig Firebase Backend配置
@@ -212,6 +267,203 @@ type FirebaseConfig struct {
 	AppSecret string `toml:"app_secret"` // 应用密钥
 }
 
+// SubtitleConfig 字幕处理配置
+type SubtitleConfig struct {
+	Resegment          bool `toml:"resegment"`           // 是否对自动字幕去重并重新断句
+	RestorePunctuation bool `toml:"restore_punctuation"` // 是否使用 LLM 恢复标点（需要启用 DeepSeek）
+	MaxLineChars       int  `toml:"max_line_chars"`      // 每行最大字符数
+	MaxLines           int  `toml:"max_lines"`           // 每条字幕最大行数
+	MaxDurationMs      int  `toml:"max_duration_ms"`     // 每条字幕最长持续时间（毫秒）
+	MinDurationMs      int  `toml:"min_duration_ms"`     // 每条字幕最短持续时间（毫秒）
+	MinGapMs           int  `toml:"min_gap_ms"`          // 相邻字幕最小间隔（毫秒）
+
+	QualityCheck       bool    `toml:"quality_check"`         // 是否对翻译后的字幕进行质检
+	AutoFix            bool    `toml:"auto_fix"`              // 质检时是否自动修复可安全修复的问题
+	MaxCPS             float64 `toml:"max_cps"`               // 中文字幕每秒最大字数
+	TargetMaxLineChars int     `toml:"target_max_line_chars"` // 中文字幕每行最大字数
+
+	Bilingual         bool                        `toml:"bilingual"`          // 是否生成双语字幕（中文在上，原文在下）
+	ASSPreset         string                      `toml:"ass_preset"`         // 双语 ASS 样式预设: default, bilibili, boxed, top 或自定义预设名
+	ASSPresets        map[string]*ASSPresetConfig `toml:"ass_presets"`        // 自定义 ASS 样式预设（未设置的字段沿用 default 预设）
+	UploadTracks      []string                    `toml:"upload_tracks"`      // 上传到 Bilibili 的字幕轨道: zh（译文，每个目标语言一条）, original, bilingual
+	OriginalLanguage  string                      `toml:"original_language"`  // 原文字幕的语言代码
+	BilingualLanguage string                      `toml:"bilingual_language"` // 双语字幕的语言代码（需与译文、原文轨道不同）
+}
+
+// BurnInConfig 字幕烧录（硬字幕）配置
+type BurnInConfig struct {
+	Enabled      bool   `toml:"enabled"`       // 是否将字幕烧录进视频，启用后上传烧录后的视频
+	Track        string `toml:"track"`         // 烧录的字幕轨道: zh, original, bilingual
+	ASSPreset    string `toml:"ass_preset"`    // ASS 样式预设（为空时使用 SubtitleConfig.ass_preset）
+	FontsDir     string `toml:"fonts_dir"`     // 字体目录（可选，供 libass 查找字体）
+	VideoCodec   string `toml:"video_codec"`   // 视频编码器，如 libx264, libx265, h264_nvenc
+	Preset       string `toml:"preset"`        // 编码预设，如 veryfast, medium, slow
+	CRF          int    `toml:"crf"`           // 画质（CRF，越小越清晰）
+	AudioBitrate string `toml:"audio_bitrate"` // 音频码率，为空时直接复制音频流
+}
+
+// QualityGateConfig 翻译质量门禁配置：译文不达标的视频状态设为 150（待人工审核），不会进入上传队列
+type QualityGateConfig struct {
+	Enabled               bool    `toml:"enabled"`                  // 是否启用翻译质量门禁
+	MinCoverage           float64 `toml:"min_coverage"`             // 最低覆盖率（有译文的条目 / 原文条目）
+	MaxEnglishRatio       float64 `toml:"max_english_ratio"`        // 英文残留条目最高占比
+	MinLengthRatio        float64 `toml:"min_length_ratio"`         // 译文/原文长度比下限，低于视为异常
+	MaxLengthRatio        float64 `toml:"max_length_ratio"`         // 译文/原文长度比上限，高于视为异常
+	MaxLengthAnomalyRatio float64 `toml:"max_length_anomaly_ratio"` // 长度比异常条目最高占比
+	LLMSample             bool    `toml:"llm_sample"`               // 是否抽样由 LLM 打分（需要启用 DeepSeek）
+	SampleSize            int     `toml:"sample_size"`              // 抽样条数
+	MinSampleScore        float64 `toml:"min_sample_score"`         // 抽样平均分下限（1-10）
+}
+
+// FrameSamplerConfig 视频关键帧采样配置：Gemini（video_sample_frames > 0）和支持图像输入的
+// OpenAI 兼容模型（vision_frames > 0）生成元数据时，发送采样的关键帧和对应时间点的字幕摘录
+type FrameSamplerConfig struct {
+	Mode           string  `toml:"mode"`            // 采样方式：scene（场景切换检测，不足时均匀补齐）/ interval（均匀间隔）
+	SceneThreshold float64 `toml:"scene_threshold"` // 场景切换阈值（0-1），越小检测到的场景越多
+	MaxWidth       int     `toml:"max_width"`       // 关键帧图片最大宽度（像素）
+	ExcerptSeconds int     `toml:"excerpt_seconds"` // 每帧前后摘录字幕的时间范围（秒）
+}
+
+// CoverConfig 自动生成封面配置：从视频中挑选清晰、非黑场的画面，裁剪为B站封面尺寸并可叠加AI生成的标题
+type CoverConfig struct {
+	Enabled      bool     `toml:"enabled"`       // 是否启用（关闭时使用下载的YouTube缩略图）
+	Candidates   int      `toml:"candidates"`    // 候选画面数
+	Sizes        []string `toml:"sizes"`         // 封面比例：16:10、4:3、16:9，第一个用于默认封面
+	Templates    []string `toml:"templates"`     // 标题样式：plain、bottom_bar、top_banner、outline，第一个用于默认封面
+	OverlayTitle bool     `toml:"overlay_title"` // 是否叠加AI生成的中文标题
+	FontFile     string   `toml:"font_file"`     // 字体文件路径（需支持中文），为空时按 font 查找系统字体
+	Font         string   `toml:"font"`          // fontconfig 字体名称
+}
+
+// PartitionConfig 投稿分区自动选择配置：先按规则匹配来源视频的分类、标签和频道，
+// 未命中时由大模型在B站分区列表中选择，都无法确定时使用 BilibiliConfig.Tid
+type PartitionConfig struct {
+	Enabled    bool            `toml:"enabled"`     // 是否启用
+	UseLLM     bool            `toml:"use_llm"`     // 规则未命中时由大模型选择分区
+	CacheHours int             `toml:"cache_hours"` // 分区列表缓存时间（小时）
+	Rules      []PartitionRule `toml:"rules"`       // 映射规则，按顺序匹配，第一个命中的生效
+}
+
+// PartitionRule 分区映射规则：设置了的条件都满足才算命中，同一条件中的多个值满足任意一个即可
+type PartitionRule struct {
+	Tid        int      `toml:"tid"`        // 命中时使用的分区ID
+	Categories []string `toml:"categories"` // YouTube 分类（如 Music、Gaming，不区分大小写）
+	Keywords   []string `toml:"keywords"`   // 来源标签、原标题或AI生成的标签中包含的关键词（不区分大小写）
+	Channels   []string `toml:"channels"`   // 来源频道ID或名称
+}
+
+// ChapterConfig 视频章节生成配置：来源视频自带章节时翻译章节标题，否则由大模型根据带时间点的字幕划分章节，
+// 章节以时间点列表写入简介（自定义简介模板中使用 {chapters} 占位符）
+type ChapterConfig struct {
+	Enabled           bool `toml:"enabled"`             // 是否启用
+	MinVideoMinutes   int  `toml:"min_video_minutes"`   // 短于该时长（分钟）的视频不生成章节
+	UseLLM            bool `toml:"use_llm"`             // 来源视频没有章节时由大模型根据字幕生成
+	MaxChapters       int  `toml:"max_chapters"`        // 最多章节数
+	MinChapterSeconds int  `toml:"min_chapter_seconds"` // 每章最短时长（秒），间隔过近的章节会被合并
+}
+
+// ReviewConfig 上传前人工审核配置：需要审核的视频处理完成后进入待人工审核（150），审核通过后才进入上传队列
+type ReviewConfig struct {
+	Required bool `toml:"required"` // 默认是否需要审核（频道/播放列表的审核设置优先）
+}
+
+// ComplianceConfig 上传前内容合规检查：检查标题、简介、标签和字幕中的违规词、外部链接和引流文字，
+// 可选由大模型检查字幕内容。有 block 级别的问题时视频进入待人工审核（150），修改并重新检查通过前不会上传
+type ComplianceConfig struct {
+	Enabled        bool             `toml:"enabled"`         // 是否启用合规检查
+	LinkAction     string           `toml:"link_action"`     // 标题和简介中的外部链接：block、replace（上传时删除）、warn
+	QRCodeAction   string           `toml:"qrcode_action"`   // 简介中的二维码、加微信等引流文字：block、warn
+	AllowedDomains []string         `toml:"allowed_domains"` // 不算外部链接的域名（含子域名）
+	CheckSubtitles bool             `toml:"check_subtitles"` // 是否检查主语言字幕中的违规词（字幕已烧录，replace 只作为警告）
+	UseLLM         bool             `toml:"use_llm"`         // 是否由大模型检查字幕内容是否违反平台规范
+	LLMBlock       bool             `toml:"llm_block"`       // 大模型判定为 block 时是否阻止上传（否则只作为警告）
+	Terms          []ComplianceTerm `toml:"terms"`           // 违规词表
+}
+
+// ComplianceTerm 违规词：不区分大小写
+type ComplianceTerm struct {
+	Term        string `toml:"term"`        // 词语，regex = true 时为正则表达式
+	Action      string `toml:"action"`      // block（阻止上传）、replace（上传时替换）、warn（只提示）
+	Replacement string `toml:"replacement"` // action = replace 时的替换文本，为空时删除
+	Regex       bool   `toml:"regex"`       // term 是否为正则表达式
+}
+
+// DubbingConfig 语音配音配置：按主语言字幕逐条合成语音，语音长于字幕时加速以适配时间轴，
+// 与压低音量的原声混音后生成配音版视频（<id>_dubbed.mp4），可选上传配音版代替原视频
+type DubbingConfig struct {
+	Enabled        bool    `toml:"enabled"`         // 是否生成配音
+	Provider       string  `toml:"provider"`        // TTS 提供商: openai（OpenAI 兼容 /audio/speech）、http（本地 TTS 服务）、edge（Edge 风格 TTS 服务）
+	Endpoint       string  `toml:"endpoint"`        // openai: API 基础地址（默认 https://api.openai.com/v1）；http/edge: 合成接口地址
+	APIKey         string  `toml:"api_key"`         // API 密钥（可选，以 Bearer 方式发送）
+	Model          string  `toml:"model"`           // openai: 模型名称（为空时按 tier 选择 tts-1 或 tts-1-hd）
+	Format         string  `toml:"format"`          // 请求的音频格式: mp3, wav
+	Gender         string  `toml:"gender"`          // 音色性别: female, male（voice_name 为空时按性别选择默认音色）
+	Tier           string  `toml:"tier"`            // 音质档位: standard, hd
+	VoiceName      string  `toml:"voice_name"`      // 音色名称，如 nova、zh-CN-XiaoxiaoNeural
+	VoiceSpeed     float64 `toml:"voice_speed"`     // 语速（1.0 为正常语速）
+	Concurrency    int     `toml:"concurrency"`     // 同时合成的条数
+	Timeout        int     `toml:"timeout"`         // 单条合成请求超时（秒）
+	MaxTempo       float64 `toml:"max_tempo"`       // 语音长于字幕时最多加速的倍数，仍然过长时截断
+	OriginalVolume float64 `toml:"original_volume"` // 原声音量（0-1）
+	DuckRatio      float64 `toml:"duck_ratio"`      // 配音时压低原声的压缩比（越大原声越小）
+	VoiceVolume    float64 `toml:"voice_volume"`    // 配音音量
+	AudioBitrate   string  `toml:"audio_bitrate"`   // 配音版视频的音频码率
+	UseForUpload   bool    `toml:"use_for_upload"`  // 上传配音版视频代替原视频
+}
+
+// LLMUsageConfig LLM 用量、费用与预算配置
+type LLMUsageConfig struct {
+	Enabled       bool                   `toml:"enabled"`        // 是否记录每次 LLM/翻译调用的用量
+	Currency      string                 `toml:"currency"`       // 价格和预算使用的货币
+	DailyBudget   float64                `toml:"daily_budget"`   // 每日费用上限（0 表示不限制）
+	MonthlyBudget float64                `toml:"monthly_budget"` // 每月费用上限（0 表示不限制）
+	VideoBudget   float64                `toml:"video_budget"`   // 单个视频费用上限（0 表示不限制）
+	Prices        map[string]*ModelPrice `toml:"prices"`         // 价格表，键为 "提供商/模型" 或 "提供商"（前者优先）
+}
+
+// ModelPrice 模型价格
+type ModelPrice struct {
+	InputPerMillion  float64 `toml:"input_per_million"`  // 每百万输入 token 价格
+	OutputPerMillion float64 `toml:"output_per_million"` // 每百万输出 token 价格
+	CharsPerMillion  float64 `toml:"chars_per_million"`  // 每百万字符价格（按字符计费的机器翻译）
+}
+
+// RateLimitConfig AI/翻译提供商限流与熔断配置，同一提供商的所有调用（翻译、元数据、字幕修复等）共享限额
+type RateLimitConfig struct {
+	Enabled   bool                      `toml:"enabled"`   // 是否启用限流与熔断
+	Providers map[string]*ProviderLimit `toml:"providers"` // 各提供商的限额，键为提供商名称，"default" 用于未列出的提供商
+}
+
+// ProviderLimit 单个提供商的限额与熔断参数（0 表示不限制）
+type ProviderLimit struct {
+	RequestsPerMinute int `toml:"requests_per_minute"` // 每分钟请求数
+	TokensPerMinute   int `toml:"tokens_per_minute"`   // 每分钟 token 数（按请求文本估算）
+	MaxConcurrency    int `toml:"max_concurrency"`     // 最大并发请求数
+	FailureThreshold  int `toml:"failure_threshold"`   // 连续多少次 429/5xx 后熔断
+	CooldownSeconds   int `toml:"cooldown_seconds"`    // 熔断持续时间，之后放行一个探测请求
+}
+
+// ASSPresetConfig 双语 ASS 样式预设配置
+type ASSPresetConfig struct {
+	Primary   *ASSStyleConfig `toml:"primary"`   // 中文（上方）样式
+	Secondary *ASSStyleConfig `toml:"secondary"` // 原文（下方）样式
+}
+
+// ASSStyleConfig ASS 样式配置（颜色为 &HAABBGGRR 格式，零值表示沿用默认值）
+type ASSStyleConfig struct {
+	FontName      string  `toml:"font_name"`      // 字体
+	FontSize      int     `toml:"font_size"`      // 字号（基于 1920x1080）
+	PrimaryColour string  `toml:"primary_colour"` // 文字颜色
+	OutlineColour string  `toml:"outline_colour"` // 描边颜色
+	BackColour    string  `toml:"back_colour"`    // 阴影/背景颜色
+	Bold          bool    `toml:"bold"`           // 是否粗体
+	BorderStyle   int     `toml:"border_style"`   // 1=描边+阴影, 3=不透明背景框
+	Outline       float64 `toml:"outline"`        // 描边宽度
+	Shadow        float64 `toml:"shadow"`         // 阴影深度
+	Alignment     int     `toml:"alignment"`      // 位置（小键盘布局）: 2=底部居中, 8=顶部居中
+	MarginV       int     `toml:"margin_v"`       // 垂直边距
+}
+
 // NewDefaultConfig 创建默认配置
 func NewDefaultConfig() *AppConfig {
 	return &AppConfig{
@@ -268,6 +520,42 @@ func NewDefaultConfig() *AppConfig {
 			MaxTokens: 4000,
 		},
 
+		// 其他翻译提供商（默认关闭，可被 config.toml 覆盖）
+		OllamaTransConfig: &OllamaTransConfig{
+			Enabled:     false,
+			Endpoint:    "http://localhost:11434",
+			Model:       "qwen2.5:7b",
+			Timeout:     120,
+			Temperature: 0.3,
+		},
+		GoogleTransConfig: &GoogleTransConfig{
+			Enabled:  false,
+			Endpoint: "https://translation.googleapis.com/language/translate/v2",
+			Timeout:  30,
+		},
+		MicrosoftTransConfig: &MicrosoftTransConfig{
+			Enabled:  false,
+			Endpoint: "https://api.cognitive.microsofttranslator.com",
+			Timeout:  30,
+		},
+		TencentTransConfig: &TencentTransConfig{
+			Enabled:  false,
+			Region:   "ap-guangzhou",
+			Endpoint: "https://tmt.tencentcloudapi.com",
+			Timeout:  30,
+		},
+
+		// 翻译器配置（默认值，可被 config.toml 覆盖）
+		TranslatorConfig: &TranslatorConfig{
+			DefaultProvider:   "deepseek",
+			FallbackProviders: []string{"baidu"},
+			MaxRetries:        2,
+			Timeout:           120,
+			EnableCache:       true,
+			CacheExpiry:       0,
+			TargetLanguages:   []string{"zh-Hans"},
+		},
+
 		// Gemini 多模态配置（默认值，可被 config.toml 覆盖）
 		GeminiConfig: &GeminiConfig{
 			Enabled:           false,
@@ -275,7 +563,7 @@ func NewDefaultConfig() *AppConfig {
 			Model:             "gemini-2.5-flash",
 			Timeout:           120,
 			MaxTokens:         8000,
-			UseForMetadata:    false, // 默认不启用，优先使用DeepSeek
+			UseForMetadata:    false, // 默认不启用，按首选AI服务根据字幕生成
 			AnalyzeVideo:      true,  // 默认启用视频分析
 			VideoSampleFrames: 0,     // 默认上传完整视频
 		},
@@ -323,6 +611,144 @@ func NewDefaultConfig() *AppConfig {
 			MaxTokens:   4000,
 			Temperature: 0.7,
 		},
+
+		// 字幕处理配置（默认值，可被 config.toml 覆盖）
+		SubtitleConfig: &SubtitleConfig{
+			Resegment:          true,
+			RestorePunctuation: false,
+			MaxLineChars:       42,
+			MaxLines:           2,
+			MaxDurationMs:      7000,
+			MinDurationMs:      1000,
+			MinGapMs:           80,
+			QualityCheck:       true,
+			AutoFix:            true,
+			MaxCPS:             9,
+			TargetMaxLineChars: 20,
+			Bilingual:          true,
+			ASSPreset:          "default",
+			UploadTracks:       []string{"zh", "original"},
+			OriginalLanguage:   "en",
+			BilingualLanguage:  "zh-CN", // 与译文轨道的 zh-Hans 区分，避免上传时被同语言去重跳过
+		},
+		BurnInConfig: &BurnInConfig{
+			Enabled:    false,
+			Track:      "bilingual",
+			VideoCodec: "libx264",
+			Preset:     "medium",
+			CRF:        23,
+		},
+		QualityGateConfig: &QualityGateConfig{
+			Enabled:               true,
+			MinCoverage:           0.95,
+			MaxEnglishRatio:       0.05,
+			MinLengthRatio:        0.08,
+			MaxLengthRatio:        1.5,
+			MaxLengthAnomalyRatio: 0.1,
+			LLMSample:             false,
+			SampleSize:            20,
+			MinSampleScore:        6,
+		},
+		// 默认价格为各提供商公开价格的估算值（美元），可在 config.toml 中按实际账单调整
+		LLMUsageConfig: &LLMUsageConfig{
+			Enabled:  true,
+			Currency: "USD",
+			Prices: map[string]*ModelPrice{
+				"deepseek":  {InputPerMillion: 0.27, OutputPerMillion: 1.10},
+				"openai":    {InputPerMillion: 0.15, OutputPerMillion: 0.60},
+				"gemini":    {InputPerMillion: 1.25, OutputPerMillion: 5.00},
+				"google":    {CharsPerMillion: 20},
+				"microsoft": {CharsPerMillion: 10},
+				"baidu":     {CharsPerMillion: 7},
+				"tencent":   {CharsPerMillion: 7},
+			},
+		},
+		CoverConfig: &CoverConfig{
+			Enabled:      false,
+			Candidates:   4,
+			Sizes:        []string{"16:10", "4:3"},
+			Templates:    []string{"bottom_bar", "plain"},
+			OverlayTitle: true,
+			Font:         "Noto Sans CJK SC",
+		},
+		ReviewConfig: &ReviewConfig{
+			Required: false,
+		},
+		ComplianceConfig: &ComplianceConfig{
+			Enabled:        false,
+			LinkAction:     "replace",
+			QRCodeAction:   "block",
+			AllowedDomains: []string{"bilibili.com", "b23.tv"},
+			CheckSubtitles: true,
+			UseLLM:         false,
+			LLMBlock:       false,
+			Terms: []ComplianceTerm{
+				{Term: "抖音", Action: "warn"},
+				{Term: "快手", Action: "warn"},
+				{Term: "西瓜视频", Action: "warn"},
+				{Term: "小红书", Action: "warn"},
+			},
+		},
+		DubbingConfig: &DubbingConfig{
+			Enabled:        false,
+			Provider:       "openai",
+			Format:         "mp3",
+			Gender:         "female",
+			Tier:           "standard",
+			VoiceSpeed:     1.0,
+			Concurrency:    4,
+			Timeout:        60,
+			MaxTempo:       1.5,
+			OriginalVolume: 0.8,
+			DuckRatio:      8,
+			VoiceVolume:    1.0,
+			AudioBitrate:   "192k",
+			UseForUpload:   true,
+		},
+		ChapterConfig: &ChapterConfig{
+			Enabled:           false,
+			MinVideoMinutes:   8,
+			UseLLM:            true,
+			MaxChapters:       12,
+			MinChapterSeconds: 60,
+		},
+		PartitionConfig: &PartitionConfig{
+			Enabled:    false,
+			UseLLM:     true,
+			CacheHours: 24,
+			Rules: []PartitionRule{
+				{Tid: 76, Keywords: []string{"recipe", "cooking", "美食", "食谱"}},
+				{Tid: 130, Categories: []string{"Music"}},
+				{Tid: 17, Categories: []string{"Gaming"}},
+				{Tid: 95, Categories: []string{"Science & Technology"}},
+				{Tid: 201, Categories: []string{"Education"}},
+				{Tid: 122, Categories: []string{"Howto & Style"}},
+				{Tid: 182, Categories: []string{"Film & Animation"}},
+				{Tid: 176, Categories: []string{"Autos & Vehicles"}},
+				{Tid: 75, Categories: []string{"Pets & Animals"}},
+				{Tid: 138, Categories: []string{"Comedy"}},
+				{Tid: 71, Categories: []string{"Entertainment"}},
+				{Tid: 203, Categories: []string{"News & Politics"}},
+			},
+		},
+		FrameSamplerConfig: &FrameSamplerConfig{
+			Mode:           "scene",
+			SceneThreshold: 0.3,
+			MaxWidth:       768,
+			ExcerptSeconds: 10,
+		},
+		// 默认限额偏保守，按账号的实际配额在 config.toml 中调整
+		RateLimitConfig: &RateLimitConfig{
+			Enabled: true,
+			Providers: map[string]*ProviderLimit{
+				"default":  {RequestsPerMinute: 60, MaxConcurrency: 4, FailureThreshold: 5, CooldownSeconds: 60},
+				"deepseek": {RequestsPerMinute: 60, TokensPerMinute: 500000, MaxConcurrency: 5, FailureThreshold: 5, CooldownSeconds: 60},
+				"openai":   {RequestsPerMinute: 60, TokensPerMinute: 200000, MaxConcurrency: 4, FailureThreshold: 5, CooldownSeconds: 60},
+				"gemini":   {RequestsPerMinute: 15, TokensPerMinute: 1000000, MaxConcurrency: 2, FailureThreshold: 3, CooldownSeconds: 120},
+				"baidu":    {RequestsPerMinute: 60, MaxConcurrency: 1, FailureThreshold: 5, CooldownSeconds: 60},
+				"ollama":   {MaxConcurrency: 2, FailureThreshold: 5, CooldownSeconds: 30},
+			},
+		},
 	}
 }
 
@@ -353,12 +779,30 @@ func LoadConfig(configFile string) (*AppConfig, error) {
 		PrimaryAIService       string                  `toml:"primary_ai_service"`
 		TenCosConfig           *TencentCosConfig       `toml:"TenCosConfig"`
 		OpenAICompatibleConfig *OpenAICompatibleConfig `toml:"OpenAICompatibleConfig"`
+		BaiduTransConfig       *BaiduTransConfig       `toml:"BaiduTransConfig"`
 		DeepSeekTransConfig    *DeepSeekTransConfig    `toml:"DeepSeekTransConfig"`
+		OllamaTransConfig      *OllamaTransConfig      `toml:"OllamaTransConfig"`
+		GoogleTransConfig      *GoogleTransConfig      `toml:"GoogleTransConfig"`
+		MicrosoftTransConfig   *MicrosoftTransConfig   `toml:"MicrosoftTransConfig"`
+		TencentTransConfig     *TencentTransConfig     `toml:"TencentTransConfig"`
+		TranslatorConfig       *TranslatorConfig       `toml:"TranslatorConfig"`
 		GeminiConfig           *GeminiConfig           `toml:"GeminiConfig"`
 		ProxyConfig            *ProxyConfig            `toml:"ProxyConfig"`
 		AnalyticsConfig        *AnalyticsConfig        `toml:"AnalyticsConfig"`
 		BilibiliConfig         *BilibiliConfig         `toml:"BilibiliConfig"`
 		WhisperConfig          *WhisperConfig          `toml:"WhisperConfig"`
+		SubtitleConfig         *SubtitleConfig         `toml:"SubtitleConfig"`
+		BurnInConfig           *BurnInConfig           `toml:"BurnInConfig"`
+		QualityGateConfig      *QualityGateConfig      `toml:"QualityGateConfig"`
+		LLMUsageConfig         *LLMUsageConfig         `toml:"LLMUsageConfig"`
+		RateLimitConfig        *RateLimitConfig        `toml:"RateLimitConfig"`
+		FrameSamplerConfig     *FrameSamplerConfig     `toml:"FrameSamplerConfig"`
+		CoverConfig            *CoverConfig            `toml:"CoverConfig"`
+		PartitionConfig        *PartitionConfig        `toml:"PartitionConfig"`
+		ChapterConfig          *ChapterConfig          `toml:"ChapterConfig"`
+		ReviewConfig           *ReviewConfig           `toml:"ReviewConfig"`
+		ComplianceConfig       *ComplianceConfig       `toml:"ComplianceConfig"`
+		DubbingConfig          *DubbingConfig          `toml:"DubbingConfig"`
 	}
 
 	// 解码TOML配置文件
@@ -389,9 +833,27 @@ func LoadConfig(configFile string) (*AppConfig, error) {
 	if fileConfig.OpenAICompatibleConfig != nil {
 		config.OpenAICompatibleConfig = fileConfig.OpenAICompatibleConfig
 	}
+	if fileConfig.BaiduTransConfig != nil {
+		config.BaiduTransConfig = fileConfig.BaiduTransConfig
+	}
 	if fileConfig.DeepSeekTransConfig != nil {
 		config.DeepSeekTransConfig = fileConfig.DeepSeekTransConfig
 	}
+	if fileConfig.OllamaTransConfig != nil {
+		config.OllamaTransConfig = fileConfig.OllamaTransConfig
+	}
+	if fileConfig.GoogleTransConfig != nil {
+		config.GoogleTransConfig = fileConfig.GoogleTransConfig
+	}
+	if fileConfig.MicrosoftTransConfig != nil {
+		config.MicrosoftTransConfig = fileConfig.MicrosoftTransConfig
+	}
+	if fileConfig.TencentTransConfig != nil {
+		config.TencentTransConfig = fileConfig.TencentTransConfig
+	}
+	if fileConfig.TranslatorConfig != nil {
+		config.TranslatorConfig = fileConfig.TranslatorConfig
+	}
 	if fileConfig.GeminiConfig != nil {
 		config.GeminiConfig = fileConfig.GeminiConfig
 	}
@@ -407,6 +869,42 @@ func LoadConfig(configFile string) (*AppConfig, error) {
 	if fileConfig.WhisperConfig != nil {
 		config.WhisperConfig = fileConfig.WhisperConfig
 	}
+	if fileConfig.SubtitleConfig != nil {
+		config.SubtitleConfig = fileConfig.SubtitleConfig
+	}
+	if fileConfig.BurnInConfig != nil {
+		config.BurnInConfig = fileConfig.BurnInConfig
+	}
+	if fileConfig.QualityGateConfig != nil {
+		config.QualityGateConfig = fileConfig.QualityGateConfig
+	}
+	if fileConfig.LLMUsageConfig != nil {
+		config.LLMUsageConfig = fileConfig.LLMUsageConfig
+	}
+	if fileConfig.RateLimitConfig != nil {
+		config.RateLimitConfig = fileConfig.RateLimitConfig
+	}
+	if fileConfig.FrameSamplerConfig != nil {
+		config.FrameSamplerConfig = fileConfig.FrameSamplerConfig
+	}
+	if fileConfig.CoverConfig != nil {
+		config.CoverConfig = fileConfig.CoverConfig
+	}
+	if fileConfig.PartitionConfig != nil {
+		config.PartitionConfig = fileConfig.PartitionConfig
+	}
+	if fileConfig.ChapterConfig != nil {
+		config.ChapterConfig = fileConfig.ChapterConfig
+	}
+	if fileConfig.ReviewConfig != nil {
+		config.ReviewConfig = fileConfig.ReviewConfig
+	}
+	if fileConfig.ComplianceConfig != nil {
+		config.ComplianceConfig = fileConfig.ComplianceConfig
+	}
+	if fileConfig.DubbingConfig != nil {
+		config.DubbingConfig = fileConfig.DubbingConfig
+	}
 
 
 	return config, nil
@@ -428,12 +926,30 @@ func SaveConfig(config *AppConfig) error {
 		PrimaryAIService       string                  `toml:"primary_ai_service"`
 		TenCosConfig           *TencentCosConfig       `toml:"TenCosConfig"`
 		OpenAICompatibleConfig *OpenAICompatibleConfig `toml:"OpenAICompatibleConfig"`
+		BaiduTransConfig       *BaiduTransConfig       `toml:"BaiduTransConfig"`
 		DeepSeekTransConfig    *DeepSeekTransConfig    `toml:"DeepSeekTransConfig"`
+		OllamaTransConfig      *OllamaTransConfig      `toml:"OllamaTransConfig"`
+		GoogleTransConfig      *GoogleTransConfig      `toml:"GoogleTransConfig"`
+		MicrosoftTransConfig   *MicrosoftTransConfig   `toml:"MicrosoftTransConfig"`
+		TencentTransConfig     *TencentTransConfig     `toml:"TencentTransConfig"`
+		TranslatorConfig       *TranslatorConfig       `toml:"TranslatorConfig"`
 		GeminiConfig           *GeminiConfig           `toml:"GeminiConfig"`
 		ProxyConfig            *ProxyConfig            `toml:"ProxyConfig"`
 		AnalyticsConfig        *AnalyticsConfig        `toml:"AnalyticsConfig"`
 		BilibiliConfig         *BilibiliConfig         `toml:"BilibiliConfig"`
 		WhisperConfig          *WhisperConfig          `toml:"WhisperConfig"`
+		SubtitleConfig         *SubtitleConfig         `toml:"SubtitleConfig"`
+		BurnInConfig           *BurnInConfig           `toml:"BurnInConfig"`
+		QualityGateConfig      *QualityGateConfig      `toml:"QualityGateConfig"`
+		LLMUsageConfig         *LLMUsageConfig         `toml:"LLMUsageConfig"`
+		RateLimitConfig        *RateLimitConfig        `toml:"RateLimitConfig"`
+		FrameSamplerConfig     *FrameSamplerConfig     `toml:"FrameSamplerConfig"`
+		CoverConfig            *CoverConfig            `toml:"CoverConfig"`
+		PartitionConfig        *PartitionConfig        `toml:"PartitionConfig"`
+		ChapterConfig          *ChapterConfig          `toml:"ChapterConfig"`
+		ReviewConfig           *ReviewConfig           `toml:"ReviewConfig"`
+		ComplianceConfig       *ComplianceConfig       `toml:"ComplianceConfig"`
+		DubbingConfig          *DubbingConfig          `toml:"DubbingConfig"`
 	}{
 		Listen:                 config.Listen,
 		Environment:            config.Environment,
@@ -447,12 +963,30 @@ func SaveConfig(config *AppConfig) error {
 		PrimaryAIService:       config.PrimaryAIService,
 		TenCosConfig:           config.TenCosConfig,
 		OpenAICompatibleConfig: config.OpenAICompatibleConfig,
+		BaiduTransConfig:       config.BaiduTransConfig,
 		DeepSeekTransConfig:    config.DeepSeekTransConfig,
+		OllamaTransConfig:      config.OllamaTransConfig,
+		GoogleTransConfig:      config.GoogleTransConfig,
+		MicrosoftTransConfig:   config.MicrosoftTransConfig,
+		TencentTransConfig:     config.TencentTransConfig,
+		TranslatorConfig:       config.TranslatorConfig,
 		GeminiConfig:           config.GeminiConfig,
 		ProxyConfig:            config.ProxyConfig,
 		AnalyticsConfig:        config.AnalyticsConfig,
 		BilibiliConfig:         config.BilibiliConfig,
 		WhisperConfig:          config.WhisperConfig,
+		SubtitleConfig:         config.SubtitleConfig,
+		BurnInConfig:           config.BurnInConfig,
+		QualityGateConfig:      config.QualityGateConfig,
+		LLMUsageConfig:         config.LLMUsageConfig,
+		RateLimitConfig:        config.RateLimitConfig,
+		FrameSamplerConfig:     config.FrameSamplerConfig,
+		CoverConfig:            config.CoverConfig,
+		PartitionConfig:        config.PartitionConfig,
+		ChapterConfig:          config.ChapterConfig,
+		ReviewConfig:           config.ReviewConfig,
+		ComplianceConfig:       config.ComplianceConfig,
+		DubbingConfig:          config.DubbingConfig,
 	}
 
 	buf := new(bytes.Buffer)
@@ -460,7 +994,6 @@ func SaveConfig(config *AppConfig) error {
 	// 写入注释说明
 	buf.WriteString("# Bilibili 视频上传后端 - 配置文件\n\n")
 	buf.WriteString("# 注意：以下配置已硬编码在代码中，无需在此配置：\n")
-	buf.WriteString("# - BaiduTransConfig (百度翻译)\n")
 	buf.WriteString("# - app_auth (应用认证)\n")
 	buf.WriteString("# \n")
 	buf.WriteString("# 所有配置都可以通过 config.toml 或 API 接口动态配置\n\n")
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
 	"github.com/difyz9/bilibili-go-sdk/bilibili"
 	"net/http"
 
@@ -10,11 +11,13 @@ import (
 
 type CategoryHandler struct {
 	BaseHandler
+	PartitionService *services.BiliPartitionService
 }
 
-func NewCategoryHandler(app *core.AppServer) *CategoryHandler {
+func NewCategoryHandler(app *core.AppServer, partitionService *services.BiliPartitionService) *CategoryHandler {
 	return &CategoryHandler{
-		BaseHandler: BaseHandler{App: app},
+		BaseHandler:      BaseHandler{App: app},
+		PartitionService: partitionService,
 	}
 }
 
@@ -53,6 +56,11 @@ func (h *CategoryHandler) getCategoryList(c *gin.Context) {
 		return
 	}
 
+	// 刷新分区列表缓存（选择分区步骤使用）
+	if err := h.PartitionService.Store(archiveData.TypeList); err != nil {
+		h.App.Logger.Warnf("⚠️ 缓存分区列表失败: %v", err)
+	}
+
 	// 返回分区列表
 	c.JSON(http.StatusOK, APIResponse{
 		Code:    200,
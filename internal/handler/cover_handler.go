@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CoverHandler 候选封面：查看生成封面步骤产生的候选封面，手动选择上传时使用的封面
+type CoverHandler struct {
+	BaseHandler
+	SavedVideoService *services.SavedVideoService
+}
+
+func NewCoverHandler(app *core.AppServer, savedVideoService *services.SavedVideoService) *CoverHandler {
+	return &CoverHandler{
+		BaseHandler:       BaseHandler{App: app},
+		SavedVideoService: savedVideoService,
+	}
+}
+
+// RegisterRoutes 注册封面相关路由
+func (h *CoverHandler) RegisterRoutes(api *gin.RouterGroup) {
+	covers := api.Group("/videos/:id/covers")
+	{
+		covers.GET("", h.listCovers)
+		covers.GET("/:name", h.getCoverImage)
+		covers.POST("/:name/select", h.selectCover)
+	}
+}
+
+// CoverCandidateInfo 候选封面及其图片地址
+type CoverCandidateInfo struct {
+	utils.CoverCandidate
+	URL string `json:"url"`
+}
+
+// listCovers 列出候选封面
+func (h *CoverHandler) listCovers(c *gin.Context) {
+	savedVideo, stateManager, ok := h.resolveVideo(c)
+	if !ok {
+		return
+	}
+
+	index, err := utils.LoadCoverIndex(stateManager.CoversDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			c.JSON(http.StatusOK, VideoListResponse{Code: 200, Message: "success", Data: gin.H{"video_id": savedVideo.VideoID, "candidates": []CoverCandidateInfo{}}})
+			return
+		}
+		h.App.Logger.Errorf("读取候选封面失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "读取候选封面失败"})
+		return
+	}
+
+	candidates := make([]CoverCandidateInfo, 0, len(index.Candidates))
+	for _, candidate := range index.Candidates {
+		candidates = append(candidates, CoverCandidateInfo{
+			CoverCandidate: candidate,
+			URL:            "/api/v1/videos/" + savedVideo.VideoID + "/covers/" + candidate.Name,
+		})
+	}
+
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "success",
+		Data: gin.H{
+			"video_id":   savedVideo.VideoID,
+			"candidates": candidates,
+		},
+	})
+}
+
+// getCoverImage 返回候选封面图片
+func (h *CoverHandler) getCoverImage(c *gin.Context) {
+	_, stateManager, ok := h.resolveVideo(c)
+	if !ok {
+		return
+	}
+
+	candidate, ok := h.findCandidate(c, stateManager)
+	if !ok {
+		return
+	}
+	c.File(filepath.Join(stateManager.CoversDir, candidate.Name))
+}
+
+// selectCover 选择上传时使用的封面（复制为 cover.jpg）
+func (h *CoverHandler) selectCover(c *gin.Context) {
+	savedVideo, stateManager, ok := h.resolveVideo(c)
+	if !ok {
+		return
+	}
+
+	candidate, ok := h.findCandidate(c, stateManager)
+	if !ok {
+		return
+	}
+	if err := utils.SelectCover(stateManager.CoversDir, candidate.Name, stateManager.ImageCover); err != nil {
+		h.App.Logger.Errorf("选择封面失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "选择封面失败"})
+		return
+	}
+
+	h.App.Logger.Infof("🖼️ 已选择封面: %s - %s", savedVideo.VideoID, candidate.Name)
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "封面已更新，上传时生效",
+		Data: gin.H{
+			"video_id": savedVideo.VideoID,
+			"selected": candidate.Name,
+		},
+	})
+}
+
+// findCandidate 在候选列表中查找路径参数指定的封面（只允许访问列表中的文件），失败时已写入响应
+func (h *CoverHandler) findCandidate(c *gin.Context, stateManager *manager.StateManager) (*utils.CoverCandidate, bool) {
+	index, err := utils.LoadCoverIndex(stateManager.CoversDir)
+	if err == nil {
+		name := c.Param("name")
+		for i := range index.Candidates {
+			if index.Candidates[i].Name == name {
+				return &index.Candidates[i], true
+			}
+		}
+	}
+	c.JSON(http.StatusNotFound, VideoListResponse{Code: 404, Message: "候选封面不存在"})
+	return nil, false
+}
+
+// resolveVideo 按数字ID或video_id查询视频，失败时已写入响应
+func (h *CoverHandler) resolveVideo(c *gin.Context) (*model.SavedVideo, *manager.StateManager, bool) {
+	idStr := c.Param("id")
+
+	var savedVideo *model.SavedVideo
+	var err error
+	if id, parseErr := strconv.ParseUint(idStr, 10, 32); parseErr == nil {
+		savedVideo, err = h.SavedVideoService.GetByID(uint(id))
+	} else {
+		savedVideo, err = h.SavedVideoService.GetVideoByVideoID(idStr)
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, VideoListResponse{Code: 404, Message: "视频不存在"})
+		return nil, nil, false
+	}
+
+	root, err := filepath.Abs(h.App.Config.FileUpDir)
+	if err != nil {
+		h.App.Logger.Errorf("获取文件上传目录失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "获取文件目录失败"})
+		return nil, nil, false
+	}
+	return savedVideo, manager.NewStateManager(savedVideo.ID, savedVideo.VideoID, root, savedVideo.CreatedAt), true
+}
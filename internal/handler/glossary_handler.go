@@ -0,0 +1,372 @@
+package handler
+
+import (
+	stdcontext "context"
+	"errors"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+	"github.com/difyz9/ytb2bili/pkg/translator"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// 术语提取时发送给大模型的字幕文本上限（字符数）
+const maxGlossarySuggestChars = 12000
+
+// GlossaryHandler 术语表：管理全局/频道/播放列表/视频级术语，以及大模型术语建议的审核
+type GlossaryHandler struct {
+	BaseHandler
+	GlossaryService   *services.GlossaryService
+	SavedVideoService *services.SavedVideoService
+}
+
+func NewGlossaryHandler(app *core.AppServer, glossaryService *services.GlossaryService, savedVideoService *services.SavedVideoService) *GlossaryHandler {
+	return &GlossaryHandler{
+		BaseHandler:       BaseHandler{App: app},
+		GlossaryService:   glossaryService,
+		SavedVideoService: savedVideoService,
+	}
+}
+
+// GlossaryTermRequest 创建/更新术语请求
+type GlossaryTermRequest struct {
+	Scope          string   `json:"scope"`
+	ScopeID        string   `json:"scope_id"`
+	TargetLang     string   `json:"target_lang"`
+	SourceTerm     string   `json:"source_term" binding:"required"`
+	TargetTerm     string   `json:"target_term"`
+	Forms          []string `json:"forms"`
+	CaseSensitive  bool     `json:"case_sensitive"`
+	DoNotTranslate bool     `json:"do_not_translate"`
+	Status         string   `json:"status"`
+	Note           string   `json:"note"`
+}
+
+// SuggestGlossaryRequest 大模型术语建议请求
+type SuggestGlossaryRequest struct {
+	VideoID    string `json:"video_id" binding:"required"`
+	Scope      string `json:"scope"`       // 建议术语保存到的作用域，默认 video
+	TargetLang string `json:"target_lang"` // 默认 zh
+}
+
+// RegisterRoutes 注册术语表相关路由
+func (h *GlossaryHandler) RegisterRoutes(api *gin.RouterGroup) {
+	glossary := api.Group("/glossary")
+	{
+		glossary.GET("/terms", h.listTerms)
+		glossary.POST("/terms", h.createTerm)
+		glossary.PUT("/terms/:id", h.updateTerm)
+		glossary.DELETE("/terms/:id", h.deleteTerm)
+		glossary.POST("/terms/:id/approve", h.approveTerm)
+		glossary.POST("/terms/:id/reject", h.rejectTerm)
+		glossary.GET("/effective", h.getEffectiveTerms)
+		glossary.POST("/suggest", h.suggestTerms)
+	}
+}
+
+// listTerms 查询术语
+// 查询参数: scope, scope_id, target_lang, status, q
+func (h *GlossaryHandler) listTerms(c *gin.Context) {
+	terms, err := h.GlossaryService.ListTerms(services.GlossaryFilter{
+		Scope:      c.Query("scope"),
+		ScopeID:    c.Query("scope_id"),
+		TargetLang: c.Query("target_lang"),
+		Status:     c.Query("status"),
+		Keyword:    c.Query("q"),
+	})
+	if err != nil {
+		h.App.Logger.Errorf("查询术语失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "查询术语失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "success",
+		Data: gin.H{
+			"terms": terms,
+			"total": len(terms),
+		},
+	})
+}
+
+// createTerm 创建术语
+func (h *GlossaryHandler) createTerm(c *gin.Context) {
+	var req GlossaryTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "请求参数错误: " + err.Error()})
+		return
+	}
+
+	term := &model.GlossaryTerm{CreatedBy: "admin"}
+	req.applyTo(term)
+	if err := h.GlossaryService.CreateTerm(term); err != nil {
+		h.respondTermError(c, err, "创建术语失败")
+		return
+	}
+
+	h.App.Logger.Infof("📖 新增术语: %s → %s (%s)", term.SourceTerm, term.TargetTerm, term.Scope)
+	c.JSON(http.StatusOK, VideoListResponse{Code: 200, Message: "success", Data: term})
+}
+
+// updateTerm 更新术语
+func (h *GlossaryHandler) updateTerm(c *gin.Context) {
+	term, ok := h.loadTerm(c)
+	if !ok {
+		return
+	}
+
+	var req GlossaryTermRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "请求参数错误: " + err.Error()})
+		return
+	}
+
+	req.applyTo(term)
+	if err := h.GlossaryService.UpdateTerm(term); err != nil {
+		h.respondTermError(c, err, "更新术语失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, VideoListResponse{Code: 200, Message: "success", Data: term})
+}
+
+// deleteTerm 删除术语
+func (h *GlossaryHandler) deleteTerm(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "无效的术语ID"})
+		return
+	}
+
+	if err := h.GlossaryService.DeleteTerm(uint(id)); err != nil {
+		h.respondTermError(c, err, "删除术语失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, VideoListResponse{Code: 200, Message: "success"})
+}
+
+// approveTerm 审核通过建议术语，之后的翻译会使用该术语
+func (h *GlossaryHandler) approveTerm(c *gin.Context) {
+	h.setTermStatus(c, model.GlossaryStatusActive)
+}
+
+// rejectTerm 拒绝建议术语
+func (h *GlossaryHandler) rejectTerm(c *gin.Context) {
+	h.setTermStatus(c, model.GlossaryStatusRejected)
+}
+
+// getEffectiveTerms 查看视频实际生效的术语（合并各作用域后的结果）
+// 查询参数: video_id（为空时只返回全局术语）, target_lang
+func (h *GlossaryHandler) getEffectiveTerms(c *gin.Context) {
+	var video *model.SavedVideo
+	if videoID := c.Query("video_id"); videoID != "" {
+		v, err := h.SavedVideoService.GetVideoByVideoID(videoID)
+		if err != nil {
+			c.JSON(http.StatusNotFound, VideoListResponse{Code: 404, Message: "视频不存在"})
+			return
+		}
+		video = v
+	}
+
+	terms, err := h.GlossaryService.EffectiveTerms(video, c.DefaultQuery("target_lang", "zh"))
+	if err != nil {
+		h.App.Logger.Errorf("获取生效术语失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "获取生效术语失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "success",
+		Data: gin.H{
+			"terms": terms,
+			"total": len(terms),
+		},
+	})
+}
+
+// suggestTerms 让大模型从视频字幕中提取候选术语，保存为待审核状态
+func (h *GlossaryHandler) suggestTerms(c *gin.Context) {
+	var req SuggestGlossaryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "请求参数错误: " + err.Error()})
+		return
+	}
+	if req.Scope == "" {
+		req.Scope = model.GlossaryScopeVideo
+	}
+	if req.TargetLang == "" {
+		req.TargetLang = "zh"
+	}
+	if !services.IsValidGlossaryScope(req.Scope) {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "无效的作用域: " + req.Scope})
+		return
+	}
+
+	video, err := h.SavedVideoService.GetVideoByVideoID(req.VideoID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, VideoListResponse{Code: 404, Message: "视频不存在"})
+		return
+	}
+
+	scopeID, ok := suggestionScopeID(video, req.Scope)
+	if !ok {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "视频没有对应的" + req.Scope + "信息"})
+		return
+	}
+
+	text, err := h.readTranscript(video)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "视频字幕不存在，请先生成字幕"})
+		return
+	}
+
+	ctx, cancel := stdcontext.WithTimeout(c.Request.Context(), 3*time.Minute)
+	defer cancel()
+
+	suggestions, provider, err := translator.NewTranslatorManager(h.App.Config).SuggestGlossaryTerms(ctx, text, req.TargetLang)
+	if err != nil {
+		h.App.Logger.Errorf("术语提取失败: %v", err)
+		c.JSON(http.StatusBadGateway, VideoListResponse{Code: 502, Message: "术语提取失败: " + err.Error()})
+		return
+	}
+
+	created, err := h.GlossaryService.SaveSuggestions(req.Scope, scopeID, req.TargetLang, provider, suggestions)
+	if err != nil {
+		h.App.Logger.Errorf("保存建议术语失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "保存建议术语失败"})
+		return
+	}
+
+	h.App.Logger.Infof("💡 %s 建议了 %d 条术语，新增待审核 %d 条 (视频: %s)", provider, len(suggestions), len(created), video.VideoID)
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "success",
+		Data: gin.H{
+			"provider":  provider,
+			"suggested": len(suggestions),
+			"terms":     created,
+		},
+	})
+}
+
+// readTranscript 读取视频原文字幕文本，超长时截断
+func (h *GlossaryHandler) readTranscript(video *model.SavedVideo) (string, error) {
+	root, err := filepath.Abs(h.App.Config.FileUpDir)
+	if err != nil {
+		return "", err
+	}
+	stateManager := manager.NewStateManager(video.ID, video.VideoID, root, video.CreatedAt)
+
+	path := stateManager.OriginalSubtitlePath()
+	if path == "" {
+		return "", errors.New("original subtitle not found")
+	}
+	doc, err := subtitle.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	text := strings.Join(doc.Texts(), "\n")
+	if runes := []rune(text); len(runes) > maxGlossarySuggestChars {
+		text = string(runes[:maxGlossarySuggestChars])
+	}
+	if strings.TrimSpace(text) == "" {
+		return "", errors.New("original subtitle is empty")
+	}
+	return text, nil
+}
+
+// setTermStatus 修改术语审核状态
+func (h *GlossaryHandler) setTermStatus(c *gin.Context, status string) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "无效的术语ID"})
+		return
+	}
+
+	term, err := h.GlossaryService.SetStatus(uint(id), status)
+	if err != nil {
+		h.respondTermError(c, err, "修改术语状态失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, VideoListResponse{Code: 200, Message: "success", Data: term})
+}
+
+// loadTerm 读取路径参数中的术语，失败时已写入响应
+func (h *GlossaryHandler) loadTerm(c *gin.Context) (*model.GlossaryTerm, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "无效的术语ID"})
+		return nil, false
+	}
+
+	term, err := h.GlossaryService.GetTerm(uint(id))
+	if err != nil {
+		h.respondTermError(c, err, "获取术语失败")
+		return nil, false
+	}
+	return term, true
+}
+
+// respondTermError 将术语服务的错误转换为响应
+func (h *GlossaryHandler) respondTermError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, VideoListResponse{Code: 404, Message: "术语不存在"})
+	case errors.Is(err, services.ErrInvalidGlossaryTerm):
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: err.Error()})
+	case errors.Is(err, services.ErrGlossaryTermExists):
+		c.JSON(http.StatusConflict, VideoListResponse{Code: 409, Message: err.Error()})
+	default:
+		h.App.Logger.Errorf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: message})
+	}
+}
+
+// applyTo 将请求字段写入术语，作用域、目标语言和状态为空时保持原值
+func (req *GlossaryTermRequest) applyTo(term *model.GlossaryTerm) {
+	if req.Scope != "" {
+		term.Scope = req.Scope
+		term.ScopeID = req.ScopeID
+	}
+	if req.TargetLang != "" {
+		term.TargetLang = req.TargetLang
+	}
+	if req.Status != "" {
+		term.Status = req.Status
+	}
+	term.SourceTerm = req.SourceTerm
+	term.TargetTerm = req.TargetTerm
+	term.Forms = strings.Join(req.Forms, ",")
+	term.CaseSensitive = req.CaseSensitive
+	term.DoNotTranslate = req.DoNotTranslate
+	term.Note = req.Note
+}
+
+// suggestionScopeID 建议术语所属作用域对应的ID
+func suggestionScopeID(video *model.SavedVideo, scope string) (string, bool) {
+	switch scope {
+	case model.GlossaryScopeGlobal:
+		return "", true
+	case model.GlossaryScopeChannel:
+		return video.ChannelID, video.ChannelID != ""
+	case model.GlossaryScopePlaylist:
+		return video.PlaylistID, video.PlaylistID != ""
+	default:
+		return video.VideoID, true
+	}
+}
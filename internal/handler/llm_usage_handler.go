@@ -0,0 +1,178 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/ratelimit"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LLMUsageHandler LLM 用量与费用：按视频、提供商、日期统计，以及预算上限设置
+type LLMUsageHandler struct {
+	BaseHandler
+	UsageService *services.LLMUsageService
+}
+
+func NewLLMUsageHandler(app *core.AppServer, usageService *services.LLMUsageService) *LLMUsageHandler {
+	return &LLMUsageHandler{
+		BaseHandler:  BaseHandler{App: app},
+		UsageService: usageService,
+	}
+}
+
+// UpdateBudgetRequest 更新预算上限请求（字段为空表示不修改，0 表示不限制）
+type UpdateBudgetRequest struct {
+	Enabled       *bool    `json:"enabled"`
+	DailyBudget   *float64 `json:"daily_budget"`
+	MonthlyBudget *float64 `json:"monthly_budget"`
+	VideoBudget   *float64 `json:"video_budget"`
+}
+
+// RegisterRoutes 注册 LLM 用量相关路由
+func (h *LLMUsageHandler) RegisterRoutes(api *gin.RouterGroup) {
+	usage := api.Group("/usage")
+	{
+		usage.GET("/videos/:videoId", h.getVideoUsage)
+		usage.GET("/spend", h.getSpend)
+		usage.GET("/budget", h.getBudget)
+		usage.PUT("/budget", h.updateBudget)
+		usage.GET("/limits", h.getLimits)
+	}
+}
+
+// getVideoUsage 单个视频的用量和费用，按步骤/提供商/模型分组
+func (h *LLMUsageHandler) getVideoUsage(c *gin.Context) {
+	usage, err := h.UsageService.GetVideoUsage(c.Param("videoId"))
+	if err != nil {
+		h.App.Logger.Errorf("获取视频用量失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "获取视频用量失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, VideoListResponse{Code: 200, Message: "success", Data: usage})
+}
+
+// getSpend 按天或按月统计各提供商的费用
+// 查询参数: period=day|month（默认 day），from、to（YYYY-MM-DD，默认最近 30 天或最近 12 个月）
+func (h *LLMUsageHandler) getSpend(c *gin.Context) {
+	period := c.DefaultQuery("period", "day")
+	if period != "day" && period != "month" {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "period 只能是 day 或 month"})
+		return
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	to := today.AddDate(0, 0, 1)
+	from := today.AddDate(0, 0, -29)
+	if period == "month" {
+		from = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()).AddDate(0, -11, 0)
+	}
+
+	var err error
+	if value := c.Query("from"); value != "" {
+		if from, err = time.ParseInLocation("2006-01-02", value, now.Location()); err != nil {
+			c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "from 格式应为 YYYY-MM-DD"})
+			return
+		}
+	}
+	if value := c.Query("to"); value != "" {
+		if to, err = time.ParseInLocation("2006-01-02", value, now.Location()); err != nil {
+			c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "to 格式应为 YYYY-MM-DD"})
+			return
+		}
+		// to 包含当天
+		to = to.AddDate(0, 0, 1)
+	}
+	if !from.Before(to) {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "from 必须早于 to"})
+		return
+	}
+
+	spend, err := h.UsageService.GetSpend(period, from, to)
+	if err != nil {
+		h.App.Logger.Errorf("获取费用统计失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "获取费用统计失败"})
+		return
+	}
+
+	currency := ""
+	if h.App.Config.LLMUsageConfig != nil {
+		currency = h.App.Config.LLMUsageConfig.Currency
+	}
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "success",
+		Data: gin.H{
+			"period":   period,
+			"from":     from.Format("2006-01-02"),
+			"to":       to.AddDate(0, 0, -1).Format("2006-01-02"),
+			"currency": currency,
+			"spend":    spend,
+		},
+	})
+}
+
+// getBudget 当前预算上限与今日、本月已用费用
+func (h *LLMUsageHandler) getBudget(c *gin.Context) {
+	status, err := h.UsageService.BudgetStatus()
+	if err != nil {
+		h.App.Logger.Errorf("获取预算状态失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "获取预算状态失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, VideoListResponse{Code: 200, Message: "success", Data: status})
+}
+
+// getLimits 各提供商的限流与熔断状态
+func (h *LLMUsageHandler) getLimits(c *gin.Context) {
+	c.JSON(http.StatusOK, VideoListResponse{Code: 200, Message: "success", Data: ratelimit.Statuses()})
+}
+
+// updateBudget 更新预算上限并保存到配置文件，立即生效
+func (h *LLMUsageHandler) updateBudget(c *gin.Context) {
+	var req UpdateBudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "请求参数错误: " + err.Error()})
+		return
+	}
+	for _, value := range []*float64{req.DailyBudget, req.MonthlyBudget, req.VideoBudget} {
+		if value != nil && *value < 0 {
+			c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "预算上限不能为负数"})
+			return
+		}
+	}
+
+	if h.App.Config.LLMUsageConfig == nil {
+		h.App.Config.LLMUsageConfig = types.NewDefaultConfig().LLMUsageConfig
+	}
+	config := h.App.Config.LLMUsageConfig
+	if req.Enabled != nil {
+		config.Enabled = *req.Enabled
+	}
+	if req.DailyBudget != nil {
+		config.DailyBudget = *req.DailyBudget
+	}
+	if req.MonthlyBudget != nil {
+		config.MonthlyBudget = *req.MonthlyBudget
+	}
+	if req.VideoBudget != nil {
+		config.VideoBudget = *req.VideoBudget
+	}
+
+	if err := types.SaveConfig(h.App.Config); err != nil {
+		h.App.Logger.Errorf("Failed to save config: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "保存配置失败: " + err.Error()})
+		return
+	}
+
+	h.App.Logger.Infof("💰 预算上限已更新: 每日 %.2f, 每月 %.2f, 单视频 %.2f %s",
+		config.DailyBudget, config.MonthlyBudget, config.VideoBudget, config.Currency)
+	h.getBudget(c)
+}
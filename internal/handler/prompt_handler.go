@@ -0,0 +1,335 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/pkg/prompt"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+	"github.com/difyz9/ytb2bili/pkg/translator"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// 预览时截取的字幕长度，与元数据生成步骤一致
+const promptPreviewSubtitleChars = 1000
+
+// PromptHandler 提示词模板：查看、新增版本、切换启用版本，以及用指定视频预览渲染结果
+type PromptHandler struct {
+	BaseHandler
+	PromptService     *services.PromptTemplateService
+	SavedVideoService *services.SavedVideoService
+	GlossaryService   *services.GlossaryService
+}
+
+func NewPromptHandler(app *core.AppServer, promptService *services.PromptTemplateService, savedVideoService *services.SavedVideoService, glossaryService *services.GlossaryService) *PromptHandler {
+	return &PromptHandler{
+		BaseHandler:       BaseHandler{App: app},
+		PromptService:     promptService,
+		SavedVideoService: savedVideoService,
+		GlossaryService:   glossaryService,
+	}
+}
+
+// CreatePromptVersionRequest 新增模板版本请求
+type CreatePromptVersionRequest struct {
+	System   string `json:"system"`
+	User     string `json:"user"`
+	Note     string `json:"note"`
+	Activate bool   `json:"activate"` // 保存后立即启用
+}
+
+// ActivatePromptRequest 切换启用版本请求（version 为 0 表示恢复内置模板）
+type ActivatePromptRequest struct {
+	Version *int `json:"version" binding:"required"`
+}
+
+// PreviewPromptRequest 预览请求：提供 system/user 时预览未保存的草稿，否则预览指定版本（默认为启用版本）
+type PreviewPromptRequest struct {
+	VideoID    string  `json:"video_id" binding:"required"`
+	Version    *int    `json:"version"`
+	System     *string `json:"system"`
+	User       *string `json:"user"`
+	TargetLang string  `json:"target_lang"` // 翻译类提示词的目标语言，默认为视频的主语言
+}
+
+// RegisterRoutes 注册提示词模板相关路由
+func (h *PromptHandler) RegisterRoutes(api *gin.RouterGroup) {
+	prompts := api.Group("/prompts")
+	{
+		prompts.GET("", h.listPrompts)
+		prompts.GET("/:name", h.getPrompt)
+		prompts.GET("/:name/versions/:version", h.getVersion)
+		prompts.POST("/:name/versions", h.createVersion)
+		prompts.PUT("/:name/active", h.activateVersion)
+		prompts.POST("/:name/preview", h.previewPrompt)
+	}
+}
+
+// listPrompts 所有可配置的提示词及当前启用的版本
+func (h *PromptHandler) listPrompts(c *gin.Context) {
+	prompts, err := h.PromptService.ListPrompts()
+	if err != nil {
+		h.App.Logger.Errorf("获取提示词列表失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "获取提示词列表失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, VideoListResponse{Code: 200, Message: "success", Data: prompts})
+}
+
+// getPrompt 提示词定义、内置模板和所有已保存的版本
+func (h *PromptHandler) getPrompt(c *gin.Context) {
+	name := c.Param("name")
+	definition, ok := prompt.Lookup(name)
+	if !ok {
+		c.JSON(http.StatusNotFound, VideoListResponse{Code: 404, Message: "提示词不存在"})
+		return
+	}
+
+	versions, err := h.PromptService.ListVersions(name)
+	if err != nil {
+		h.respondPromptError(c, err, "获取提示词版本失败")
+		return
+	}
+	active, err := h.PromptService.ActiveTemplate(name)
+	if err != nil {
+		h.respondPromptError(c, err, "获取提示词版本失败")
+		return
+	}
+	activeVersion := 0
+	if active != nil {
+		activeVersion = active.Version
+	}
+
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "success",
+		Data: gin.H{
+			"definition":     definition,
+			"active_version": activeVersion,
+			"versions":       versions,
+		},
+	})
+}
+
+// getVersion 指定版本的模板，版本 0 为内置模板
+func (h *PromptHandler) getVersion(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 0 {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "无效的版本号"})
+		return
+	}
+
+	tmpl, err := h.PromptService.GetTemplate(c.Param("name"), version)
+	if err != nil {
+		h.respondPromptError(c, err, "获取提示词版本失败")
+		return
+	}
+
+	c.JSON(http.StatusOK, VideoListResponse{Code: 200, Message: "success", Data: tmpl})
+}
+
+// createVersion 保存新版本（模板先用示例数据校验能否渲染）
+func (h *PromptHandler) createVersion(c *gin.Context) {
+	var req CreatePromptVersionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "请求参数错误: " + err.Error()})
+		return
+	}
+
+	name := c.Param("name")
+	record, err := h.PromptService.CreateVersion(name, req.System, req.User, req.Note, req.Activate)
+	if err != nil {
+		h.respondPromptError(c, err, "保存提示词失败")
+		return
+	}
+
+	if req.Activate {
+		h.App.Logger.Infof("📝 提示词 %s 已保存并启用版本 v%d", name, record.Version)
+	} else {
+		h.App.Logger.Infof("📝 提示词 %s 已保存版本 v%d", name, record.Version)
+	}
+	c.JSON(http.StatusOK, VideoListResponse{Code: 200, Message: "success", Data: record})
+}
+
+// activateVersion 切换启用的版本，之后的任务立即使用新版本
+func (h *PromptHandler) activateVersion(c *gin.Context) {
+	var req ActivatePromptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "请求参数错误: " + err.Error()})
+		return
+	}
+	if *req.Version < 0 {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "无效的版本号"})
+		return
+	}
+
+	name := c.Param("name")
+	if err := h.PromptService.Activate(name, *req.Version); err != nil {
+		h.respondPromptError(c, err, "切换提示词版本失败")
+		return
+	}
+
+	tmpl := prompt.Active(name)
+	h.App.Logger.Infof("📝 提示词 %s 已切换为 %s", name, tmpl.Label())
+	c.JSON(http.StatusOK, VideoListResponse{Code: 200, Message: "success", Data: tmpl})
+}
+
+// previewPrompt 用指定视频的标题、频道、字幕和术语表渲染模板，不调用大模型
+func (h *PromptHandler) previewPrompt(c *gin.Context) {
+	var req PreviewPromptRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "请求参数错误: " + err.Error()})
+		return
+	}
+
+	name := c.Param("name")
+	var tmpl *prompt.Template
+	switch {
+	case req.System != nil || req.User != nil:
+		tmpl = &prompt.Template{Name: name}
+		if req.System != nil {
+			tmpl.System = *req.System
+		}
+		if req.User != nil {
+			tmpl.User = *req.User
+		}
+		if err := prompt.Validate(tmpl); err != nil {
+			c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: err.Error()})
+			return
+		}
+	case req.Version != nil:
+		var err error
+		if tmpl, err = h.PromptService.GetTemplate(name, *req.Version); err != nil {
+			h.respondPromptError(c, err, "获取提示词版本失败")
+			return
+		}
+	default:
+		if _, ok := prompt.Lookup(name); !ok {
+			c.JSON(http.StatusNotFound, VideoListResponse{Code: 404, Message: "提示词不存在"})
+			return
+		}
+		tmpl = prompt.Active(name)
+	}
+
+	video, err := h.SavedVideoService.GetVideoByVideoID(req.VideoID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, VideoListResponse{Code: 404, Message: "视频不存在"})
+		return
+	}
+
+	data := h.previewData(name, video, req.TargetLang)
+	rendered, err := tmpl.Render(data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: err.Error()})
+		return
+	}
+	if tmpl.Version == 0 && (req.System != nil || req.User != nil) {
+		rendered.Version = name + "@draft"
+	}
+	// 与实际调用一致：翻译类提示词由程序追加结构化格式说明
+	if name == prompt.Translation || name == prompt.SubtitleFix {
+		rendered.System += "\n" + translator.CueFormatPrompt()
+	}
+
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "success",
+		Data: gin.H{
+			"rendered": rendered,
+			"data":     data,
+		},
+	})
+}
+
+// previewData 按实际任务的取值方式准备模板变量：翻译类提示词使用原文字幕和目标语言术语表，
+// 元数据提示词使用主语言译文字幕
+func (h *PromptHandler) previewData(name string, video *model.SavedVideo, targetLang string) *prompt.Data {
+	data := services.PromptDataForVideo(video)
+
+	language := h.previewTargetLanguage(video, targetLang)
+	if name == prompt.SubtitleFix {
+		language = subtitle.LanguageSimplifiedChinese
+	}
+
+	root, err := filepath.Abs(h.App.Config.FileUpDir)
+	if err != nil {
+		return data
+	}
+	stateManager := manager.NewStateManager(video.ID, video.VideoID, root, video.CreatedAt)
+
+	switch name {
+	case prompt.Translation, prompt.SubtitleFix:
+		code := translator.NormalizeLangCode(language)
+		data.TargetLanguage = translator.LanguageName(code)
+		data.TextType = "subtitle"
+		if path := stateManager.OriginalSubtitlePath(); path != "" {
+			data.SubtitleText = readSubtitleText(path)
+		}
+		glossary, err := h.GlossaryService.EffectiveTranslatorTerms(video, code)
+		if err != nil {
+			h.App.Logger.Warnf("⚠️  加载术语表失败: %v", err)
+		}
+		data.Glossary = translator.GlossaryPrompt(translator.FilterGlossary(glossary, []string{data.SubtitleText}))
+	default:
+		path := stateManager.TranslatedSRTPath(language)
+		if _, err := os.Stat(path); err != nil {
+			path = stateManager.TranslateSRT
+		}
+		data.SubtitleText = readSubtitleText(path)
+	}
+	return data
+}
+
+// previewTargetLanguage 预览使用的目标语言：请求指定优先，其次视频设置的主语言，最后全局配置
+func (h *PromptHandler) previewTargetLanguage(video *model.SavedVideo, targetLang string) string {
+	candidates := []string{targetLang}
+	if video.TargetLanguages != "" {
+		candidates = append(candidates, strings.Split(video.TargetLanguages, ",")...)
+	}
+	if cfg := h.App.Config.TranslatorConfig; cfg != nil {
+		candidates = append(candidates, cfg.TargetLanguages...)
+	}
+	if languages := subtitle.NormalizeLanguages(candidates); len(languages) > 0 {
+		return languages[0]
+	}
+	return subtitle.LanguageSimplifiedChinese
+}
+
+// readSubtitleText 读取字幕纯文本并截断，文件不存在或无法解析时返回空字符串
+func readSubtitleText(path string) string {
+	doc, err := subtitle.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	text := doc.PlainText(" ")
+	if runes := []rune(text); len(runes) > promptPreviewSubtitleChars {
+		text = string(runes[:promptPreviewSubtitleChars]) + "..."
+	}
+	return text
+}
+
+// respondPromptError 将提示词服务的错误转换为响应
+func (h *PromptHandler) respondPromptError(c *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, services.ErrUnknownPrompt):
+		c.JSON(http.StatusNotFound, VideoListResponse{Code: 404, Message: "提示词不存在"})
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.JSON(http.StatusNotFound, VideoListResponse{Code: 404, Message: "提示词版本不存在"})
+	case errors.Is(err, services.ErrInvalidPromptTemplate):
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: err.Error()})
+	default:
+		h.App.Logger.Errorf("%s: %v", message, err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: message})
+	}
+}
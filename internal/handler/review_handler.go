@@ -0,0 +1,632 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// 审核时允许退回重新执行的步骤（按任务链顺序执行）
+var reviewSendBackSteps = map[string]bool{
+	"翻译字幕":    true,
+	"字幕质检":    true,
+	"翻译质量门禁":  true,
+	"生成双语字幕":  true,
+	"烧录字幕":    true,
+	"生成配音":    true,
+	"生成视频元数据": true,
+	"生成封面":    true,
+	"选择分区":    true,
+	"生成章节":    true,
+	"合规检查":    true,
+}
+
+// 审核时上传封面的大小限制
+const maxReviewCoverSize = 5 << 20
+
+// ReviewHandler 上传前人工审核：待审核队列、修改投稿信息、审核通过/驳回/退回，以及频道/播放列表的审核设置
+type ReviewHandler struct {
+	BaseHandler
+	SavedVideoService *services.SavedVideoService
+	TaskStepService   *services.TaskStepService
+	ReviewService     *services.ReviewService
+}
+
+func NewReviewHandler(app *core.AppServer, savedVideoService *services.SavedVideoService, taskStepService *services.TaskStepService, reviewService *services.ReviewService) *ReviewHandler {
+	return &ReviewHandler{
+		BaseHandler:       BaseHandler{App: app},
+		SavedVideoService: savedVideoService,
+		TaskStepService:   taskStepService,
+		ReviewService:     reviewService,
+	}
+}
+
+// RegisterRoutes 注册审核相关路由
+func (h *ReviewHandler) RegisterRoutes(api *gin.RouterGroup) {
+	reviews := api.Group("/reviews")
+	{
+		reviews.GET("", h.listQueue)
+		reviews.GET("/policies", h.listPolicies)
+		reviews.PUT("/policies", h.savePolicy)
+		reviews.DELETE("/policies/:policyId", h.deletePolicy)
+		reviews.GET("/:id", h.getReview)
+		reviews.PUT("/:id", h.editReview)
+		reviews.GET("/:id/cover", h.getCover)
+		reviews.POST("/:id/cover", h.uploadCover)
+		reviews.POST("/:id/approve", h.approve)
+		reviews.POST("/:id/reject", h.reject)
+		reviews.POST("/:id/send-back", h.sendBack)
+	}
+}
+
+// ReviewActionRequest 审核通过/驳回请求
+type ReviewActionRequest struct {
+	Reviewer string `json:"reviewer"`
+	Comment  string `json:"comment"`
+}
+
+// ReviewSendBackRequest 退回重新执行请求
+type ReviewSendBackRequest struct {
+	Steps    []string `json:"steps" binding:"required"`
+	Reviewer string   `json:"reviewer"`
+	Comment  string   `json:"comment"`
+}
+
+// ReviewEditRequest 修改投稿信息请求，未提供的字段保持不变
+type ReviewEditRequest struct {
+	Title       *string `json:"title"`
+	Description *string `json:"description"`
+	Tags        *string `json:"tags"`
+	Tid         *int    `json:"tid"`
+	Reviewer    string  `json:"reviewer"`
+	Comment     string  `json:"comment"`
+}
+
+// ReviewQueueItem 待审核队列中的视频
+type ReviewQueueItem struct {
+	ID           uint   `json:"id"`
+	VideoID      string `json:"video_id"`
+	Title        string `json:"title"`
+	UploadTitle  string `json:"upload_title"`
+	ChannelName  string `json:"channel_name"`
+	PlaylistID   string `json:"playlist_id"`
+	ReviewReason string `json:"review_reason"`
+	UpdatedAt    string `json:"updated_at"`
+}
+
+// listQueue 待审核（150）的视频
+func (h *ReviewHandler) listQueue(c *gin.Context) {
+	page := h.GetInt(c, "page", 1)
+	limit := h.GetInt(c, "limit", 20)
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	videos, total, err := h.SavedVideoService.ListVideos(page, limit, "150")
+	if err != nil {
+		h.App.Logger.Errorf("获取待审核视频失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "获取待审核视频失败"})
+		return
+	}
+
+	items := make([]ReviewQueueItem, 0, len(videos))
+	for _, video := range videos {
+		items = append(items, ReviewQueueItem{
+			ID:           video.ID,
+			VideoID:      video.VideoID,
+			Title:        video.Title,
+			UploadTitle:  uploadTitle(&video),
+			ChannelName:  video.ChannelName,
+			PlaylistID:   video.PlaylistID,
+			ReviewReason: video.ReviewReason,
+			UpdatedAt:    video.UpdatedAt.Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "success",
+		Data: gin.H{
+			"videos": items,
+			"total":  total,
+			"page":   page,
+			"limit":  limit,
+		},
+	})
+}
+
+// getReview 审核详情：投稿信息、分区、章节、封面、字幕编辑入口和审核记录
+func (h *ReviewHandler) getReview(c *gin.Context) {
+	savedVideo, stateManager, ok := h.resolveVideo(c)
+	if !ok {
+		return
+	}
+
+	var chapters []utils.Chapter
+	if list, err := utils.LoadChapters(stateManager.Chapters); err == nil {
+		chapters = list.Chapters
+	}
+
+	records, err := h.ReviewService.ListRecords(savedVideo.VideoID)
+	if err != nil {
+		h.App.Logger.Errorf("获取审核记录失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "获取审核记录失败"})
+		return
+	}
+
+	var steps []model.TaskStep
+	if h.TaskStepService != nil {
+		steps, _ = h.TaskStepService.GetTaskStepsByVideoID(savedVideo.VideoID)
+	}
+	stepStatus := make(map[string]string, len(steps))
+	for _, step := range steps {
+		stepStatus[step.StepName] = step.Status
+	}
+
+	var compliance *services.ComplianceReport
+	if report, err := services.LoadComplianceReport(stateManager.Compliance); err == nil {
+		compliance = report
+	}
+
+	coverURL := ""
+	if _, err := os.Stat(stateManager.ImageCover); err == nil {
+		coverURL = "/api/v1/reviews/" + savedVideo.VideoID + "/cover"
+	}
+
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "success",
+		Data: gin.H{
+			"id":              savedVideo.ID,
+			"video_id":        savedVideo.VideoID,
+			"status":          savedVideo.Status,
+			"review_reason":   savedVideo.ReviewReason,
+			"review_required": h.ReviewService.Required(savedVideo),
+			"original_title":  savedVideo.Title,
+			"original_desc":   savedVideo.Description,
+			"generated_title": savedVideo.GeneratedTitle,
+			"generated_desc":  savedVideo.GeneratedDesc,
+			"reviewed_title":  savedVideo.ReviewedTitle,
+			"reviewed_desc":   savedVideo.ReviewedDesc,
+			"tags":            savedVideo.GeneratedTags,
+			"tid":             savedVideo.Tid,
+			"tid_reason":      savedVideo.TidReason,
+			"chapters":        chapters,
+			"cover_url":       coverURL,
+			"covers_url":      "/api/v1/videos/" + savedVideo.VideoID + "/covers",
+			"subtitles_url":   "/api/v1/videos/" + savedVideo.VideoID + "/subtitles",
+			"task_steps":      stepStatus,
+			"compliance":      compliance,
+			"records":         records,
+		},
+	})
+}
+
+// editReview 修改上传时使用的标题、简介、标签和分区
+func (h *ReviewHandler) editReview(c *gin.Context) {
+	var req ReviewEditRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "请求参数错误: " + err.Error()})
+		return
+	}
+
+	savedVideo, _, ok := h.resolveVideo(c)
+	if !ok || !h.requirePending(c, savedVideo) {
+		return
+	}
+
+	changes := map[string]interface{}{}
+	if req.Title != nil {
+		title := strings.TrimSpace(*req.Title)
+		if len([]rune(title)) > 80 {
+			c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "标题不能超过 80 个字符"})
+			return
+		}
+		savedVideo.ReviewedTitle = title
+		changes["title"] = title
+	}
+	if req.Description != nil {
+		desc := strings.TrimSpace(strings.ReplaceAll(*req.Description, "\r\n", "\n"))
+		if len([]rune(desc)) > 2000 {
+			c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "简介不能超过 2000 个字符"})
+			return
+		}
+		savedVideo.ReviewedDesc = desc
+		changes["description"] = desc
+	}
+	if req.Tags != nil {
+		tags := truncateTags(splitTags(*req.Tags), 500)
+		savedVideo.GeneratedTags = tags
+		changes["tags"] = tags
+	}
+	if req.Tid != nil {
+		if *req.Tid < 0 {
+			c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "分区ID无效"})
+			return
+		}
+		savedVideo.Tid = *req.Tid
+		savedVideo.TidReason = "审核时手动指定"
+		changes["tid"] = *req.Tid
+	}
+	if len(changes) == 0 {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "没有需要修改的字段"})
+		return
+	}
+
+	if err := h.SavedVideoService.UpdateVideo(savedVideo); err != nil {
+		h.App.Logger.Errorf("保存审核修改失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "保存修改失败"})
+		return
+	}
+	h.addEditRecord(c, savedVideo, req.Reviewer, req.Comment, changes)
+
+	h.App.Logger.Infof("✏️ 审核修改投稿信息: %s %v", savedVideo.VideoID, changes)
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "修改已保存，上传时生效",
+		Data: gin.H{
+			"video_id":       savedVideo.VideoID,
+			"reviewed_title": savedVideo.ReviewedTitle,
+			"reviewed_desc":  savedVideo.ReviewedDesc,
+			"tags":           savedVideo.GeneratedTags,
+			"tid":            savedVideo.Tid,
+		},
+	})
+}
+
+// getCover 返回当前上传时使用的封面
+func (h *ReviewHandler) getCover(c *gin.Context) {
+	_, stateManager, ok := h.resolveVideo(c)
+	if !ok {
+		return
+	}
+	if _, err := os.Stat(stateManager.ImageCover); err != nil {
+		c.JSON(http.StatusNotFound, VideoListResponse{Code: 404, Message: "封面不存在"})
+		return
+	}
+	c.File(stateManager.ImageCover)
+}
+
+// uploadCover 上传自定义封面（JPEG/PNG，替换 cover.jpg）。从候选封面中选择请使用 /videos/:id/covers
+func (h *ReviewHandler) uploadCover(c *gin.Context) {
+	savedVideo, stateManager, ok := h.resolveVideo(c)
+	if !ok || !h.requirePending(c, savedVideo) {
+		return
+	}
+
+	fileHeader, err := c.FormFile("cover")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "请上传封面文件（字段 cover）"})
+		return
+	}
+	if fileHeader.Size > maxReviewCoverSize {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "封面文件不能超过 5MB"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "读取封面文件失败"})
+		return
+	}
+	defer file.Close()
+
+	if err := os.MkdirAll(filepath.Dir(stateManager.ImageCover), 0755); err != nil {
+		h.App.Logger.Errorf("创建视频目录失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "创建视频目录失败"})
+		return
+	}
+	if err := utils.SaveCustomCover(file, stateManager.CoversDir, stateManager.ImageCover); err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: err.Error()})
+		return
+	}
+	h.addEditRecord(c, savedVideo, c.PostForm("reviewer"), c.PostForm("comment"), map[string]interface{}{"cover": fileHeader.Filename})
+
+	h.App.Logger.Infof("🖼️ 审核上传自定义封面: %s - %s", savedVideo.VideoID, fileHeader.Filename)
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "封面已更新，上传时生效",
+		Data: gin.H{
+			"video_id":  savedVideo.VideoID,
+			"cover_url": "/api/v1/reviews/" + savedVideo.VideoID + "/cover",
+		},
+	})
+}
+
+// approve 审核通过，进入上传队列
+func (h *ReviewHandler) approve(c *gin.Context) {
+	var req ReviewActionRequest
+	if !h.bindOptional(c, &req) {
+		return
+	}
+	savedVideo, stateManager, ok := h.resolveVideo(c)
+	if !ok || !h.requirePending(c, savedVideo) {
+		return
+	}
+	if reasons := complianceBlockReasons(h.App, stateManager.Compliance); len(reasons) > 0 {
+		c.JSON(http.StatusBadRequest, VideoListResponse{
+			Code:    400,
+			Message: "合规检查未通过，请修改后退回重新执行“合规检查”: " + strings.Join(reasons, "; "),
+		})
+		return
+	}
+
+	if err := h.ReviewService.Approve(savedVideo, resolveAuthor(c, req.Reviewer), req.Comment); err != nil {
+		h.App.Logger.Errorf("审核通过失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "更新视频状态失败"})
+		return
+	}
+
+	h.App.Logger.Infof("👍 视频 %s 人工审核通过，进入上传队列", savedVideo.VideoID)
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "审核通过",
+		Data:    gin.H{"video_id": savedVideo.VideoID, "status": "200"},
+	})
+}
+
+// reject 驳回，视频不再上传
+func (h *ReviewHandler) reject(c *gin.Context) {
+	var req ReviewActionRequest
+	if !h.bindOptional(c, &req) {
+		return
+	}
+	if strings.TrimSpace(req.Comment) == "" {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "驳回时请填写审核意见"})
+		return
+	}
+	savedVideo, _, ok := h.resolveVideo(c)
+	if !ok || !h.requirePending(c, savedVideo) {
+		return
+	}
+
+	if err := h.ReviewService.Reject(savedVideo, resolveAuthor(c, req.Reviewer), req.Comment); err != nil {
+		h.App.Logger.Errorf("驳回失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "更新视频状态失败"})
+		return
+	}
+
+	h.App.Logger.Infof("👎 视频 %s 审核驳回: %s", savedVideo.VideoID, req.Comment)
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "已驳回",
+		Data:    gin.H{"video_id": savedVideo.VideoID, "status": "160"},
+	})
+}
+
+// sendBack 退回到指定步骤重新执行，执行完成后视频仍为待审核
+func (h *ReviewHandler) sendBack(c *gin.Context) {
+	var req ReviewSendBackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "请求参数错误: " + err.Error()})
+		return
+	}
+	if len(req.Steps) == 0 {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "请指定需要重新执行的步骤"})
+		return
+	}
+	for _, step := range req.Steps {
+		if !reviewSendBackSteps[step] {
+			c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: fmt.Sprintf("不支持退回的步骤: %s", step)})
+			return
+		}
+	}
+
+	savedVideo, _, ok := h.resolveVideo(c)
+	if !ok || !h.requirePending(c, savedVideo) {
+		return
+	}
+
+	if err := h.ReviewService.SendBack(savedVideo, req.Steps, resolveAuthor(c, req.Reviewer), req.Comment); err != nil {
+		h.App.Logger.Errorf("退回失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "保存审核记录失败"})
+		return
+	}
+	for _, step := range req.Steps {
+		if err := h.TaskStepService.RequeueTaskStep(savedVideo.VideoID, step); err != nil {
+			h.App.Logger.Errorf("重置任务步骤失败: %s - %s: %v", savedVideo.VideoID, step, err)
+			c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: fmt.Sprintf("重置任务步骤 %s 失败", step)})
+			return
+		}
+	}
+
+	h.App.Logger.Infof("↩️ 视频 %s 审核退回重新执行: %v", savedVideo.VideoID, req.Steps)
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "已退回，步骤执行完成后请重新审核",
+		Data:    gin.H{"video_id": savedVideo.VideoID, "queued": req.Steps},
+	})
+}
+
+// listPolicies 频道/播放列表的审核设置，以及全局默认值
+func (h *ReviewHandler) listPolicies(c *gin.Context) {
+	policies, err := h.ReviewService.ListPolicies()
+	if err != nil {
+		h.App.Logger.Errorf("获取审核设置失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "获取审核设置失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "success",
+		Data: gin.H{
+			"default_required": h.App.Config.ReviewConfig != nil && h.App.Config.ReviewConfig.Required,
+			"policies":         policies,
+		},
+	})
+}
+
+// savePolicy 新增或修改频道/播放列表的审核设置
+func (h *ReviewHandler) savePolicy(c *gin.Context) {
+	var policy model.ReviewPolicy
+	if err := c.ShouldBindJSON(&policy); err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "请求参数错误: " + err.Error()})
+		return
+	}
+	policy.ID = 0
+
+	if err := h.ReviewService.SavePolicy(&policy); err != nil {
+		if errors.Is(err, services.ErrInvalidReviewPolicy) {
+			c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "scope 必须为 channel 或 playlist，且 scope_id 不能为空"})
+			return
+		}
+		h.App.Logger.Errorf("保存审核设置失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "保存审核设置失败"})
+		return
+	}
+
+	h.App.Logger.Infof("✓ 审核设置已保存: %s %s required=%v", policy.Scope, policy.ScopeID, policy.Required)
+	c.JSON(http.StatusOK, VideoListResponse{Code: 200, Message: "审核设置已保存", Data: policy})
+}
+
+// deletePolicy 删除审核设置，恢复使用全局配置
+func (h *ReviewHandler) deletePolicy(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("policyId"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "审核设置ID无效"})
+		return
+	}
+
+	if err := h.ReviewService.DeletePolicy(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, VideoListResponse{Code: 404, Message: "审核设置不存在"})
+			return
+		}
+		h.App.Logger.Errorf("删除审核设置失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "删除审核设置失败"})
+		return
+	}
+	c.JSON(http.StatusOK, VideoListResponse{Code: 200, Message: "审核设置已删除"})
+}
+
+// addEditRecord 记录一次修改，记录失败只写日志（修改本身已经保存）
+func (h *ReviewHandler) addEditRecord(c *gin.Context, savedVideo *model.SavedVideo, reviewer, comment string, changes map[string]interface{}) {
+	data, _ := json.Marshal(changes)
+	if err := h.ReviewService.AddRecord(&model.ReviewRecord{
+		VideoID:  savedVideo.VideoID,
+		Action:   model.ReviewActionEdit,
+		Reviewer: resolveAuthor(c, reviewer),
+		Comment:  comment,
+		Changes:  string(data),
+	}); err != nil {
+		h.App.Logger.Warnf("保存审核记录失败: %v", err)
+	}
+}
+
+// bindOptional 解析可选的请求体，失败时已写入响应
+func (h *ReviewHandler) bindOptional(c *gin.Context, req interface{}) bool {
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(req); err != nil {
+			c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "请求参数错误: " + err.Error()})
+			return false
+		}
+	}
+	return true
+}
+
+// requirePending 只有待审核（150）的视频可以审核，失败时已写入响应
+func (h *ReviewHandler) requirePending(c *gin.Context, savedVideo *model.SavedVideo) bool {
+	if savedVideo.Status != "150" {
+		c.JSON(http.StatusBadRequest, VideoListResponse{
+			Code:    400,
+			Message: fmt.Sprintf("当前状态 %s 不在审核中，只有状态为 150(待人工审核) 的视频才能审核", savedVideo.Status),
+		})
+		return false
+	}
+	return true
+}
+
+// resolveVideo 按数字ID或video_id查询视频，失败时已写入响应
+func (h *ReviewHandler) resolveVideo(c *gin.Context) (*model.SavedVideo, *manager.StateManager, bool) {
+	idStr := c.Param("id")
+
+	var savedVideo *model.SavedVideo
+	var err error
+	if id, parseErr := strconv.ParseUint(idStr, 10, 32); parseErr == nil {
+		savedVideo, err = h.SavedVideoService.GetByID(uint(id))
+	} else {
+		savedVideo, err = h.SavedVideoService.GetVideoByVideoID(idStr)
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, VideoListResponse{Code: 404, Message: "视频不存在"})
+		return nil, nil, false
+	}
+
+	root, err := filepath.Abs(h.App.Config.FileUpDir)
+	if err != nil {
+		h.App.Logger.Errorf("获取文件上传目录失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "获取文件目录失败"})
+		return nil, nil, false
+	}
+	return savedVideo, manager.NewStateManager(savedVideo.ID, savedVideo.VideoID, root, savedVideo.CreatedAt), true
+}
+
+// complianceBlockReasons 启用合规检查时读取检查报告，返回 block 级别问题的说明（未检查时返回空）
+func complianceBlockReasons(app *core.AppServer, path string) []string {
+	if cfg := app.Config.ComplianceConfig; cfg == nil || !cfg.Enabled {
+		return nil
+	}
+	report, err := services.LoadComplianceReport(path)
+	if err != nil || report.Passed {
+		return nil
+	}
+	return report.BlockReasons()
+}
+
+// uploadTitle 上传时优先使用的标题：审核修改 > AI生成 > 原标题
+func uploadTitle(video *model.SavedVideo) string {
+	if video.ReviewedTitle != "" {
+		return video.ReviewedTitle
+	}
+	if video.GeneratedTitle != "" {
+		return video.GeneratedTitle
+	}
+	return video.Title
+}
+
+// splitTags 拆分逗号分隔的标签（支持中文逗号），去掉空白和重复项
+func splitTags(value string) []string {
+	seen := map[string]bool{}
+	var tags []string
+	for _, tag := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '，' }) {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !seen[tag] {
+			seen[tag] = true
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+// truncateTags 用逗号连接标签，超出 maxLen 个字符的标签丢弃
+func truncateTags(tags []string, maxLen int) string {
+	result := ""
+	for _, tag := range tags {
+		next := tag
+		if result != "" {
+			next = result + "," + tag
+		}
+		if len([]rune(next)) > maxLen {
+			break
+		}
+		result = next
+	}
+	return result
+}
@@ -0,0 +1,691 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// errWriteTrack 写入字幕文件失败（修订记录已随事务回滚）
+var errWriteTrack = errors.New("写入字幕文件失败")
+
+// 目标语言轨道代码，只允许字母、数字和连字符，防止拼出目录外的路径
+var trackLanguagePattern = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// 字幕编辑后默认重新执行的下游步骤
+var defaultSubtitleRerunSteps = []string{"生成双语字幕", "烧录字幕", "生成配音", "生成视频元数据", "上传字幕到Bilibili"}
+
+// 允许通过字幕编辑器重新执行的步骤
+var subtitleRerunSteps = map[string]bool{
+	"生成双语字幕":        true,
+	"烧录字幕":          true,
+	"生成配音":          true,
+	"生成视频元数据":       true,
+	"生成封面":          true,
+	"选择分区":          true,
+	"生成章节":          true,
+	"合规检查":          true,
+	"上传字幕到Bilibili": true,
+}
+
+// SubtitleEditorHandler 字幕编辑器：查看/修改字幕条目，保存修订版本并重新执行下游步骤
+type SubtitleEditorHandler struct {
+	BaseHandler
+	SavedVideoService *services.SavedVideoService
+	TaskStepService   *services.TaskStepService
+	RevisionService   *services.SubtitleRevisionService
+	UploadScheduler   interface {
+		ExecuteManualUpload(videoID, taskType string) error
+	}
+}
+
+func NewSubtitleEditorHandler(app *core.AppServer, savedVideoService *services.SavedVideoService, taskStepService *services.TaskStepService, revisionService *services.SubtitleRevisionService) *SubtitleEditorHandler {
+	return &SubtitleEditorHandler{
+		BaseHandler:       BaseHandler{App: app},
+		SavedVideoService: savedVideoService,
+		TaskStepService:   taskStepService,
+		RevisionService:   revisionService,
+	}
+}
+
+// SetUploadScheduler 设置上传调度器（避免循环依赖）
+func (h *SubtitleEditorHandler) SetUploadScheduler(scheduler interface {
+	ExecuteManualUpload(videoID, taskType string) error
+}) {
+	h.UploadScheduler = scheduler
+}
+
+// RegisterRoutes 注册字幕编辑相关路由
+func (h *SubtitleEditorHandler) RegisterRoutes(api *gin.RouterGroup) {
+	video := api.Group("/videos/:id/subtitles")
+	{
+		video.GET("", h.getSubtitles)
+		video.PATCH("", h.patchSubtitles)
+		video.GET("/revisions", h.listRevisions)
+		video.GET("/revisions/:version", h.getRevision)
+		video.POST("/revisions/:version/restore", h.restoreRevision)
+		video.POST("/rerun", h.rerunDownstream)
+	}
+}
+
+// EditorCue 编辑器中的字幕条目（译文与原文并排）
+type EditorCue struct {
+	Index    int    `json:"index"`
+	Start    int64  `json:"start"` // 毫秒
+	End      int64  `json:"end"`   // 毫秒
+	Text     string `json:"text"`
+	Original string `json:"original,omitempty"` // 对应时间段内的原文（仅译文轨道）
+}
+
+// CuePatch 单条字幕修改，未提供的字段保持不变
+type CuePatch struct {
+	Index  int     `json:"index" binding:"required"`
+	Start  *int64  `json:"start"`
+	End    *int64  `json:"end"`
+	Text   *string `json:"text"`
+	Delete bool    `json:"delete"`
+}
+
+// PatchSubtitlesRequest 修改字幕请求
+type PatchSubtitlesRequest struct {
+	Track       string     `json:"track"`
+	BaseVersion int        `json:"base_version"` // 编辑时看到的版本号，用于乐观锁
+	Author      string     `json:"author"`
+	Comment     string     `json:"comment"`
+	Cues        []CuePatch `json:"cues" binding:"required"`
+}
+
+// RestoreRevisionRequest 恢复版本请求
+type RestoreRevisionRequest struct {
+	Track       string `json:"track"`
+	BaseVersion int    `json:"base_version"`
+	Author      string `json:"author"`
+	Comment     string `json:"comment"`
+}
+
+// RerunRequest 重新执行下游步骤请求
+type RerunRequest struct {
+	Steps []string `json:"steps"`
+}
+
+// getSubtitles 获取字幕条目
+func (h *SubtitleEditorHandler) getSubtitles(c *gin.Context) {
+	savedVideo, ok := h.resolveVideo(c)
+	if !ok {
+		return
+	}
+	track, ok := h.resolveTrack(c, c.DefaultQuery("track", "zh"))
+	if !ok {
+		return
+	}
+
+	stateManager, err := h.getStateManager(savedVideo)
+	if err != nil {
+		h.App.Logger.Errorf("获取视频目录失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "获取视频目录失败"})
+		return
+	}
+
+	doc, err := h.readTrack(stateManager, track)
+	if err != nil {
+		if os.IsNotExist(err) {
+			c.JSON(http.StatusNotFound, VideoListResponse{Code: 404, Message: fmt.Sprintf("字幕轨道 %s 不存在", track)})
+			return
+		}
+		h.App.Logger.Errorf("读取字幕失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "读取字幕失败"})
+		return
+	}
+
+	version, err := h.RevisionService.GetLatestVersion(savedVideo.VideoID, track)
+	if err != nil {
+		h.App.Logger.Errorf("获取字幕版本失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "获取字幕版本失败"})
+		return
+	}
+
+	cues := make([]EditorCue, len(doc.Cues))
+	for i, cue := range doc.Cues {
+		cues[i] = EditorCue{Index: i + 1, Start: cue.Start, End: cue.End, Text: cue.Text}
+	}
+	if track != "original" {
+		if originalDoc, err := h.readTrack(stateManager, "original"); err == nil {
+			for i, aligned := range subtitle.AlignBilingual(doc, originalDoc) {
+				cues[i].Original = aligned.Secondary
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "success",
+		Data: gin.H{
+			"video_id": savedVideo.VideoID,
+			"track":    track,
+			"version":  version,
+			"cues":     cues,
+		},
+	})
+}
+
+// patchSubtitles 修改字幕条目并保存为新版本
+func (h *SubtitleEditorHandler) patchSubtitles(c *gin.Context) {
+	var req PatchSubtitlesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "请求参数错误: " + err.Error()})
+		return
+	}
+	if len(req.Cues) == 0 {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "没有需要修改的字幕"})
+		return
+	}
+
+	savedVideo, ok := h.resolveVideo(c)
+	if !ok {
+		return
+	}
+	track, ok := h.resolveTrack(c, req.Track)
+	if !ok {
+		return
+	}
+
+	stateManager, err := h.getStateManager(savedVideo)
+	if err != nil {
+		h.App.Logger.Errorf("获取视频目录失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "获取视频目录失败"})
+		return
+	}
+
+	current, err := h.readTrack(stateManager, track)
+	if err != nil {
+		c.JSON(http.StatusNotFound, VideoListResponse{Code: 404, Message: fmt.Sprintf("字幕轨道 %s 不存在", track)})
+		return
+	}
+
+	edited, err := applyCuePatches(current, req.Cues)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: err.Error()})
+		return
+	}
+
+	revision, err := h.saveRevision(c, stateManager, savedVideo.VideoID, track, current, edited, req.BaseVersion, req.Author, req.Comment, 0)
+	if err != nil {
+		return
+	}
+
+	h.App.Logger.Infof("✏️  字幕已修改: %s [%s] v%d, 修改 %d 条 (%s)", savedVideo.VideoID, track, revision.Version, revision.ChangedCues, revision.Author)
+
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "字幕已保存",
+		Data:    revision,
+	})
+}
+
+// listRevisions 获取修订列表
+func (h *SubtitleEditorHandler) listRevisions(c *gin.Context) {
+	savedVideo, ok := h.resolveVideo(c)
+	if !ok {
+		return
+	}
+	track, ok := h.resolveTrack(c, c.DefaultQuery("track", "zh"))
+	if !ok {
+		return
+	}
+
+	revisions, err := h.RevisionService.ListRevisions(savedVideo.VideoID, track)
+	if err != nil {
+		h.App.Logger.Errorf("获取字幕修订列表失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "获取字幕修订列表失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "success",
+		Data: gin.H{
+			"video_id":  savedVideo.VideoID,
+			"track":     track,
+			"revisions": revisions,
+		},
+	})
+}
+
+// getRevision 获取指定版本的内容和差异
+func (h *SubtitleEditorHandler) getRevision(c *gin.Context) {
+	savedVideo, ok := h.resolveVideo(c)
+	if !ok {
+		return
+	}
+	track, ok := h.resolveTrack(c, c.DefaultQuery("track", "zh"))
+	if !ok {
+		return
+	}
+	revision, ok := h.loadRevision(c, savedVideo.VideoID, track)
+	if !ok {
+		return
+	}
+
+	doc, err := subtitle.ParseSRT(revision.Content)
+	if err != nil {
+		h.App.Logger.Errorf("解析字幕修订内容失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "解析字幕修订内容失败"})
+		return
+	}
+
+	var diff []subtitle.CueChange
+	if revision.Diff != "" {
+		if err := json.Unmarshal([]byte(revision.Diff), &diff); err != nil {
+			h.App.Logger.Warnf("解析字幕差异失败: %v", err)
+		}
+	}
+
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "success",
+		Data: gin.H{
+			"revision": revision,
+			"cues":     doc.Cues,
+			"changes":  diff,
+		},
+	})
+}
+
+// restoreRevision 将字幕恢复到指定版本（作为一个新版本保存）
+func (h *SubtitleEditorHandler) restoreRevision(c *gin.Context) {
+	var req RestoreRevisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "请求参数错误: " + err.Error()})
+		return
+	}
+
+	savedVideo, ok := h.resolveVideo(c)
+	if !ok {
+		return
+	}
+	track, ok := h.resolveTrack(c, req.Track)
+	if !ok {
+		return
+	}
+	target, ok := h.loadRevision(c, savedVideo.VideoID, track)
+	if !ok {
+		return
+	}
+
+	restored, err := subtitle.ParseSRT(target.Content)
+	if err != nil {
+		h.App.Logger.Errorf("解析字幕修订内容失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "解析字幕修订内容失败"})
+		return
+	}
+
+	stateManager, err := h.getStateManager(savedVideo)
+	if err != nil {
+		h.App.Logger.Errorf("获取视频目录失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "获取视频目录失败"})
+		return
+	}
+
+	current, err := h.readTrack(stateManager, track)
+	if err != nil {
+		current = subtitle.NewDocument("")
+	}
+
+	comment := req.Comment
+	if comment == "" {
+		comment = fmt.Sprintf("恢复到版本 %d", target.Version)
+	}
+
+	revision, err := h.saveRevision(c, stateManager, savedVideo.VideoID, track, current, restored, req.BaseVersion, req.Author, comment, target.Version)
+	if err != nil {
+		return
+	}
+
+	h.App.Logger.Infof("⏪ 字幕已恢复: %s [%s] v%d -> v%d (%s)", savedVideo.VideoID, track, target.Version, revision.Version, revision.Author)
+
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: fmt.Sprintf("字幕已恢复到版本 %d", target.Version),
+		Data:    revision,
+	})
+}
+
+// rerunDownstream 基于编辑后的字幕重新执行下游步骤
+func (h *SubtitleEditorHandler) rerunDownstream(c *gin.Context) {
+	var req RerunRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "请求参数错误: " + err.Error()})
+			return
+		}
+	}
+	steps := req.Steps
+	if len(steps) == 0 {
+		steps = defaultSubtitleRerunSteps
+	}
+	for _, step := range steps {
+		if !subtitleRerunSteps[step] {
+			c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: fmt.Sprintf("不支持重新执行的步骤: %s", step)})
+			return
+		}
+	}
+
+	savedVideo, ok := h.resolveVideo(c)
+	if !ok {
+		return
+	}
+
+	queued := []string{}
+	skipped := map[string]string{}
+	for _, step := range steps {
+		if step == "上传字幕到Bilibili" {
+			// 字幕上传走上传调度器，需要视频已上传到B站
+			if reason := h.triggerSubtitleUpload(savedVideo); reason != "" {
+				skipped[step] = reason
+				continue
+			}
+			queued = append(queued, step)
+			continue
+		}
+
+		if err := h.TaskStepService.RequeueTaskStep(savedVideo.VideoID, step); err != nil {
+			h.App.Logger.Errorf("重置任务步骤失败: %s - %s: %v", savedVideo.VideoID, step, err)
+			c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: fmt.Sprintf("重置任务步骤 %s 失败", step)})
+			return
+		}
+		queued = append(queued, step)
+	}
+
+	h.App.Logger.Infof("🔄 字幕编辑后重新执行下游步骤: %s %v", savedVideo.VideoID, queued)
+
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "下游步骤已加入重新执行队列",
+		Data: gin.H{
+			"video_id": savedVideo.VideoID,
+			"queued":   queued,
+			"skipped":  skipped,
+		},
+	})
+}
+
+// triggerSubtitleUpload 异步重新上传字幕，无法上传时返回原因
+func (h *SubtitleEditorHandler) triggerSubtitleUpload(savedVideo *model.SavedVideo) string {
+	if savedVideo.BiliBVID == "" {
+		return "视频尚未上传到Bilibili"
+	}
+	if h.UploadScheduler == nil {
+		return "上传调度器未初始化"
+	}
+	if savedVideo.Status == "301" {
+		return "字幕正在上传中"
+	}
+
+	if err := h.SavedVideoService.UpdateStatus(savedVideo.ID, "301"); err != nil {
+		h.App.Logger.Errorf("更新视频状态失败: %v", err)
+		return "更新视频状态失败"
+	}
+
+	go func() {
+		if err := h.UploadScheduler.ExecuteManualUpload(savedVideo.VideoID, "subtitle"); err != nil {
+			h.App.Logger.Errorf("❌ 重新上传字幕失败: %v", err)
+			h.SavedVideoService.UpdateStatus(savedVideo.ID, "399")
+		} else {
+			h.App.Logger.Infof("✅ 重新上传字幕成功: %s", savedVideo.VideoID)
+			h.SavedVideoService.UpdateStatus(savedVideo.ID, "400")
+		}
+	}()
+	return ""
+}
+
+// saveRevision 保存新版本并写入字幕文件，两者在同一事务中完成。首次编辑时先把当前字幕保存为版本 1 作为基线。
+// 失败时已写入响应，调用方直接返回即可
+func (h *SubtitleEditorHandler) saveRevision(c *gin.Context, stateManager *manager.StateManager, videoID, track string, current, edited *subtitle.Document, baseVersion int, author, comment string, restoredFrom int) (*model.SubtitleRevision, error) {
+	author = resolveAuthor(c, author)
+
+	latest, err := h.RevisionService.GetLatestVersion(videoID, track)
+	if err != nil {
+		h.App.Logger.Errorf("获取字幕版本失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "获取字幕版本失败"})
+		return nil, err
+	}
+	if latest == 0 && baseVersion == 0 {
+		baseline := &model.SubtitleRevision{
+			VideoID:  videoID,
+			Track:    track,
+			Author:   "system",
+			Comment:  "编辑前的原始字幕",
+			CueCount: current.Len(),
+			Content:  subtitle.FormatSRTDocument(current),
+		}
+		if err := h.RevisionService.CreateRevision(baseline, 0, nil); err != nil {
+			h.respondRevisionError(c, err)
+			return nil, err
+		}
+		baseVersion = baseline.Version
+	}
+
+	changes := subtitle.DiffDocuments(current, edited)
+	diff, _ := json.Marshal(changes)
+
+	revision := &model.SubtitleRevision{
+		VideoID:      videoID,
+		Track:        track,
+		Author:       author,
+		Comment:      comment,
+		CueCount:     edited.Len(),
+		ChangedCues:  len(changes),
+		RestoredFrom: restoredFrom,
+		Content:      subtitle.FormatSRTDocument(edited),
+		Diff:         string(diff),
+	}
+	writeFile := func() error {
+		if err := h.writeTrack(stateManager, track, edited); err != nil {
+			return fmt.Errorf("%w: %v", errWriteTrack, err)
+		}
+		return nil
+	}
+	if err := h.RevisionService.CreateRevision(revision, baseVersion, writeFile); err != nil {
+		h.respondRevisionError(c, err)
+		return nil, err
+	}
+	return revision, nil
+}
+
+func (h *SubtitleEditorHandler) respondRevisionError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrRevisionConflict) {
+		c.JSON(http.StatusConflict, VideoListResponse{Code: 409, Message: err.Error()})
+		return
+	}
+	if errors.Is(err, errWriteTrack) {
+		h.App.Logger.Errorf("写入字幕文件失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "写入字幕文件失败"})
+		return
+	}
+	h.App.Logger.Errorf("保存字幕修订失败: %v", err)
+	c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "保存字幕修订失败"})
+}
+
+// applyCuePatches 在文档副本上应用修改，返回重新编号后的新文档
+func applyCuePatches(doc *subtitle.Document, patches []CuePatch) (*subtitle.Document, error) {
+	edited := doc.Clone()
+	deleted := map[int]bool{}
+
+	for _, patch := range patches {
+		if patch.Index < 1 || patch.Index > len(edited.Cues) {
+			return nil, fmt.Errorf("字幕序号 %d 超出范围 (1-%d)", patch.Index, len(edited.Cues))
+		}
+		if patch.Delete {
+			deleted[patch.Index] = true
+			continue
+		}
+
+		cue := &edited.Cues[patch.Index-1]
+		if patch.Start != nil {
+			cue.Start = *patch.Start
+		}
+		if patch.End != nil {
+			cue.End = *patch.End
+		}
+		if patch.Text != nil {
+			cue.Text = strings.TrimSpace(strings.ReplaceAll(*patch.Text, "\r\n", "\n"))
+		}
+		if cue.Start < 0 || cue.End <= cue.Start {
+			return nil, fmt.Errorf("字幕 %d 的时间无效: %d -> %d", patch.Index, cue.Start, cue.End)
+		}
+		if cue.Text == "" {
+			return nil, fmt.Errorf("字幕 %d 的文本为空，如需删除请设置 delete", patch.Index)
+		}
+	}
+
+	if len(deleted) > 0 {
+		cues := make([]subtitle.Cue, 0, len(edited.Cues)-len(deleted))
+		for i, cue := range edited.Cues {
+			if !deleted[i+1] {
+				cues = append(cues, cue)
+			}
+		}
+		edited.Cues = cues
+	}
+
+	edited.Sort()
+	edited.Renumber()
+	return edited, nil
+}
+
+// resolveVideo 按数字ID或video_id查询视频，失败时已写入响应
+func (h *SubtitleEditorHandler) resolveVideo(c *gin.Context) (*model.SavedVideo, bool) {
+	idStr := c.Param("id")
+
+	var savedVideo *model.SavedVideo
+	var err error
+	if id, parseErr := strconv.ParseUint(idStr, 10, 32); parseErr == nil {
+		savedVideo, err = h.SavedVideoService.GetByID(uint(id))
+	} else {
+		savedVideo, err = h.SavedVideoService.GetVideoByVideoID(idStr)
+	}
+
+	if err != nil {
+		h.App.Logger.Errorf("获取视频详情失败: %v", err)
+		c.JSON(http.StatusNotFound, VideoListResponse{Code: 404, Message: "视频不存在"})
+		return nil, false
+	}
+	return savedVideo, true
+}
+
+// resolveTrack 校验字幕轨道：zh（简体中文译文）、original（原文）或其他目标语言代码（如 en、ja、zh-Hant），
+// 简体中文的各种写法统一为 zh
+func (h *SubtitleEditorHandler) resolveTrack(c *gin.Context, track string) (string, bool) {
+	if track == "" {
+		return "zh", true
+	}
+	if track == "original" {
+		return track, true
+	}
+	language := subtitle.NormalizeLanguage(track)
+	if !trackLanguagePattern.MatchString(language) {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: fmt.Sprintf("不支持的字幕轨道: %s（可选 zh, original 或目标语言代码）", track)})
+		return "", false
+	}
+	if language == subtitle.LanguageSimplifiedChinese {
+		return "zh", true
+	}
+	return language, true
+}
+
+// resolveAuthor 修改人：请求中指定 > 登录用户 > admin
+func resolveAuthor(c *gin.Context, author string) string {
+	if author = strings.TrimSpace(author); author != "" {
+		return author
+	}
+	if uid := c.GetHeader("X-Firebase-UID"); uid != "" {
+		return uid
+	}
+	if uid, err := c.Cookie("firebase_uid"); err == nil && uid != "" {
+		return uid
+	}
+	return "admin"
+}
+
+// loadRevision 读取路径参数中的版本，失败时已写入响应
+func (h *SubtitleEditorHandler) loadRevision(c *gin.Context, videoID, track string) (*model.SubtitleRevision, bool) {
+	version, err := strconv.Atoi(c.Param("version"))
+	if err != nil || version < 1 {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "版本号无效"})
+		return nil, false
+	}
+
+	revision, err := h.RevisionService.GetRevision(videoID, track, version)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, VideoListResponse{Code: 404, Message: fmt.Sprintf("版本 %d 不存在", version)})
+			return nil, false
+		}
+		h.App.Logger.Errorf("获取字幕修订失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "获取字幕修订失败"})
+		return nil, false
+	}
+	return revision, true
+}
+
+func (h *SubtitleEditorHandler) getStateManager(savedVideo *model.SavedVideo) (*manager.StateManager, error) {
+	root, err := filepath.Abs(h.App.Config.FileUpDir)
+	if err != nil {
+		return nil, err
+	}
+	return manager.NewStateManager(savedVideo.ID, savedVideo.VideoID, root, savedVideo.CreatedAt), nil
+}
+
+// trackPath 字幕轨道对应的文件
+func trackPath(stateManager *manager.StateManager, track string) string {
+	if track == "original" {
+		if path := stateManager.OriginalSubtitlePath(); path != "" {
+			return path
+		}
+		return stateManager.OriginalSRT
+	}
+	return stateManager.TranslatedSRTPath(track)
+}
+
+func (h *SubtitleEditorHandler) readTrack(stateManager *manager.StateManager, track string) (*subtitle.Document, error) {
+	path := trackPath(stateManager, track)
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	doc, err := subtitle.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc.Sort()
+	return doc, nil
+}
+
+// writeTrack 先写入临时文件再重命名，避免写入中途失败留下不完整的字幕
+func (h *SubtitleEditorHandler) writeTrack(stateManager *manager.StateManager, track string, doc *subtitle.Document) error {
+	path := trackPath(stateManager, track)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, []byte(subtitle.FormatSRTDocument(doc)), 0644); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/difyz9/ytb2bili/internal/core"
 	"github.com/difyz9/ytb2bili/pkg/auth"
 	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
 	"github.com/difyz9/ytb2bili/pkg/utils"
 	"encoding/json"
 	"fmt"
@@ -32,15 +33,18 @@ func NewSubtitleHandler(app *core.AppServer) *SubtitleHandler {
 
 // SaveVideoRequest 保存视频请求
 type SaveVideoRequest struct {
-	URL           string                     `json:"url" binding:"required"`
-	Title         string                     `json:"title"`
-	Description   string                     `json:"description"`
-	OperationType string                     `json:"operationType"`
-	Subtitles     []model.SavedVideoSubtitle `json:"subtitles"`
-	PlaylistID    string                     `json:"playlistId"`
-	Timestamp     string                     `json:"timestamp"`
-	SavedAt       string                     `json:"savedAt"`
-	Meta          string                     `json:"meta"` // 加密的 cookies 数据
+	URL             string                     `json:"url" binding:"required"`
+	Title           string                     `json:"title"`
+	Description     string                     `json:"description"`
+	OperationType   string                     `json:"operationType"`
+	Subtitles       []model.SavedVideoSubtitle `json:"subtitles"`
+	PlaylistID      string                     `json:"playlistId"`
+	Timestamp       string                     `json:"timestamp"`
+	SavedAt         string                     `json:"savedAt"`
+	Meta            string                     `json:"meta"`            // 加密的 cookies 数据
+	TargetLanguages []string                   `json:"targetLanguages"` // 字幕翻译目标语言（可选，第一个为主语言）
+
+	TranslationSettings *model.TranslationSettings `json:"translationSettings"` // 翻译和配音设置（可选，配音音色覆盖全局配置）
 }
 
 // Cookie 结构体（兼容 Chrome cookies API）
@@ -121,6 +125,23 @@ func (h *SubtitleHandler) saveVideoSubtitles(c *gin.Context) {
 		fmt.Printf("字幕数据: %s\n", subtitlesJSONStr)
 	}
 
+	// 翻译设置按 JSON 保存，配音步骤读取其中的音色设置；未单独指定目标语言时使用翻译设置中的目标语言
+	var settingsJSON string
+	if req.TranslationSettings != nil {
+		if len(req.TargetLanguages) == 0 {
+			req.TargetLanguages = req.TranslationSettings.Languages()
+		}
+		data, err := json.Marshal(req.TranslationSettings)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"message": "Invalid translation settings: " + err.Error(),
+			})
+			return
+		}
+		settingsJSON = string(data)
+	}
+
 	// 检查是否已存在相同的 videoId（包括已删除的记录）
 	var existingVideo model.SavedVideo
 	err = h.App.DB.Unscoped().Where("video_id = ?", videoID).First(&existingVideo).Error
@@ -139,6 +160,12 @@ func (h *SubtitleHandler) saveVideoSubtitles(c *gin.Context) {
 		existingVideo.PlaylistID = req.PlaylistID
 		existingVideo.Timestamp = req.Timestamp
 		existingVideo.SavedAt = req.SavedAt
+		if len(req.TargetLanguages) > 0 {
+			existingVideo.TargetLanguages = strings.Join(subtitle.NormalizeLanguages(req.TargetLanguages), ",")
+		}
+		if settingsJSON != "" {
+			existingVideo.TranslationSettings = settingsJSON
+		}
 		existingVideo.Status = "001" // 重置状态为待处理
 		existingVideo.DeletedAt = gorm.DeletedAt{} // 恢复记录（清除删除标记）
 
@@ -159,16 +186,19 @@ func (h *SubtitleHandler) saveVideoSubtitles(c *gin.Context) {
 	} else if err == gorm.ErrRecordNotFound {
 		// 记录不存在，创建新记录
 		savedVideo = &model.SavedVideo{
-			VideoID:       videoID,
-			URL:           req.URL,
-			Title:         req.Title,
-			Status:        "001",
-			Description:   req.Description,
-			OperationType: req.OperationType,
-			Subtitles:     subtitlesJSONStr,
-			PlaylistID:    req.PlaylistID,
-			Timestamp:     req.Timestamp,
-			SavedAt:       req.SavedAt,
+			VideoID:         videoID,
+			URL:             req.URL,
+			Title:           req.Title,
+			Status:          "001",
+			Description:     req.Description,
+			OperationType:   req.OperationType,
+			Subtitles:       subtitlesJSONStr,
+			PlaylistID:      req.PlaylistID,
+			Timestamp:       req.Timestamp,
+			SavedAt:         req.SavedAt,
+			TargetLanguages: strings.Join(subtitle.NormalizeLanguages(req.TargetLanguages), ","),
+
+			TranslationSettings: settingsJSON,
 		}
 
 		// 保存到数据库
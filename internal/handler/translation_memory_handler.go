@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core"
+	"github.com/difyz9/ytb2bili/internal/core/services"
+	"github.com/difyz9/ytb2bili/pkg/translator"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// TMX 导入文件大小上限
+const maxTMXImportSize = 50 << 20
+
+// TranslationMemoryHandler 翻译记忆：查看、统计、清理以及 TMX 导入/导出
+type TranslationMemoryHandler struct {
+	BaseHandler
+	MemoryService *services.TranslationMemoryService
+}
+
+func NewTranslationMemoryHandler(app *core.AppServer, memoryService *services.TranslationMemoryService) *TranslationMemoryHandler {
+	return &TranslationMemoryHandler{
+		BaseHandler:   BaseHandler{App: app},
+		MemoryService: memoryService,
+	}
+}
+
+// RegisterRoutes 注册翻译记忆相关路由
+func (h *TranslationMemoryHandler) RegisterRoutes(api *gin.RouterGroup) {
+	tm := api.Group("/translation-memory")
+	{
+		tm.GET("", h.listEntries)
+		tm.GET("/stats", h.getStats)
+		tm.DELETE("", h.purgeEntries)
+		tm.DELETE("/:id", h.deleteEntry)
+		tm.GET("/export", h.exportTMX)
+		tm.POST("/import", h.importTMX)
+	}
+}
+
+// listEntries 分页查询翻译记忆
+// 查询参数: q, provider, source_lang, target_lang, page, limit
+func (h *TranslationMemoryHandler) listEntries(c *gin.Context) {
+	page, err := strconv.Atoi(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	filter := h.parseFilter(c)
+
+	entries, total, err := h.MemoryService.ListEntries(filter, (page-1)*limit, limit)
+	if err != nil {
+		h.App.Logger.Errorf("查询翻译记忆失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "查询翻译记忆失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "success",
+		Data: gin.H{
+			"entries": entries,
+			"total":   total,
+			"page":    page,
+			"limit":   limit,
+		},
+	})
+}
+
+// getStats 翻译记忆统计（条目数、累计命中、本次启动以来的命中率）
+func (h *TranslationMemoryHandler) getStats(c *gin.Context) {
+	stats, err := h.MemoryService.GetStats()
+	if err != nil {
+		h.App.Logger.Errorf("获取翻译记忆统计失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "获取翻译记忆统计失败"})
+		return
+	}
+
+	enabled := h.App.Config.TranslatorConfig != nil && h.App.Config.TranslatorConfig.EnableCache
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "success",
+		Data: gin.H{
+			"enabled": enabled,
+			"stats":   stats,
+		},
+	})
+}
+
+// purgeEntries 按条件清理翻译记忆
+// 查询参数: provider, source_lang, target_lang, q, older_than_days；不带任何条件时需要 all=true
+func (h *TranslationMemoryHandler) purgeEntries(c *gin.Context) {
+	filter := h.parseFilter(c)
+	if days := c.Query("older_than_days"); days != "" {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "older_than_days 必须是非负整数"})
+			return
+		}
+		filter.Before = time.Now().AddDate(0, 0, -n)
+	}
+	if filter == (services.TranslationMemoryFilter{}) && c.Query("all") != "true" {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "未指定清理条件，清空全部翻译记忆请传 all=true"})
+		return
+	}
+
+	deleted, err := h.MemoryService.PurgeEntries(filter)
+	if err != nil {
+		h.App.Logger.Errorf("清理翻译记忆失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "清理翻译记忆失败"})
+		return
+	}
+
+	h.App.Logger.Infof("🧹 已清理 %d 条翻译记忆", deleted)
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "success",
+		Data:    gin.H{"deleted": deleted},
+	})
+}
+
+// deleteEntry 删除单条翻译记忆
+func (h *TranslationMemoryHandler) deleteEntry(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "无效的条目ID"})
+		return
+	}
+
+	if err := h.MemoryService.DeleteEntry(uint(id)); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, VideoListResponse{Code: 404, Message: "翻译记忆条目不存在"})
+			return
+		}
+		h.App.Logger.Errorf("删除翻译记忆失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "删除翻译记忆失败"})
+		return
+	}
+
+	c.JSON(http.StatusOK, VideoListResponse{Code: 200, Message: "success"})
+}
+
+// exportTMX 导出为 TMX 文件
+// 查询参数同列表筛选；auto_source_lang 指定自动检测源语言的条目在 TMX 中的语言代码（默认 en）
+func (h *TranslationMemoryHandler) exportTMX(c *gin.Context) {
+	filter := h.parseFilter(c)
+	autoSourceLang := c.DefaultQuery("auto_source_lang", "en")
+
+	entries, err := h.MemoryService.ExportEntries(filter)
+	if err != nil {
+		h.App.Logger.Errorf("导出翻译记忆失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "导出翻译记忆失败"})
+		return
+	}
+
+	units := make([]translator.TMXUnit, 0, len(entries))
+	for _, entry := range entries {
+		sourceLang := entry.SourceLang
+		if sourceLang == "auto" {
+			sourceLang = autoSourceLang
+		}
+		units = append(units, translator.TMXUnit{
+			SourceLang:     sourceLang,
+			TargetLang:     entry.TargetLang,
+			SourceText:     entry.SourceText,
+			TranslatedText: entry.TranslatedText,
+			Provider:       entry.Provider,
+			Model:          entry.Model,
+		})
+	}
+
+	var buf bytes.Buffer
+	if err := translator.WriteTMX(&buf, units); err != nil {
+		h.App.Logger.Errorf("生成TMX失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "生成TMX失败"})
+		return
+	}
+
+	filename := fmt.Sprintf("translation-memory-%s.tmx", time.Now().Format("20060102-150405"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", filename))
+	c.Data(http.StatusOK, "application/x-tmx+xml; charset=utf-8", buf.Bytes())
+}
+
+// importTMX 导入 TMX 文件（multipart 字段 file，或直接作为请求体）
+// 默认按人工译文保存（优先于机器翻译、不过期）；provider 参数可指定保存为某个提供商的译文
+func (h *TranslationMemoryHandler) importTMX(c *gin.Context) {
+	var reader io.Reader
+	if file, err := c.FormFile("file"); err == nil {
+		if file.Size > maxTMXImportSize {
+			c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "TMX 文件过大"})
+			return
+		}
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "读取上传文件失败"})
+			return
+		}
+		defer f.Close()
+		reader = f
+	} else {
+		reader = http.MaxBytesReader(c.Writer, c.Request.Body, maxTMXImportSize)
+	}
+
+	units, err := translator.ParseTMX(reader)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: fmt.Sprintf("TMX 格式错误: %v", err)})
+		return
+	}
+	if len(units) == 0 {
+		c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "TMX 中没有可导入的翻译单元"})
+		return
+	}
+
+	imported, err := h.MemoryService.ImportUnits(c.Request.Context(), units, c.Query("provider"))
+	if err != nil {
+		h.App.Logger.Errorf("导入翻译记忆失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "导入翻译记忆失败"})
+		return
+	}
+
+	h.App.Logger.Infof("📥 已导入 %d 条翻译记忆", imported)
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "success",
+		Data:    gin.H{"imported": imported},
+	})
+}
+
+// parseFilter 解析通用筛选参数
+func (h *TranslationMemoryHandler) parseFilter(c *gin.Context) services.TranslationMemoryFilter {
+	return services.TranslationMemoryFilter{
+		Keyword:    c.Query("q"),
+		Provider:   c.Query("provider"),
+		SourceLang: c.Query("source_lang"),
+		TargetLang: c.Query("target_lang"),
+	}
+}
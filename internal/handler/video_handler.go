@@ -9,9 +9,11 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/difyz9/ytb2bili/internal/chain_task/manager"
 	"github.com/difyz9/ytb2bili/internal/core"
 	"github.com/difyz9/ytb2bili/internal/core/services"
 	"github.com/difyz9/ytb2bili/pkg/store/model"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
 
 	"github.com/gin-gonic/gin"
 )
@@ -24,6 +26,9 @@ type VideoHandler struct {
 		ExecuteManualUpload(videoID, taskType string) error
 	}
 	AnalyticsHandler *AnalyticsHandler
+	RevisionService  *services.SubtitleRevisionService
+	GlossaryService  *services.GlossaryService
+	ReviewService    *services.ReviewService
 }
 
 func NewVideoHandler(app *core.AppServer, savedVideoService *services.SavedVideoService, taskStepService *services.TaskStepService) *VideoHandler {
@@ -53,6 +58,8 @@ func (h *VideoHandler) RegisterRoutes(api *gin.RouterGroup) {
 		video.GET("/:id/files", h.getVideoFiles)
 		video.POST("/:id/upload/video", h.manualUploadVideo)
 		video.POST("/:id/upload/subtitle", h.manualUploadSubtitle)
+		video.PUT("/:id/target-languages", h.updateTargetLanguages)
+		video.POST("/:id/review/approve", h.approveReview)
 	}
 }
 
@@ -89,6 +96,14 @@ type VideoInfo struct {
 	Progress       map[string]interface{} `json:"progress,omitempty"`
 	CoverImage     string                 `json:"cover_image,omitempty"`
 	MetaData       map[string]interface{} `json:"meta_data,omitempty"`
+	SubtitleQA     map[string]interface{} `json:"subtitle_qa,omitempty"`
+	QualityGate    map[string]interface{} `json:"quality_gate,omitempty"`
+	Compliance     map[string]interface{} `json:"compliance,omitempty"` // 合规检查报告
+	PromptVersions map[string]interface{} `json:"prompt_versions,omitempty"` // 各产物使用的提示词模板版本
+
+	TargetLanguages []string `json:"target_languages,omitempty"` // 视频单独设置的字幕翻译目标语言
+	Tid             int      `json:"tid,omitempty"`              // 自动选择的投稿分区（0 表示使用全局配置）
+	TidReason       string   `json:"tid_reason,omitempty"`       // 选择该分区的依据
 }
 
 // TaskStepInfo 任务步骤信息
@@ -229,6 +244,18 @@ func (h *VideoHandler) getVideoDetail(c *gin.Context) {
 	// 获取封面图片
 	coverImage := h.getVideoCoverImage(savedVideo.VideoID)
 
+	// 获取字幕质检报告
+	subtitleQA := h.getVideoReport(savedVideo.VideoID, "subtitle_qa.json")
+
+	// 获取翻译质量门禁报告
+	qualityGate := h.getVideoReport(savedVideo.VideoID, "quality_gate.json")
+
+	// 获取合规检查报告
+	compliance := h.getVideoReport(savedVideo.VideoID, "compliance.json")
+
+	// 获取各产物使用的提示词模板版本
+	promptVersions := h.getVideoReport(savedVideo.VideoID, "prompt_versions.json")
+
 	videoInfo := VideoInfo{
 		ID:             savedVideo.ID,
 		VideoID:        savedVideo.VideoID,
@@ -246,6 +273,15 @@ func (h *VideoHandler) getVideoDetail(c *gin.Context) {
 		Progress:       progress,
 		CoverImage:     coverImage,
 		MetaData:       metaData,
+		SubtitleQA:     subtitleQA,
+		QualityGate:    qualityGate,
+		Compliance:     compliance,
+		PromptVersions: promptVersions,
+		Tid:            savedVideo.Tid,
+		TidReason:      savedVideo.TidReason,
+	}
+	if savedVideo.TargetLanguages != "" {
+		videoInfo.TargetLanguages = strings.Split(savedVideo.TargetLanguages, ",")
 	}
 
 	c.JSON(http.StatusOK, VideoListResponse{
@@ -360,6 +396,18 @@ func (h *VideoHandler) deleteVideo(c *gin.Context) {
 		return
 	}
 
+	if h.RevisionService != nil {
+		if err := h.RevisionService.DeleteRevisionsByVideoID(savedVideo.VideoID); err != nil {
+			h.App.Logger.Errorf("删除字幕修订记录失败: %v", err)
+		}
+	}
+
+	if h.GlossaryService != nil {
+		if err := h.GlossaryService.DeleteTermsByVideoID(savedVideo.VideoID); err != nil {
+			h.App.Logger.Errorf("删除视频术语失败: %v", err)
+		}
+	}
+
 	// 2. 删除视频文件（可选）
 	videoDir := h.getVideoDirectory(savedVideo.VideoID)
 	if _, err := os.Stat(videoDir); err == nil {
@@ -455,6 +503,28 @@ func (h *VideoHandler) getVideoMetaData(videoID string) map[string]interface{} {
 	return metaData
 }
 
+// getVideoReport 读取视频目录下的 JSON 报告（字幕质检、翻译质量门禁等）
+func (h *VideoHandler) getVideoReport(videoID, fileName string) map[string]interface{} {
+	matches, err := filepath.Glob(filepath.Join(h.getVideoDirectory(videoID), fileName))
+	if err != nil || len(matches) == 0 {
+		return nil
+	}
+
+	data, err := os.ReadFile(matches[0])
+	if err != nil {
+		h.App.Logger.Errorf("读取%s失败: %v", fileName, err)
+		return nil
+	}
+
+	var report map[string]interface{}
+	if err := json.Unmarshal(data, &report); err != nil {
+		h.App.Logger.Errorf("解析%s失败: %v", fileName, err)
+		return nil
+	}
+
+	return report
+}
+
 // getVideoCoverImage 获取视频封面图片路径
 func (h *VideoHandler) getVideoCoverImage(videoID string) string {
 	videoDir := h.getVideoDirectory(videoID)
@@ -527,7 +597,7 @@ func (h *VideoHandler) getFileType(filename string) string {
 	switch ext {
 	case ".mp4", ".flv", ".mkv", ".webm", ".avi", ".mov":
 		return "video"
-	case ".srt", ".vtt":
+	case ".srt", ".vtt", ".ass", ".bcc", ".json3":
 		return "subtitle"
 	case ".jpg", ".jpeg", ".png", ".webp":
 		return "image"
@@ -617,6 +687,136 @@ func (h *VideoHandler) manualUploadVideo(c *gin.Context) {
 	})
 }
 
+// UpdateTargetLanguagesRequest 设置视频字幕翻译目标语言请求
+type UpdateTargetLanguagesRequest struct {
+	Languages []string `json:"languages"` // 目标语言（第一个为主语言），为空表示使用全局配置
+}
+
+// updateTargetLanguages 设置视频的字幕翻译目标语言，重新执行翻译步骤后生效
+func (h *VideoHandler) updateTargetLanguages(c *gin.Context) {
+	idStr := c.Param("id")
+
+	var savedVideo *model.SavedVideo
+	var err error
+	if id, parseErr := strconv.ParseUint(idStr, 10, 32); parseErr == nil {
+		savedVideo, err = h.SavedVideoService.GetByID(uint(id))
+	} else {
+		savedVideo, err = h.SavedVideoService.GetVideoByVideoID(idStr)
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, VideoListResponse{
+			Code:    404,
+			Message: "视频不存在",
+		})
+		return
+	}
+
+	var req UpdateTargetLanguagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, VideoListResponse{
+			Code:    400,
+			Message: "请求参数错误: " + err.Error(),
+		})
+		return
+	}
+
+	languages := subtitle.NormalizeLanguages(req.Languages)
+	savedVideo.TargetLanguages = strings.Join(languages, ",")
+	if err := h.SavedVideoService.UpdateVideo(savedVideo); err != nil {
+		h.App.Logger.Errorf("更新目标语言失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{
+			Code:    500,
+			Message: "更新目标语言失败",
+		})
+		return
+	}
+
+	h.App.Logger.Infof("🌍 视频 %s 目标语言已设置为: %v", savedVideo.VideoID, languages)
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "success",
+		Data: gin.H{
+			"video_id":         savedVideo.VideoID,
+			"target_languages": languages,
+		},
+	})
+}
+
+// approveReview 人工审核通过：待审核（150）的视频恢复为准备就绪（200），进入上传队列
+func (h *VideoHandler) approveReview(c *gin.Context) {
+	idStr := c.Param("id")
+
+	var savedVideo *model.SavedVideo
+	var err error
+	if id, parseErr := strconv.ParseUint(idStr, 10, 32); parseErr == nil {
+		savedVideo, err = h.SavedVideoService.GetByID(uint(id))
+	} else {
+		savedVideo, err = h.SavedVideoService.GetVideoByVideoID(idStr)
+	}
+	if err != nil {
+		c.JSON(http.StatusNotFound, VideoListResponse{
+			Code:    404,
+			Message: "视频不存在",
+		})
+		return
+	}
+
+	if savedVideo.Status != "150" {
+		c.JSON(http.StatusBadRequest, VideoListResponse{
+			Code:    400,
+			Message: fmt.Sprintf("当前状态 %s 不需要审核，只有状态为 150(待人工审核) 的视频才能审核通过", savedVideo.Status),
+		})
+		return
+	}
+
+	root, err := filepath.Abs(h.App.Config.FileUpDir)
+	if err != nil {
+		h.App.Logger.Errorf("获取文件上传目录失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{Code: 500, Message: "获取文件目录失败"})
+		return
+	}
+	stateManager := manager.NewStateManager(savedVideo.ID, savedVideo.VideoID, root, savedVideo.CreatedAt)
+	if reasons := complianceBlockReasons(h.App, stateManager.Compliance); len(reasons) > 0 {
+		c.JSON(http.StatusBadRequest, VideoListResponse{
+			Code:    400,
+			Message: "合规检查未通过，请修改后重新执行“合规检查”: " + strings.Join(reasons, "; "),
+		})
+		return
+	}
+
+	var req ReviewActionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, VideoListResponse{Code: 400, Message: "请求参数错误: " + err.Error()})
+			return
+		}
+	}
+
+	if h.ReviewService != nil {
+		err = h.ReviewService.Approve(savedVideo, resolveAuthor(c, req.Reviewer), req.Comment)
+	} else {
+		err = h.SavedVideoService.UpdateStatus(savedVideo.ID, "200")
+	}
+	if err != nil {
+		h.App.Logger.Errorf("更新视频状态失败: %v", err)
+		c.JSON(http.StatusInternalServerError, VideoListResponse{
+			Code:    500,
+			Message: "更新视频状态失败",
+		})
+		return
+	}
+
+	h.App.Logger.Infof("👍 视频 %s 人工审核通过，进入上传队列", savedVideo.VideoID)
+	c.JSON(http.StatusOK, VideoListResponse{
+		Code:    200,
+		Message: "审核通过",
+		Data: gin.H{
+			"video_id": savedVideo.VideoID,
+			"status":   "200",
+		},
+	})
+}
+
 // manualUploadSubtitle 手动触发字幕上传
 func (h *VideoHandler) manualUploadSubtitle(c *gin.Context) {
 	idStr := c.Param("id")
@@ -11,8 +11,11 @@ import (
 	"github.com/difyz9/ytb2bili/pkg/auth"
 	"github.com/difyz9/ytb2bili/pkg/cos"
 	"github.com/difyz9/ytb2bili/pkg/logger"
+	"github.com/difyz9/ytb2bili/pkg/prompt"
+	"github.com/difyz9/ytb2bili/pkg/ratelimit"
 	biliAccountService "github.com/difyz9/ytb2bili/pkg/services"
 	"github.com/difyz9/ytb2bili/pkg/store"
+	"github.com/difyz9/ytb2bili/pkg/translator"
 	"context"
 	"github.com/gin-gonic/gin"
 	"github.com/robfig/cron/v3"
@@ -120,6 +123,14 @@ func main() {
 		fx.Provide(services.NewVideoService),
 		fx.Provide(services.NewSavedVideoService),
 		fx.Provide(services.NewTaskStepService),
+		fx.Provide(services.NewSubtitleRevisionService),
+		fx.Provide(services.NewTranslationMemoryService),
+		fx.Provide(services.NewGlossaryService),
+		fx.Provide(services.NewLLMUsageService),
+		fx.Provide(services.NewPromptTemplateService),
+		fx.Provide(services.NewAIServiceManager),
+		fx.Provide(services.NewBiliPartitionService),
+		fx.Provide(services.NewReviewService),
 		fx.Provide(biliAccountService.NewBilibiliAccountService),
 
 		// 注册cron
@@ -143,6 +154,13 @@ func main() {
 			return store.MigrateDatabase(db)
 		}),
 
+		// 注入 LLM 用量记录器、提示词模板存储，配置提供商限流（需在任务消费者启动前完成）
+		fx.Invoke(func(s *services.LLMUsageService, prompts *services.PromptTemplateService, config *types.AppConfig) {
+			translator.SetUsageRecorder(s)
+			prompt.SetStore(prompts)
+			ratelimit.Configure(config.RateLimitConfig)
+		}),
+
 
 		fx.Provide(chain_task.NewChainTaskHandler),
 		fx.Invoke(func(h *chain_task.ChainTaskHandler) {
@@ -237,14 +255,92 @@ func main() {
 			server *core.AppServer,
 			uploadScheduler *chain_task.UploadScheduler,
 			analyticsHandler *handler.AnalyticsHandler,
+			revisionService *services.SubtitleRevisionService,
+			glossaryService *services.GlossaryService,
+			reviewService *services.ReviewService,
 			logger *zap.SugaredLogger,
 		) {
 			h.AnalyticsHandler = analyticsHandler
+			h.RevisionService = revisionService
+			h.GlossaryService = glossaryService
+			h.ReviewService = reviewService
 			h.SetUploadScheduler(uploadScheduler)
 			h.RegisterRoutes(server.Engine.Group("/api/v1"))
 			logger.Info("✓ Video routes registered")
 		}),
 
+		fx.Provide(handler.NewSubtitleEditorHandler),
+		fx.Invoke(func(
+			h *handler.SubtitleEditorHandler,
+			server *core.AppServer,
+			uploadScheduler *chain_task.UploadScheduler,
+			logger *zap.SugaredLogger,
+		) {
+			h.SetUploadScheduler(uploadScheduler)
+			h.RegisterRoutes(server.Engine.Group("/api/v1"))
+			logger.Info("✓ Subtitle editor routes registered")
+		}),
+
+		fx.Provide(handler.NewCoverHandler),
+		fx.Invoke(func(
+			h *handler.CoverHandler,
+			server *core.AppServer,
+			logger *zap.SugaredLogger,
+		) {
+			h.RegisterRoutes(server.Engine.Group("/api/v1"))
+			logger.Info("✓ Cover routes registered")
+		}),
+
+		fx.Provide(handler.NewReviewHandler),
+		fx.Invoke(func(
+			h *handler.ReviewHandler,
+			server *core.AppServer,
+			logger *zap.SugaredLogger,
+		) {
+			h.RegisterRoutes(server.Engine.Group("/api/v1"))
+			logger.Info("✓ Review routes registered")
+		}),
+
+		fx.Provide(handler.NewTranslationMemoryHandler),
+		fx.Invoke(func(
+			h *handler.TranslationMemoryHandler,
+			server *core.AppServer,
+			logger *zap.SugaredLogger,
+		) {
+			h.RegisterRoutes(server.Engine.Group("/api/v1"))
+			logger.Info("✓ Translation memory routes registered")
+		}),
+
+		fx.Provide(handler.NewGlossaryHandler),
+		fx.Invoke(func(
+			h *handler.GlossaryHandler,
+			server *core.AppServer,
+			logger *zap.SugaredLogger,
+		) {
+			h.RegisterRoutes(server.Engine.Group("/api/v1"))
+			logger.Info("✓ Glossary routes registered")
+		}),
+
+		fx.Provide(handler.NewLLMUsageHandler),
+		fx.Invoke(func(
+			h *handler.LLMUsageHandler,
+			server *core.AppServer,
+			logger *zap.SugaredLogger,
+		) {
+			h.RegisterRoutes(server.Engine.Group("/api/v1"))
+			logger.Info("✓ LLM usage routes registered")
+		}),
+
+		fx.Provide(handler.NewPromptHandler),
+		fx.Invoke(func(
+			h *handler.PromptHandler,
+			server *core.AppServer,
+			logger *zap.SugaredLogger,
+		) {
+			h.RegisterRoutes(server.Engine.Group("/api/v1"))
+			logger.Info("✓ Prompt template routes registered")
+		}),
+
 		// 健康检查和静态文件服务
 		fx.Invoke(func(server *core.AppServer, logger *zap.SugaredLogger) {
 			// 健康检查
@@ -0,0 +1,300 @@
+package prompt
+
+// Definition 提示词的说明和内置默认模板
+type Definition struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Variables   []string `json:"variables"`    // 模板中可用的变量
+	RequireUser bool     `json:"require_user"` // 是否必须提供 user 模板（否则必须提供 system 模板）
+	Builtin     Template `json:"builtin"`
+}
+
+// 内置模板：保持与模板化之前的提示词一致
+var definitions = []Definition{
+	{
+		Name:        Translation,
+		Description: "批量翻译字幕的系统提示词（结构化 JSON 输入输出格式说明由程序自动追加）",
+		Variables:   []string{"SourceLanguage", "TargetLanguage", "TextType", "Domain", "SourceTitle", "Channel", "Glossary"},
+		Builtin: Template{
+			Name: Translation,
+			System: `你是一位专业的翻译专家。请将输入 JSON 中 cues 的每条文本逐条翻译。
+
+翻译要求：
+1. 保持原文的意思和语调
+2. 使用自然流畅的目标语言表达
+3. 每个编号对应一条译文，不要合并或拆分
+4. 译文中不要保留编号
+{{- if .SourceLanguage}}
+- 源语言：{{.SourceLanguage}}
+{{- end}}
+{{- if .TargetLanguage}}
+- 目标语言：{{.TargetLanguage}}
+{{- end}}
+{{- if .TextType}}
+- 文本类型：{{.TextType}}
+{{- end}}
+{{- if .Domain}}
+- 领域：{{.Domain}}
+{{- end}}
+{{- if .SourceTitle}}
+- 视频标题：{{.SourceTitle}}
+{{- end}}
+{{- if .Channel}}
+- 来源频道：{{.Channel}}
+{{- end}}
+{{- if eq .TextType "subtitle"}}
+
+这些是同一视频中连续的字幕，请结合上下文翻译，使用口语化、简洁的表达，便于观众快速阅读。
+{{- end}}
+{{.Glossary}}`,
+		},
+	},
+	{
+		Name:        SubtitleFix,
+		Description: "字幕校验后重新翻译缺失或有误条目的系统提示词（结构化 JSON 输入输出格式说明由程序自动追加）",
+		Variables:   []string{"TargetLanguage", "SourceTitle", "Channel", "Glossary"},
+		Builtin: Template{
+			Name: SubtitleFix,
+			System: `你是专业的视频字幕翻译专家。现在需要重新翻译有问题的英文字幕。
+
+翻译要求：
+1. 自然流畅：使用口语化表达，符合中文字幕习惯
+2. 准确传神：忠实原文含义，保持语气和情感
+3. 简洁明了：字幕需要快速阅读，避免冗长
+4. 完整输出：必须为每个编号提供完整的中文翻译
+
+注意：之前的翻译中可能有缺失、错误或术语不一致，请提供完整准确的重新翻译。
+{{.Glossary}}`,
+		},
+	},
+	{
+		Name:        Metadata,
+		Description: "根据译文字幕生成 B 站标题、简介和标签（按首选 AI 服务调用），模型需返回 JSON",
+		Variables:   []string{"SubtitleText", "SourceTitle", "Channel"},
+		RequireUser: true,
+		Builtin: Template{
+			Name:   Metadata,
+			System: "你是一个专业的视频内容分析助手，擅长根据视频字幕生成吸引人的标题和描述。",
+			User: `请根据以下视频字幕内容，生成一个吸引人的视频标题、详细描述和3-5个相关标签。
+
+字幕内容：
+{{.SubtitleText}}
+
+要求：
+1. 标题要简洁有力，严格控制在30个字以内（B站限制80字，但建议30字以内更易读），能够准确概括视频主题，吸引观众点击
+2. 描述要详细但不要过长，严格控制在600-800字以内，包含视频的主要内容和亮点（注意：B站简介限制2000字，需要预留约200字给原视频链接和分隔线）
+3. 标签要准确反映视频内容，3-5个即可
+4. 必须使用中文
+5. 输出格式必须是JSON，格式如下：
+{
+  "title": "视频标题",
+  "description": "视频描述",
+  "tags": ["标签1", "标签2", "标签3"]
+}
+
+请直接返回JSON格式的结果，不要包含任何其他说明文字。`,
+		},
+	},
+	{
+		Name:        MetadataGeminiVideo,
+		Description: "根据视频画面生成 B 站标题、简介和标签（Gemini 视频模式，视频文件随提示词一起发送），模型需返回 JSON",
+		Variables:   []string{"SourceTitle", "Channel"},
+		RequireUser: true,
+		Builtin: Template{
+			Name: MetadataGeminiVideo,
+			User: `请作为一个专业的 Bilibili UP 主，分析这个视频并生成以下内容：
+
+1. 一个吸引眼球的标题（严格控制在30个字以内，能够准确概括视频主题）
+2. 一个精炼的视频介绍（严格控制在100个字以内，提炼视频的核心内容和亮点）
+3. 3-5个相关的标签
+
+要求：
+- 必须使用中文
+- 标题要简洁有力，吸引观众点击
+- 介绍要精炼，突出重点，严格控制在100字以内
+- 标签要准确反映视频内容
+- 输出格式必须是JSON，格式如下：
+{
+  "title": "视频标题",
+  "description": "视频介绍（100字以内）",
+  "tags": ["标签1", "标签2", "标签3"]
+}
+
+请直接返回JSON格式的结果，不要包含任何其他说明文字。`,
+		},
+	},
+	{
+		Name:        MetadataFrames,
+		Description: "根据视频关键帧和对应时间点的字幕摘录生成 B 站标题、简介和标签（Gemini 或支持图像输入的 OpenAI 兼容模型，关键帧随提示词一起发送），模型需返回 JSON",
+		Variables:   []string{"SubtitleText", "SourceTitle", "Channel"},
+		RequireUser: true,
+		Builtin: Template{
+			Name: MetadataFrames,
+			User: `请作为一个专业的 Bilibili UP 主，根据上面按时间顺序排列的视频关键帧（每张图片前标注了时间点）
+{{- if .SubtitleText}}和下面对应时间点的字幕摘录{{end}}，生成以下内容：
+
+1. 一个吸引眼球的标题（严格控制在30个字以内，能够准确概括视频主题）
+2. 一个精炼的视频介绍（严格控制在100个字以内，提炼视频的核心内容和亮点）
+3. 3-5个相关的标签
+{{- if .SubtitleText}}
+
+字幕摘录：
+{{.SubtitleText}}
+{{- end}}
+
+要求：
+- 必须使用中文
+- 标题要简洁有力，吸引观众点击
+- 介绍要精炼，突出重点，严格控制在100字以内
+- 标签要准确反映视频内容
+- 输出格式必须是JSON，格式如下：
+{
+  "title": "视频标题",
+  "description": "视频介绍（100字以内）",
+  "tags": ["标签1", "标签2", "标签3"]
+}
+
+请直接返回JSON格式的结果，不要包含任何其他说明文字。`,
+		},
+	},
+	{
+		Name:        Partition,
+		Description: "在B站分区列表中为视频选择投稿分区（映射规则未命中时使用），模型需返回 JSON，tid 必须取自候选分区列表",
+		Variables:   []string{"SourceTitle", "Channel", "SourceCategory", "SourceTags", "Summary", "Partitions"},
+		RequireUser: true,
+		Builtin: Template{
+			Name:   Partition,
+			System: "你是熟悉 Bilibili 各分区内容定位的运营编辑，擅长为搬运视频选择最合适的投稿分区。",
+			User: `请根据以下视频信息，从候选分区列表中选择一个最合适的投稿分区。
+
+视频信息：
+{{- if .SourceTitle}}
+- 原标题：{{.SourceTitle}}
+{{- end}}
+{{- if .Channel}}
+- 来源频道：{{.Channel}}
+{{- end}}
+{{- if .SourceCategory}}
+- YouTube 分类：{{.SourceCategory}}
+{{- end}}
+{{- if .SourceTags}}
+- 原视频标签：{{.SourceTags}}
+{{- end}}
+{{- if .Summary}}
+
+内容概要：
+{{.Summary}}
+{{- end}}
+
+候选分区列表（每行格式：分区ID 一级分区/分区 - 说明）：
+{{.Partitions}}
+
+要求：
+1. tid 必须是候选分区列表中的分区ID，不能自行编造
+2. 根据视频的实际内容选择，而不是只看来源分类
+3. reason 用一句中文说明选择该分区的理由
+4. 输出格式必须是JSON，格式如下：
+{
+  "tid": 95,
+  "reason": "选择理由"
+}
+
+请直接返回JSON格式的结果，不要包含任何其他说明文字。`,
+		},
+	},
+	{
+		Name:        Chapters,
+		Description: "根据带时间点的字幕为长视频划分章节（来源视频没有章节时使用），模型需返回 JSON",
+		Variables:   []string{"SubtitleText", "TargetLanguage", "MaxChapters", "SourceTitle", "Channel"},
+		RequireUser: true,
+		Builtin: Template{
+			Name:   Chapters,
+			System: "你是专业的视频编辑，擅长根据字幕内容的话题变化为长视频划分章节。",
+			User: `请根据以下带时间点的视频字幕，按话题变化为视频划分章节。
+{{- if .SourceTitle}}
+
+视频标题：{{.SourceTitle}}
+{{- end}}
+
+字幕（每行格式：[时间点] 字幕文本）：
+{{.SubtitleText}}
+
+要求：
+1. 只在话题明显变化的地方划分章节，{{if .MaxChapters}}不超过{{.MaxChapters}}个，{{end}}第一章从 00:00 开始
+2. start 使用字幕中出现的时间点，格式为 mm:ss 或 hh:mm:ss
+3. 章节标题简洁概括该段内容，不超过15个字{{if .TargetLanguage}}，使用{{.TargetLanguage}}{{end}}
+4. 输出格式必须是JSON，格式如下：
+{
+  "chapters": [
+    {"start": "00:00", "title": "章节标题"},
+    {"start": "03:25", "title": "章节标题"}
+  ]
+}
+
+请直接返回JSON格式的结果，不要包含任何其他说明文字。`,
+		},
+	},
+	{
+		Name:        Compliance,
+		Description: "上传前检查字幕内容是否违反B站社区规范（合规检查启用 use_llm 时使用），模型需返回 JSON",
+		Variables:   []string{"SubtitleText", "SourceTitle", "Channel", "Summary"},
+		RequireUser: true,
+		Builtin: Template{
+			Name:   Compliance,
+			System: "你是熟悉 Bilibili 社区规范和投稿审核规则的内容审核员，负责在视频投稿前发现可能导致审核不通过或限流的内容。",
+			User: `请检查以下即将投稿到B站的视频内容是否违反平台规范。
+{{- if .SourceTitle}}
+
+原视频标题：{{.SourceTitle}}
+{{- end}}
+{{- if .Summary}}
+
+投稿信息：
+{{.Summary}}
+{{- end}}
+
+字幕（每行格式：[时间点] 字幕文本）：
+{{.SubtitleText}}
+
+检查范围：政治敏感、色情低俗、血腥暴力、违法违规（赌博、毒品、诈骗等）、危险行为、引流到其他平台（二维码、联系方式、其他平台账号）、商业广告。
+只有明确违反规范的内容才判定为 block，可能有风险但不确定的判定为 warn，正常内容判定为 pass。
+
+输出格式必须是JSON，格式如下：
+{
+  "verdict": "pass",
+  "categories": ["问题类别"],
+  "reason": "一句话说明判定理由",
+  "issues": [
+    {"time": "03:25", "text": "有问题的字幕原文", "issue": "问题说明"}
+  ]
+}
+
+请直接返回JSON格式的结果，不要包含任何其他说明文字。`,
+		},
+	},
+}
+
+// Definitions 返回所有可配置的提示词
+func Definitions() []Definition {
+	return definitions
+}
+
+// Lookup 按名称查找提示词定义
+func Lookup(name string) (*Definition, bool) {
+	for i := range definitions {
+		if definitions[i].Name == name {
+			return &definitions[i], true
+		}
+	}
+	return nil, false
+}
+
+// Builtin 返回内置默认模板的副本
+func Builtin(name string) (*Template, bool) {
+	definition, ok := Lookup(name)
+	if !ok {
+		return nil, false
+	}
+	builtin := definition.Builtin
+	return &builtin, true
+}
@@ -0,0 +1,184 @@
+package prompt
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"text/template"
+
+	logger2 "github.com/difyz9/ytb2bili/pkg/logger"
+)
+
+var logger = logger2.GetLogger()
+
+// 提示词名称
+const (
+	Translation         = "translation"           // 批量翻译（系统提示词）
+	SubtitleFix         = "subtitle_fix"          // 字幕校验后重新翻译问题条目（系统提示词）
+	Metadata            = "metadata"              // 根据字幕生成标题、简介、标签（按首选 AI 服务调用）
+	MetadataGeminiVideo = "metadata_gemini_video" // 根据视频画面生成标题、简介、标签（Gemini）
+	MetadataFrames      = "metadata_frames"       // 根据关键帧和字幕摘录生成标题、简介、标签（多模态模型）
+	Partition           = "partition"             // 在B站分区列表中为视频选择投稿分区
+	Chapters            = "chapters"              // 根据带时间点的字幕划分视频章节
+	Compliance          = "compliance"            // 检查字幕内容是否违反B站社区规范
+)
+
+// Data 模板可用的变量
+type Data struct {
+	SubtitleText   string // 字幕文本（元数据生成时为截断后的译文字幕，章节生成时为带时间点的字幕）
+	SourceTitle    string // 原视频标题
+	Channel        string // 来源频道
+	Glossary       string // 术语表（已格式化为提示词片段，没有术语时为空）
+	SourceLanguage string // 源语言名称（自动检测时为空）
+	TargetLanguage string // 目标语言名称
+	TextType       string // 文本类型，如 subtitle
+	Domain         string // 领域
+	SourceCategory string // 来源视频分类（YouTube 分类）
+	SourceTags     string // 来源视频标签
+	Summary        string // AI生成的标题、简介和标签
+	Partitions     string // 候选分区列表（每行一个：tid 一级分区/分区 - 说明）
+	MaxChapters    int    // 最多章节数
+}
+
+// SampleData 校验和预览模板时使用的示例数据
+func SampleData() *Data {
+	return &Data{
+		SubtitleText:   "Welcome back to the channel. Today we are building a tiny robot.",
+		SourceTitle:    "Building a Tiny Robot",
+		Channel:        "Example Channel",
+		Glossary:       "\n术语表（必须严格遵守，原文出现以下术语时使用规定译法）：\n- robot → 机器人\n",
+		SourceLanguage: "英语",
+		TargetLanguage: "简体中文",
+		TextType:       "subtitle",
+		SourceCategory: "Science & Technology",
+		SourceTags:     "robot, diy, arduino",
+		Summary:        "标题：从零打造迷你机器人\n简介：手把手用 Arduino 制作一个小型机器人。\n标签：机器人, DIY, Arduino",
+		Partitions:     "95 科技/数码 - 手机、电脑、相机等数码产品\n122 知识/野生技能协会 - 技能展示或技能教学分享",
+		MaxChapters:    12,
+	}
+}
+
+// Template 一个版本的提示词模板，Version 为 0 表示内置默认模板
+type Template struct {
+	Name    string `json:"name"`
+	Version int    `json:"version"`
+	System  string `json:"system"`
+	User    string `json:"user"`
+}
+
+// Label 版本标识，记录在生成的产物中，如 metadata@v3、metadata@builtin
+func (t *Template) Label() string {
+	if t.Version == 0 {
+		return t.Name + "@builtin"
+	}
+	return fmt.Sprintf("%s@v%d", t.Name, t.Version)
+}
+
+// Rendered 渲染后的提示词
+type Rendered struct {
+	System  string `json:"system"`
+	User    string `json:"user"`
+	Version string `json:"version"`
+}
+
+// Render 使用给定数据渲染模板
+func (t *Template) Render(data *Data) (*Rendered, error) {
+	if data == nil {
+		data = &Data{}
+	}
+	system, err := execute(t.Name+".system", t.System, data)
+	if err != nil {
+		return nil, err
+	}
+	user, err := execute(t.Name+".user", t.User, data)
+	if err != nil {
+		return nil, err
+	}
+	return &Rendered{System: system, User: user, Version: t.Label()}, nil
+}
+
+func execute(name, text string, data *Data) (string, error) {
+	if text == "" {
+		return "", nil
+	}
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("解析模板 %s 失败: %w", name, err)
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("渲染模板 %s 失败: %w", name, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// Validate 检查模板能否解析并用示例数据渲染，以及是否包含该提示词必需的部分
+func Validate(tmpl *Template) error {
+	definition, ok := Lookup(tmpl.Name)
+	if !ok {
+		return fmt.Errorf("未知的提示词: %s", tmpl.Name)
+	}
+	if definition.RequireUser && strings.TrimSpace(tmpl.User) == "" {
+		return fmt.Errorf("提示词 %s 的 user 模板不能为空", tmpl.Name)
+	}
+	if !definition.RequireUser && strings.TrimSpace(tmpl.System) == "" {
+		return fmt.Errorf("提示词 %s 的 system 模板不能为空", tmpl.Name)
+	}
+	_, err := tmpl.Render(SampleData())
+	return err
+}
+
+// Store 提示词模板存储，返回当前启用的版本；没有启用的版本时返回 nil
+type Store interface {
+	ActiveTemplate(name string) (*Template, error)
+}
+
+var (
+	store      Store
+	storeMutex sync.RWMutex
+)
+
+// SetStore 设置全局模板存储。翻译器、字幕校验器等按需临时创建，
+// 因此存储放在包级别，由启动流程注入一次；未设置时使用内置模板
+func SetStore(s Store) {
+	storeMutex.Lock()
+	defer storeMutex.Unlock()
+	store = s
+}
+
+func currentStore() Store {
+	storeMutex.RLock()
+	defer storeMutex.RUnlock()
+	return store
+}
+
+// Active 返回当前启用的模板，存储不可用或没有启用的版本时返回内置模板
+func Active(name string) *Template {
+	if s := currentStore(); s != nil {
+		tmpl, err := s.ActiveTemplate(name)
+		if err != nil {
+			logger.Warnf("Failed to load prompt template %s, using builtin: %v", name, err)
+		} else if tmpl != nil {
+			return tmpl
+		}
+	}
+	builtin, _ := Builtin(name)
+	return builtin
+}
+
+// Render 使用当前启用的模板渲染提示词；启用的版本渲染失败时回退到内置模板，保证任务不因模板错误中断
+func Render(name string, data *Data) (*Rendered, error) {
+	tmpl := Active(name)
+	if tmpl == nil {
+		return nil, fmt.Errorf("未知的提示词: %s", name)
+	}
+	rendered, err := tmpl.Render(data)
+	if err == nil || tmpl.Version == 0 {
+		return rendered, err
+	}
+
+	logger.Warnf("Prompt template %s failed to render, using builtin: %v", tmpl.Label(), err)
+	builtin, _ := Builtin(name)
+	return builtin.Render(data)
+}
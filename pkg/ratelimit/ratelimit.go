@@ -0,0 +1,438 @@
+// Package ratelimit 为 AI 和翻译提供商提供进程内共享的限流与熔断。
+// 同一提供商的所有调用（翻译、元数据生成、字幕修复等）都通过 Acquire 获取许可，
+// 调用结束后通过 Permit.Done 报告结果；连续的 429/5xx 响应会使该提供商熔断一段时间。
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	logger2 "github.com/difyz9/ytb2bili/pkg/logger"
+)
+
+var logger = logger2.GetLogger()
+
+// ErrCircuitOpen 提供商处于熔断状态，调用方应切换到备选提供商或稍后重试
+var ErrCircuitOpen = errors.New("provider circuit open")
+
+// 熔断器状态
+const (
+	StateClosed   = "closed"    // 正常
+	StateOpen     = "open"      // 熔断中，拒绝所有请求
+	StateHalfOpen = "half_open" // 冷却结束，放行一个探测请求
+)
+
+// 限流窗口
+const window = time.Minute
+
+// Limits 单个提供商的限额（0 表示不限制）
+type Limits struct {
+	RequestsPerMinute int
+	TokensPerMinute   int
+	MaxConcurrency    int
+	FailureThreshold  int
+	Cooldown          time.Duration
+}
+
+// Status 提供商当前的限流与熔断状态
+type Status struct {
+	Provider  string    `json:"provider"`
+	State     string    `json:"state"`
+	Failures  int       `json:"failures"`
+	InFlight  int       `json:"in_flight"`
+	OpenUntil time.Time `json:"open_until,omitempty"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+type reservation struct {
+	at     time.Time
+	tokens int
+}
+
+// limiter 单个提供商的限流器和熔断器
+type limiter struct {
+	provider string
+
+	mu        sync.Mutex
+	limits    Limits
+	inFlight  int
+	recent    []reservation // 最近一分钟内发出的请求
+	changed   chan struct{} // 释放许可或熔断状态变化时关闭，唤醒等待者
+	state     string
+	failures  int
+	openUntil time.Time
+	probing   bool
+	lastError string
+}
+
+var (
+	registryMu sync.Mutex
+	enabled    bool
+	limitTable map[string]Limits
+	limiters   = make(map[string]*limiter)
+)
+
+// Configure 按配置设置各提供商的限额，未列出的提供商使用 "default" 限额；
+// 配置为空或未启用时不做任何限制。可重复调用，已有的限流器会立即使用新限额
+func Configure(cfg *types.RateLimitConfig) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	enabled = cfg != nil && cfg.Enabled
+	limitTable = make(map[string]Limits)
+	if cfg != nil {
+		for provider, limit := range cfg.Providers {
+			if limit == nil {
+				continue
+			}
+			limitTable[provider] = Limits{
+				RequestsPerMinute: limit.RequestsPerMinute,
+				TokensPerMinute:   limit.TokensPerMinute,
+				MaxConcurrency:    limit.MaxConcurrency,
+				FailureThreshold:  limit.FailureThreshold,
+				Cooldown:          time.Duration(limit.CooldownSeconds) * time.Second,
+			}
+		}
+	}
+
+	for provider, l := range limiters {
+		l.setLimits(limitsFor(provider))
+	}
+}
+
+// limitsFor 查找提供商的限额（调用方持有 registryMu）
+func limitsFor(provider string) Limits {
+	if limit, ok := limitTable[provider]; ok {
+		return limit
+	}
+	return limitTable["default"]
+}
+
+// get 返回提供商的限流器，未启用时返回 nil
+func get(provider string) *limiter {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if !enabled {
+		return nil
+	}
+	l, ok := limiters[provider]
+	if !ok {
+		l = &limiter{
+			provider: provider,
+			limits:   limitsFor(provider),
+			changed:  make(chan struct{}),
+			state:    StateClosed,
+		}
+		limiters[provider] = l
+	}
+	return l
+}
+
+// Permit 一次调用的许可，调用结束后必须调用 Done
+type Permit struct {
+	limiter *limiter
+	probe   bool
+	once    sync.Once
+}
+
+// Acquire 为一次调用获取许可：超出每分钟请求数、token 数或并发数时阻塞等待，
+// 提供商熔断时立即返回包装了 ErrCircuitOpen 的错误。tokens 为请求的估算 token 数
+func Acquire(ctx context.Context, provider string, tokens int) (*Permit, error) {
+	l := get(provider)
+	if l == nil {
+		return &Permit{}, nil
+	}
+	return l.acquire(ctx, tokens)
+}
+
+// Done 报告调用结果并释放许可。429/5xx 计入熔断失败次数，其他错误（参数错误、鉴权失败等）不影响熔断
+func (p *Permit) Done(err error) {
+	if p == nil || p.limiter == nil {
+		return
+	}
+	p.once.Do(func() {
+		p.limiter.release(p.probe, err)
+	})
+}
+
+// Available 提供商当前是否可用（未熔断），不可用时返回熔断原因
+func Available(provider string) (bool, string) {
+	l := get(provider)
+	if l == nil {
+		return true, ""
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.state == StateOpen && time.Now().Before(l.openUntil) {
+		return false, fmt.Sprintf("熔断至 %s: %s", l.openUntil.Format("15:04:05"), l.lastError)
+	}
+	return true, ""
+}
+
+// Statuses 返回已使用过的提供商的状态
+func Statuses() []Status {
+	registryMu.Lock()
+	list := make([]*limiter, 0, len(limiters))
+	for _, l := range limiters {
+		list = append(list, l)
+	}
+	registryMu.Unlock()
+
+	statuses := make([]Status, 0, len(list))
+	for _, l := range list {
+		l.mu.Lock()
+		status := Status{
+			Provider:  l.provider,
+			State:     l.state,
+			Failures:  l.failures,
+			InFlight:  l.inFlight,
+			LastError: l.lastError,
+		}
+		if l.state == StateOpen {
+			status.OpenUntil = l.openUntil
+		}
+		l.mu.Unlock()
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func (l *limiter) setLimits(limits Limits) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.limits = limits
+	l.notifyLocked()
+}
+
+func (l *limiter) acquire(ctx context.Context, tokens int) (*Permit, error) {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		if err := l.checkCircuitLocked(now); err != nil {
+			l.mu.Unlock()
+			return nil, err
+		}
+
+		wait := l.waitLocked(now, tokens)
+		if wait <= 0 {
+			permit := &Permit{limiter: l, probe: l.state == StateHalfOpen}
+			if permit.probe {
+				l.probing = true
+			}
+			l.inFlight++
+			l.recent = append(l.recent, reservation{at: now, tokens: l.capTokens(tokens)})
+			l.mu.Unlock()
+			return permit, nil
+		}
+		changed := l.changed
+		l.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-changed:
+			timer.Stop()
+		case <-timer.C:
+		}
+	}
+}
+
+// checkCircuitLocked 熔断中或已有探测请求时拒绝请求；冷却结束后进入半开状态
+func (l *limiter) checkCircuitLocked(now time.Time) error {
+	switch l.state {
+	case StateOpen:
+		if now.Before(l.openUntil) {
+			return fmt.Errorf("%w: %s（%s 后恢复）: %s", ErrCircuitOpen, l.provider,
+				l.openUntil.Sub(now).Round(time.Second), l.lastError)
+		}
+		l.state = StateHalfOpen
+		l.probing = false
+		logger.Infof("Provider %s circuit half-open, sending probe request", l.provider)
+	case StateHalfOpen:
+		if l.probing {
+			return fmt.Errorf("%w: %s（等待探测请求结果）", ErrCircuitOpen, l.provider)
+		}
+	}
+	return nil
+}
+
+// waitLocked 计算需要等待多久才能发出请求，0 表示可以立即发出
+func (l *limiter) waitLocked(now time.Time, tokens int) time.Duration {
+	cutoff := now.Add(-window)
+	expired := 0
+	for expired < len(l.recent) && !l.recent[expired].at.After(cutoff) {
+		expired++
+	}
+	l.recent = l.recent[expired:]
+
+	var wait time.Duration
+	if l.limits.MaxConcurrency > 0 && l.inFlight >= l.limits.MaxConcurrency {
+		// 释放许可时会被唤醒，这里只是兜底
+		wait = time.Second
+	}
+	if l.limits.RequestsPerMinute > 0 && len(l.recent) >= l.limits.RequestsPerMinute {
+		oldest := l.recent[len(l.recent)-l.limits.RequestsPerMinute]
+		wait = maxDuration(wait, oldest.at.Add(window).Sub(now))
+	}
+	if l.limits.TokensPerMinute > 0 {
+		used := 0
+		for _, r := range l.recent {
+			used += r.tokens
+		}
+		need := l.capTokens(tokens)
+		for i := 0; used+need > l.limits.TokensPerMinute && i < len(l.recent); i++ {
+			used -= l.recent[i].tokens
+			wait = maxDuration(wait, l.recent[i].at.Add(window).Sub(now))
+		}
+	}
+	if wait > 0 && wait < 10*time.Millisecond {
+		wait = 10 * time.Millisecond
+	}
+	return wait
+}
+
+// capTokens 单个请求超过每分钟 token 上限时按上限计算，否则永远无法发出
+func (l *limiter) capTokens(tokens int) int {
+	if l.limits.TokensPerMinute > 0 && tokens > l.limits.TokensPerMinute {
+		return l.limits.TokensPerMinute
+	}
+	return tokens
+}
+
+func (l *limiter) release(probe bool, err error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.inFlight--
+	if probe {
+		l.probing = false
+	}
+
+	if IsTransient(err) {
+		l.failures++
+		l.lastError = truncate(err.Error(), 200)
+		threshold := l.limits.FailureThreshold
+		if l.state == StateHalfOpen || (threshold > 0 && l.failures >= threshold) {
+			l.state = StateOpen
+			l.openUntil = time.Now().Add(l.cooldown())
+			logger.Warnf("Provider %s circuit open for %s after %d failures: %s",
+				l.provider, l.cooldown(), l.failures, l.lastError)
+		}
+	} else if err == nil {
+		if l.state != StateClosed {
+			logger.Infof("Provider %s circuit closed", l.provider)
+		}
+		l.state = StateClosed
+		l.failures = 0
+		l.lastError = ""
+	}
+	l.notifyLocked()
+}
+
+func (l *limiter) cooldown() time.Duration {
+	if l.limits.Cooldown > 0 {
+		return l.limits.Cooldown
+	}
+	return time.Minute
+}
+
+func (l *limiter) notifyLocked() {
+	close(l.changed)
+	l.changed = make(chan struct{})
+}
+
+// statusError 带 HTTP 状态码的错误
+type statusError struct {
+	code int
+	err  error
+}
+
+func (e *statusError) Error() string   { return e.err.Error() }
+func (e *statusError) Unwrap() error   { return e.err }
+func (e *statusError) HTTPStatus() int { return e.code }
+
+// WithStatus 为错误附加 HTTP 状态码，供熔断器判断是否为限流或服务端错误
+func WithStatus(code int, err error) error {
+	if err == nil {
+		return nil
+	}
+	return &statusError{code: code, err: err}
+}
+
+var statusPattern = regexp.MustCompile(`(?i)(?:status|状态码|error)\D{0,3}(\d{3})\b`)
+
+// StatusCode 提取错误对应的 HTTP 状态码：优先使用 WithStatus 附加的状态码，
+// 否则从 "status 429"、"状态码: 503"、"Error 500" 等错误信息中解析，无法判断时返回 0
+func StatusCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var withStatus interface{ HTTPStatus() int }
+	if errors.As(err, &withStatus) {
+		return withStatus.HTTPStatus()
+	}
+
+	message := err.Error()
+	if match := statusPattern.FindStringSubmatch(message); match != nil {
+		if code, convErr := strconv.Atoi(match[1]); convErr == nil && code >= 400 && code < 600 {
+			return code
+		}
+	}
+	// gRPC 错误（如 Gemini SDK）
+	switch {
+	case strings.Contains(message, "code = ResourceExhausted"):
+		return 429
+	case strings.Contains(message, "code = Unavailable"), strings.Contains(message, "code = Internal"):
+		return 503
+	}
+	return 0
+}
+
+// IsTransient 是否为限流（429）或服务端错误（5xx）
+func IsTransient(err error) bool {
+	code := StatusCode(err)
+	return code == 429 || code >= 500
+}
+
+// EstimateTokens 粗略估算文本的 token 数：中日韩字符每字约 1 个 token，其他字符约 4 个字符 1 个 token
+func EstimateTokens(texts ...string) int {
+	cjk, other := 0, 0
+	for _, text := range texts {
+		for _, r := range text {
+			if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+				cjk++
+			} else {
+				other++
+			}
+		}
+	}
+	return cjk + (other+3)/4
+}
+
+func maxDuration(a, b time.Duration) time.Duration {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func truncate(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit]) + "..."
+}
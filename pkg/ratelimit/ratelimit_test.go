@@ -0,0 +1,206 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+)
+
+// newTestLimiter 启用限流并返回使用指定限额的限流器
+func newTestLimiter(t *testing.T, provider string, limits Limits) *limiter {
+	t.Helper()
+	Configure(&types.RateLimitConfig{Enabled: true})
+	l := get(provider)
+	if l == nil {
+		t.Fatal("limiter should be created when enabled")
+	}
+	l.setLimits(limits)
+	return l
+}
+
+// mustBlock 在 timeout 内无法获取许可
+func mustBlock(t *testing.T, provider string, tokens int) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := Acquire(ctx, provider, tokens); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected acquire to block, got %v", err)
+	}
+}
+
+func mustAcquire(t *testing.T, provider string, tokens int) *Permit {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	permit, err := Acquire(ctx, provider, tokens)
+	if err != nil {
+		t.Fatalf("acquire failed: %v", err)
+	}
+	return permit
+}
+
+func TestDisabledDoesNotLimit(t *testing.T) {
+	Configure(nil)
+	for i := 0; i < 100; i++ {
+		permit := mustAcquire(t, "disabled", 1000000)
+		permit.Done(WithStatus(503, errors.New("unavailable")))
+	}
+	if ok, _ := Available("disabled"); !ok {
+		t.Fatal("provider should be available when rate limiting is disabled")
+	}
+}
+
+func TestRequestsPerMinute(t *testing.T) {
+	newTestLimiter(t, "rpm", Limits{RequestsPerMinute: 2})
+
+	mustAcquire(t, "rpm", 1).Done(nil)
+	mustAcquire(t, "rpm", 1).Done(nil)
+	mustBlock(t, "rpm", 1)
+}
+
+func TestTokensPerMinute(t *testing.T) {
+	newTestLimiter(t, "tpm", Limits{TokensPerMinute: 100})
+
+	mustAcquire(t, "tpm", 60).Done(nil)
+	mustAcquire(t, "tpm", 40).Done(nil)
+	mustBlock(t, "tpm", 1)
+}
+
+func TestTokensOverLimitAreCapped(t *testing.T) {
+	newTestLimiter(t, "tpm-cap", Limits{TokensPerMinute: 100})
+
+	// 单个请求超过上限时按上限计算，空窗口中可以立即发出
+	mustAcquire(t, "tpm-cap", 500).Done(nil)
+	mustBlock(t, "tpm-cap", 1)
+}
+
+func TestMaxConcurrency(t *testing.T) {
+	newTestLimiter(t, "concurrency", Limits{MaxConcurrency: 1})
+
+	first := mustAcquire(t, "concurrency", 1)
+	mustBlock(t, "concurrency", 1)
+
+	// 释放许可后等待者被唤醒
+	go func() {
+		time.Sleep(20 * time.Millisecond)
+		first.Done(nil)
+	}()
+	start := time.Now()
+	mustAcquire(t, "concurrency", 1).Done(nil)
+	if waited := time.Since(start); waited > 500*time.Millisecond {
+		t.Fatalf("waiter should be woken on release, waited %v", waited)
+	}
+}
+
+func TestCircuitOpensAfterThreshold(t *testing.T) {
+	newTestLimiter(t, "breaker", Limits{FailureThreshold: 2, Cooldown: time.Hour})
+
+	mustAcquire(t, "breaker", 1).Done(WithStatus(503, errors.New("unavailable")))
+	if ok, _ := Available("breaker"); !ok {
+		t.Fatal("circuit should stay closed below the threshold")
+	}
+	mustAcquire(t, "breaker", 1).Done(fmt.Errorf("API返回错误 (状态码: 429): too many requests"))
+
+	if ok, reason := Available("breaker"); ok || reason == "" {
+		t.Fatalf("circuit should be open, got ok=%v reason=%q", ok, reason)
+	}
+	if _, err := Acquire(context.Background(), "breaker", 1); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+}
+
+func TestNonTransientErrorsDoNotOpenCircuit(t *testing.T) {
+	newTestLimiter(t, "breaker-4xx", Limits{FailureThreshold: 1, Cooldown: time.Hour})
+
+	mustAcquire(t, "breaker-4xx", 1).Done(WithStatus(401, errors.New("unauthorized")))
+	mustAcquire(t, "breaker-4xx", 1).Done(errors.New("invalid request"))
+	if ok, _ := Available("breaker-4xx"); !ok {
+		t.Fatal("non-transient errors should not open the circuit")
+	}
+}
+
+func TestCircuitHalfOpenProbeCloses(t *testing.T) {
+	l := newTestLimiter(t, "probe-ok", Limits{FailureThreshold: 1, Cooldown: 30 * time.Millisecond})
+
+	mustAcquire(t, "probe-ok", 1).Done(WithStatus(500, errors.New("internal error")))
+	if _, err := Acquire(context.Background(), "probe-ok", 1); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+
+	// 冷却结束后放行一个探测请求，探测结果返回前拒绝其他请求
+	time.Sleep(40 * time.Millisecond)
+	probe := mustAcquire(t, "probe-ok", 1)
+	if state := limiterState(l); state != StateHalfOpen {
+		t.Fatalf("expected half-open, got %s", state)
+	}
+	if _, err := Acquire(context.Background(), "probe-ok", 1); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen while probing, got %v", err)
+	}
+
+	probe.Done(nil)
+	if state := limiterState(l); state != StateClosed {
+		t.Fatalf("expected closed after successful probe, got %s", state)
+	}
+	if l.failures != 0 {
+		t.Fatalf("failures should be reset, got %d", l.failures)
+	}
+	mustAcquire(t, "probe-ok", 1).Done(nil)
+}
+
+func TestCircuitHalfOpenProbeFailureReopens(t *testing.T) {
+	l := newTestLimiter(t, "probe-fail", Limits{FailureThreshold: 3, Cooldown: 30 * time.Millisecond})
+
+	for i := 0; i < 3; i++ {
+		mustAcquire(t, "probe-fail", 1).Done(WithStatus(502, errors.New("bad gateway")))
+	}
+	time.Sleep(40 * time.Millisecond)
+
+	// 探测失败时不等待阈值，立即重新熔断
+	mustAcquire(t, "probe-fail", 1).Done(WithStatus(503, errors.New("unavailable")))
+	if state := limiterState(l); state != StateOpen {
+		t.Fatalf("expected open after failed probe, got %s", state)
+	}
+	if _, err := Acquire(context.Background(), "probe-fail", 1); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+}
+
+func TestStatusCode(t *testing.T) {
+	cases := []struct {
+		err  error
+		code int
+	}{
+		{nil, 0},
+		{WithStatus(429, errors.New("x")), 429},
+		{fmt.Errorf("wrapped: %w", WithStatus(503, errors.New("x"))), 503},
+		{errors.New("openai API returned status 500: boom"), 500},
+		{errors.New("API返回错误 (状态码: 429): x"), 429},
+		{errors.New("googleapi: Error 503: unavailable"), 503},
+		{errors.New("rpc error: code = ResourceExhausted desc = quota"), 429},
+		{errors.New("invalid api key"), 0},
+	}
+	for _, c := range cases {
+		if got := StatusCode(c.err); got != c.code {
+			t.Errorf("StatusCode(%v) = %d, want %d", c.err, got, c.code)
+		}
+	}
+}
+
+func TestEstimateTokens(t *testing.T) {
+	if got := EstimateTokens("你好世界"); got != 4 {
+		t.Errorf("EstimateTokens(cjk) = %d, want 4", got)
+	}
+	if got := EstimateTokens("hello world!"); got != 3 {
+		t.Errorf("EstimateTokens(latin) = %d, want 3", got)
+	}
+}
+
+func limiterState(l *limiter) string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.state
+}
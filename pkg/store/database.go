@@ -21,6 +21,8 @@ func NewDatabase(config *types.AppConfig) (*gorm.DB, error) {
 			TablePrefix:   "tb_", // crypto_wallet prefix
 			SingularTable: false,
 		},
+		// 将驱动错误转换为 gorm.ErrDuplicatedKey 等通用错误，便于识别唯一索引冲突
+		TranslateError: true,
 	}
 
 	// 设置日志级别
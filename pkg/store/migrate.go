@@ -7,10 +7,24 @@ import (
 
 // MigrateDatabase 自动迁移数据库表
 func MigrateDatabase(db *gorm.DB) error {
+	// 翻译记忆的唯一键加入了提示词版本，删除旧的唯一索引，新索引由 AutoMigrate 创建
+	if migrator := db.Migrator(); migrator.HasIndex(&model.TranslationMemory{}, "idx_translation_memory") {
+		if err := migrator.DropIndex(&model.TranslationMemory{}, "idx_translation_memory"); err != nil {
+			return err
+		}
+	}
+
 	return db.AutoMigrate(
 		&model.User{},
 		&model.SavedVideo{},
 		&model.TaskStep{},
 		&model.AccountBinding{},
+		&model.SubtitleRevision{},
+		&model.TranslationMemory{},
+		&model.GlossaryTerm{},
+		&model.LLMUsage{},
+		&model.PromptTemplate{},
+		&model.ReviewPolicy{},
+		&model.ReviewRecord{},
 	)
 }
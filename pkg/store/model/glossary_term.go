@@ -0,0 +1,37 @@
+package model
+
+// 术语表作用域
+const (
+	GlossaryScopeGlobal   = "global"   // 全局
+	GlossaryScopeChannel  = "channel"  // 频道（ScopeID 为频道ID）
+	GlossaryScopePlaylist = "playlist" // 播放列表（ScopeID 为播放列表ID）
+	GlossaryScopeVideo    = "video"    // 单个视频（ScopeID 为视频ID）
+)
+
+// 术语状态
+const (
+	GlossaryStatusActive    = "active"    // 生效
+	GlossaryStatusSuggested = "suggested" // 大模型建议，待审核
+	GlossaryStatusRejected  = "rejected"  // 审核未通过
+)
+
+// GlossaryTerm 术语表条目
+type GlossaryTerm struct {
+	BaseModel
+	Scope          string `gorm:"type:varchar(20);not null;index:idx_glossary_scope" json:"scope"`     // 作用域: global, channel, playlist, video
+	ScopeID        string `gorm:"type:varchar(100);not null;index:idx_glossary_scope" json:"scope_id"` // 作用域ID（全局为空）
+	TargetLang     string `gorm:"type:varchar(20);not null;default:'zh'" json:"target_lang"`           // 目标语言
+	SourceTerm     string `gorm:"type:varchar(200);not null" json:"source_term"`                       // 原文术语
+	TargetTerm     string `gorm:"type:varchar(200)" json:"target_term"`                                // 固定译法
+	Forms          string `gorm:"type:varchar(1000)" json:"forms"`                                     // 原文的其他形式（逗号分隔）
+	CaseSensitive  bool   `gorm:"default:false" json:"case_sensitive"`                                 // 是否区分大小写
+	DoNotTranslate bool   `gorm:"default:false" json:"do_not_translate"`                               // 保留原文不翻译
+	Status         string `gorm:"type:varchar(20);not null;default:'active';index" json:"status"`      // 状态: active, suggested, rejected
+	Note           string `gorm:"type:varchar(500)" json:"note"`                                       // 备注（建议理由等）
+	CreatedBy      string `gorm:"type:varchar(100)" json:"created_by"`                                 // 创建人（大模型建议时为提供商名称）
+}
+
+// TableName 指定表名
+func (GlossaryTerm) TableName() string {
+	return "tb_glossary_terms"
+}
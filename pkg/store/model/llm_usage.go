@@ -0,0 +1,23 @@
+package model
+
+// LLMUsage 一次 LLM 或翻译调用的用量和估算费用
+type LLMUsage struct {
+	BaseModel
+	VideoID      string  `gorm:"type:varchar(100);index" json:"video_id"`         // 所属视频（非任务调用为空）
+	Step         string  `gorm:"type:varchar(50)" json:"step"`                    // 任务步骤
+	Provider     string  `gorm:"type:varchar(50);not null;index" json:"provider"` // 提供商
+	Model        string  `gorm:"type:varchar(100)" json:"model"`                  // 模型
+	Kind         string  `gorm:"type:varchar(20);not null" json:"kind"`           // 调用类型: translation, chat
+	InputTokens  int     `gorm:"default:0" json:"input_tokens"`                   // 输入 token 数
+	OutputTokens int     `gorm:"default:0" json:"output_tokens"`                  // 输出 token 数
+	Characters   int     `gorm:"default:0" json:"characters"`                     // 原文字符数（按字符计费的机器翻译）
+	LatencyMs    int64   `gorm:"default:0" json:"latency_ms"`                     // 耗时（毫秒）
+	Cost         float64 `gorm:"type:decimal(12,6);default:0" json:"cost"`        // 估算费用
+	Success      bool    `gorm:"default:true" json:"success"`                     // 是否成功
+	Error        string  `gorm:"type:varchar(500)" json:"error,omitempty"`        // 失败原因
+}
+
+// TableName 指定表名
+func (LLMUsage) TableName() string {
+	return "tb_llm_usages"
+}
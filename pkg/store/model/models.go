@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 
 	"gorm.io/gorm"
@@ -26,13 +27,25 @@ type AudioResult struct {
 
 // TranslationSettings 翻译设置
 type TranslationSettings struct {
-	SourceLanguage string  `json:"source_language"`
-	TargetLanguage string  `json:"target_language"`
-	Service        string  `json:"service"`
-	Gender         string  `json:"gender"`
-	Tier           string  `json:"tier"`
-	VoiceName      string  `json:"voice_name"`
-	VoiceSpeed     float64 `json:"voice_speed"`
+	SourceLanguage  string   `json:"source_language"`
+	TargetLanguage  string   `json:"target_language"`
+	TargetLanguages []string `json:"target_languages"` // 多个目标语言（优先于 TargetLanguage，第一个为主语言）
+	Service         string   `json:"service"`
+	Gender          string   `json:"gender"`
+	Tier            string   `json:"tier"`
+	VoiceName       string   `json:"voice_name"`
+	VoiceSpeed      float64  `json:"voice_speed"`
+}
+
+// Languages 返回目标语言列表：优先 TargetLanguages，否则为 TargetLanguage
+func (s TranslationSettings) Languages() []string {
+	if len(s.TargetLanguages) > 0 {
+		return s.TargetLanguages
+	}
+	if s.TargetLanguage != "" {
+		return []string{s.TargetLanguage}
+	}
+	return nil
 }
 
 // VideoProcessingRequest 视频处理请求（根据用户提供的JSON格式）
@@ -88,6 +101,17 @@ type SavedVideo struct {
 	OperationType    string `gorm:"type:varchar(50)" json:"operation_type"`                    // 操作类型 (download/upload等)
 	Subtitles        string `gorm:"type:longtext" json:"subtitles"`                           // 字幕JSON字符串
 	PlaylistID       string `gorm:"type:varchar(100);index" json:"playlist_id"`                // 播放列表ID
+	ChannelID        string `gorm:"type:varchar(100);index" json:"channel_id"`                 // 来源频道ID（下载时从 yt-dlp 元数据获取）
+	ChannelName      string `gorm:"type:varchar(200)" json:"channel_name"`                     // 来源频道名称（下载时从 yt-dlp 元数据获取）
+	TargetLanguages  string `gorm:"type:varchar(200)" json:"target_languages"`                 // 字幕翻译目标语言（逗号分隔，第一个为主语言；为空时使用全局配置）
+	TranslationSettings string `gorm:"type:text" json:"translation_settings"`                 // 提交时的翻译设置（JSON），其中的配音音色覆盖全局配置
+	SourceCategory   string `gorm:"type:varchar(200)" json:"source_category"`                  // 来源视频分类（YouTube 分类，逗号分隔，下载时从 yt-dlp 元数据获取）
+	SourceTags       string `gorm:"type:varchar(1000)" json:"source_tags"`                     // 来源视频标签（逗号分隔，下载时从 yt-dlp 元数据获取）
+	Tid              int    `gorm:"type:int;default:0" json:"tid"`                             // 投稿分区ID（选择分区步骤确定，0 表示使用全局配置）
+	TidReason        string `gorm:"type:varchar(500)" json:"tid_reason"`                       // 选择该分区的依据（命中的规则或模型给出的理由）
+	ReviewReason     string `gorm:"type:varchar(500)" json:"review_reason"`                    // 进入待人工审核（150）的原因
+	ReviewedTitle    string `gorm:"type:varchar(500)" json:"reviewed_title"`                   // 审核时修改的投稿标题（非空时上传优先使用）
+	ReviewedDesc     string `gorm:"type:text" json:"reviewed_desc"`                            // 审核时修改的投稿简介（非空时上传优先使用）
 	Timestamp        string `gorm:"type:varchar(50)" json:"timestamp"`                         // 时间戳
 	SavedAt          string `gorm:"type:varchar(50)" json:"saved_at"`                          // 保存时间
 }
@@ -96,3 +120,12 @@ type SavedVideo struct {
 func (SavedVideo) TableName() string {
 	return "tb_saved_videos"
 }
+
+// Settings 解析提交时的翻译设置，未设置或格式错误时返回零值
+func (v *SavedVideo) Settings() TranslationSettings {
+	var settings TranslationSettings
+	if v.TranslationSettings != "" {
+		json.Unmarshal([]byte(v.TranslationSettings), &settings)
+	}
+	return settings
+}
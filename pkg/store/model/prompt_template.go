@@ -0,0 +1,18 @@
+package model
+
+// PromptTemplate 提示词模板的一个版本。同名模板每次修改都新增一个版本，
+// 同一时间最多一个版本处于启用状态；没有启用的版本时使用内置模板
+type PromptTemplate struct {
+	BaseModel
+	Name    string `gorm:"type:varchar(50);not null;uniqueIndex:idx_prompt_name_version" json:"name"` // 提示词名称，如 translation、metadata
+	Version int    `gorm:"not null;uniqueIndex:idx_prompt_name_version" json:"version"`               // 版本号（同名模板内递增，从 1 开始）
+	System  string `gorm:"type:longtext" json:"system"`                                               // 系统提示词模板（text/template）
+	User    string `gorm:"type:longtext" json:"user"`                                                 // 用户提示词模板（text/template）
+	Note    string `gorm:"type:varchar(500)" json:"note"`                                             // 修改说明
+	Active  bool   `gorm:"default:false;index" json:"active"`                                         // 是否为当前启用的版本
+}
+
+// TableName 指定表名
+func (PromptTemplate) TableName() string {
+	return "tb_prompt_templates"
+}
@@ -0,0 +1,45 @@
+package model
+
+// 审核设置的作用域：一个频道或播放列表对应一个订阅来源
+const (
+	ReviewScopeChannel  = "channel"  // 频道（ScopeID 为频道ID）
+	ReviewScopePlaylist = "playlist" // 播放列表（ScopeID 为播放列表ID）
+)
+
+// 审核操作
+const (
+	ReviewActionApprove  = "approve"   // 审核通过，进入上传队列
+	ReviewActionReject   = "reject"    // 驳回，不上传
+	ReviewActionSendBack = "send_back" // 退回到指定步骤重新执行
+	ReviewActionEdit     = "edit"      // 修改标题、简介、标签、分区或封面
+)
+
+// ReviewPolicy 订阅来源的审核设置，覆盖全局配置 ReviewConfig.Required（播放列表优先于频道）
+type ReviewPolicy struct {
+	BaseModel
+	Scope    string `gorm:"type:varchar(20);not null;uniqueIndex:idx_review_policy" json:"scope"`     // 作用域: channel, playlist
+	ScopeID  string `gorm:"type:varchar(100);not null;uniqueIndex:idx_review_policy" json:"scope_id"` // 频道ID或播放列表ID
+	Required bool   `gorm:"default:false" json:"required"`                                            // 是否需要人工审核后才能上传
+	Note     string `gorm:"type:varchar(500)" json:"note"`                                            // 备注
+}
+
+// TableName 指定表名
+func (ReviewPolicy) TableName() string {
+	return "tb_review_policies"
+}
+
+// ReviewRecord 审核记录：审核通过、驳回、退回和修改都会记录一条
+type ReviewRecord struct {
+	BaseModel
+	VideoID  string `gorm:"type:varchar(100);not null;index" json:"video_id"` // 关联的视频ID
+	Action   string `gorm:"type:varchar(20);not null" json:"action"`          // 操作: approve, reject, send_back, edit
+	Step     string `gorm:"type:varchar(200)" json:"step,omitempty"`          // 退回重新执行的步骤（多个用逗号分隔）
+	Comment  string `gorm:"type:varchar(1000)" json:"comment"`                // 审核意见
+	Reviewer string `gorm:"type:varchar(100)" json:"reviewer"`                // 审核人
+	Changes  string `gorm:"type:text" json:"changes,omitempty"`               // 修改的字段（JSON）
+}
+
+// TableName 指定表名
+func (ReviewRecord) TableName() string {
+	return "tb_review_records"
+}
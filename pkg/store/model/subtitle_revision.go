@@ -0,0 +1,21 @@
+package model
+
+// SubtitleRevision 字幕修订记录，每次人工编辑或恢复保存一个新版本
+type SubtitleRevision struct {
+	BaseModel
+	VideoID      string `gorm:"type:varchar(100);not null;uniqueIndex:idx_subtitle_revision" json:"video_id"` // 关联的视频ID
+	Track        string `gorm:"type:varchar(20);not null;uniqueIndex:idx_subtitle_revision" json:"track"`     // 字幕轨道: zh, original
+	Version      int    `gorm:"type:int;not null;uniqueIndex:idx_subtitle_revision" json:"version"`           // 版本号（从1开始递增，用于乐观锁）
+	Author       string `gorm:"type:varchar(100)" json:"author"`                                              // 修改人
+	Comment      string `gorm:"type:varchar(500)" json:"comment"`                                             // 修改说明
+	CueCount     int    `gorm:"type:int" json:"cue_count"`                                                    // 字幕条数
+	ChangedCues  int    `gorm:"type:int" json:"changed_cues"`                                                 // 相对上一版本变化的条数
+	RestoredFrom int    `gorm:"type:int;default:0" json:"restored_from"`                                      // 从哪个版本恢复（0 表示非恢复）
+	Content      string `gorm:"type:longtext" json:"content,omitempty"`                                       // 字幕内容（SRT）
+	Diff         string `gorm:"type:longtext" json:"diff,omitempty"`                                          // 相对上一版本的差异（JSON）
+}
+
+// TableName 指定表名
+func (SubtitleRevision) TableName() string {
+	return "tb_subtitle_revisions"
+}
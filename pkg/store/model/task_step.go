@@ -10,7 +10,7 @@ type TaskStep struct {
 	VideoID     string    `gorm:"type:varchar(100);not null;index" json:"video_id"`       // 关联的视频ID
 	StepName    string    `gorm:"type:varchar(100);not null" json:"step_name"`            // 步骤名称
 	StepOrder   int       `gorm:"type:int;not null" json:"step_order"`                    // 步骤顺序
-	Status      string    `gorm:"type:varchar(20);not null" json:"status"`                // 步骤状态: pending, running, completed, failed, skipped
+	Status      string    `gorm:"type:varchar(20);not null" json:"status"`                // 步骤状态: pending, running, completed, failed, skipped, budget_paused
 	StartTime   *time.Time `gorm:"type:datetime" json:"start_time"`                       // 开始时间
 	EndTime     *time.Time `gorm:"type:datetime" json:"end_time"`                         // 结束时间
 	Duration    int64     `gorm:"type:bigint" json:"duration"`                            // 执行时长（毫秒）
@@ -26,9 +26,10 @@ func (TaskStep) TableName() string {
 
 // TaskStepStatus 任务步骤状态常量
 const (
-	TaskStepStatusPending   = "pending"   // 待执行
-	TaskStepStatusRunning   = "running"   // 执行中
-	TaskStepStatusCompleted = "completed" // 已完成
-	TaskStepStatusFailed    = "failed"    // 失败
-	TaskStepStatusSkipped   = "skipped"   // 跳过
+	TaskStepStatusPending      = "pending"       // 待执行
+	TaskStepStatusRunning      = "running"       // 执行中
+	TaskStepStatusCompleted    = "completed"     // 已完成
+	TaskStepStatusFailed       = "failed"        // 失败
+	TaskStepStatusSkipped      = "skipped"       // 跳过
+	TaskStepStatusBudgetPaused = "budget_paused" // AI 费用超出预算暂停，预算恢复后继续执行
 )
\ No newline at end of file
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// TranslationMemory 翻译记忆条目，按（规范化原文哈希, 源语言, 目标语言, 提供商, 模型, 术语表版本, 提示词版本）唯一
+type TranslationMemory struct {
+	BaseModel
+	SourceHash      string     `gorm:"type:varchar(64);not null;uniqueIndex:idx_translation_memory_key" json:"source_hash"`               // 规范化原文的 SHA-256
+	SourceLang      string     `gorm:"type:varchar(20);not null;uniqueIndex:idx_translation_memory_key" json:"source_lang"`               // 源语言（auto 表示自动检测）
+	TargetLang      string     `gorm:"type:varchar(20);not null;uniqueIndex:idx_translation_memory_key" json:"target_lang"`               // 目标语言
+	Provider        string     `gorm:"type:varchar(50);not null;uniqueIndex:idx_translation_memory_key" json:"provider"`                  // 翻译提供商（human 表示人工校对/TMX 导入）
+	Model           string     `gorm:"type:varchar(100);not null;uniqueIndex:idx_translation_memory_key" json:"model"`                    // 模型
+	GlossaryVersion string     `gorm:"type:varchar(50);not null;uniqueIndex:idx_translation_memory_key" json:"glossary_version"`          // 术语表版本
+	PromptVersion   string     `gorm:"type:varchar(50);not null;default:'';uniqueIndex:idx_translation_memory_key" json:"prompt_version"` // 翻译提示词版本（大模型翻译器，如 translation@v2）
+	SourceText      string     `gorm:"type:text" json:"source_text"`                                                                      // 规范化后的原文
+	TranslatedText  string     `gorm:"type:text" json:"translated_text"`                                                                  // 译文
+	HitCount        int64      `gorm:"type:bigint;default:0" json:"hit_count"`                                                            // 命中次数
+	LastHitAt       *time.Time `json:"last_hit_at,omitempty"`                                                                             // 最近命中时间
+}
+
+// TableName 指定表名
+func (TranslationMemory) TableName() string {
+	return "tb_translation_memories"
+}
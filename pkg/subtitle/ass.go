@@ -0,0 +1,218 @@
+package subtitle
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ASSStyle ASS 样式定义（颜色为 &HAABBGGRR 格式）
+type ASSStyle struct {
+	Name          string
+	FontName      string
+	FontSize      int
+	PrimaryColour string
+	OutlineColour string
+	BackColour    string
+	Bold          bool
+	BorderStyle   int // 1=描边+阴影, 3=不透明背景框
+	Outline       float64
+	Shadow        float64
+	Alignment     int // 小键盘布局: 2=底部居中, 8=顶部居中
+	MarginV       int
+}
+
+// DefaultASSStyle 默认样式（底部居中白字黑边）
+func DefaultASSStyle() ASSStyle {
+	return ASSStyle{
+		Name:          "Default",
+		FontName:      "Microsoft YaHei",
+		FontSize:      60,
+		PrimaryColour: "&H00FFFFFF",
+		OutlineColour: "&H00000000",
+		BackColour:    "&H80000000",
+		BorderStyle:   1,
+		Outline:       3,
+		Shadow:        1,
+		Alignment:     2,
+		MarginV:       50,
+	}
+}
+
+// ParseASS 解析 ASS/SSA 字幕（只读取 Dialogue 事件）
+func ParseASS(content string) (*Document, error) {
+	doc := NewDocument("")
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	section := ""
+	var fields []string
+	for _, raw := range lines {
+		line := strings.TrimSpace(raw)
+		if line == "" || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.ToLower(line)
+			continue
+		}
+		if section == "[script info]" && strings.HasPrefix(line, "Title:") {
+			doc.Title = strings.TrimSpace(strings.TrimPrefix(line, "Title:"))
+			continue
+		}
+		if section != "[events]" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "Format:") {
+			fields = splitASSFields(strings.TrimPrefix(line, "Format:"), 0)
+			continue
+		}
+		if !strings.HasPrefix(line, "Dialogue:") {
+			continue
+		}
+		if len(fields) == 0 {
+			fields = []string{"layer", "start", "end", "style", "name", "marginl", "marginr", "marginv", "effect", "text"}
+		}
+
+		values := splitASSFields(strings.TrimPrefix(line, "Dialogue:"), len(fields))
+		if len(values) != len(fields) {
+			return nil, fmt.Errorf("ASS 事件字段数量不匹配: %s", line)
+		}
+
+		cue := Cue{Index: len(doc.Cues) + 1}
+		for i, field := range fields {
+			value := values[i]
+			switch field {
+			case "start":
+				start, err := ParseTimecode(value)
+				if err != nil {
+					return nil, err
+				}
+				cue.Start = start
+			case "end":
+				end, err := ParseTimecode(value)
+				if err != nil {
+					return nil, err
+				}
+				cue.End = end
+			case "style":
+				cue.Style = value
+			case "name", "actor":
+				cue.Speaker = value
+			case "text":
+				cue.Text = unescapeASSText(value)
+			}
+		}
+		if strings.TrimSpace(cue.Text) != "" {
+			doc.Cues = append(doc.Cues, cue)
+		}
+	}
+
+	doc.Sort()
+	doc.Renumber()
+	return doc, nil
+}
+
+// FormatASSDocument 输出 ASS 字幕，cue.Style 为空时使用第一个样式
+func FormatASSDocument(doc *Document, styles ...ASSStyle) string {
+	if len(styles) == 0 {
+		styles = []ASSStyle{DefaultASSStyle()}
+	}
+
+	var builder strings.Builder
+	builder.WriteString("[Script Info]\n")
+	if doc.Title != "" {
+		builder.WriteString("Title: " + doc.Title + "\n")
+	}
+	builder.WriteString("ScriptType: v4.00+\n")
+	builder.WriteString("WrapStyle: 0\n")
+	builder.WriteString("ScaledBorderAndShadow: yes\n")
+	builder.WriteString("PlayResX: 1920\n")
+	builder.WriteString("PlayResY: 1080\n\n")
+
+	builder.WriteString("[V4+ Styles]\n")
+	builder.WriteString("Format: Name, Fontname, Fontsize, PrimaryColour, SecondaryColour, OutlineColour, BackColour, Bold, Italic, Underline, StrikeOut, ScaleX, ScaleY, Spacing, Angle, BorderStyle, Outline, Shadow, Alignment, MarginL, MarginR, MarginV, Encoding\n")
+	for _, style := range styles {
+		bold := 0
+		if style.Bold {
+			bold = -1
+		}
+		builder.WriteString(fmt.Sprintf("Style: %s,%s,%d,%s,&H000000FF,%s,%s,%d,0,0,0,100,100,0,0,%d,%g,%g,%d,20,20,%d,1\n",
+			style.Name, style.FontName, style.FontSize, style.PrimaryColour, style.OutlineColour, style.BackColour,
+			bold, style.BorderStyle, style.Outline, style.Shadow, style.Alignment, style.MarginV))
+	}
+	builder.WriteString("\n")
+
+	builder.WriteString("[Events]\n")
+	builder.WriteString("Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text\n")
+	for _, cue := range doc.Cues {
+		style := cue.Style
+		if style == "" {
+			style = styles[0].Name
+		}
+		builder.WriteString(fmt.Sprintf("Dialogue: 0,%s,%s,%s,%s,0,0,0,,%s\n",
+			FormatASSTime(cue.Start), FormatASSTime(cue.End), style, cue.Speaker, escapeASSText(cue.Text)))
+	}
+	return builder.String()
+}
+
+// splitASSFields 按逗号切分字段，n>0 时最后一个字段保留剩余内容（Text 中可包含逗号）
+func splitASSFields(value string, n int) []string {
+	var parts []string
+	if n > 0 {
+		parts = strings.SplitN(value, ",", n)
+	} else {
+		parts = strings.Split(value, ",")
+	}
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+		if n <= 0 {
+			parts[i] = strings.ToLower(parts[i])
+		}
+	}
+	return parts
+}
+
+// assLiteralBackslash 写出时在字面反斜杠后插入的零宽连接符，避免与后续字符组成 \N、\h 等控制序列
+const assLiteralBackslash = "\\\u2060"
+
+// unescapeASSText 去掉覆盖标签 {...}，还原 \N、\n、\h 和转义的 \{、\}
+func unescapeASSText(text string) string {
+	var builder strings.Builder
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '{':
+			// 覆盖标签，跳到对应的 }
+			for i < len(runes) && runes[i] != '}' {
+				i++
+			}
+		case r == '\\' && i+1 < len(runes):
+			i++
+			switch runes[i] {
+			case 'N', 'n':
+				builder.WriteRune('\n')
+			case 'h':
+				builder.WriteRune(' ')
+			case '{', '}':
+				builder.WriteRune(runes[i])
+			case '\u2060':
+				builder.WriteRune('\\')
+			default:
+				builder.WriteRune('\\')
+				builder.WriteRune(runes[i])
+			}
+		default:
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+// escapeASSText 转义文本：换行写为 \N，花括号写为 \{、\}，字面反斜杠后插入零宽连接符
+func escapeASSText(text string) string {
+	text = strings.ReplaceAll(text, "\\", assLiteralBackslash)
+	text = strings.ReplaceAll(text, "{", "\\{")
+	text = strings.ReplaceAll(text, "}", "\\}")
+	return strings.ReplaceAll(text, "\n", "\\N")
+}
@@ -0,0 +1,63 @@
+package subtitle
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// bccDocument Bilibili BCC 字幕格式（时间单位：秒）
+type bccDocument struct {
+	FontSize        float64  `json:"font_size"`
+	FontColor       string   `json:"font_color"`
+	BackgroundAlpha float64  `json:"background_alpha"`
+	BackgroundColor string   `json:"background_color"`
+	Stroke          string   `json:"Stroke"`
+	Body            []bccCue `json:"body"`
+}
+
+type bccCue struct {
+	From     float64 `json:"from"`
+	To       float64 `json:"to"`
+	Location int     `json:"location"`
+	Content  string  `json:"content"`
+}
+
+// ParseBCC 解析 Bilibili BCC 字幕
+func ParseBCC(data []byte) (*Document, error) {
+	var bcc bccDocument
+	if err := json.Unmarshal(data, &bcc); err != nil {
+		return nil, fmt.Errorf("解析 BCC 字幕失败: %w", err)
+	}
+
+	doc := NewDocument("")
+	for _, item := range bcc.Body {
+		doc.Add(SecondsToMS(item.From), SecondsToMS(item.To), item.Content)
+	}
+	return doc, nil
+}
+
+// MarshalBCC 输出 Bilibili BCC 字幕
+func MarshalBCC(doc *Document) ([]byte, error) {
+	bcc := bccDocument{
+		FontSize:        0.4,
+		FontColor:       "#FFFFFF",
+		BackgroundAlpha: 0.5,
+		BackgroundColor: "#9C27B0",
+		Stroke:          "none",
+		Body:            make([]bccCue, 0, len(doc.Cues)),
+	}
+	for _, cue := range doc.Cues {
+		bcc.Body = append(bcc.Body, bccCue{
+			From:     roundSeconds(cue.Start),
+			To:       roundSeconds(cue.End),
+			Location: 2,
+			Content:  cue.Text,
+		})
+	}
+	return json.MarshalIndent(bcc, "", "  ")
+}
+
+func roundSeconds(ms int64) float64 {
+	return math.Round(MSToSeconds(ms)*1000) / 1000
+}
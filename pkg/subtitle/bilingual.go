@@ -0,0 +1,170 @@
+package subtitle
+
+import (
+	"strings"
+)
+
+// ASSPreset 双语 ASS 样式预设：Primary 为上方的译文，Secondary 为下方的原文
+type ASSPreset struct {
+	Name      string
+	Primary   ASSStyle
+	Secondary ASSStyle
+}
+
+// 双语 ASS 中使用的样式名
+const (
+	ASSPrimaryStyleName   = "Primary"
+	ASSSecondaryStyleName = "Secondary"
+)
+
+// DefaultASSPresetName 默认双语样式预设
+const DefaultASSPresetName = "default"
+
+// BuiltinASSPresets 内置双语样式预设
+func BuiltinASSPresets() map[string]ASSPreset {
+	primary := DefaultASSStyle()
+	primary.Name = ASSPrimaryStyleName
+	primary.MarginV = 40
+
+	secondary := primary
+	secondary.Name = ASSSecondaryStyleName
+	secondary.FontSize = 40
+	secondary.PrimaryColour = "&H00E0E0E0"
+	secondary.Outline = 2
+
+	presets := map[string]ASSPreset{
+		DefaultASSPresetName: {Primary: primary, Secondary: secondary},
+	}
+
+	// bilibili: 粗体白字，原文金黄色
+	bPrimary, bSecondary := primary, secondary
+	bPrimary.FontSize = 64
+	bPrimary.Bold = true
+	bSecondary.PrimaryColour = "&H0000D7FF"
+	presets["bilibili"] = ASSPreset{Primary: bPrimary, Secondary: bSecondary}
+
+	// boxed: 半透明背景框，适合画面较亮的视频
+	xPrimary, xSecondary := primary, secondary
+	xPrimary.BorderStyle, xSecondary.BorderStyle = 3, 3
+	xPrimary.Outline, xSecondary.Outline = 1, 1
+	xPrimary.Shadow, xSecondary.Shadow = 0, 0
+	xPrimary.OutlineColour, xSecondary.OutlineColour = "&H80000000", "&H80000000"
+	presets["boxed"] = ASSPreset{Primary: xPrimary, Secondary: xSecondary}
+
+	// top: 显示在画面顶部，避免遮挡底部的硬字幕
+	tPrimary, tSecondary := primary, secondary
+	tPrimary.Alignment, tSecondary.Alignment = 8, 8
+	presets["top"] = ASSPreset{Primary: tPrimary, Secondary: tSecondary}
+
+	for name, preset := range presets {
+		preset.Name = name
+		presets[name] = preset
+	}
+	return presets
+}
+
+// BilingualCue 对齐后的双语字幕条目
+type BilingualCue struct {
+	Cue
+	Secondary string // 对应时间段内的原文（单行）
+}
+
+// AlignBilingual 按时间将 secondary 的条目对齐到 primary 的条目上：
+// 每条 secondary 字幕归入与其重叠时间最长的 primary 字幕（两个文档都需按时间排序）
+func AlignBilingual(primary, secondary *Document) []BilingualCue {
+	result := make([]BilingualCue, len(primary.Cues))
+	for i, cue := range primary.Cues {
+		result[i] = BilingualCue{Cue: cue}
+	}
+	if len(primary.Cues) == 0 {
+		return result
+	}
+
+	parts := make([][]string, len(primary.Cues))
+	j := 0
+	for _, sec := range secondary.Cues {
+		text := strings.Join(strings.Fields(sec.Text), " ")
+		if text == "" {
+			continue
+		}
+
+		// primary 已按时间排序，跳过已经结束的条目
+		for j < len(primary.Cues)-1 && primary.Cues[j].End <= sec.Start {
+			j++
+		}
+		best, bestOverlap := -1, int64(0)
+		for k := j; k < len(primary.Cues) && primary.Cues[k].Start < sec.End; k++ {
+			if overlap := overlapMS(primary.Cues[k], sec); overlap > bestOverlap {
+				best, bestOverlap = k, overlap
+			}
+		}
+		if best < 0 {
+			// 没有重叠时归入开始时间最接近的条目
+			best = nearestCue(primary.Cues, sec.Start)
+		}
+		parts[best] = append(parts[best], text)
+	}
+
+	for i := range result {
+		result[i].Secondary = strings.Join(parts[i], " ")
+	}
+	return result
+}
+
+// MergeBilingual 生成双语字幕文档，每条字幕为译文在上、原文在下
+func MergeBilingual(primary, secondary *Document) *Document {
+	doc := NewDocument(primary.Language)
+	doc.Title = primary.Title
+	for _, cue := range AlignBilingual(primary, secondary) {
+		if cue.Secondary != "" {
+			cue.Text = strings.TrimSpace(cue.Text) + "\n" + cue.Secondary
+		}
+		doc.Cues = append(doc.Cues, cue.Cue)
+	}
+	doc.Renumber()
+	return doc
+}
+
+// FormatBilingualASS 输出双语 ASS 字幕：译文使用 Primary 样式，原文通过行内 \r 切换为 Secondary 样式，
+// 两种语言在同一事件中，保证上下顺序和间距稳定
+func FormatBilingualASS(primary, secondary *Document, preset ASSPreset) string {
+	preset.Primary.Name = ASSPrimaryStyleName
+	preset.Secondary.Name = ASSSecondaryStyleName
+
+	doc := NewDocument(primary.Language)
+	doc.Title = primary.Title
+	for _, cue := range AlignBilingual(primary, secondary) {
+		cue.Style = ASSPrimaryStyleName
+		if cue.Secondary != "" {
+			cue.Text = strings.TrimSpace(cue.Text) + "\n{\\r" + ASSSecondaryStyleName + "}" + cue.Secondary
+		}
+		doc.Cues = append(doc.Cues, cue.Cue)
+	}
+	doc.Renumber()
+	return FormatASSDocument(doc, preset.Primary, preset.Secondary)
+}
+
+func overlapMS(a, b Cue) int64 {
+	start, end := a.Start, a.End
+	if b.Start > start {
+		start = b.Start
+	}
+	if b.End < end {
+		end = b.End
+	}
+	return end - start
+}
+
+func nearestCue(cues []Cue, at int64) int {
+	best, bestDist := 0, int64(-1)
+	for i, cue := range cues {
+		dist := cue.Start - at
+		if dist < 0 {
+			dist = -dist
+		}
+		if bestDist < 0 || dist < bestDist {
+			best, bestDist = i, dist
+		}
+	}
+	return best
+}
@@ -0,0 +1,147 @@
+package subtitle
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Format 字幕格式
+type Format string
+
+const (
+	FormatSRT       Format = "srt"   // SubRip
+	FormatVTT       Format = "vtt"   // WebVTT
+	FormatASS       Format = "ass"   // Advanced SubStation Alpha
+	FormatBCC       Format = "bcc"   // Bilibili BCC JSON
+	FormatJSON3     Format = "json3" // YouTube json3
+	FormatTimedText Format = "xml"   // YouTube timedtext XML (srv1/srv3)
+)
+
+// Parse 按指定格式解析字幕
+func Parse(data []byte, format Format) (*Document, error) {
+	// 去除 UTF-8 BOM
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	switch format {
+	case FormatSRT:
+		return ParseSRT(string(data))
+	case FormatVTT:
+		return ParseVTT(string(data))
+	case FormatASS:
+		return ParseASS(string(data))
+	case FormatBCC:
+		return ParseBCC(data)
+	case FormatJSON3:
+		return ParseJSON3(data)
+	case FormatTimedText:
+		return ParseTimedText(data)
+	default:
+		return nil, fmt.Errorf("不支持的字幕格式: %s", format)
+	}
+}
+
+// Marshal 按指定格式输出字幕
+func Marshal(doc *Document, format Format) ([]byte, error) {
+	switch format {
+	case FormatSRT:
+		return []byte(FormatSRTDocument(doc)), nil
+	case FormatVTT:
+		return []byte(FormatVTTDocument(doc)), nil
+	case FormatASS:
+		return []byte(FormatASSDocument(doc, DefaultASSStyle())), nil
+	case FormatBCC:
+		return MarshalBCC(doc)
+	case FormatJSON3:
+		return MarshalJSON3(doc)
+	case FormatTimedText:
+		return MarshalTimedText(doc)
+	default:
+		return nil, fmt.Errorf("不支持的字幕格式: %s", format)
+	}
+}
+
+// FormatFromPath 根据文件扩展名判断字幕格式
+func FormatFromPath(path string) (Format, error) {
+	ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	switch ext {
+	case "srt":
+		return FormatSRT, nil
+	case "vtt":
+		return FormatVTT, nil
+	case "ass", "ssa":
+		return FormatASS, nil
+	case "bcc":
+		return FormatBCC, nil
+	case "json3":
+		return FormatJSON3, nil
+	case "xml", "srv1", "srv2", "srv3", "ttml":
+		return FormatTimedText, nil
+	case "json":
+		return "", fmt.Errorf("无法仅根据 .json 扩展名判断字幕格式: %s", path)
+	default:
+		return "", fmt.Errorf("未知的字幕文件格式: %s", path)
+	}
+}
+
+// DetectFormat 根据内容猜测字幕格式
+func DetectFormat(data []byte) Format {
+	text := strings.TrimSpace(string(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))))
+	switch {
+	case strings.HasPrefix(text, "WEBVTT"):
+		return FormatVTT
+	case strings.HasPrefix(text, "[Script Info]"):
+		return FormatASS
+	case strings.HasPrefix(text, "<"):
+		return FormatTimedText
+	case strings.HasPrefix(text, "{"):
+		if strings.Contains(text, "\"events\"") {
+			return FormatJSON3
+		}
+		return FormatBCC
+	default:
+		return FormatSRT
+	}
+}
+
+// ReadFile 读取字幕文件（格式由扩展名决定，无法判断时按内容猜测）
+func ReadFile(path string) (*Document, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	format, err := FormatFromPath(path)
+	if err != nil {
+		format = DetectFormat(data)
+	}
+
+	doc, err := Parse(data, format)
+	if err != nil {
+		return nil, fmt.Errorf("解析字幕文件失败 %s: %w", filepath.Base(path), err)
+	}
+	return doc, nil
+}
+
+// WriteFile 写入字幕文件（格式由扩展名决定）
+func WriteFile(path string, doc *Document) error {
+	format, err := FormatFromPath(path)
+	if err != nil {
+		return err
+	}
+	return WriteFileAs(path, doc, format)
+}
+
+// WriteFileAs 以指定格式写入字幕文件
+func WriteFileAs(path string, doc *Document, format Format) error {
+	data, err := Marshal(doc, format)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("创建字幕目录失败: %w", err)
+	}
+	return os.WriteFile(path, data, 0644)
+}
@@ -0,0 +1,138 @@
+package subtitle
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func sampleDocument() *Document {
+	doc := NewDocument("")
+	doc.Add(0, 1500, "Hello, world!")
+	doc.Add(1500, 3200, "Two lines\nof text")
+	doc.Add(4000, 6120, `Tom & Jerry's <b>"show"</b>`)
+	return doc
+}
+
+func assertSameCues(t *testing.T, got, want *Document) {
+	t.Helper()
+	if got.Len() != want.Len() {
+		t.Fatalf("cue count = %d, want %d", got.Len(), want.Len())
+	}
+	for i := range want.Cues {
+		g, w := got.Cues[i], want.Cues[i]
+		if g.Index != w.Index || g.Start != w.Start || g.End != w.End || g.Text != w.Text {
+			t.Errorf("cue %d = %+v, want %+v", i+1, g, w)
+		}
+	}
+}
+
+// 各格式输出后再解析，条目时间和文本保持不变
+func TestCodecRoundTrip(t *testing.T) {
+	formats := []Format{FormatSRT, FormatVTT, FormatASS, FormatBCC, FormatJSON3, FormatTimedText}
+	for _, format := range formats {
+		t.Run(string(format), func(t *testing.T) {
+			want := sampleDocument()
+			data, err := Marshal(want, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := Parse(data, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			assertSameCues(t, got, want)
+		})
+	}
+}
+
+// 空文本条目需要保留，否则与其它语言轨道按序号对应时会错位
+func TestSRTKeepsEmptyCues(t *testing.T) {
+	want := NewDocument("")
+	want.Add(0, 1000, "first")
+	want.Add(1000, 2000, "")
+	want.Add(2000, 3000, "third")
+
+	got, err := ParseSRT(FormatSRTDocument(want))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameCues(t, got, want)
+}
+
+// 条目之间缺少空行时，序号行 + 时间行开始新条目
+func TestSRTMissingBlankLine(t *testing.T) {
+	content := "1\n00:00:00,000 --> 00:00:01,000\nfirst\n2\n00:00:01,000 --> 00:00:02,000\nsecond\n\n3\n00:00:02,000 --> 00:00:03,000\n2024\nthird\n"
+	got, err := ParseSRT(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := NewDocument("")
+	want.Add(0, 1000, "first")
+	want.Add(1000, 2000, "second")
+	want.Add(2000, 3000, "2024\nthird")
+	assertSameCues(t, got, want)
+}
+
+// ASS 文本中的花括号、反斜杠和换行需要转义，否则会被当作覆盖标签或控制序列
+func TestASSEscaping(t *testing.T) {
+	want := NewDocument("")
+	want.Add(0, 1000, "{not a tag} and }{")
+	want.Add(1000, 2000, `C:\Netflix\new\h`)
+	want.Add(2000, 3000, "line one\nline two")
+
+	content := FormatASSDocument(want, DefaultASSStyle())
+	if strings.Contains(content, "{not a tag}") {
+		t.Errorf("braces were not escaped:\n%s", content)
+	}
+	if !strings.Contains(content, `line one\Nline two`) {
+		t.Errorf("newline was not written as \\N:\n%s", content)
+	}
+
+	got, err := ParseASS(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertSameCues(t, got, want)
+}
+
+// 解析 ASS 时去掉覆盖标签，还原 \N、\h
+func TestParseASSOverrideTags(t *testing.T) {
+	content := `[Script Info]
+ScriptType: v4.00+
+
+[Events]
+Format: Layer, Start, End, Style, Name, MarginL, MarginR, MarginV, Effect, Text
+Dialogue: 0,0:00:01.00,0:00:02.50,Default,,0,0,0,,{\an8\b1}Hello,\hworld\NSecond line
+`
+	doc, err := ParseASS(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if doc.Len() != 1 {
+		t.Fatalf("cue count = %d, want 1", doc.Len())
+	}
+	cue := doc.Cues[0]
+	if cue.Start != 1000 || cue.End != 2500 || cue.Text != "Hello, world\nSecond line" {
+		t.Errorf("unexpected cue: %+v", cue)
+	}
+}
+
+// timedtext 文本中的字面 &amp; 等实体经过二次转义后可以原样还原
+func TestTimedTextLiteralEntities(t *testing.T) {
+	want := NewDocument("")
+	want.Add(0, 1000, "write &amp; as &amp;amp;")
+
+	data, err := MarshalTimedText(want)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ParseTimedText(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got.Texts(), want.Texts()) {
+		t.Errorf("texts = %q, want %q", got.Texts(), want.Texts())
+	}
+}
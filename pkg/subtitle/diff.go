@@ -0,0 +1,42 @@
+package subtitle
+
+// 字幕差异类型
+const (
+	ChangeAdded    = "added"
+	ChangeRemoved  = "removed"
+	ChangeModified = "modified"
+)
+
+// CueChange 单条字幕的变化（按条目位置比较）
+type CueChange struct {
+	Index  int    `json:"index"`            // 条目序号（从1开始）
+	Type   string `json:"type"`             // added, removed, modified
+	Before *Cue   `json:"before,omitempty"` // 修改前
+	After  *Cue   `json:"after,omitempty"`  // 修改后
+}
+
+// DiffDocuments 按条目位置比较两个字幕文档的时间和文本
+func DiffDocuments(before, after *Document) []CueChange {
+	changes := []CueChange{}
+	n := len(before.Cues)
+	if len(after.Cues) > n {
+		n = len(after.Cues)
+	}
+
+	for i := 0; i < n; i++ {
+		switch {
+		case i >= len(before.Cues):
+			cue := after.Cues[i]
+			changes = append(changes, CueChange{Index: i + 1, Type: ChangeAdded, After: &cue})
+		case i >= len(after.Cues):
+			cue := before.Cues[i]
+			changes = append(changes, CueChange{Index: i + 1, Type: ChangeRemoved, Before: &cue})
+		default:
+			b, a := before.Cues[i], after.Cues[i]
+			if b.Start != a.Start || b.End != a.End || b.Text != a.Text {
+				changes = append(changes, CueChange{Index: i + 1, Type: ChangeModified, Before: &b, After: &a})
+			}
+		}
+	}
+	return changes
+}
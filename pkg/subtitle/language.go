@@ -0,0 +1,52 @@
+package subtitle
+
+import "strings"
+
+// LanguageSimplifiedChinese 简体中文字幕的语言代码（翻译字幕的默认目标语言）
+const LanguageSimplifiedChinese = "zh-Hans"
+
+// 常见写法到 Bilibili 字幕语言代码的映射
+var languageAliases = map[string]string{
+	"zh":      LanguageSimplifiedChinese,
+	"zh-cn":   LanguageSimplifiedChinese,
+	"zh-sg":   LanguageSimplifiedChinese,
+	"zh-hans": LanguageSimplifiedChinese,
+	"chs":     LanguageSimplifiedChinese,
+	"cn":      LanguageSimplifiedChinese,
+	"zh-tw":   "zh-Hant",
+	"zh-hk":   "zh-Hant",
+	"zh-mo":   "zh-Hant",
+	"zh-hant": "zh-Hant",
+	"cht":     "zh-Hant",
+	"en-us":   "en",
+	"en-gb":   "en",
+	"jp":      "ja",
+	"ja-jp":   "ja",
+	"kr":      "ko",
+	"ko-kr":   "ko",
+}
+
+// NormalizeLanguage 将语言代码规范为 Bilibili 字幕使用的写法（zh-Hans、zh-Hant、en、ja 等），
+// 未知代码转为小写原样返回
+func NormalizeLanguage(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "_", "-"))
+	if normalized, ok := languageAliases[code]; ok {
+		return normalized
+	}
+	return code
+}
+
+// NormalizeLanguages 规范化语言代码列表，去掉空值和重复项并保持顺序
+func NormalizeLanguages(codes []string) []string {
+	var result []string
+	seen := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		normalized := NormalizeLanguage(code)
+		if normalized == "" || seen[normalized] {
+			continue
+		}
+		seen[normalized] = true
+		result = append(result, normalized)
+	}
+	return result
+}
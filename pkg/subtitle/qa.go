@@ -0,0 +1,348 @@
+package subtitle
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 问题严重程度
+const (
+	SeverityError   = "error"   // 影响观看，必须处理
+	SeverityWarning = "warning" // 影响体验
+	SeverityInfo    = "info"    // 提示
+)
+
+// 问题类型
+const (
+	IssueEmpty           = "empty"            // 空字幕
+	IssueInvalidDuration = "invalid_duration" // 时长为零或负数
+	IssueOverlap         = "overlap"          // 与下一条重叠
+	IssueTooShort        = "too_short"        // 时长过短
+	IssueTooLong         = "too_long"         // 时长过长
+	IssueReadingSpeed    = "reading_speed"    // 阅读速度过快
+	IssueLineTooLong     = "line_too_long"    // 单行过长
+	IssueTooManyLines    = "too_many_lines"   // 行数过多
+	IssuePastEnd         = "past_end"         // 超出视频结尾
+)
+
+// QAOptions 字幕质检参数（时间单位：毫秒）
+type QAOptions struct {
+	MaxCPS        float64 `json:"max_cps"`        // 每秒最大字符数（不含空白和标点）
+	MaxLineChars  int     `json:"max_line_chars"` // 每行最大字符数
+	MaxLines      int     `json:"max_lines"`      // 每条最大行数
+	MinDuration   int64   `json:"min_duration"`   // 最短时长
+	MaxDuration   int64   `json:"max_duration"`   // 最长时长
+	MinGap        int64   `json:"min_gap"`        // 相邻字幕最小间隔
+	MediaDuration int64   `json:"media_duration"` // 视频时长，0 表示未知（不检查超出结尾）
+}
+
+// DefaultQAOptions 默认质检参数（适用于简体中文字幕）
+func DefaultQAOptions() QAOptions {
+	return QAOptions{
+		MaxCPS:       9,
+		MaxLineChars: 20,
+		MaxLines:     2,
+		MinDuration:  1000,
+		MaxDuration:  7000,
+		MinGap:       80,
+	}
+}
+
+// QAIssue 质检问题
+type QAIssue struct {
+	Index    int    `json:"index"`    // 字幕序号
+	Start    int64  `json:"start"`    // 字幕开始时间（毫秒）
+	Type     string `json:"type"`     // 问题类型
+	Severity string `json:"severity"` // 严重程度
+	Message  string `json:"message"`  // 问题描述
+}
+
+// QAReport 质检报告
+type QAReport struct {
+	TotalCues int       `json:"total_cues"`
+	Errors    int       `json:"errors"`
+	Warnings  int       `json:"warnings"`
+	Infos     int       `json:"infos"`
+	Issues    []QAIssue `json:"issues"`
+}
+
+// CountByType 按问题类型统计
+func (r *QAReport) CountByType() map[string]int {
+	counts := make(map[string]int)
+	for _, issue := range r.Issues {
+		counts[issue.Type]++
+	}
+	return counts
+}
+
+func (r *QAReport) add(cue Cue, issueType, severity, format string, args ...interface{}) {
+	r.Issues = append(r.Issues, QAIssue{
+		Index:    cue.Index,
+		Start:    cue.Start,
+		Type:     issueType,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+	switch severity {
+	case SeverityError:
+		r.Errors++
+	case SeverityWarning:
+		r.Warnings++
+	default:
+		r.Infos++
+	}
+}
+
+// QAFixResult 自动修复结果
+type QAFixResult struct {
+	Before  *QAReport      `json:"before"`  // 修复前的问题
+	After   *QAReport      `json:"after"`   // 修复后仍存在的问题
+	Actions map[string]int `json:"actions"` // 各类修复操作次数
+}
+
+// 自动修复操作
+const (
+	FixClampedEnd     = "clamped_end"     // 截断到视频结尾
+	FixTrimmedOverlap = "trimmed_overlap" // 截断重叠
+	FixExtendedShort  = "extended_short"  // 延长过短或阅读速度过快的字幕
+	FixRewrapped      = "rewrapped"       // 重新折行
+)
+
+// CheckQuality 检查字幕时间轴和排版问题
+func CheckQuality(doc *Document, opts QAOptions) *QAReport {
+	opts = normalizeQAOptions(opts)
+	report := &QAReport{TotalCues: doc.Len(), Issues: []QAIssue{}}
+
+	for i, cue := range doc.Cues {
+		text := strings.TrimSpace(cue.Text)
+		if text == "" {
+			report.add(cue, IssueEmpty, SeverityWarning, "字幕内容为空")
+			continue
+		}
+
+		duration := cue.Duration()
+		if duration <= 0 {
+			report.add(cue, IssueInvalidDuration, SeverityError, "时长无效: %dms", duration)
+		} else {
+			if duration < opts.MinDuration {
+				report.add(cue, IssueTooShort, SeverityWarning, "时长过短: %dms < %dms", duration, opts.MinDuration)
+			}
+			if duration > opts.MaxDuration {
+				report.add(cue, IssueTooLong, SeverityInfo, "时长过长: %dms > %dms", duration, opts.MaxDuration)
+			}
+			if cps := readingSpeed(text, duration); cps > opts.MaxCPS {
+				severity := SeverityWarning
+				if cps > opts.MaxCPS*1.5 {
+					severity = SeverityError
+				}
+				report.add(cue, IssueReadingSpeed, severity, "阅读速度过快: %.1f 字/秒 > %.1f", cps, opts.MaxCPS)
+			}
+		}
+
+		if i+1 < len(doc.Cues) {
+			next := doc.Cues[i+1]
+			if cue.End > next.Start {
+				report.add(cue, IssueOverlap, SeverityError, "与下一条字幕重叠 %dms", cue.End-next.Start)
+			}
+		}
+
+		lines := strings.Split(text, "\n")
+		if len(lines) > opts.MaxLines {
+			report.add(cue, IssueTooManyLines, SeverityWarning, "行数过多: %d > %d", len(lines), opts.MaxLines)
+		}
+		for _, line := range lines {
+			if n := utf8.RuneCountInString(strings.TrimSpace(line)); n > opts.MaxLineChars {
+				report.add(cue, IssueLineTooLong, SeverityWarning, "单行过长: %d 字 > %d", n, opts.MaxLineChars)
+				break
+			}
+		}
+
+		if opts.MediaDuration > 0 && cue.End > opts.MediaDuration {
+			severity := SeverityWarning
+			if cue.Start >= opts.MediaDuration {
+				severity = SeverityError
+			}
+			report.add(cue, IssuePastEnd, severity, "超出视频结尾 %dms", cue.End-opts.MediaDuration)
+		}
+	}
+
+	return report
+}
+
+// AutoFix 自动修复可以安全修复的问题：截断超出结尾和重叠部分、延长过短字幕、重新折行。
+// 只调整时间和折行，不拆分、删除或重排字幕，条目数量和顺序与输入一致，
+// 译文与原文按序号一一对应的关系不会被破坏。返回修复后的文档和修复前后的质检报告
+func AutoFix(doc *Document, opts QAOptions) (*Document, *QAFixResult) {
+	opts = normalizeQAOptions(opts)
+	result := &QAFixResult{
+		Before:  CheckQuality(doc, opts),
+		Actions: make(map[string]int),
+	}
+
+	fixed := doc.Clone()
+	cues := fixed.Cues
+
+	// 1. 截断超出视频结尾的部分（完全超出结尾的字幕无法修复，保留在报告中）
+	for i := range cues {
+		cues[i].Text = strings.TrimSpace(cues[i].Text)
+		if opts.MediaDuration > 0 && cues[i].Start < opts.MediaDuration && cues[i].End > opts.MediaDuration {
+			cues[i].End = opts.MediaDuration
+			result.Actions[FixClampedEnd]++
+		}
+	}
+
+	// 2. 截断与下一条重叠的部分（截断后仍需保留正时长）
+	for i := 0; i+1 < len(cues); i++ {
+		limit := cues[i+1].Start - opts.MinGap
+		if cues[i].End > cues[i+1].Start && limit > cues[i].Start {
+			cues[i].End = limit
+			result.Actions[FixTrimmedOverlap]++
+		}
+	}
+
+	// 3. 延长过短、无效或阅读速度过快的字幕（不超过下一条开始和视频结尾）
+	for i := range cues {
+		needed := opts.MinDuration
+		if chars := countReadableChars(cues[i].Text); chars > 0 {
+			if byCPS := int64(math.Ceil(float64(chars) / opts.MaxCPS * 1000)); byCPS > needed {
+				needed = byCPS
+			}
+		}
+		if needed > opts.MaxDuration {
+			needed = opts.MaxDuration
+		}
+		if cues[i].Duration() >= needed {
+			continue
+		}
+
+		target := cues[i].Start + needed
+		if i+1 < len(cues) && target > cues[i+1].Start-opts.MinGap {
+			target = cues[i+1].Start - opts.MinGap
+		}
+		if opts.MediaDuration > 0 && target > opts.MediaDuration {
+			target = opts.MediaDuration
+		}
+		if target > cues[i].End {
+			cues[i].End = target
+			result.Actions[FixExtendedShort]++
+		}
+	}
+
+	// 4. 重新折行（超出行数时最后一行容纳剩余文字，不丢弃内容）
+	for i := range cues {
+		if !needsRewrap(cues[i].Text, opts) {
+			continue
+		}
+		wrapped := wrapText(cues[i].Text, opts.MaxLineChars, opts.MaxLines)
+		if wrapped != cues[i].Text {
+			cues[i].Text = wrapped
+			result.Actions[FixRewrapped]++
+		}
+	}
+
+	result.After = CheckQuality(fixed, opts)
+	return fixed, result
+}
+
+func normalizeQAOptions(opts QAOptions) QAOptions {
+	def := DefaultQAOptions()
+	if opts.MaxCPS <= 0 {
+		opts.MaxCPS = def.MaxCPS
+	}
+	if opts.MaxLineChars <= 0 {
+		opts.MaxLineChars = def.MaxLineChars
+	}
+	if opts.MaxLines <= 0 {
+		opts.MaxLines = def.MaxLines
+	}
+	if opts.MinDuration < 0 {
+		opts.MinDuration = 0
+	}
+	if opts.MaxDuration <= 0 {
+		opts.MaxDuration = def.MaxDuration
+	}
+	if opts.MinGap < 0 {
+		opts.MinGap = 0
+	}
+	return opts
+}
+
+// countReadableChars 统计需要阅读的字符数（不含空白和标点）
+func countReadableChars(text string) int {
+	n := 0
+	for _, r := range text {
+		if unicode.IsSpace(r) || unicode.IsPunct(r) || unicode.IsSymbol(r) {
+			continue
+		}
+		n++
+	}
+	return n
+}
+
+// readingSpeed 每秒字符数
+func readingSpeed(text string, duration int64) float64 {
+	if duration <= 0 {
+		return 0
+	}
+	return float64(countReadableChars(text)) / MSToSeconds(duration)
+}
+
+// needsRewrap 判断是否存在单行过长或行数过多
+func needsRewrap(text string, opts QAOptions) bool {
+	lines := strings.Split(text, "\n")
+	if len(lines) > opts.MaxLines {
+		return true
+	}
+	for _, line := range lines {
+		if utf8.RuneCountInString(line) > opts.MaxLineChars {
+			return true
+		}
+	}
+	return false
+}
+
+// wrapText 对文本重新折行（中日韩文字按字，其它按词）
+func wrapText(text string, maxLineChars, maxLines int) string {
+	return wrapLines(tokenizeText(text), maxLineChars, maxLines)
+}
+
+// tokenizeText 将文本拆分为词元：中日韩文字每个字（连同其后的标点）为一个词元，其它文字按空白分词
+func tokenizeText(text string) []string {
+	var tokens []string
+	for _, field := range strings.Fields(text) {
+		var word strings.Builder
+		flush := func() {
+			if word.Len() > 0 {
+				tokens = append(tokens, word.String())
+				word.Reset()
+			}
+		}
+		for _, r := range field {
+			switch {
+			case isCJK(r):
+				flush()
+				word.WriteRune(r)
+			case unicode.IsPunct(r) && word.Len() > 0:
+				// 标点附着在前一个词元上，避免出现在行首
+				word.WriteRune(r)
+			default:
+				if word.Len() > 0 {
+					last, _ := utf8.DecodeLastRuneInString(word.String())
+					if isCJK(last) || isCJKPunct(last) {
+						flush()
+					}
+				}
+				word.WriteRune(r)
+			}
+		}
+		flush()
+	}
+	return tokens
+}
+
+func isCJKPunct(r rune) bool {
+	return unicode.IsPunct(r) && r > unicode.MaxLatin1
+}
@@ -0,0 +1,52 @@
+package subtitle
+
+import "testing"
+
+// 自动修复只调整时间和折行，条目数量、顺序和文本内容保持不变
+func TestAutoFixKeepsCueAlignment(t *testing.T) {
+	doc := NewDocument("zh-Hans")
+	doc.Add(0, 300, "太短了")
+	doc.Add(1000, 2000, "")
+	doc.Add(1500, 12000, "这是一条非常非常长的字幕，时长和字数都超过了限制，以前会被拆分成好几条字幕")
+	doc.Add(12500, 14000, "超出视频结尾")
+	doc.Add(21000, 22000, "完全在结尾之后")
+
+	opts := DefaultQAOptions()
+	opts.MediaDuration = 13000
+	fixed, result := AutoFix(doc, opts)
+
+	if fixed.Len() != doc.Len() {
+		t.Fatalf("cue count changed: %d -> %d", doc.Len(), fixed.Len())
+	}
+	for i, cue := range fixed.Cues {
+		if cue.Index != doc.Cues[i].Index || cue.Start != doc.Cues[i].Start {
+			t.Errorf("cue %d moved: %+v -> %+v", i+1, doc.Cues[i], cue)
+		}
+		if removeSpace(cue.Text) != removeSpace(doc.Cues[i].Text) {
+			t.Errorf("cue %d text changed: %q -> %q", i+1, doc.Cues[i].Text, cue.Text)
+		}
+	}
+
+	if fixed.Cues[0].End != 1000-opts.MinGap {
+		t.Errorf("short cue should be extended up to the next cue, got end %d", fixed.Cues[0].End)
+	}
+	if fixed.Cues[1].End != 1500-opts.MinGap {
+		t.Errorf("overlap should be trimmed, got end %d", fixed.Cues[1].End)
+	}
+	if fixed.Cues[3].End != opts.MediaDuration {
+		t.Errorf("cue past the end should be clamped, got end %d", fixed.Cues[3].End)
+	}
+	if result.Actions[FixRewrapped] == 0 {
+		t.Errorf("long cue should be rewrapped: %v", result.Actions)
+	}
+}
+
+func removeSpace(text string) string {
+	out := make([]rune, 0, len(text))
+	for _, r := range text {
+		if r != ' ' && r != '\n' {
+			out = append(out, r)
+		}
+	}
+	return string(out)
+}
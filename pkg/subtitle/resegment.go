@@ -0,0 +1,459 @@
+package subtitle
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// PunctuateFunc 标点恢复函数：输入无标点文本，返回加上标点（和大小写）的同一段文本
+type PunctuateFunc func(text string) (string, error)
+
+// ResegmentOptions 重新断句参数（时间单位：毫秒）
+type ResegmentOptions struct {
+	MaxLineChars int   // 每行最大字符数
+	MaxLines     int   // 每条字幕最大行数
+	MaxDuration  int64 // 每条字幕最长持续时间
+	MinDuration  int64 // 每条字幕最短持续时间
+	MinGap       int64 // 相邻字幕最小间隔
+	PauseGap     int64 // 超过该停顿强制断句
+
+	// Punctuate 可选的标点恢复（如 LLM），为 nil 时跳过
+	Punctuate PunctuateFunc
+	// PunctuateChunkWords 每次标点恢复的词数
+	PunctuateChunkWords int
+}
+
+// DefaultResegmentOptions 默认断句参数
+func DefaultResegmentOptions() ResegmentOptions {
+	return ResegmentOptions{
+		MaxLineChars:        42,
+		MaxLines:            2,
+		MaxDuration:         7000,
+		MinDuration:         1000,
+		MinGap:              80,
+		PauseGap:            1500,
+		PunctuateChunkWords: 150,
+	}
+}
+
+// timedWord 带时间的词
+type timedWord struct {
+	Text    string
+	Start   int64
+	End     int64
+	Speaker string
+}
+
+// ResegmentStats 断句统计
+type ResegmentStats struct {
+	InputCues      int  `json:"input_cues"`
+	OutputCues     int  `json:"output_cues"`
+	Words          int  `json:"words"`
+	DroppedRepeats int  `json:"dropped_repeats"` // 滚动字幕中被去除的重复词数
+	Punctuated     bool `json:"punctuated"`      // 是否执行了标点恢复
+}
+
+// Resegment 对滚动式自动字幕进行清洗和重新断句：
+// 去除相邻条目之间的重叠重复、按句子合并碎片，并限制每行字符数、时长和最小间隔
+func Resegment(doc *Document, opts ResegmentOptions) (*Document, *ResegmentStats, error) {
+	opts = normalizeResegmentOptions(opts)
+	stats := &ResegmentStats{InputCues: doc.Len()}
+
+	sorted := doc.Clone()
+	sorted.Sort()
+
+	words, dropped := dedupeRollingWords(sorted.Cues)
+	stats.Words = len(words)
+	stats.DroppedRepeats = dropped
+
+	if opts.Punctuate != nil && needsPunctuation(words) {
+		if err := restorePunctuation(words, opts.Punctuate, opts.PunctuateChunkWords); err != nil {
+			return nil, nil, err
+		}
+		stats.Punctuated = true
+	}
+
+	out := NewDocument(doc.Language)
+	out.Title = doc.Title
+	for _, group := range groupWords(words, opts) {
+		texts := make([]string, len(group))
+		for i, w := range group {
+			texts[i] = w.Text
+		}
+		out.Cues = append(out.Cues, Cue{
+			Start:   group[0].Start,
+			End:     group[len(group)-1].End,
+			Text:    wrapLines(texts, opts.MaxLineChars, opts.MaxLines),
+			Speaker: group[0].Speaker,
+		})
+	}
+
+	out.Cues = fixTiming(out.Cues, opts)
+	out.Renumber()
+	stats.OutputCues = out.Len()
+
+	return out, stats, nil
+}
+
+func normalizeResegmentOptions(opts ResegmentOptions) ResegmentOptions {
+	def := DefaultResegmentOptions()
+	if opts.MaxLineChars <= 0 {
+		opts.MaxLineChars = def.MaxLineChars
+	}
+	if opts.MaxLines <= 0 {
+		opts.MaxLines = def.MaxLines
+	}
+	if opts.MaxDuration <= 0 {
+		opts.MaxDuration = def.MaxDuration
+	}
+	if opts.MinDuration < 0 {
+		opts.MinDuration = 0
+	}
+	if opts.MinGap < 0 {
+		opts.MinGap = 0
+	}
+	if opts.PauseGap <= 0 {
+		opts.PauseGap = def.PauseGap
+	}
+	if opts.PunctuateChunkWords <= 0 {
+		opts.PunctuateChunkWords = def.PunctuateChunkWords
+	}
+	return opts
+}
+
+// dedupeRollingWords 将条目拆分为带时间的词，并去除与上一条重叠的前缀。
+// 滚动字幕中每一条通常会重复上一条末尾的一行；只有相邻两条被识别为滚动字幕时才去重，
+// 普通字幕中的正常重复（如 "no" 之后的 "no way"）保持不变
+func dedupeRollingWords(cues []Cue) ([]timedWord, int) {
+	var words []timedWord
+	var prev []string
+	var prevCue Cue
+	dropped := 0
+
+	for _, cue := range cues {
+		current := strings.Fields(strings.ReplaceAll(cue.Text, "\n", " "))
+		if len(current) == 0 {
+			continue
+		}
+
+		overlap := 0
+		if len(prev) > 0 && isRollingPair(prevCue, cue) {
+			overlap = overlapLength(prev, current)
+		}
+		dropped += overlap
+		fresh := current[overlap:]
+		prev = current
+		prevCue = cue
+		if len(fresh) == 0 {
+			continue
+		}
+
+		start := cue.Start
+		if n := len(words); n > 0 && words[n-1].End > start {
+			start = words[n-1].End
+		}
+		end := cue.End
+		if end <= start {
+			end = start + int64(len(fresh))*10
+		}
+
+		// 按字符数比例分配每个词的时间
+		totalRunes := 0
+		for _, w := range fresh {
+			totalRunes += utf8.RuneCountInString(w)
+		}
+		span := end - start
+		cursor := start
+		for i, w := range fresh {
+			wordEnd := cursor + span*int64(utf8.RuneCountInString(w))/int64(totalRunes)
+			if i == len(fresh)-1 {
+				wordEnd = end
+			}
+			words = append(words, timedWord{Text: w, Start: cursor, End: wordEnd, Speaker: cue.Speaker})
+			cursor = wordEnd
+		}
+	}
+
+	return words, dropped
+}
+
+// isRollingPair 判断相邻两条是否为滚动字幕：时间轴重叠，或多行字幕中当前条目的首行重复上一条的末行
+func isRollingPair(prev, current Cue) bool {
+	if current.Start < prev.End {
+		return true
+	}
+	prevLines := cueLines(prev.Text)
+	currentLines := cueLines(current.Text)
+	if len(prevLines) == 0 || len(currentLines) == 0 {
+		return false
+	}
+	if len(prevLines) < 2 && len(currentLines) < 2 {
+		return false
+	}
+	last := strings.Fields(prevLines[len(prevLines)-1])
+	first := strings.Fields(currentLines[0])
+	return len(last) > 0 && len(last) == len(first) && overlapLength(last, first) == len(first)
+}
+
+// cueLines 返回非空行
+func cueLines(text string) []string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// overlapLength 返回 prev 的后缀与 current 的前缀相同的最大词数（仅用于已识别为滚动字幕的相邻条目）
+func overlapLength(prev, current []string) int {
+	max := len(prev)
+	if len(current) < max {
+		max = len(current)
+	}
+	for k := max; k > 0; k-- {
+		match := true
+		for i := 0; i < k; i++ {
+			if normalizeWord(prev[len(prev)-k+i]) != normalizeWord(current[i]) {
+				match = false
+				break
+			}
+		}
+		if match {
+			return k
+		}
+	}
+	return 0
+}
+
+// normalizeWord 去除标点并转为小写，用于比较
+func normalizeWord(w string) string {
+	return strings.ToLower(strings.TrimFunc(w, func(r rune) bool {
+		return unicode.IsPunct(r) || unicode.IsSymbol(r)
+	}))
+}
+
+// needsPunctuation 判断文本是否缺少句末标点
+func needsPunctuation(words []timedWord) bool {
+	if len(words) < 20 {
+		return false
+	}
+	enders := 0
+	for _, w := range words {
+		if isSentenceEnd(w.Text) {
+			enders++
+		}
+	}
+	// 平均每 30 个词不到一个句末标点，视为无标点
+	return enders*30 < len(words)
+}
+
+// restorePunctuation 分块调用标点恢复，并将结果按词对齐回时间轴
+// 对齐失败（词不一致）的词保持原样
+func restorePunctuation(words []timedWord, punctuate PunctuateFunc, chunkSize int) error {
+	for start := 0; start < len(words); start += chunkSize {
+		end := start + chunkSize
+		if end > len(words) {
+			end = len(words)
+		}
+
+		texts := make([]string, 0, end-start)
+		for _, w := range words[start:end] {
+			texts = append(texts, w.Text)
+		}
+
+		restored, err := punctuate(strings.Join(texts, " "))
+		if err != nil {
+			return err
+		}
+
+		tokens := strings.Fields(restored)
+		if len(tokens) != len(texts) {
+			continue
+		}
+		for i, token := range tokens {
+			if normalizeWord(token) == normalizeWord(texts[i]) {
+				words[start+i].Text = token
+			}
+		}
+	}
+	return nil
+}
+
+// groupWords 将词合并为字幕条目
+func groupWords(words []timedWord, opts ResegmentOptions) [][]timedWord {
+	maxChars := opts.MaxLineChars * opts.MaxLines
+	var groups [][]timedWord
+	var current []timedWord
+	chars := 0
+
+	flush := func() {
+		if len(current) > 0 {
+			groups = append(groups, current)
+		}
+		current = nil
+		chars = 0
+	}
+
+	for _, w := range words {
+		if len(current) > 0 {
+			last := current[len(current)-1]
+			wordChars := utf8.RuneCountInString(w.Text) + 1
+			switch {
+			case w.Start-last.End > opts.PauseGap:
+				flush()
+			case w.Speaker != last.Speaker:
+				flush()
+			case chars+wordChars > maxChars, w.End-current[0].Start > opts.MaxDuration:
+				// 超出长度或时长时优先在逗号处断开
+				if i := lastClauseBreak(current); i > 0 {
+					rest := append([]timedWord{}, current[i:]...)
+					current = current[:i]
+					flush()
+					current = rest
+					for _, r := range rest {
+						chars += utf8.RuneCountInString(r.Text) + 1
+					}
+				} else {
+					flush()
+				}
+			}
+		}
+
+		current = append(current, w)
+		chars += utf8.RuneCountInString(w.Text) + 1
+
+		if isSentenceEnd(w.Text) {
+			flush()
+		}
+	}
+	flush()
+
+	return groups
+}
+
+// lastClauseBreak 返回最后一个逗号类标点之后的位置（位于后半段才有效）
+func lastClauseBreak(words []timedWord) int {
+	for i := len(words) - 1; i >= len(words)/2 && i > 0; i-- {
+		if isClauseEnd(words[i-1].Text) {
+			return i
+		}
+	}
+	return 0
+}
+
+// fixTiming 保证最短时长和最小间隔。下一条在本条开始后 MinGap 内就开始时本条没有可显示的时长，
+// 将其合并到下一条，而不是输出只有 1 毫秒的字幕；合并后超出字数或时长限制时改为两条平分时长
+func fixTiming(cues []Cue, opts ResegmentOptions) []Cue {
+	out := make([]Cue, 0, len(cues))
+	for i := 0; i < len(cues); i++ {
+		cue := cues[i]
+		limit := int64(-1)
+		if i+1 < len(cues) {
+			next := &cues[i+1]
+			limit = next.Start - opts.MinGap
+			if limit <= cue.Start {
+				end := next.End
+				if end < cue.End {
+					end = cue.End
+				}
+				merged := mergeCueText(cue.Text, next.Text, opts)
+				if fitsCue(merged, end-cue.Start, opts) {
+					next.Text = merged
+					next.Start = cue.Start
+					if next.Speaker == "" {
+						next.Speaker = cue.Speaker
+					}
+					next.End = end
+					continue
+				}
+
+				// 合并后超出字数或时长限制：保留两条，平分时长，下一条顺延到本条之后
+				cue.End = cue.Start + (end-cue.Start)/2
+				if cue.End <= cue.Start {
+					cue.End = cue.Start + 1
+				}
+				next.Start = cue.End + opts.MinGap
+				if next.End <= next.Start {
+					next.End = next.Start + 1
+				}
+				limit = cue.End
+			}
+		}
+
+		if cue.Duration() < opts.MinDuration {
+			cue.End = cue.Start + opts.MinDuration
+		}
+		if limit >= 0 && cue.End > limit {
+			cue.End = limit
+		}
+		if cue.End <= cue.Start {
+			cue.End = cue.Start + 1
+		}
+		out = append(out, cue)
+	}
+	return out
+}
+
+// fitsCue 合并后的字幕是否仍在字数和时长限制内
+func fitsCue(text string, duration int64, opts ResegmentOptions) bool {
+	chars := utf8.RuneCountInString(strings.Join(strings.Fields(text), " "))
+	return chars <= opts.MaxLineChars*opts.MaxLines && duration <= opts.MaxDuration
+}
+
+// mergeCueText 合并两条字幕的文本并重新折行
+func mergeCueText(a, b string, opts ResegmentOptions) string {
+	words := strings.Fields(strings.ReplaceAll(a+"\n"+b, "\n", " "))
+	return wrapLines(words, opts.MaxLineChars, opts.MaxLines)
+}
+
+// wrapLines 按每行最大字符数折行，超过最大行数时合并到最后一行
+func wrapLines(words []string, maxLineChars, maxLines int) string {
+	var lines []string
+	var line string
+	for _, w := range words {
+		candidate := joinWords(line, w)
+		if line != "" && utf8.RuneCountInString(candidate) > maxLineChars && len(lines) < maxLines-1 {
+			lines = append(lines, line)
+			line = w
+			continue
+		}
+		line = candidate
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// joinWords 连接两个词，中日韩文字之间不加空格
+func joinWords(a, b string) string {
+	if a == "" {
+		return b
+	}
+	last, _ := utf8.DecodeLastRuneInString(a)
+	first, _ := utf8.DecodeRuneInString(b)
+	if (isCJK(last) || isCJKPunct(last)) && (isCJK(first) || isCJKPunct(first)) {
+		return a + b
+	}
+	return a + " " + b
+}
+
+func isCJK(r rune) bool {
+	return unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) ||
+		unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r)
+}
+
+func isSentenceEnd(w string) bool {
+	w = strings.TrimRight(w, `"'”’)]」』`)
+	return strings.HasSuffix(w, ".") || strings.HasSuffix(w, "?") || strings.HasSuffix(w, "!") ||
+		strings.HasSuffix(w, "。") || strings.HasSuffix(w, "？") || strings.HasSuffix(w, "！") ||
+		strings.HasSuffix(w, "…")
+}
+
+func isClauseEnd(w string) bool {
+	return strings.HasSuffix(w, ",") || strings.HasSuffix(w, ";") || strings.HasSuffix(w, ":") ||
+		strings.HasSuffix(w, "，") || strings.HasSuffix(w, "；") || strings.HasSuffix(w, "：")
+}
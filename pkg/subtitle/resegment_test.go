@@ -0,0 +1,64 @@
+package subtitle
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func assertResegmentLimits(t *testing.T, cues []Cue, opts ResegmentOptions) {
+	t.Helper()
+	for i, cue := range cues {
+		if chars := utf8.RuneCountInString(strings.ReplaceAll(cue.Text, "\n", " ")); chars > opts.MaxLineChars*opts.MaxLines {
+			t.Errorf("cue %d has %d chars: %q", i+1, chars, cue.Text)
+		}
+		if cue.Duration() > opts.MaxDuration || cue.Duration() <= 0 {
+			t.Errorf("cue %d duration = %d", i+1, cue.Duration())
+		}
+		if i > 0 && cue.Start-cues[i-1].End < opts.MinGap {
+			t.Errorf("cue %d starts %dms after cue %d", i+1, cue.Start-cues[i-1].End, i)
+		}
+	}
+}
+
+// 几乎同时开始的两条字幕合并后仍在限制内时合并为一条
+func TestFixTimingMergesSimultaneousCues(t *testing.T) {
+	opts := DefaultResegmentOptions()
+	cues := fixTiming([]Cue{
+		{Start: 1000, End: 1500, Text: "Hello"},
+		{Start: 1050, End: 3000, Text: "world."},
+	}, opts)
+
+	if len(cues) != 1 || cues[0].Text != "Hello world." || cues[0].Start != 1000 || cues[0].End != 3000 {
+		t.Fatalf("unexpected cues: %+v", cues)
+	}
+}
+
+// 合并后超出字数或时长限制时不合并，两条平分时长
+func TestFixTimingKeepsLimitsAfterMerge(t *testing.T) {
+	opts := DefaultResegmentOptions()
+	long := "This sentence is long enough to fill most of the two lines"
+
+	cases := []struct {
+		name string
+		cues []Cue
+	}{
+		{"chars", []Cue{
+			{Start: 1000, End: 3000, Text: long},
+			{Start: 1050, End: 4000, Text: "and this one adds even more words to it."},
+		}},
+		{"duration", []Cue{
+			{Start: 1000, End: 5000, Text: "Short one,"},
+			{Start: 1050, End: 9000, Text: "another short one."},
+		}},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cues := fixTiming(c.cues, opts)
+			if len(cues) != 2 {
+				t.Fatalf("expected 2 cues, got %+v", cues)
+			}
+			assertResegmentLimits(t, cues, opts)
+		})
+	}
+}
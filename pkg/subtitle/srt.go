@@ -0,0 +1,117 @@
+package subtitle
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ParseSRT 解析 SRT 字幕
+// 空文本的条目会保留（保证与其它轨道按序号对应）；条目之间缺少空行时，
+// 遇到"序号行 + 时间行"或新的时间行即开始下一条
+func ParseSRT(content string) (*Document, error) {
+	doc := NewDocument("")
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+
+	var current *Cue
+	var textLines []string
+	flush := func() {
+		if current != nil {
+			current.Text = strings.Join(textLines, "\n")
+			doc.Cues = append(doc.Cues, *current)
+		}
+		current = nil
+		textLines = nil
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimSpace(lines[i])
+
+		if line == "" {
+			flush()
+			continue
+		}
+
+		// 序号行 + 时间行：开始新条目
+		if index, err := strconv.Atoi(line); err == nil && i+1 < len(lines) && isArrowLine(lines[i+1]) {
+			start, end, err := parseArrowLine(strings.TrimSpace(lines[i+1]))
+			if err != nil {
+				return nil, fmt.Errorf("第 %d 行: %w", i+2, err)
+			}
+			flush()
+			current = &Cue{Index: index, Start: start, End: end}
+			i++
+			continue
+		}
+
+		// 时间行（序号行可缺省）：开始新条目
+		if isArrowLine(line) {
+			start, end, err := parseArrowLine(line)
+			if err != nil {
+				return nil, fmt.Errorf("第 %d 行: %w", i+1, err)
+			}
+			flush()
+			current = &Cue{Index: len(doc.Cues) + 1, Start: start, End: end}
+			continue
+		}
+
+		if current == nil {
+			if strings.Contains(line, "-->") {
+				_, _, err := parseArrowLine(line)
+				return nil, fmt.Errorf("第 %d 行: %w", i+1, err)
+			}
+			continue
+		}
+		textLines = append(textLines, line)
+	}
+	flush()
+
+	return doc, nil
+}
+
+// FormatSRTDocument 输出 SRT 字幕（重新从1开始编号）
+func FormatSRTDocument(doc *Document) string {
+	var builder strings.Builder
+	for i, cue := range doc.Cues {
+		builder.WriteString(strconv.Itoa(i + 1))
+		builder.WriteString("\n")
+		builder.WriteString(FormatSRTTime(cue.Start))
+		builder.WriteString(" --> ")
+		builder.WriteString(FormatSRTTime(cue.End))
+		builder.WriteString("\n")
+		builder.WriteString(cue.Text)
+		builder.WriteString("\n\n")
+	}
+	return builder.String()
+}
+
+// isArrowLine 判断是否为时间行
+func isArrowLine(line string) bool {
+	_, _, err := parseArrowLine(strings.TrimSpace(line))
+	return err == nil
+}
+
+// parseArrowLine 解析 "start --> end [settings]" 形式的时间行
+func parseArrowLine(line string) (int64, int64, error) {
+	parts := strings.SplitN(line, "-->", 2)
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("无效时间行: %s", line)
+	}
+
+	start, err := ParseTimecode(parts[0])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	// 结束时间后面可能跟着 WebVTT 的 cue settings
+	endField := strings.Fields(parts[1])
+	if len(endField) == 0 {
+		return 0, 0, fmt.Errorf("无效时间行: %s", line)
+	}
+	end, err := ParseTimecode(endField[0])
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return start, end, nil
+}
@@ -0,0 +1,118 @@
+package subtitle
+
+import (
+	"sort"
+	"strings"
+)
+
+// Cue 字幕条目（时间单位：毫秒）
+type Cue struct {
+	Index   int    `json:"index"`             // 序号（从1开始）
+	Start   int64  `json:"start"`             // 开始时间（毫秒）
+	End     int64  `json:"end"`               // 结束时间（毫秒）
+	Text    string `json:"text"`              // 字幕文本（多行以 \n 分隔）
+	Speaker string `json:"speaker,omitempty"` // 说话人
+	Style   string `json:"style,omitempty"`   // 样式名（ASS）
+}
+
+// Duration 条目持续时间（毫秒）
+func (c Cue) Duration() int64 {
+	return c.End - c.Start
+}
+
+// Document 字幕文档
+type Document struct {
+	Language string `json:"language,omitempty"` // 语言代码 (如: en, zh-Hans)
+	Title    string `json:"title,omitempty"`    // 标题
+	Cues     []Cue  `json:"cues"`               // 字幕条目
+}
+
+// NewDocument 创建字幕文档
+func NewDocument(language string) *Document {
+	return &Document{
+		Language: language,
+		Cues:     []Cue{},
+	}
+}
+
+// Add 追加一条字幕（自动编号）
+func (d *Document) Add(start, end int64, text string) {
+	d.Cues = append(d.Cues, Cue{
+		Index: len(d.Cues) + 1,
+		Start: start,
+		End:   end,
+		Text:  text,
+	})
+}
+
+// Len 字幕条数
+func (d *Document) Len() int {
+	return len(d.Cues)
+}
+
+// Texts 按顺序返回所有条目文本
+func (d *Document) Texts() []string {
+	texts := make([]string, len(d.Cues))
+	for i, cue := range d.Cues {
+		texts[i] = cue.Text
+	}
+	return texts
+}
+
+// PlainText 将所有条目文本以 sep 连接为纯文本（多行文本合并为一行）
+func (d *Document) PlainText(sep string) string {
+	var parts []string
+	for _, cue := range d.Cues {
+		text := strings.TrimSpace(strings.ReplaceAll(cue.Text, "\n", " "))
+		if text != "" {
+			parts = append(parts, text)
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+// Duration 字幕覆盖的总时长（最后一条结束时间，毫秒）
+func (d *Document) Duration() int64 {
+	var end int64
+	for _, cue := range d.Cues {
+		if cue.End > end {
+			end = cue.End
+		}
+	}
+	return end
+}
+
+// Clone 深拷贝文档
+func (d *Document) Clone() *Document {
+	clone := *d
+	clone.Cues = make([]Cue, len(d.Cues))
+	copy(clone.Cues, d.Cues)
+	return &clone
+}
+
+// WithTexts 返回保持时间轴、替换文本后的新文档（用于翻译结果）
+// texts 数量不足时保留原文
+func (d *Document) WithTexts(language string, texts []string) *Document {
+	out := d.Clone()
+	out.Language = language
+	for i := range out.Cues {
+		if i < len(texts) {
+			out.Cues[i].Text = texts[i]
+		}
+	}
+	return out
+}
+
+// Sort 按开始时间排序
+func (d *Document) Sort() {
+	sort.SliceStable(d.Cues, func(i, j int) bool {
+		return d.Cues[i].Start < d.Cues[j].Start
+	})
+}
+
+// Renumber 重新编号（从1开始）
+func (d *Document) Renumber() {
+	for i := range d.Cues {
+		d.Cues[i].Index = i + 1
+	}
+}
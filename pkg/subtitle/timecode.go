@@ -0,0 +1,73 @@
+package subtitle
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// FormatSRTTime 格式化毫秒为 SRT 时间 (HH:MM:SS,mmm)
+func FormatSRTTime(ms int64) string {
+	h, m, s, milli := splitMS(ms)
+	return fmt.Sprintf("%02d:%02d:%02d,%03d", h, m, s, milli)
+}
+
+// FormatVTTTime 格式化毫秒为 WebVTT 时间 (HH:MM:SS.mmm)
+func FormatVTTTime(ms int64) string {
+	h, m, s, milli := splitMS(ms)
+	return fmt.Sprintf("%02d:%02d:%02d.%03d", h, m, s, milli)
+}
+
+// FormatASSTime 格式化毫秒为 ASS 时间 (H:MM:SS.cc)
+func FormatASSTime(ms int64) string {
+	h, m, s, milli := splitMS(ms)
+	return fmt.Sprintf("%d:%02d:%02d.%02d", h, m, s, milli/10)
+}
+
+// ParseTimecode 解析时间码为毫秒
+// 支持 HH:MM:SS,mmm / HH:MM:SS.mmm / MM:SS.mmm / H:MM:SS.cc 以及纯秒数
+func ParseTimecode(value string) (int64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, fmt.Errorf("空时间码")
+	}
+	value = strings.Replace(value, ",", ".", 1)
+
+	parts := strings.Split(value, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("无效时间码: %s", value)
+	}
+
+	var total float64
+	for _, part := range parts {
+		v, err := strconv.ParseFloat(part, 64)
+		if err != nil || v < 0 {
+			return 0, fmt.Errorf("无效时间码: %s", value)
+		}
+		total = total*60 + v
+	}
+
+	return int64(total*1000 + 0.5), nil
+}
+
+// SecondsToMS 秒转毫秒
+func SecondsToMS(seconds float64) int64 {
+	return int64(seconds*1000 + 0.5)
+}
+
+// MSToSeconds 毫秒转秒
+func MSToSeconds(ms int64) float64 {
+	return float64(ms) / 1000
+}
+
+func splitMS(ms int64) (h, m, s, milli int64) {
+	if ms < 0 {
+		ms = 0
+	}
+	milli = ms % 1000
+	total := ms / 1000
+	h = total / 3600
+	m = (total % 3600) / 60
+	s = total % 60
+	return
+}
@@ -0,0 +1,98 @@
+package subtitle
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var (
+	vttVoiceTagRe = regexp.MustCompile(`^<v(?:\.[^ >]+)?\s+([^>]+)>`)
+	vttTagRe      = regexp.MustCompile(`</?[a-zA-Z][^>]*>|<\d{2}:[\d:.]+>`)
+)
+
+// ParseVTT 解析 WebVTT 字幕
+// 说话人来自 <v Name> 标签，其余内联标签（含逐字时间戳）会被移除
+func ParseVTT(content string) (*Document, error) {
+	doc := NewDocument("")
+	blocks := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n\n")
+
+	for _, block := range blocks {
+		lines := strings.Split(strings.TrimSpace(block), "\n")
+		if len(lines) == 0 || lines[0] == "" {
+			continue
+		}
+
+		// 跳过文件头、NOTE、STYLE、REGION 块
+		first := strings.TrimSpace(lines[0])
+		if strings.HasPrefix(first, "WEBVTT") || strings.HasPrefix(first, "NOTE") ||
+			strings.HasPrefix(first, "STYLE") || strings.HasPrefix(first, "REGION") {
+			continue
+		}
+
+		// 可选的 cue 标识行
+		timeLine := 0
+		if !strings.Contains(first, "-->") {
+			timeLine = 1
+		}
+		if timeLine >= len(lines) || !strings.Contains(lines[timeLine], "-->") {
+			continue
+		}
+
+		start, end, err := parseArrowLine(strings.TrimSpace(lines[timeLine]))
+		if err != nil {
+			return nil, fmt.Errorf("WebVTT 时间行错误: %w", err)
+		}
+
+		cue := Cue{Index: len(doc.Cues) + 1, Start: start, End: end}
+		var textLines []string
+		for _, line := range lines[timeLine+1:] {
+			line = strings.TrimSpace(line)
+			if m := vttVoiceTagRe.FindStringSubmatch(line); m != nil && cue.Speaker == "" {
+				cue.Speaker = strings.TrimSpace(m[1])
+			}
+			line = strings.TrimSpace(unescapeVTT(vttTagRe.ReplaceAllString(line, "")))
+			if line != "" {
+				textLines = append(textLines, line)
+			}
+		}
+		cue.Text = strings.Join(textLines, "\n")
+		if cue.Text != "" {
+			doc.Cues = append(doc.Cues, cue)
+		}
+	}
+
+	return doc, nil
+}
+
+// FormatVTTDocument 输出 WebVTT 字幕
+func FormatVTTDocument(doc *Document) string {
+	var builder strings.Builder
+	builder.WriteString("WEBVTT\n")
+	if doc.Language != "" {
+		builder.WriteString("Language: " + doc.Language + "\n")
+	}
+	builder.WriteString("\n")
+
+	for i, cue := range doc.Cues {
+		builder.WriteString(fmt.Sprintf("%d\n", i+1))
+		builder.WriteString(FormatVTTTime(cue.Start) + " --> " + FormatVTTTime(cue.End) + "\n")
+		text := escapeVTT(cue.Text)
+		if cue.Speaker != "" {
+			text = "<v " + cue.Speaker + ">" + text
+		}
+		builder.WriteString(text + "\n\n")
+	}
+	return builder.String()
+}
+
+func escapeVTT(text string) string {
+	text = strings.ReplaceAll(text, "&", "&amp;")
+	text = strings.ReplaceAll(text, "<", "&lt;")
+	return strings.ReplaceAll(text, ">", "&gt;")
+}
+
+func unescapeVTT(text string) string {
+	replacer := strings.NewReplacer("&lt;", "<", "&gt;", ">", "&nbsp;", " ", "&amp;", "&")
+	return replacer.Replace(text)
+}
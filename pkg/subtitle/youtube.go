@@ -0,0 +1,182 @@
+package subtitle
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// json3Document YouTube json3 字幕格式（时间单位：毫秒）
+type json3Document struct {
+	WireMagic string       `json:"wireMagic,omitempty"`
+	Events    []json3Event `json:"events"`
+}
+
+type json3Event struct {
+	TStartMs    int64      `json:"tStartMs"`
+	DDurationMs int64      `json:"dDurationMs"`
+	AAppend     int        `json:"aAppend,omitempty"`
+	Segs        []json3Seg `json:"segs,omitempty"`
+}
+
+type json3Seg struct {
+	UTF8      string `json:"utf8"`
+	TOffsetMs int64  `json:"tOffsetMs,omitempty"`
+}
+
+// ParseJSON3 解析 YouTube json3 字幕
+// 自动字幕中只包含换行的追加事件会被忽略
+func ParseJSON3(data []byte) (*Document, error) {
+	var j json3Document
+	if err := json.Unmarshal(data, &j); err != nil {
+		return nil, fmt.Errorf("解析 json3 字幕失败: %w", err)
+	}
+
+	doc := NewDocument("")
+	for _, event := range j.Events {
+		if len(event.Segs) == 0 {
+			continue
+		}
+		var builder strings.Builder
+		for _, seg := range event.Segs {
+			builder.WriteString(seg.UTF8)
+		}
+		text := strings.TrimSpace(builder.String())
+		if text == "" {
+			continue
+		}
+		doc.Add(event.TStartMs, event.TStartMs+event.DDurationMs, text)
+	}
+	return doc, nil
+}
+
+// MarshalJSON3 输出 YouTube json3 字幕
+func MarshalJSON3(doc *Document) ([]byte, error) {
+	j := json3Document{
+		WireMagic: "pb3",
+		Events:    make([]json3Event, 0, len(doc.Cues)),
+	}
+	for _, cue := range doc.Cues {
+		j.Events = append(j.Events, json3Event{
+			TStartMs:    cue.Start,
+			DDurationMs: cue.Duration(),
+			Segs:        []json3Seg{{UTF8: cue.Text}},
+		})
+	}
+	return json.MarshalIndent(j, "", "  ")
+}
+
+// ParseTimedText 解析 YouTube timedtext XML 字幕
+// 支持 srv1（<text start="秒" dur="秒">）和 srv3（<p t="毫秒" d="毫秒">）
+func ParseTimedText(data []byte) (*Document, error) {
+	doc := NewDocument("")
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+
+	var (
+		inCue bool
+		cue   Cue
+		text  strings.Builder
+	)
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("解析 timedtext 字幕失败: %w", err)
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "text", "p":
+				start, end, err := timedTextRange(t)
+				if err != nil {
+					return nil, err
+				}
+				inCue = true
+				cue = Cue{Start: start, End: end}
+				text.Reset()
+			case "br":
+				if inCue {
+					text.WriteString("\n")
+				}
+			}
+		case xml.CharData:
+			if inCue {
+				text.Write(t)
+			}
+		case xml.EndElement:
+			if inCue && (t.Name.Local == "text" || t.Name.Local == "p") {
+				inCue = false
+				// srv1 的文本经过二次 HTML 转义（如 &amp;#39;）
+				cue.Text = strings.TrimSpace(html.UnescapeString(text.String()))
+				if cue.Text != "" {
+					cue.Index = len(doc.Cues) + 1
+					doc.Cues = append(doc.Cues, cue)
+				}
+			}
+		}
+	}
+
+	return doc, nil
+}
+
+// MarshalTimedText 输出 srv1 格式的 timedtext XML
+func MarshalTimedText(doc *Document) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString("<transcript>\n")
+	for _, cue := range doc.Cues {
+		buf.WriteString(fmt.Sprintf(`<text start="%s" dur="%s">`,
+			strconv.FormatFloat(MSToSeconds(cue.Start), 'f', -1, 64),
+			strconv.FormatFloat(MSToSeconds(cue.Duration()), 'f', -1, 64)))
+		// 与 YouTube 一致做二次转义，解析时的 HTML 反转义才不会误伤文本中的 &amp; 等字面内容
+		if err := xml.EscapeText(&buf, []byte(html.EscapeString(cue.Text))); err != nil {
+			return nil, err
+		}
+		buf.WriteString("</text>\n")
+	}
+	buf.WriteString("</transcript>\n")
+	return buf.Bytes(), nil
+}
+
+// timedTextRange 读取 timedtext 元素的起止时间（毫秒）
+func timedTextRange(el xml.StartElement) (int64, int64, error) {
+	var start, dur int64
+	for _, attr := range el.Attr {
+		switch attr.Name.Local {
+		case "start", "dur":
+			// srv1: 秒
+			v, err := strconv.ParseFloat(attr.Value, 64)
+			if err != nil {
+				return 0, 0, fmt.Errorf("无效时间属性 %s=%q", attr.Name.Local, attr.Value)
+			}
+			if attr.Name.Local == "start" {
+				start = SecondsToMS(v)
+			} else {
+				dur = SecondsToMS(v)
+			}
+		case "t", "d":
+			// srv3: 毫秒
+			v, err := strconv.ParseInt(attr.Value, 10, 64)
+			if err != nil {
+				return 0, 0, fmt.Errorf("无效时间属性 %s=%q", attr.Name.Local, attr.Value)
+			}
+			if attr.Name.Local == "t" {
+				start = v
+			} else {
+				dur = v
+			}
+		}
+	}
+	return start, start + dur, nil
+}
@@ -13,6 +13,7 @@ import (
 	"time"
 
 	logger2 "github.com/difyz9/ytb2bili/pkg/logger"
+	"github.com/difyz9/ytb2bili/pkg/ratelimit"
 )
 
 var logger = logger2.GetLogger()
@@ -104,28 +105,15 @@ func (bt *BaiduTranslator) Translate(ctx context.Context, req *TranslationReques
 	params.Set("salt", salt)
 	params.Set("sign", sign)
 
-	// 发起请求
-	resp, err := bt.client.PostForm(bt.endpoint, params)
+	// 发起请求（每个请求单独获取限流许可，批量翻译逐条回退时也不会超出 QPS）
+	permit, err := ratelimit.Acquire(ctx, "baidu", 0)
 	if err != nil {
-		return nil, fmt.Errorf("failed to send request: %v", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
-
-	// 读取响应
-	body, err := io.ReadAll(resp.Body)
+	apiResp, err := bt.post(params)
+	permit.Done(err)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %v", err)
-	}
-
-	// 解析响应
-	var apiResp BaiduTranslationResponse
-	if err := json.Unmarshal(body, &apiResp); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %v", err)
-	}
-
-	// 检查错误
-	if apiResp.ErrorCode != "" && apiResp.ErrorCode != "52000" {
-		return nil, fmt.Errorf("baidu API error: %s - %s", apiResp.ErrorCode, apiResp.ErrorMsg)
+		return nil, err
 	}
 
 	// 检查翻译结果
@@ -153,6 +141,44 @@ func (bt *BaiduTranslator) Translate(ctx context.Context, req *TranslationReques
 	return result, nil
 }
 
+// post 发送翻译请求并解析响应，频率受限和服务端错误附加对应的 HTTP 状态码供熔断器判断
+func (bt *BaiduTranslator) post(params url.Values) (*BaiduTranslationResponse, error) {
+	resp, err := bt.client.PostForm(bt.endpoint, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to send request: %v", err)
+	}
+	defer resp.Body.Close()
+
+	// 读取响应
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+
+	// 解析响应
+	var apiResp BaiduTranslationResponse
+	if err := json.Unmarshal(body, &apiResp); err != nil {
+		return nil, ratelimit.WithStatus(resp.StatusCode, fmt.Errorf("failed to parse response: %v", err))
+	}
+
+	// 检查错误
+	if apiResp.ErrorCode != "" && apiResp.ErrorCode != "52000" {
+		err := fmt.Errorf("baidu API error: %s - %s", apiResp.ErrorCode, apiResp.ErrorMsg)
+		switch apiResp.ErrorCode {
+		case "54003": // 访问频率受限
+			return nil, ratelimit.WithStatus(http.StatusTooManyRequests, err)
+		case "52001", "52002": // 请求超时、系统错误
+			return nil, ratelimit.WithStatus(http.StatusServiceUnavailable, err)
+		}
+		return nil, err
+	}
+
+	return &apiResp, nil
+}
+
+// selfRateLimited 百度翻译在每个 HTTP 请求上获取限流许可，管理器不再按调用获取
+func (bt *BaiduTranslator) selfRateLimited() {}
+
 // BatchTranslate 批量翻译
 func (bt *BaiduTranslator) BatchTranslate(ctx context.Context, req *BatchTranslationRequest) (*BatchTranslationResult, error) {
 	startTime := time.Now()
@@ -0,0 +1,334 @@
+package translator
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/difyz9/ytb2bili/pkg/prompt"
+)
+
+// chatFunc 调用对话补全接口，返回回复内容和 token 用量
+type chatFunc func(ctx context.Context, systemPrompt, userPrompt string) (string, *Usage, error)
+
+// chatTranslator 基于大模型对话接口的通用翻译实现（Ollama、OpenAI 兼容接口共用）
+type chatTranslator struct {
+	provider string
+	model    string
+	chat     chatFunc
+}
+
+// 批量翻译时单次请求的最大条数
+const chatMaxBatchSize = 20
+
+// Translate 单个文本翻译
+func (c *chatTranslator) Translate(ctx context.Context, req *TranslationRequest) (*TranslationResult, error) {
+	if req.Text == "" {
+		return nil, fmt.Errorf("text cannot be empty")
+	}
+
+	startTime := time.Now()
+	content, usage, err := c.chat(ctx, buildChatSystemPrompt(req.SourceLang, req.TargetLang, req.TextType, req.Domain, FilterGlossary(req.Glossary, []string{req.Text})), req.Text)
+	if err != nil {
+		return nil, fmt.Errorf("%s API call failed: %w", c.provider, err)
+	}
+	if usage == nil {
+		usage = &Usage{}
+	}
+	usage.Characters = len(req.Text)
+	usage.Duration = time.Since(startTime).Milliseconds()
+
+	sourceLang := req.SourceLang
+	if sourceLang == "" {
+		sourceLang = "auto"
+	}
+
+	return &TranslationResult{
+		OriginalText:   req.Text,
+		TranslatedText: strings.TrimSpace(content),
+		SourceLang:     sourceLang,
+		TargetLang:     req.TargetLang,
+		Provider:       c.provider,
+		Model:          c.model,
+		Confidence:     0.9,
+		Usage:          usage,
+	}, nil
+}
+
+// BatchTranslate 批量翻译：以 JSON 编号格式组合成一个请求，缺失的编号单独重新请求
+func (c *chatTranslator) BatchTranslate(ctx context.Context, req *BatchTranslationRequest) (*BatchTranslationResult, error) {
+	if len(req.Texts) == 0 {
+		return nil, fmt.Errorf("texts cannot be empty")
+	}
+
+	startTime := time.Now()
+	results := make([]*TranslationResult, 0, len(req.Texts))
+	totalUsage := &Usage{}
+	var promptVersion string
+
+	for i := 0; i < len(req.Texts); i += chatMaxBatchSize {
+		end := i + chatMaxBatchSize
+		if end > len(req.Texts) {
+			end = len(req.Texts)
+		}
+
+		batchResults, version, err := c.translateBatch(ctx, req.Texts[i:end], req)
+		if err != nil {
+			return nil, fmt.Errorf("batch translation failed: %w", err)
+		}
+		promptVersion = version
+		results = append(results, batchResults...)
+
+		for _, result := range batchResults {
+			if result.Usage != nil {
+				totalUsage.InputTokens += result.Usage.InputTokens
+				totalUsage.OutputTokens += result.Usage.OutputTokens
+				totalUsage.TotalTokens += result.Usage.TotalTokens
+				totalUsage.Characters += result.Usage.Characters
+			}
+		}
+	}
+
+	totalUsage.Duration = time.Since(startTime).Milliseconds()
+
+	return &BatchTranslationResult{
+		Results:       results,
+		Provider:      c.provider,
+		Usage:         totalUsage,
+		PromptVersion: promptVersion,
+	}, nil
+}
+
+// translateBatch 以 JSON 编号格式翻译一批文本，多次请求后仍缺失的条目逐条补译，保证译文与原文一一对应
+func (c *chatTranslator) translateBatch(ctx context.Context, texts []string, req *BatchTranslationRequest) ([]*TranslationResult, string, error) {
+	systemPrompt, promptVersion, err := buildBatchSystemPrompt(req, FilterGlossary(req.Glossary, texts))
+	if err != nil {
+		return nil, "", err
+	}
+	translated, usage, missing, err := translateCues(ctx, systemPrompt, texts, c.chat)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s API call failed: %w", c.provider, err)
+	}
+
+	if len(missing) > 0 {
+		logger.Warnf("%s structured translation still missing %d cues, translating them individually", c.provider, len(missing))
+		missingTexts := make([]string, len(missing))
+		for j, idx := range missing {
+			missingTexts[j] = texts[idx]
+		}
+		individual, err := c.translateIndividually(ctx, missingTexts, req)
+		if err != nil {
+			return nil, "", err
+		}
+		for j, idx := range missing {
+			translated[idx] = individual[j].TranslatedText
+			usage = addUsage(usage, individual[j].Usage)
+		}
+	}
+
+	perItem := splitUsage(usage, len(texts))
+	results := make([]*TranslationResult, len(texts))
+	for i, text := range texts {
+		itemUsage := *perItem
+		itemUsage.Characters = len(text)
+		results[i] = &TranslationResult{
+			OriginalText:   text,
+			TranslatedText: translated[i],
+			SourceLang:     req.SourceLang,
+			TargetLang:     req.TargetLang,
+			Provider:       c.provider,
+			Model:          c.model,
+			Confidence:     0.9,
+			Usage:          &itemUsage,
+		}
+	}
+	return results, promptVersion, nil
+}
+
+func (c *chatTranslator) translateIndividually(ctx context.Context, texts []string, req *BatchTranslationRequest) ([]*TranslationResult, error) {
+	results := make([]*TranslationResult, len(texts))
+	for i, text := range texts {
+		result, err := c.Translate(ctx, &TranslationRequest{
+			Text:       text,
+			SourceLang: req.SourceLang,
+			TargetLang: req.TargetLang,
+			TextType:   req.TextType,
+			Domain:     req.Domain,
+			Glossary:   req.Glossary,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to translate text %d: %w", i+1, err)
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+// DetectLanguage 检测语言
+func (c *chatTranslator) DetectLanguage(ctx context.Context, text string) (string, float64, error) {
+	if text == "" {
+		return "", 0, fmt.Errorf("text cannot be empty")
+	}
+
+	systemPrompt := "你是一个语言检测专家。请检测给定文本的语言，并返回ISO 639-1语言代码（如'en'、'zh'、'ja'等）。只返回语言代码，不要其他说明。"
+	content, _, err := c.chat(ctx, systemPrompt, text)
+	if err != nil {
+		return "", 0, fmt.Errorf("language detection failed: %w", err)
+	}
+
+	langCode := strings.Trim(strings.ToLower(strings.TrimSpace(content)), "'\"`.")
+	if len(langCode) < 2 || len(langCode) > 5 {
+		langCode = "auto"
+	}
+	return langCode, 0.8, nil
+}
+
+// SuggestTerms 从文本中提取候选术语
+func (c *chatTranslator) SuggestTerms(ctx context.Context, text, targetLang string) ([]SuggestedTerm, error) {
+	content, _, err := c.chat(ctx, buildSuggestTermsPrompt(targetLang), text)
+	if err != nil {
+		return nil, fmt.Errorf("%s API call failed: %w", c.provider, err)
+	}
+	return parseSuggestedTerms(content)
+}
+
+// GetSupportedLanguages 获取支持的语言列表（大模型没有固定列表，返回常用语言）
+func (c *chatTranslator) GetSupportedLanguages(ctx context.Context) ([]LanguageInfo, error) {
+	languages := make([]LanguageInfo, 0, len(commonLanguageCodes))
+	for _, code := range commonLanguageCodes {
+		languages = append(languages, LanguageInfo{
+			Code:        code,
+			Name:        commonLanguageNames[code],
+			NativeName:  commonLanguageNames[code],
+			Direction:   languageDirection(code),
+			IsSupported: true,
+		})
+	}
+	return languages, nil
+}
+
+// IsHealthy 健康检查：发送一个简短的翻译请求
+func (c *chatTranslator) IsHealthy(ctx context.Context) error {
+	if _, err := c.Translate(ctx, &TranslationRequest{Text: "Hello", SourceLang: "en", TargetLang: "zh"}); err != nil {
+		return fmt.Errorf("%s health check failed: %w", c.provider, err)
+	}
+	return nil
+}
+
+// 常用语言（按列表顺序展示）
+var commonLanguageCodes = []string{"zh", "zh-tw", "en", "ja", "ko", "es", "fr", "de", "ru", "it", "pt", "ar", "hi", "th", "vi"}
+
+var commonLanguageNames = map[string]string{
+	"zh":    "简体中文",
+	"zh-cn": "简体中文",
+	"zh-tw": "繁体中文",
+	"en":    "英语",
+	"ja":    "日语",
+	"ko":    "韩语",
+	"es":    "西班牙语",
+	"fr":    "法语",
+	"de":    "德语",
+	"ru":    "俄语",
+	"it":    "意大利语",
+	"pt":    "葡萄牙语",
+	"ar":    "阿拉伯语",
+	"hi":    "印地语",
+	"th":    "泰语",
+	"vi":    "越南语",
+}
+
+// LanguageName 语言代码对应的中文名称，未知代码原样返回
+func LanguageName(code string) string {
+	if name, ok := commonLanguageNames[strings.ToLower(code)]; ok {
+		return name
+	}
+	return code
+}
+
+func languageDirection(code string) string {
+	switch strings.ToLower(code) {
+	case "ar", "he", "fa", "ur":
+		return "rtl"
+	}
+	return "ltr"
+}
+
+func buildChatSystemPrompt(sourceLang, targetLang, textType, domain string, glossary []GlossaryTerm) string {
+	var prompt strings.Builder
+	prompt.WriteString("你是一位专业的翻译专家。请将给定的文本进行准确、自然的翻译。\n\n")
+	prompt.WriteString("翻译要求：\n")
+	prompt.WriteString("1. 保持原文的意思和语调\n")
+	prompt.WriteString("2. 使用自然流畅的目标语言表达\n")
+	prompt.WriteString("3. 对于专业术语，使用准确的对应词汇\n")
+	writeLanguageHints(&prompt, sourceLang, targetLang, textType, domain)
+	writeGlossaryPrompt(&prompt, glossary)
+	prompt.WriteString("\n请直接返回翻译结果，不要包含任何解释或其他内容。")
+	return prompt.String()
+}
+
+// buildBatchSystemPrompt 使用翻译提示词模板构建批量翻译系统提示词并追加结构化格式说明，返回提示词和模板版本
+func buildBatchSystemPrompt(req *BatchTranslationRequest, glossary []GlossaryTerm) (string, string, error) {
+	data := &prompt.Data{
+		TextType:    req.TextType,
+		Domain:      req.Domain,
+		SourceTitle: req.VideoTitle,
+		Channel:     req.Channel,
+		Glossary:    GlossaryPrompt(glossary),
+	}
+	if req.SourceLang != "" && req.SourceLang != "auto" {
+		data.SourceLanguage = LanguageName(req.SourceLang)
+	}
+	if req.TargetLang != "" {
+		data.TargetLanguage = LanguageName(req.TargetLang)
+	}
+
+	rendered, err := prompt.Render(prompt.Translation, data)
+	if err != nil {
+		return "", "", err
+	}
+	return rendered.System + "\n" + CueFormatPrompt(), rendered.Version, nil
+}
+
+func writeLanguageHints(prompt *strings.Builder, sourceLang, targetLang, textType, domain string) {
+	if sourceLang != "" && sourceLang != "auto" {
+		prompt.WriteString(fmt.Sprintf("- 源语言：%s\n", LanguageName(sourceLang)))
+	}
+	if targetLang != "" {
+		prompt.WriteString(fmt.Sprintf("- 目标语言：%s\n", LanguageName(targetLang)))
+	}
+	if textType != "" {
+		prompt.WriteString(fmt.Sprintf("- 文本类型：%s\n", textType))
+	}
+	if domain != "" {
+		prompt.WriteString(fmt.Sprintf("- 领域：%s\n", domain))
+	}
+}
+
+// addUsage 累加 token 用量（逐条补译的用量计入批量请求的总用量）
+func addUsage(total, usage *Usage) *Usage {
+	if total == nil {
+		total = &Usage{}
+	}
+	if usage != nil {
+		total.InputTokens += usage.InputTokens
+		total.OutputTokens += usage.OutputTokens
+		total.TotalTokens += usage.TotalTokens
+	}
+	return total
+}
+
+// splitUsage 将一次请求的 token 用量平均分配到每条文本
+func splitUsage(usage *Usage, count int) *Usage {
+	if usage == nil {
+		return &Usage{}
+	}
+	if count <= 0 {
+		count = 1
+	}
+	return &Usage{
+		InputTokens:  usage.InputTokens / count,
+		OutputTokens: usage.OutputTokens / count,
+		TotalTokens:  usage.TotalTokens / count,
+	}
+}
@@ -2,6 +2,7 @@ package translator
 
 import (
 	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/ratelimit"
 	"bytes"
 	"context"
 	"encoding/json"
@@ -115,7 +116,7 @@ func (d *DeepSeekTranslator) Translate(ctx context.Context, req *TranslationRequ
 	startTime := time.Now()
 
 	// 构建翻译提示词
-	systemPrompt := d.buildSystemPrompt(req.SourceLang, req.TargetLang, req.TextType, req.Domain)
+	systemPrompt := d.buildSystemPrompt(req.SourceLang, req.TargetLang, req.TextType, req.Domain, FilterGlossary(req.Glossary, []string{req.Text}))
 	userPrompt := req.Text
 
 	// 调用DeepSeek API
@@ -162,6 +163,7 @@ func (d *DeepSeekTranslator) BatchTranslate(ctx context.Context, req *BatchTrans
 	startTime := time.Now()
 	results := make([]*TranslationResult, 0, len(req.Texts))
 	totalUsage := &Usage{}
+	var promptVersion string
 
 	// DeepSeek支持批量处理，我们将多个文本组合到一个请求中
 	// 如果文本数量太多，分批处理
@@ -174,19 +176,18 @@ func (d *DeepSeekTranslator) BatchTranslate(ctx context.Context, req *BatchTrans
 		}
 
 		batchTexts := req.Texts[i:end]
-		batchResults, err := d.translateBatch(ctx, batchTexts, req.SourceLang, req.TargetLang, req.TextType, req.Domain)
+		batchResults, batchUsage, version, err := d.translateBatch(ctx, batchTexts, req)
 		if err != nil {
 			return nil, fmt.Errorf("batch translation failed: %w", err)
 		}
+		promptVersion = version
 
 		results = append(results, batchResults...)
 
-		// 累加使用统计
+		// 累加使用统计（按整批请求的实际用量，避免平均分配时的取整误差）
+		addUsage(totalUsage, batchUsage)
 		for _, result := range batchResults {
 			if result.Usage != nil {
-				totalUsage.InputTokens += result.Usage.InputTokens
-				totalUsage.OutputTokens += result.Usage.OutputTokens
-				totalUsage.TotalTokens += result.Usage.TotalTokens
 				totalUsage.Characters += result.Usage.Characters
 			}
 		}
@@ -195,42 +196,47 @@ func (d *DeepSeekTranslator) BatchTranslate(ctx context.Context, req *BatchTrans
 	totalUsage.Duration = time.Since(startTime).Milliseconds()
 
 	return &BatchTranslationResult{
-		Results:  results,
-		Provider: "deepseek",
-		Usage:    totalUsage,
+		Results:       results,
+		Provider:      "deepseek",
+		Usage:         totalUsage,
+		PromptVersion: promptVersion,
 	}, nil
 }
 
-// translateBatch 翻译一批文本
-func (d *DeepSeekTranslator) translateBatch(ctx context.Context, texts []string, sourceLang, targetLang, textType, domain string) ([]*TranslationResult, error) {
-	// 构建批量翻译提示词
-	systemPrompt := d.buildBatchSystemPrompt(sourceLang, targetLang, textType, domain)
+// translateBatch 以 JSON 编号格式翻译一批文本，多次请求后仍缺失的条目逐条补译，保证译文与原文一一对应
+func (d *DeepSeekTranslator) translateBatch(ctx context.Context, texts []string, req *BatchTranslationRequest) ([]*TranslationResult, *Usage, string, error) {
+	sourceLang, targetLang := req.SourceLang, req.TargetLang
 
-	// 将文本组合成编号格式
-	var userPrompt strings.Builder
-	userPrompt.WriteString("请翻译以下文本，保持原有的编号格式：\n\n")
-	for i, text := range texts {
-		userPrompt.WriteString(fmt.Sprintf("%d. %s\n", i+1, text))
+	// 构建批量翻译提示词
+	systemPrompt, promptVersion, err := buildBatchSystemPrompt(req, FilterGlossary(req.Glossary, texts))
+	if err != nil {
+		return nil, nil, "", err
 	}
 
-	// 调用DeepSeek API
-	response, err := d.callDeepSeekAPI(ctx, systemPrompt, userPrompt.String())
+	translatedTexts, usage, missing, err := translateCues(ctx, systemPrompt, texts, d.chat)
 	if err != nil {
-		return nil, err
+		return nil, nil, "", err
 	}
 
-	// 解析批量翻译结果
-	translatedTexts := d.parseBatchResponse(response.Choices[0].Message.Content, len(texts))
-
-	// 确保翻译结果数量匹配
-	if len(translatedTexts) != len(texts) {
-		// 如果批量翻译失败，降级为逐个翻译
-		return d.fallbackToIndividualTranslation(ctx, texts, sourceLang, targetLang, textType, domain)
+	// 多次请求后仍缺失的编号降级为逐个翻译
+	if len(missing) > 0 {
+		missingTexts := make([]string, len(missing))
+		for j, idx := range missing {
+			missingTexts[j] = texts[idx]
+		}
+		individual, err := d.fallbackToIndividualTranslation(ctx, missingTexts, sourceLang, targetLang, req.TextType, req.Domain, req.Glossary)
+		if err != nil {
+			return nil, nil, "", err
+		}
+		for j, idx := range missing {
+			translatedTexts[idx] = individual[j].TranslatedText
+			usage = addUsage(usage, individual[j].Usage)
+		}
 	}
 
 	// 构建结果
 	results := make([]*TranslationResult, len(texts))
-	avgUsage := d.distributeUsage(response.Usage, len(texts))
+	avgUsage := splitUsage(usage, len(texts))
 
 	for i, text := range texts {
 		results[i] = &TranslationResult{
@@ -245,11 +251,24 @@ func (d *DeepSeekTranslator) translateBatch(ctx context.Context, texts []string,
 		}
 	}
 
-	return results, nil
+	return results, usage, promptVersion, nil
+}
+
+// chat 调用 DeepSeek 对话接口，返回回复内容和 token 用量（供结构化批量翻译使用）
+func (d *DeepSeekTranslator) chat(ctx context.Context, systemPrompt, userPrompt string) (string, *Usage, error) {
+	response, err := d.callDeepSeekAPI(ctx, systemPrompt, userPrompt)
+	if err != nil {
+		return "", nil, err
+	}
+	return response.Choices[0].Message.Content, &Usage{
+		InputTokens:  response.Usage.PromptTokens,
+		OutputTokens: response.Usage.CompletionTokens,
+		TotalTokens:  response.Usage.TotalTokens,
+	}, nil
 }
 
 // fallbackToIndividualTranslation 降级为逐个翻译
-func (d *DeepSeekTranslator) fallbackToIndividualTranslation(ctx context.Context, texts []string, sourceLang, targetLang, textType, domain string) ([]*TranslationResult, error) {
+func (d *DeepSeekTranslator) fallbackToIndividualTranslation(ctx context.Context, texts []string, sourceLang, targetLang, textType, domain string, glossary []GlossaryTerm) ([]*TranslationResult, error) {
 	results := make([]*TranslationResult, len(texts))
 
 	for i, text := range texts {
@@ -259,6 +278,7 @@ func (d *DeepSeekTranslator) fallbackToIndividualTranslation(ctx context.Context
 			TargetLang: targetLang,
 			TextType:   textType,
 			Domain:     domain,
+			Glossary:   glossary,
 		}
 
 		result, err := d.Translate(ctx, req)
@@ -320,6 +340,15 @@ func (d *DeepSeekTranslator) DetectLanguage(ctx context.Context, text string) (s
 	return langCode, 0.9, nil
 }
 
+// SuggestTerms 从文本中提取候选术语
+func (d *DeepSeekTranslator) SuggestTerms(ctx context.Context, text, targetLang string) ([]SuggestedTerm, error) {
+	response, err := d.callDeepSeekAPI(ctx, buildSuggestTermsPrompt(targetLang), text)
+	if err != nil {
+		return nil, fmt.Errorf("deepseek API call failed: %w", err)
+	}
+	return parseSuggestedTerms(response.Choices[0].Message.Content)
+}
+
 // GetInfo 获取翻译器信息
 func (d *DeepSeekTranslator) GetInfo() *TranslatorInfo {
 	return &TranslatorInfo{
@@ -386,7 +415,18 @@ func (d *DeepSeekTranslator) callDeepSeekAPI(ctx context.Context, systemPrompt,
 	httpReq.Header.Set("Content-Type", "application/json")
 	httpReq.Header.Set("Authorization", "Bearer "+d.apiKey)
 
-	// 发送请求
+	// 发送请求（每个请求单独获取限流许可，结构化重试和逐条补译也计入限额）
+	permit, err := ratelimit.Acquire(ctx, "deepseek", ratelimit.EstimateTokens(systemPrompt, userPrompt))
+	if err != nil {
+		return nil, err
+	}
+	response, err := d.send(httpReq)
+	permit.Done(err)
+	return response, err
+}
+
+// send 发送请求并解析响应，非 200 状态码附带给限流器用于退避
+func (d *DeepSeekTranslator) send(httpReq *http.Request) (*DeepSeekResponse, error) {
 	resp, err := d.client.Do(httpReq)
 	if err != nil {
 		return nil, fmt.Errorf("failed to send request: %w", err)
@@ -394,7 +434,7 @@ func (d *DeepSeekTranslator) callDeepSeekAPI(ctx context.Context, systemPrompt,
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("deepseek API returned status %d", resp.StatusCode)
+		return nil, ratelimit.WithStatus(resp.StatusCode, fmt.Errorf("deepseek API returned status %d", resp.StatusCode))
 	}
 
 	// 解析响应
@@ -410,8 +450,11 @@ func (d *DeepSeekTranslator) callDeepSeekAPI(ctx context.Context, systemPrompt,
 	return &response, nil
 }
 
+// selfRateLimited DeepSeek 一次批量翻译会发出多个请求，在每个 HTTP 请求上获取限流许可，管理器不再按调用获取
+func (d *DeepSeekTranslator) selfRateLimited() {}
+
 // buildSystemPrompt 构建系统提示词
-func (d *DeepSeekTranslator) buildSystemPrompt(sourceLang, targetLang, textType, domain string) string {
+func (d *DeepSeekTranslator) buildSystemPrompt(sourceLang, targetLang, textType, domain string, glossary []GlossaryTerm) string {
 	var prompt strings.Builder
 
 	prompt.WriteString("你是一位专业的翻译专家。请将给定的文本进行准确、自然的翻译。\n\n")
@@ -437,37 +480,9 @@ func (d *DeepSeekTranslator) buildSystemPrompt(sourceLang, targetLang, textType,
 		prompt.WriteString(fmt.Sprintf("8. 领域：%s\n", domain))
 	}
 
-	prompt.WriteString("\n请直接返回翻译结果，不要包含任何解释或其他内容。")
-
-	return prompt.String()
-}
-
-// buildBatchSystemPrompt 构建批量翻译系统提示词
-func (d *DeepSeekTranslator) buildBatchSystemPrompt(sourceLang, targetLang, textType, domain string) string {
-	var prompt strings.Builder
-
-	prompt.WriteString("你是一位专业的翻译专家。请将以下编号的文本逐条翻译，保持相同的编号格式。\n\n")
-	prompt.WriteString("翻译要求：\n")
-	prompt.WriteString("1. 保持原文的意思和语调\n")
-	prompt.WriteString("2. 使用自然流畅的目标语言表达\n")
-	prompt.WriteString("3. 保留编号格式：1. 翻译内容\n")
-	prompt.WriteString("4. 每个编号对应一行翻译结果\n")
-
-	if sourceLang != "" && sourceLang != "auto" {
-		prompt.WriteString(fmt.Sprintf("5. 源语言：%s\n", d.getLanguageName(sourceLang)))
-	}
-
-	if targetLang != "" {
-		prompt.WriteString(fmt.Sprintf("6. 目标语言：%s\n", d.getLanguageName(targetLang)))
-	}
-
-	if textType != "" {
-		prompt.WriteString(fmt.Sprintf("7. 文本类型：%s\n", textType))
-	}
+	writeGlossaryPrompt(&prompt, glossary)
 
-	if domain != "" {
-		prompt.WriteString(fmt.Sprintf("8. 领域：%s\n", domain))
-	}
+	prompt.WriteString("\n请直接返回翻译结果，不要包含任何解释或其他内容。")
 
 	return prompt.String()
 }
@@ -498,40 +513,3 @@ func (d *DeepSeekTranslator) getLanguageName(code string) string {
 	}
 	return code
 }
-
-// parseBatchResponse 解析批量翻译响应
-func (d *DeepSeekTranslator) parseBatchResponse(response string, expectedCount int) []string {
-	lines := strings.Split(strings.TrimSpace(response), "\n")
-	results := make([]string, 0, expectedCount)
-
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" {
-			continue
-		}
-
-		// 尝试匹配编号格式：1. 内容
-		parts := strings.SplitN(line, ". ", 2)
-		if len(parts) == 2 {
-			results = append(results, strings.TrimSpace(parts[1]))
-		} else {
-			// 如果没有编号，直接添加内容
-			results = append(results, line)
-		}
-	}
-
-	return results
-}
-
-// distributeUsage 分配使用统计
-func (d *DeepSeekTranslator) distributeUsage(usage DeepSeekUsage, count int) *Usage {
-	if count <= 0 {
-		count = 1
-	}
-
-	return &Usage{
-		InputTokens:  usage.PromptTokens / count,
-		OutputTokens: usage.CompletionTokens / count,
-		TotalTokens:  usage.TotalTokens / count,
-	}
-}
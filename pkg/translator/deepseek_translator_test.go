@@ -0,0 +1,65 @@
+package translator
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/ratelimit"
+)
+
+// DeepSeek 每个请求自行获取限流许可，管理器不再按调用获取
+var _ selfRateLimited = (*DeepSeekTranslator)(nil)
+
+func newTestDeepSeekTranslator(t *testing.T, endpoint string) *DeepSeekTranslator {
+	t.Helper()
+	tr, err := NewDeepSeekTranslator(&types.DeepSeekTransConfig{
+		Enabled:  true,
+		ApiKey:   "sk-test",
+		Endpoint: endpoint,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tr
+}
+
+// 结构化请求遗漏的条目逐条补译，补译请求的 token 用量也计入总用量
+func TestDeepSeekBatchTranslateCountsFallbackUsage(t *testing.T) {
+	server := newFakeServer(t, openAIHandler(map[int]bool{2: true}))
+	tr := newTestDeepSeekTranslator(t, server.URL)
+
+	result, err := tr.BatchTranslate(context.Background(), &BatchTranslationRequest{
+		Texts:      []string{"hello", "world", "again"},
+		TargetLang: "zh",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Results[1].TranslatedText != "译:world" {
+		t.Fatalf("missing cue was not translated individually: %+v", result.Results[1])
+	}
+
+	requests := 1 + maxCueRetries + 1
+	if server.requestCount() != requests {
+		t.Fatalf("expected %d requests, got %d", requests, server.requestCount())
+	}
+	if want := requests * 16; result.Usage.TotalTokens != want {
+		t.Errorf("total tokens = %d, want %d", result.Usage.TotalTokens, want)
+	}
+}
+
+// 非 200 响应带上状态码，限流器据此退避和熔断
+func TestDeepSeekErrorStatus(t *testing.T) {
+	server := newFakeServer(t, statusHandler(http.StatusTooManyRequests, `{"error":{"message":"rate limited"}}`))
+	tr := newTestDeepSeekTranslator(t, server.URL)
+
+	_, err := tr.Translate(context.Background(), &TranslationRequest{Text: "hello", TargetLang: "zh"})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if got := ratelimit.StatusCode(err); got != http.StatusTooManyRequests {
+		t.Errorf("status code = %d, want 429 (%v)", got, err)
+	}
+}
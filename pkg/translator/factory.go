@@ -25,6 +25,16 @@ func (f *Factory) CreateTranslator(provider string, config map[string]interface{
 		return f.createBaiduTranslator(config)
 	case "deepseek":
 		return f.createDeepSeekTranslator(config)
+	case "ollama":
+		return f.createOllamaTranslator(config)
+	case "openai":
+		return f.createOpenAITranslator(config)
+	case "google":
+		return f.createGoogleTranslator(config)
+	case "microsoft":
+		return f.createMicrosoftTranslator(config)
+	case "tencent":
+		return f.createTencentTranslator(config)
 	default:
 		return nil, fmt.Errorf("unsupported translator provider: %s", provider)
 	}
@@ -33,13 +43,13 @@ func (f *Factory) CreateTranslator(provider string, config map[string]interface{
 // GetSupportedProviders 获取支持的提供商列表
 func (f *Factory) GetSupportedProviders() []string {
 	return []string{
-		"tencent",
-		"microsoft",
-		"alibaba",
+		"deepseek",
+		"openai",
 		"ollama",
 		"google",
+		"microsoft",
+		"tencent",
 		"baidu",
-		"deepseek",
 	}
 }
 
@@ -101,3 +111,111 @@ func (f *Factory) createDeepSeekTranslator(config map[string]interface{}) (Trans
 
 	return NewDeepSeekTranslator(&configCopy)
 }
+
+// createOllamaTranslator 创建 Ollama 本地大模型翻译器
+func (f *Factory) createOllamaTranslator(config map[string]interface{}) (Translator, error) {
+	ollamaConfig := f.config.OllamaTransConfig
+	if ollamaConfig == nil || !ollamaConfig.Enabled {
+		return nil, fmt.Errorf("ollama translator not enabled or config not found")
+	}
+
+	configCopy := *ollamaConfig
+	if endpoint, ok := config["endpoint"].(string); ok && endpoint != "" {
+		configCopy.Endpoint = endpoint
+	}
+	if model, ok := config["model"].(string); ok && model != "" {
+		configCopy.Model = model
+	}
+	if timeout, ok := config["timeout"].(int); ok && timeout > 0 {
+		configCopy.Timeout = timeout
+	}
+
+	return NewOllamaTranslator(&configCopy)
+}
+
+// createOpenAITranslator 创建 OpenAI 兼容接口翻译器（使用 OpenAICompatibleConfig）
+func (f *Factory) createOpenAITranslator(config map[string]interface{}) (Translator, error) {
+	openaiConfig := f.config.OpenAICompatibleConfig
+	if openaiConfig == nil || !openaiConfig.Enabled {
+		return nil, fmt.Errorf("openai compatible translator not enabled or config not found")
+	}
+
+	configCopy := *openaiConfig
+	if apiKey, ok := config["api_key"].(string); ok && apiKey != "" {
+		configCopy.APIKey = apiKey
+	}
+	if baseURL, ok := config["base_url"].(string); ok && baseURL != "" {
+		configCopy.BaseURL = baseURL
+	}
+	if model, ok := config["model"].(string); ok && model != "" {
+		configCopy.Model = model
+	}
+	if timeout, ok := config["timeout"].(int); ok && timeout > 0 {
+		configCopy.Timeout = timeout
+	}
+
+	return NewOpenAITranslator(&configCopy)
+}
+
+// createGoogleTranslator 创建 Google Cloud Translation 翻译器
+func (f *Factory) createGoogleTranslator(config map[string]interface{}) (Translator, error) {
+	googleConfig := f.config.GoogleTransConfig
+	if googleConfig == nil || !googleConfig.Enabled {
+		return nil, fmt.Errorf("google translator not enabled or config not found")
+	}
+
+	configCopy := *googleConfig
+	if apiKey, ok := config["api_key"].(string); ok && apiKey != "" {
+		configCopy.ApiKey = apiKey
+	}
+	if endpoint, ok := config["endpoint"].(string); ok && endpoint != "" {
+		configCopy.Endpoint = endpoint
+	}
+
+	return NewGoogleTranslator(&configCopy)
+}
+
+// createMicrosoftTranslator 创建 Microsoft Translator 翻译器
+func (f *Factory) createMicrosoftTranslator(config map[string]interface{}) (Translator, error) {
+	microsoftConfig := f.config.MicrosoftTransConfig
+	if microsoftConfig == nil || !microsoftConfig.Enabled {
+		return nil, fmt.Errorf("microsoft translator not enabled or config not found")
+	}
+
+	configCopy := *microsoftConfig
+	if apiKey, ok := config["api_key"].(string); ok && apiKey != "" {
+		configCopy.ApiKey = apiKey
+	}
+	if region, ok := config["region"].(string); ok && region != "" {
+		configCopy.Region = region
+	}
+	if endpoint, ok := config["endpoint"].(string); ok && endpoint != "" {
+		configCopy.Endpoint = endpoint
+	}
+
+	return NewMicrosoftTranslator(&configCopy)
+}
+
+// createTencentTranslator 创建腾讯云机器翻译翻译器
+func (f *Factory) createTencentTranslator(config map[string]interface{}) (Translator, error) {
+	tencentConfig := f.config.TencentTransConfig
+	if tencentConfig == nil || !tencentConfig.Enabled {
+		return nil, fmt.Errorf("tencent translator not enabled or config not found")
+	}
+
+	configCopy := *tencentConfig
+	if secretId, ok := config["secret_id"].(string); ok && secretId != "" {
+		configCopy.SecretId = secretId
+	}
+	if secretKey, ok := config["secret_key"].(string); ok && secretKey != "" {
+		configCopy.SecretKey = secretKey
+	}
+	if region, ok := config["region"].(string); ok && region != "" {
+		configCopy.Region = region
+	}
+	if endpoint, ok := config["endpoint"].(string); ok && endpoint != "" {
+		configCopy.Endpoint = endpoint
+	}
+
+	return NewTencentTranslator(&configCopy)
+}
@@ -0,0 +1,101 @@
+package translator
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+)
+
+func TestFactoryCreateTranslator(t *testing.T) {
+	factory := NewTranslatorFactory(&types.AppConfig{
+		OllamaTransConfig:      &types.OllamaTransConfig{Enabled: true, Model: "qwen2.5:7b"},
+		OpenAICompatibleConfig: &types.OpenAICompatibleConfig{Enabled: true, APIKey: "sk-config"},
+		GoogleTransConfig:      &types.GoogleTransConfig{Enabled: true, ApiKey: "google-key"},
+		MicrosoftTransConfig:   &types.MicrosoftTransConfig{Enabled: true, ApiKey: "ms-key"},
+		TencentTransConfig:     &types.TencentTransConfig{Enabled: true, SecretId: "id", SecretKey: "key"},
+	})
+
+	cases := map[string]string{
+		"ollama":    "ollama",
+		"openai":    "openai",
+		"google":    "google",
+		"microsoft": "microsoft",
+		"tencent":   "tencent",
+	}
+	for provider, want := range cases {
+		tr, err := factory.CreateTranslator(provider, nil)
+		if err != nil {
+			t.Errorf("CreateTranslator(%s): %v", provider, err)
+			continue
+		}
+		if got := tr.GetInfo().Provider; got != want {
+			t.Errorf("CreateTranslator(%s) provider = %s, want %s", provider, got, want)
+		}
+	}
+}
+
+func TestFactoryDisabledAndUnsupported(t *testing.T) {
+	factory := NewTranslatorFactory(&types.AppConfig{
+		GoogleTransConfig: &types.GoogleTransConfig{Enabled: false, ApiKey: "google-key"},
+	})
+
+	for _, provider := range []string{"google", "microsoft", "tencent", "ollama", "openai"} {
+		if _, err := factory.CreateTranslator(provider, nil); err == nil {
+			t.Errorf("CreateTranslator(%s) should fail when disabled or missing", provider)
+		}
+	}
+	if _, err := factory.CreateTranslator("yandex", nil); err == nil {
+		t.Error("unsupported provider should fail")
+	}
+}
+
+// 传入的配置覆盖应用配置，且不修改应用配置本身
+func TestFactoryOverrides(t *testing.T) {
+	server := newFakeServer(t, openAIHandler(nil))
+	appConfig := &types.AppConfig{
+		OpenAICompatibleConfig: &types.OpenAICompatibleConfig{Enabled: true, APIKey: "sk-config", BaseURL: "http://127.0.0.1:1", Model: "gpt-4o-mini"},
+		MicrosoftTransConfig:   &types.MicrosoftTransConfig{Enabled: true, ApiKey: "ms-config", Region: "westus"},
+	}
+	factory := NewTranslatorFactory(appConfig)
+
+	tr, err := factory.CreateTranslator("openai", map[string]interface{}{
+		"api_key":  "sk-override",
+		"base_url": server.URL,
+		"model":    "deepseek-chat",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	result, err := tr.Translate(context.Background(), &TranslationRequest{Text: "hello", TargetLang: "zh"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := server.lastRequest(t).Header.Get("Authorization"); got != "Bearer sk-override" {
+		t.Errorf("authorization = %q", got)
+	}
+	if result.Model != "deepseek-chat" {
+		t.Errorf("model = %q", result.Model)
+	}
+	if appConfig.OpenAICompatibleConfig.APIKey != "sk-config" || appConfig.OpenAICompatibleConfig.Model != "gpt-4o-mini" {
+		t.Error("overrides must not modify the app config")
+	}
+
+	msServer := newFakeServer(t, func(w http.ResponseWriter, r *http.Request, body []byte) {
+		writeJSON(w, http.StatusOK, []map[string]interface{}{
+			{"translations": []map[string]string{{"text": "你好", "to": "zh-Hans"}}},
+		})
+	})
+	tr, err = factory.CreateTranslator("microsoft", map[string]interface{}{"region": "eastasia", "endpoint": msServer.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tr.Translate(context.Background(), &TranslationRequest{Text: "hello", TargetLang: "zh"}); err != nil {
+		t.Fatal(err)
+	}
+	req := msServer.lastRequest(t)
+	if req.Header.Get("Ocp-Apim-Subscription-Region") != "eastasia" || req.Header.Get("Ocp-Apim-Subscription-Key") != "ms-config" {
+		t.Errorf("unexpected headers: %v", req.Header)
+	}
+}
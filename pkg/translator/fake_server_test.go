@@ -0,0 +1,81 @@
+package translator
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// recordedRequest 假服务器收到的请求
+type recordedRequest struct {
+	Method string
+	Path   string
+	Query  map[string][]string
+	Header http.Header
+	Body   []byte
+}
+
+// fakeServer 记录收到的请求并按 handler 返回响应的本地 HTTP 服务器
+type fakeServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	requests []recordedRequest
+}
+
+// newFakeServer 启动假服务器，handler 收到已读取的请求体
+func newFakeServer(t *testing.T, handler func(w http.ResponseWriter, r *http.Request, body []byte)) *fakeServer {
+	t.Helper()
+	fs := &fakeServer{}
+	fs.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		fs.mu.Lock()
+		fs.requests = append(fs.requests, recordedRequest{
+			Method: r.Method,
+			Path:   r.URL.Path,
+			Query:  r.URL.Query(),
+			Header: r.Header.Clone(),
+			Body:   body,
+		})
+		fs.mu.Unlock()
+		handler(w, r, body)
+	}))
+	t.Cleanup(fs.Close)
+	return fs
+}
+
+// lastRequest 最后一次收到的请求
+func (fs *fakeServer) lastRequest(t *testing.T) recordedRequest {
+	t.Helper()
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if len(fs.requests) == 0 {
+		t.Fatal("fake server received no requests")
+	}
+	return fs.requests[len(fs.requests)-1]
+}
+
+// requestCount 收到的请求数
+func (fs *fakeServer) requestCount() int {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return len(fs.requests)
+}
+
+// writeJSON 以指定状态码返回 JSON
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// statusHandler 总是返回指定状态码和响应体
+func statusHandler(status int, body string) func(w http.ResponseWriter, r *http.Request, body []byte) {
+	return func(w http.ResponseWriter, r *http.Request, _ []byte) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		io.WriteString(w, body)
+	}
+}
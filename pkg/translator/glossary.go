@@ -0,0 +1,253 @@
+package translator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// GlossaryTerm 术语表条目：原文术语（及其其他形式）对应的固定译法
+type GlossaryTerm struct {
+	Source         string   `json:"source"`                   // 原文术语
+	Target         string   `json:"target,omitempty"`         // 固定译法（DoNotTranslate 时忽略）
+	Forms          []string `json:"forms,omitempty"`          // 原文的其他形式（复数、缩写、不同写法等）
+	CaseSensitive  bool     `json:"caseSensitive,omitempty"`  // 原文匹配是否区分大小写
+	DoNotTranslate bool     `json:"doNotTranslate,omitempty"` // 保留原文不翻译（人名、品牌等）
+}
+
+// SuggestedTerm 大模型从文本中提取的候选术语，需人工审核后才会生效
+type SuggestedTerm struct {
+	GlossaryTerm
+	Note string `json:"note,omitempty"` // 建议理由
+}
+
+// TermSuggester 支持从文本中提取候选术语的翻译器（基于大模型的提供商实现）
+type TermSuggester interface {
+	SuggestTerms(ctx context.Context, text, targetLang string) ([]SuggestedTerm, error)
+}
+
+// ExpectedTarget 译文中必须出现的写法
+func (t GlossaryTerm) ExpectedTarget() string {
+	if t.DoNotTranslate || strings.TrimSpace(t.Target) == "" {
+		return t.Source
+	}
+	return t.Target
+}
+
+// MatchesSource 判断原文中是否出现该术语（任一形式），按单词边界匹配
+func (t GlossaryTerm) MatchesSource(text string) bool {
+	for _, form := range t.sourceForms() {
+		if containsTerm(text, form, t.CaseSensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// UsedIn 判断译文是否使用了规定的译法
+func (t GlossaryTerm) UsedIn(translated string) bool {
+	expected := t.ExpectedTarget()
+	if expected == "" {
+		return true
+	}
+	// 保留原文的术语在译文中可能出现大小写变化，统一忽略大小写
+	return strings.Contains(strings.ToLower(translated), strings.ToLower(expected))
+}
+
+func (t GlossaryTerm) sourceForms() []string {
+	forms := make([]string, 0, len(t.Forms)+1)
+	if s := strings.TrimSpace(t.Source); s != "" {
+		forms = append(forms, s)
+	}
+	for _, form := range t.Forms {
+		if form = strings.TrimSpace(form); form != "" {
+			forms = append(forms, form)
+		}
+	}
+	return forms
+}
+
+// FilterGlossary 只保留在给定文本中出现过的术语，避免无关术语占用提示词
+func FilterGlossary(terms []GlossaryTerm, texts []string) []GlossaryTerm {
+	var matched []GlossaryTerm
+	for _, term := range terms {
+		for _, text := range texts {
+			if term.MatchesSource(text) {
+				matched = append(matched, term)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// CheckGlossary 返回原文中出现但译文没有按规定译法翻译的术语
+func CheckGlossary(source, translated string, terms []GlossaryTerm) []GlossaryTerm {
+	var violations []GlossaryTerm
+	for _, term := range terms {
+		if term.MatchesSource(source) && !term.UsedIn(translated) {
+			violations = append(violations, term)
+		}
+	}
+	return violations
+}
+
+// GlossaryVersion 计算术语集合的版本号（与顺序无关），没有术语时返回空字符串。
+// 版本号参与翻译记忆的键，术语变化后不会复用旧译文
+func GlossaryVersion(terms []GlossaryTerm) string {
+	if len(terms) == 0 {
+		return ""
+	}
+
+	normalized := make([]GlossaryTerm, len(terms))
+	for i, term := range terms {
+		normalized[i] = term
+		normalized[i].Forms = append([]string(nil), term.Forms...)
+		sort.Strings(normalized[i].Forms)
+	}
+	sort.Slice(normalized, func(i, j int) bool {
+		if normalized[i].Source != normalized[j].Source {
+			return normalized[i].Source < normalized[j].Source
+		}
+		return normalized[i].Target < normalized[j].Target
+	})
+
+	data, _ := json.Marshal(normalized)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:16]
+}
+
+// GlossaryPrompt 返回术语表提示词片段，供翻译器以外的大模型调用（如字幕校验修复）使用
+func GlossaryPrompt(terms []GlossaryTerm) string {
+	var prompt strings.Builder
+	writeGlossaryPrompt(&prompt, terms)
+	return prompt.String()
+}
+
+// writeGlossaryPrompt 将术语表写入系统提示词
+func writeGlossaryPrompt(prompt *strings.Builder, terms []GlossaryTerm) {
+	if len(terms) == 0 {
+		return
+	}
+
+	prompt.WriteString("\n术语表（必须严格遵守，原文出现以下术语时使用规定译法）：\n")
+	for _, term := range terms {
+		source := strings.Join(term.sourceForms(), " / ")
+		if term.DoNotTranslate {
+			prompt.WriteString(fmt.Sprintf("- %s → 保留原文「%s」，不要翻译\n", source, term.Source))
+		} else {
+			prompt.WriteString(fmt.Sprintf("- %s → %s\n", source, term.Target))
+		}
+	}
+}
+
+// buildSuggestTermsPrompt 构建术语提取的系统提示词
+func buildSuggestTermsPrompt(targetLang string) string {
+	return fmt.Sprintf(`你是一位专业的本地化术语专家。请从用户提供的视频字幕文本中找出需要在整个视频（以及同一频道的其他视频）中保持统一翻译的术语，包括：人名、品牌和产品名、组织机构、专业术语和行业黑话、反复出现的特定说法。
+
+要求：
+1. 只挑选真正需要统一译法的术语，普通词汇不要列出，最多 50 条
+2. 为每个术语给出%s的推荐译法；人名、品牌等通常保留原文的，将 do_not_translate 设为 true
+3. forms 列出文本中出现的其他形式（复数、缩写、不同写法），没有则为空数组
+4. note 用一句话说明理由
+
+只返回 JSON 数组，不要包含任何解释，格式：
+[{"source":"原文术语","target":"译法","forms":[],"do_not_translate":false,"note":"理由"}]`, LanguageName(targetLang))
+}
+
+// parseSuggestedTerms 解析大模型返回的候选术语（兼容 markdown 代码块包裹）
+func parseSuggestedTerms(content string) ([]SuggestedTerm, error) {
+	content = strings.TrimSpace(content)
+	start := strings.Index(content, "[")
+	end := strings.LastIndex(content, "]")
+	if start < 0 || end <= start {
+		return nil, fmt.Errorf("no JSON array in response")
+	}
+
+	var items []struct {
+		Source         string   `json:"source"`
+		Target         string   `json:"target"`
+		Forms          []string `json:"forms"`
+		DoNotTranslate bool     `json:"do_not_translate"`
+		Note           string   `json:"note"`
+	}
+	if err := json.Unmarshal([]byte(content[start:end+1]), &items); err != nil {
+		return nil, fmt.Errorf("failed to parse suggested terms: %w", err)
+	}
+
+	terms := make([]SuggestedTerm, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		source := strings.TrimSpace(item.Source)
+		key := strings.ToLower(source)
+		if source == "" || seen[key] || (!item.DoNotTranslate && strings.TrimSpace(item.Target) == "") {
+			continue
+		}
+		seen[key] = true
+		terms = append(terms, SuggestedTerm{
+			GlossaryTerm: GlossaryTerm{
+				Source:         source,
+				Target:         strings.TrimSpace(item.Target),
+				Forms:          item.Forms,
+				DoNotTranslate: item.DoNotTranslate,
+			},
+			Note: item.Note,
+		})
+	}
+	return terms, nil
+}
+
+// containsTerm 查找术语，术语首尾是字母或数字时要求匹配位置处于单词边界
+// （避免 "AI" 匹配到 "said"），中日韩文字不做边界检查
+func containsTerm(text, term string, caseSensitive bool) bool {
+	if term == "" {
+		return false
+	}
+	if !caseSensitive {
+		text = strings.ToLower(text)
+		term = strings.ToLower(term)
+	}
+
+	first, _ := utf8.DecodeRuneInString(term)
+	last, _ := utf8.DecodeLastRuneInString(term)
+	checkStart := isWordRune(first)
+	checkEnd := isWordRune(last)
+
+	offset := 0
+	for {
+		idx := strings.Index(text[offset:], term)
+		if idx < 0 {
+			return false
+		}
+		start := offset + idx
+		end := start + len(term)
+
+		ok := true
+		if checkStart && start > 0 {
+			prev, _ := utf8.DecodeLastRuneInString(text[:start])
+			ok = !isWordRune(prev)
+		}
+		if ok && checkEnd && end < len(text) {
+			next, _ := utf8.DecodeRuneInString(text[end:])
+			ok = !isWordRune(next)
+		}
+		if ok {
+			return true
+		}
+		offset = start + 1
+	}
+}
+
+// isWordRune 是否为需要做单词边界检查的字符（拉丁字母、数字等，不含中日韩文字）
+func isWordRune(r rune) bool {
+	if unicode.Is(unicode.Han, r) || unicode.Is(unicode.Hiragana, r) || unicode.Is(unicode.Katakana, r) || unicode.Is(unicode.Hangul, r) {
+		return false
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,298 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/ratelimit"
+)
+
+// GoogleTranslator Google Cloud Translation v2（Basic）翻译器
+type GoogleTranslator struct {
+	apiKey   string
+	endpoint string
+	client   *http.Client
+}
+
+// Google v2 单次请求最多 128 段文本
+const googleMaxBatchSize = 128
+
+// googleErrorResponse Google API 错误响应
+type googleErrorResponse struct {
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// googleTranslateResponse 翻译响应
+type googleTranslateResponse struct {
+	googleErrorResponse
+	Data struct {
+		Translations []struct {
+			TranslatedText         string `json:"translatedText"`
+			DetectedSourceLanguage string `json:"detectedSourceLanguage,omitempty"`
+		} `json:"translations"`
+	} `json:"data"`
+}
+
+// googleDetectResponse 语言检测响应
+type googleDetectResponse struct {
+	googleErrorResponse
+	Data struct {
+		Detections [][]struct {
+			Language   string  `json:"language"`
+			Confidence float64 `json:"confidence"`
+		} `json:"detections"`
+	} `json:"data"`
+}
+
+// googleLanguagesResponse 支持语言响应
+type googleLanguagesResponse struct {
+	googleErrorResponse
+	Data struct {
+		Languages []struct {
+			Language string `json:"language"`
+			Name     string `json:"name"`
+		} `json:"languages"`
+	} `json:"data"`
+}
+
+// NewGoogleTranslator 创建 Google 翻译器实例
+func NewGoogleTranslator(config *types.GoogleTransConfig) (*GoogleTranslator, error) {
+	if config == nil {
+		return nil, fmt.Errorf("google translator config is nil")
+	}
+	if !config.Enabled {
+		return nil, fmt.Errorf("google translator is not enabled")
+	}
+	if config.ApiKey == "" {
+		return nil, fmt.Errorf("google API key is required")
+	}
+
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = "https://translation.googleapis.com/language/translate/v2"
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 30
+	}
+
+	return &GoogleTranslator{
+		apiKey:   config.ApiKey,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client: &http.Client{
+			Timeout: time.Duration(timeout) * time.Second,
+		},
+	}, nil
+}
+
+// Translate 单个文本翻译
+func (g *GoogleTranslator) Translate(ctx context.Context, req *TranslationRequest) (*TranslationResult, error) {
+	if req.Text == "" {
+		return nil, fmt.Errorf("text cannot be empty")
+	}
+
+	result, err := g.BatchTranslate(ctx, &BatchTranslationRequest{
+		Texts:      []string{req.Text},
+		SourceLang: req.SourceLang,
+		TargetLang: req.TargetLang,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Results[0], nil
+}
+
+// BatchTranslate 批量翻译
+func (g *GoogleTranslator) BatchTranslate(ctx context.Context, req *BatchTranslationRequest) (*BatchTranslationResult, error) {
+	if len(req.Texts) == 0 {
+		return nil, fmt.Errorf("texts cannot be empty")
+	}
+
+	startTime := time.Now()
+	results := make([]*TranslationResult, 0, len(req.Texts))
+	totalChars := 0
+
+	for i := 0; i < len(req.Texts); i += googleMaxBatchSize {
+		end := i + googleMaxBatchSize
+		if end > len(req.Texts) {
+			end = len(req.Texts)
+		}
+		batch := req.Texts[i:end]
+
+		body := map[string]interface{}{
+			"q":      batch,
+			"target": g.convertLanguageCode(req.TargetLang),
+			"format": "text",
+		}
+		if req.SourceLang != "" && req.SourceLang != "auto" {
+			body["source"] = g.convertLanguageCode(req.SourceLang)
+		}
+
+		var response googleTranslateResponse
+		if err := g.post(ctx, "", body, &response); err != nil {
+			return nil, err
+		}
+		if len(response.Data.Translations) != len(batch) {
+			return nil, fmt.Errorf("google API returned %d translations, expected %d", len(response.Data.Translations), len(batch))
+		}
+
+		for j, item := range response.Data.Translations {
+			sourceLang := req.SourceLang
+			if item.DetectedSourceLanguage != "" {
+				sourceLang = item.DetectedSourceLanguage
+			}
+			results = append(results, &TranslationResult{
+				OriginalText:   batch[j],
+				TranslatedText: html.UnescapeString(item.TranslatedText),
+				SourceLang:     sourceLang,
+				TargetLang:     req.TargetLang,
+				Provider:       "google",
+				Usage:          &Usage{Characters: len(batch[j])},
+			})
+			totalChars += len(batch[j])
+		}
+	}
+
+	return &BatchTranslationResult{
+		Results:  results,
+		Provider: "google",
+		Usage: &Usage{
+			Characters: totalChars,
+			Duration:   time.Since(startTime).Milliseconds(),
+		},
+	}, nil
+}
+
+// GetSupportedLanguages 获取支持的语言列表
+func (g *GoogleTranslator) GetSupportedLanguages(ctx context.Context) ([]LanguageInfo, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", g.buildURL("/languages")+"&target=zh-CN", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var response googleLanguagesResponse
+	if err := g.do(httpReq, &response); err != nil {
+		return nil, err
+	}
+
+	languages := make([]LanguageInfo, 0, len(response.Data.Languages))
+	for _, lang := range response.Data.Languages {
+		languages = append(languages, LanguageInfo{
+			Code:        lang.Language,
+			Name:        lang.Name,
+			NativeName:  lang.Name,
+			Direction:   languageDirection(lang.Language),
+			IsSupported: true,
+		})
+	}
+	return languages, nil
+}
+
+// DetectLanguage 检测语言
+func (g *GoogleTranslator) DetectLanguage(ctx context.Context, text string) (string, float64, error) {
+	if text == "" {
+		return "", 0, fmt.Errorf("text cannot be empty")
+	}
+
+	var response googleDetectResponse
+	if err := g.post(ctx, "/detect", map[string]interface{}{"q": []string{text}}, &response); err != nil {
+		return "", 0, err
+	}
+	if len(response.Data.Detections) == 0 || len(response.Data.Detections[0]) == 0 {
+		return "", 0, fmt.Errorf("no detection result in response")
+	}
+
+	detection := response.Data.Detections[0][0]
+	return detection.Language, detection.Confidence, nil
+}
+
+// GetInfo 获取翻译器信息
+func (g *GoogleTranslator) GetInfo() *TranslatorInfo {
+	return &TranslatorInfo{
+		Name:               "Google Cloud Translation",
+		Provider:           "google",
+		Version:            "v2",
+		MaxTextLength:      30000,
+		SupportedLanguages: commonLanguageCodes,
+		Features:           []string{"translate", "batch_translate", "detect_language"},
+		IsOnline:           true,
+	}
+}
+
+// IsHealthy 健康检查
+func (g *GoogleTranslator) IsHealthy(ctx context.Context) error {
+	if _, _, err := g.DetectLanguage(ctx, "Hello"); err != nil {
+		return fmt.Errorf("google health check failed: %w", err)
+	}
+	return nil
+}
+
+func (g *GoogleTranslator) buildURL(path string) string {
+	return g.endpoint + path + "?key=" + url.QueryEscape(g.apiKey)
+}
+
+func (g *GoogleTranslator) post(ctx context.Context, path string, body interface{}, out interface{}) error {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", g.buildURL(path), bytes.NewBuffer(jsonData))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	return g.do(httpReq, out)
+}
+
+// do 发送请求并解析响应，API 错误统一转换为 error 并附加 HTTP 状态码
+func (g *GoogleTranslator) do(httpReq *http.Request, out interface{}) error {
+	resp, err := g.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var apiErr googleErrorResponse
+	if err := json.Unmarshal(body, &apiErr); err == nil && apiErr.Error != nil {
+		return ratelimit.WithStatus(resp.StatusCode, fmt.Errorf("google API error: %d - %s", apiErr.Error.Code, apiErr.Error.Message))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return ratelimit.WithStatus(resp.StatusCode, fmt.Errorf("google API returned status %d: %s", resp.StatusCode, truncateBody(body)))
+	}
+
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// convertLanguageCode 转换语言代码到 Google 格式
+func (g *GoogleTranslator) convertLanguageCode(code string) string {
+	switch strings.ToLower(code) {
+	case "zh", "zh-cn", "zh-hans":
+		return "zh-CN"
+	case "zh-tw", "zh-hk", "zh-hant":
+		return "zh-TW"
+	}
+	return code
+}
@@ -0,0 +1,144 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/ratelimit"
+)
+
+func newTestGoogleTranslator(t *testing.T, endpoint string) *GoogleTranslator {
+	t.Helper()
+	tr, err := NewGoogleTranslator(&types.GoogleTransConfig{Enabled: true, ApiKey: "key&1", Endpoint: endpoint})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tr
+}
+
+func TestGoogleBatchTranslateRequest(t *testing.T) {
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request, body []byte) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{
+				"translations": []map[string]string{
+					{"translatedText": "你好 &amp; 再见", "detectedSourceLanguage": "en"},
+					{"translatedText": "&quot;世界&quot;", "detectedSourceLanguage": "en"},
+				},
+			},
+		})
+	})
+	tr := newTestGoogleTranslator(t, server.URL+"/language/translate/v2/")
+
+	result, err := tr.BatchTranslate(context.Background(), &BatchTranslationRequest{
+		Texts:      []string{"hello & bye", `"world"`},
+		SourceLang: "auto",
+		TargetLang: "zh-Hans",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := server.lastRequest(t)
+	if req.Method != "POST" || req.Path != "/language/translate/v2" {
+		t.Fatalf("unexpected request %s %s", req.Method, req.Path)
+	}
+	if got := req.Query["key"]; len(got) != 1 || got[0] != "key&1" {
+		t.Errorf("key query = %v", got)
+	}
+	if got := req.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("content type = %q", got)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(req.Body, &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["target"] != "zh-CN" || body["format"] != "text" {
+		t.Errorf("unexpected body: %s", req.Body)
+	}
+	if _, ok := body["source"]; ok {
+		t.Errorf("source should be omitted for auto detection: %s", req.Body)
+	}
+	if q, _ := body["q"].([]interface{}); len(q) != 2 || q[0] != "hello & bye" {
+		t.Errorf("unexpected q: %v", body["q"])
+	}
+
+	// 响应中的 HTML 实体需要还原
+	if result.Results[0].TranslatedText != "你好 & 再见" || result.Results[1].TranslatedText != `"世界"` {
+		t.Fatalf("unexpected results: %q, %q", result.Results[0].TranslatedText, result.Results[1].TranslatedText)
+	}
+	if result.Results[0].SourceLang != "en" || result.Provider != "google" {
+		t.Errorf("unexpected result metadata: %+v", result.Results[0])
+	}
+}
+
+func TestGoogleSourceLanguage(t *testing.T) {
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request, body []byte) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{"translations": []map[string]string{{"translatedText": "hola"}}},
+		})
+	})
+	tr := newTestGoogleTranslator(t, server.URL)
+
+	result, err := tr.Translate(context.Background(), &TranslationRequest{Text: "hello", SourceLang: "en", TargetLang: "es"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var body map[string]interface{}
+	json.Unmarshal(server.lastRequest(t).Body, &body)
+	if body["source"] != "en" || body["target"] != "es" {
+		t.Errorf("unexpected body: %v", body)
+	}
+	if result.TranslatedText != "hola" || result.SourceLang != "en" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
+
+func TestGoogleShortResultList(t *testing.T) {
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request, body []byte) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{"translations": []map[string]string{{"translatedText": "你好"}}},
+		})
+	})
+	tr := newTestGoogleTranslator(t, server.URL)
+
+	_, err := tr.BatchTranslate(context.Background(), &BatchTranslationRequest{Texts: []string{"hello", "world"}, TargetLang: "zh"})
+	if err == nil || !strings.Contains(err.Error(), "returned 1 translations, expected 2") {
+		t.Fatalf("expected short list error, got %v", err)
+	}
+}
+
+func TestGoogleErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		status int
+		body   string
+		code   int
+	}{
+		{"quota", http.StatusTooManyRequests, `{"error":{"code":429,"message":"Rate Limit Exceeded"}}`, 429},
+		{"backend", http.StatusInternalServerError, `{"error":{"code":500,"message":"Backend Error"}}`, 500},
+		{"plain 503", http.StatusServiceUnavailable, "unavailable", 503},
+		{"bad key", http.StatusBadRequest, `{"error":{"code":400,"message":"API key not valid"}}`, 400},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			server := newFakeServer(t, statusHandler(c.status, c.body))
+			tr := newTestGoogleTranslator(t, server.URL)
+
+			_, err := tr.BatchTranslate(context.Background(), &BatchTranslationRequest{Texts: []string{"hello"}, TargetLang: "zh"})
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if got := ratelimit.StatusCode(err); got != c.code {
+				t.Errorf("status code = %d, want %d (%v)", got, c.code, err)
+			}
+			if ratelimit.IsTransient(err) != (c.code == 429 || c.code >= 500) {
+				t.Errorf("unexpected transient classification for %v", err)
+			}
+		})
+	}
+}
@@ -4,24 +4,29 @@ import "context"
 
 // TranslationRequest 翻译请求
 type TranslationRequest struct {
-	Text       string `json:"text" binding:"required"`       // 要翻译的文本
-	SourceLang string `json:"sourceLang,omitempty"`          // 源语言，可选，auto为自动检测
-	TargetLang string `json:"targetLang" binding:"required"` // 目标语言
-	TextType   string `json:"textType,omitempty"`            // 文本类型：plain, html, markdown等
-	Domain     string `json:"domain,omitempty"`              // 领域：general, medical, legal等
-	ProjectId  string `json:"projectId,omitempty"`           // 项目ID（某些服务需要）
-	Model      string `json:"model,omitempty"`               // 使用的模型（如Ollama）
+	Text       string         `json:"text" binding:"required"`       // 要翻译的文本
+	SourceLang string         `json:"sourceLang,omitempty"`          // 源语言，可选，auto为自动检测
+	TargetLang string         `json:"targetLang" binding:"required"` // 目标语言
+	TextType   string         `json:"textType,omitempty"`            // 文本类型：plain, html, markdown等
+	Domain     string         `json:"domain,omitempty"`              // 领域：general, medical, legal等
+	ProjectId  string         `json:"projectId,omitempty"`           // 项目ID（某些服务需要）
+	Model      string         `json:"model,omitempty"`               // 使用的模型（如Ollama）
+	Glossary   []GlossaryTerm `json:"glossary,omitempty"`            // 术语表（大模型翻译器注入提示词）
 }
 
 // BatchTranslationRequest 批量翻译请求
 type BatchTranslationRequest struct {
-	Texts      []string `json:"texts" binding:"required"`      // 要翻译的文本列表
-	SourceLang string   `json:"sourceLang,omitempty"`          // 源语言
-	TargetLang string   `json:"targetLang" binding:"required"` // 目标语言
-	TextType   string   `json:"textType,omitempty"`            // 文本类型
-	Domain     string   `json:"domain,omitempty"`              // 领域
-	ProjectId  string   `json:"projectId,omitempty"`           // 项目ID
-	Model      string   `json:"model,omitempty"`               // 使用的模型
+	Texts           []string       `json:"texts" binding:"required"`      // 要翻译的文本列表
+	SourceLang      string         `json:"sourceLang,omitempty"`          // 源语言
+	TargetLang      string         `json:"targetLang" binding:"required"` // 目标语言
+	TextType        string         `json:"textType,omitempty"`            // 文本类型
+	Domain          string         `json:"domain,omitempty"`              // 领域
+	ProjectId       string         `json:"projectId,omitempty"`           // 项目ID
+	Model           string         `json:"model,omitempty"`               // 使用的模型
+	Glossary        []GlossaryTerm `json:"glossary,omitempty"`            // 术语表（大模型翻译器注入提示词）
+	GlossaryVersion string         `json:"glossaryVersion,omitempty"`     // 术语表版本（参与翻译记忆键）
+	VideoTitle      string         `json:"videoTitle,omitempty"`          // 原视频标题（提示词模板变量）
+	Channel         string         `json:"channel,omitempty"`             // 来源频道（提示词模板变量）
 }
 
 // TranslationResult 翻译结果
@@ -34,6 +39,7 @@ type TranslationResult struct {
 	Provider       string  `json:"provider"`             // 翻译服务提供商
 	Model          string  `json:"model,omitempty"`      // 使用的模型
 	Usage          *Usage  `json:"usage,omitempty"`      // 使用统计
+	Cached         bool    `json:"cached,omitempty"`     // 是否来自翻译记忆
 }
 
 type TranslationResultDto struct {
@@ -56,9 +62,10 @@ func (t *TranslationResult) ToDto() TranslationResultDto {
 
 // BatchTranslationResult 批量翻译结果
 type BatchTranslationResult struct {
-	Results  []*TranslationResult `json:"results"`         // 翻译结果列表
-	Provider string               `json:"provider"`        // 翻译服务提供商
-	Usage    *Usage               `json:"usage,omitempty"` // 使用统计
+	Results       []*TranslationResult `json:"results"`                 // 翻译结果列表
+	Provider      string               `json:"provider"`                // 翻译服务提供商
+	Usage         *Usage               `json:"usage,omitempty"`         // 使用统计
+	PromptVersion string               `json:"promptVersion,omitempty"` // 使用的提示词模板版本（仅大模型翻译器）
 }
 
 // Usage 使用统计
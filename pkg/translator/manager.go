@@ -2,8 +2,11 @@ package translator
 
 import (
 	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/prompt"
+	"github.com/difyz9/ytb2bili/pkg/ratelimit"
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 )
@@ -16,14 +19,15 @@ type TranslatorManager struct {
 	mutex             sync.RWMutex
 	defaultProvider   string
 	fallbackProviders []string
+	memory            TranslationMemory
 }
 
 // NewTranslatorManager 创建翻译器管理器
 func NewTranslatorManager(config *types.AppConfig) *TranslatorManager {
 	factory := NewTranslatorFactory(config)
 
-	defaultProvider := "tencent"
-	fallbackProviders := []string{"microsoft", "google"}
+	defaultProvider := "deepseek"
+	fallbackProviders := []string{"baidu"}
 
 	// 从配置中读取默认提供商和备选提供商
 	if config.TranslatorConfig != nil {
@@ -44,6 +48,11 @@ func NewTranslatorManager(config *types.AppConfig) *TranslatorManager {
 	}
 }
 
+// SetMemory 设置翻译记忆，TranslatorConfig.EnableCache 开启时批量翻译会先查记忆再调用提供商
+func (tm *TranslatorManager) SetMemory(memory TranslationMemory) {
+	tm.memory = memory
+}
+
 // GetTranslator 获取翻译器实例
 func (tm *TranslatorManager) GetTranslator(provider string) (Translator, error) {
 	tm.mutex.Lock()
@@ -78,12 +87,16 @@ func (tm *TranslatorManager) Translate(ctx context.Context, req *TranslationRequ
 
 // TranslateWithProvider 使用指定提供商进行翻译
 func (tm *TranslatorManager) TranslateWithProvider(ctx context.Context, provider string, req *TranslationRequest) (*TranslationResult, error) {
+	if err := CheckBudget(UsageScopeFromContext(ctx).VideoID); err != nil {
+		return nil, err
+	}
+
 	translator, err := tm.GetTranslator(provider)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get translator %s: %v", provider, err)
 	}
 
-	result, err := translator.Translate(ctx, req)
+	result, err := tm.translateAndRecord(ctx, provider, translator, req)
 	if err != nil {
 		// 如果主要提供商失败，尝试备选提供商
 		return tm.translateWithFallback(ctx, req, err)
@@ -104,7 +117,7 @@ func (tm *TranslatorManager) translateWithFallback(ctx context.Context, req *Tra
 			continue // 跳过无法创建的提供商
 		}
 
-		result, err := translator.Translate(ctx, req)
+		result, err := tm.translateAndRecord(ctx, fallbackProvider, translator, req)
 		if err == nil {
 			return result, nil
 		}
@@ -113,9 +126,103 @@ func (tm *TranslatorManager) translateWithFallback(ctx context.Context, req *Tra
 	return nil, fmt.Errorf("all translators failed, original error: %v", originalErr)
 }
 
-// BatchTranslate 批量翻译
+// translateAndRecord 单句翻译并记录用量
+func (tm *TranslatorManager) translateAndRecord(ctx context.Context, provider string, translator Translator, req *TranslationRequest) (*TranslationResult, error) {
+	permit, err := acquirePermit(ctx, provider, translator, req.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+	result, err := translator.Translate(ctx, req)
+	permit.Done(err)
+
+	var usage *Usage
+	if result != nil {
+		usage = result.Usage
+	}
+	RecordUsage(ctx, newUsageRecord(provider, tm.providerModel(provider), []string{req.Text}, usage, time.Since(start), err))
+	return result, err
+}
+
+// BatchTranslate 批量翻译：启用翻译记忆时先复用已有译文，剩余文本依次尝试默认提供商和备选提供商，
+// 每个提供商最多重试 MaxRetries 次，单次请求受 Timeout 限制
 func (tm *TranslatorManager) BatchTranslate(ctx context.Context, req *BatchTranslationRequest) (*BatchTranslationResult, error) {
-	return tm.BatchTranslateWithProvider(ctx, tm.defaultProvider, req)
+	if err := CheckBudget(UsageScopeFromContext(ctx).VideoID); err != nil {
+		return nil, err
+	}
+	if !tm.memoryEnabled() {
+		return tm.batchTranslateChain(ctx, req)
+	}
+
+	hits, err := tm.memory.Lookup(ctx, tm.memoryLookup(req))
+	if err != nil {
+		logger.Warnf("Translation memory lookup failed, translating without cache: %v", err)
+		hits = nil
+	}
+
+	results := make([]*TranslationResult, len(req.Texts))
+	var missing []int
+	for i, text := range req.Texts {
+		record, ok := hits[i]
+		if !ok {
+			missing = append(missing, i)
+			continue
+		}
+		results[i] = &TranslationResult{
+			OriginalText:   text,
+			TranslatedText: record.TranslatedText,
+			SourceLang:     record.SourceLang,
+			TargetLang:     req.TargetLang,
+			Provider:       record.Provider,
+			Model:          record.Model,
+			Cached:         true,
+		}
+	}
+
+	if len(missing) == 0 {
+		return &BatchTranslationResult{
+			Results:  results,
+			Provider: "memory",
+			Usage:    &Usage{},
+		}, nil
+	}
+
+	missingReq := *req
+	missingReq.Texts = make([]string, len(missing))
+	for j, idx := range missing {
+		missingReq.Texts[j] = req.Texts[idx]
+	}
+
+	result, err := tm.batchTranslateChain(ctx, &missingReq)
+	if err != nil {
+		return nil, err
+	}
+	for j, idx := range missing {
+		results[idx] = result.Results[j]
+	}
+	tm.saveToMemory(ctx, &missingReq, result)
+
+	result.Results = results
+	return result, nil
+}
+
+// batchTranslateChain 依次尝试默认提供商和备选提供商进行批量翻译
+func (tm *TranslatorManager) batchTranslateChain(ctx context.Context, req *BatchTranslationRequest) (*BatchTranslationResult, error) {
+	var errs []string
+	for _, provider := range tm.ProviderChain() {
+		result, err := tm.batchTranslateWithRetry(ctx, provider, req)
+		if err == nil {
+			return result, nil
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		logger.Warnf("Translator %s failed, trying next provider: %v", provider, err)
+		errs = append(errs, fmt.Sprintf("%s: %v", provider, err))
+	}
+
+	return nil, fmt.Errorf("all translators failed: %s", strings.Join(errs, "; "))
 }
 
 // BatchTranslateWithProvider 使用指定提供商进行批量翻译
@@ -128,6 +235,263 @@ func (tm *TranslatorManager) BatchTranslateWithProvider(ctx context.Context, pro
 	return translator.BatchTranslate(ctx, req)
 }
 
+// batchTranslateWithRetry 使用指定提供商批量翻译，失败时按退避时间重试
+func (tm *TranslatorManager) batchTranslateWithRetry(ctx context.Context, provider string, req *BatchTranslationRequest) (*BatchTranslationResult, error) {
+	translator, err := tm.GetTranslator(provider)
+	if err != nil {
+		// 提供商未启用或配置错误，重试没有意义
+		return nil, fmt.Errorf("failed to get translator %s: %v", provider, err)
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= tm.maxRetries(); attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(time.Duration(attempt) * 2 * time.Second):
+			}
+			logger.Infof("Retrying translator %s (%d/%d)", provider, attempt, tm.maxRetries())
+		}
+
+		permit, err := acquirePermit(ctx, provider, translator, req.Texts...)
+		if err != nil {
+			// 熔断或等待许可时被取消，交给下一个提供商
+			return nil, err
+		}
+
+		start := time.Now()
+		attemptCtx, cancel := context.WithTimeout(ctx, tm.requestTimeout())
+		result, err := translator.BatchTranslate(attemptCtx, req)
+		cancel()
+		permit.Done(err)
+		if err == nil {
+			err = validateBatchResult(result, req.Texts)
+		}
+
+		var usage *Usage
+		if result != nil {
+			usage = result.Usage
+		}
+		RecordUsage(ctx, newUsageRecord(provider, tm.providerModel(provider), req.Texts, usage, time.Since(start), err))
+
+		if err == nil {
+			if result.Provider == "" {
+				result.Provider = provider
+			}
+			return result, nil
+		}
+		lastErr = err
+	}
+
+	return nil, lastErr
+}
+
+// selfRateLimited 一次调用会发出多个请求的翻译器自行在每个请求上获取限流许可
+type selfRateLimited interface {
+	selfRateLimited()
+}
+
+// acquirePermit 获取提供商的共享限流许可，自行限流的翻译器返回空许可
+func acquirePermit(ctx context.Context, provider string, translator Translator, texts ...string) (*ratelimit.Permit, error) {
+	if _, ok := translator.(selfRateLimited); ok {
+		return &ratelimit.Permit{}, nil
+	}
+	return ratelimit.Acquire(ctx, provider, ratelimit.EstimateTokens(texts...))
+}
+
+// validateBatchResult 检查批量翻译结果数量与输入一致，且非空原文都有译文（否则重试或交给下一个提供商）
+func validateBatchResult(result *BatchTranslationResult, texts []string) error {
+	if result == nil {
+		return fmt.Errorf("empty batch translation result")
+	}
+	if len(result.Results) != len(texts) {
+		return fmt.Errorf("batch translation returned %d results, expected %d", len(result.Results), len(texts))
+	}
+	for i, item := range result.Results {
+		if item == nil {
+			return fmt.Errorf("batch translation result %d is missing", i+1)
+		}
+		if strings.TrimSpace(item.TranslatedText) == "" && strings.TrimSpace(texts[i]) != "" {
+			return fmt.Errorf("batch translation result %d is empty", i+1)
+		}
+	}
+	return nil
+}
+
+// ProviderChain 返回按优先级排列的提供商列表（默认提供商在前，去重）
+func (tm *TranslatorManager) ProviderChain() []string {
+	chain := []string{tm.defaultProvider}
+	seen := map[string]bool{tm.defaultProvider: true}
+	for _, provider := range tm.fallbackProviders {
+		if provider == "" || seen[provider] {
+			continue
+		}
+		seen[provider] = true
+		chain = append(chain, provider)
+	}
+	return chain
+}
+
+// memoryEnabled 是否启用翻译记忆
+func (tm *TranslatorManager) memoryEnabled() bool {
+	return tm.memory != nil && tm.config.TranslatorConfig != nil && tm.config.TranslatorConfig.EnableCache
+}
+
+// memoryLookup 构造翻译记忆查询：人工译文优先，其次按提供商链顺序
+func (tm *TranslatorManager) memoryLookup(req *BatchTranslationRequest) *MemoryLookup {
+	providers := []MemoryProvider{{Provider: MemoryProviderHuman}}
+	for _, provider := range tm.ProviderChain() {
+		providers = append(providers, MemoryProvider{
+			Provider:      provider,
+			Model:         tm.providerModel(provider),
+			PromptVersion: providerPromptVersion(provider),
+		})
+	}
+
+	var maxAge time.Duration
+	if tm.config.TranslatorConfig.CacheExpiry > 0 {
+		maxAge = time.Duration(tm.config.TranslatorConfig.CacheExpiry) * time.Second
+	}
+
+	return &MemoryLookup{
+		Texts:           req.Texts,
+		SourceLang:      req.SourceLang,
+		TargetLang:      req.TargetLang,
+		GlossaryVersion: requestGlossaryVersion(req),
+		Providers:       providers,
+		MaxAge:          maxAge,
+	}
+}
+
+// saveToMemory 将提供商的翻译结果写入翻译记忆，失败只记录日志
+func (tm *TranslatorManager) saveToMemory(ctx context.Context, req *BatchTranslationRequest, result *BatchTranslationResult) {
+	model := tm.providerModel(result.Provider)
+	records := make([]*MemoryRecord, 0, len(result.Results))
+	for i, item := range result.Results {
+		if strings.TrimSpace(item.TranslatedText) == "" {
+			continue
+		}
+		records = append(records, &MemoryRecord{
+			SourceText:      req.Texts[i],
+			TranslatedText:  item.TranslatedText,
+			SourceLang:      req.SourceLang,
+			TargetLang:      req.TargetLang,
+			Provider:        result.Provider,
+			Model:           model,
+			GlossaryVersion: requestGlossaryVersion(req),
+			PromptVersion:   result.PromptVersion,
+		})
+	}
+	if len(records) == 0 {
+		return
+	}
+	if err := tm.memory.Save(ctx, records); err != nil {
+		logger.Warnf("Failed to save translations to memory: %v", err)
+	}
+}
+
+// requestGlossaryVersion 请求的术语表版本，未显式指定时按术语表内容计算
+func requestGlossaryVersion(req *BatchTranslationRequest) string {
+	if req.GlossaryVersion != "" {
+		return req.GlossaryVersion
+	}
+	return GlossaryVersion(req.Glossary)
+}
+
+// SuggestGlossaryTerms 按提供商链顺序，使用第一个支持术语提取的提供商从文本中提取候选术语
+func (tm *TranslatorManager) SuggestGlossaryTerms(ctx context.Context, text, targetLang string) ([]SuggestedTerm, string, error) {
+	if err := CheckBudget(UsageScopeFromContext(ctx).VideoID); err != nil {
+		return nil, "", err
+	}
+
+	var errs []string
+	for _, provider := range tm.ProviderChain() {
+		translator, err := tm.GetTranslator(provider)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", provider, err))
+			continue
+		}
+		suggester, ok := translator.(TermSuggester)
+		if !ok {
+			continue
+		}
+
+		permit, err := acquirePermit(ctx, provider, translator, text)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", provider, err))
+			continue
+		}
+
+		start := time.Now()
+		attemptCtx, cancel := context.WithTimeout(ctx, tm.requestTimeout())
+		terms, err := suggester.SuggestTerms(attemptCtx, text, targetLang)
+		cancel()
+		permit.Done(err)
+
+		record := newUsageRecord(provider, tm.providerModel(provider), []string{text}, nil, time.Since(start), err)
+		record.Kind = UsageKindChat
+		RecordUsage(ctx, record)
+		if err == nil {
+			return terms, provider, nil
+		}
+		logger.Warnf("Term suggestion with %s failed, trying next provider: %v", provider, err)
+		errs = append(errs, fmt.Sprintf("%s: %v", provider, err))
+	}
+
+	if len(errs) == 0 {
+		return nil, "", fmt.Errorf("no configured provider supports term suggestion")
+	}
+	return nil, "", fmt.Errorf("term suggestion failed: %s", strings.Join(errs, "; "))
+}
+
+// providerModel 提供商当前配置的模型，作为翻译记忆键的一部分（切换模型后不复用旧译文）
+func (tm *TranslatorManager) providerModel(provider string) string {
+	switch provider {
+	case "deepseek":
+		if tm.config.DeepSeekTransConfig != nil {
+			return tm.config.DeepSeekTransConfig.Model
+		}
+	case "openai":
+		if tm.config.OpenAICompatibleConfig != nil {
+			return tm.config.OpenAICompatibleConfig.Model
+		}
+	case "ollama":
+		if tm.config.OllamaTransConfig != nil {
+			return tm.config.OllamaTransConfig.Model
+		}
+	}
+	return ""
+}
+
+// providerPromptVersion 提供商当前使用的翻译提示词版本：大模型翻译器为启用的翻译模板版本，
+// 模板切换后旧版本的译文不再命中翻译记忆；其他提供商不使用提示词，返回空
+func providerPromptVersion(provider string) string {
+	switch provider {
+	case "deepseek", "openai", "ollama":
+		if tmpl := prompt.Active(prompt.Translation); tmpl != nil {
+			return tmpl.Label()
+		}
+	}
+	return ""
+}
+
+// maxRetries 每个提供商的重试次数
+func (tm *TranslatorManager) maxRetries() int {
+	if tm.config.TranslatorConfig != nil && tm.config.TranslatorConfig.MaxRetries > 0 {
+		return tm.config.TranslatorConfig.MaxRetries
+	}
+	return 0
+}
+
+// requestTimeout 单次请求超时时间
+func (tm *TranslatorManager) requestTimeout() time.Duration {
+	if tm.config.TranslatorConfig != nil && tm.config.TranslatorConfig.Timeout > 0 {
+		return time.Duration(tm.config.TranslatorConfig.Timeout) * time.Second
+	}
+	return 30 * time.Second
+}
+
 // GetSupportedLanguages 获取支持的语言列表
 func (tm *TranslatorManager) GetSupportedLanguages(ctx context.Context, provider string) ([]LanguageInfo, error) {
 	translator, err := tm.GetTranslator(provider)
@@ -192,12 +556,7 @@ func (tm *TranslatorManager) HealthCheckAll(ctx context.Context) map[string]erro
 // SmartTranslate 智能翻译，自动选择最佳提供商
 func (tm *TranslatorManager) SmartTranslate(ctx context.Context, req *TranslationRequest) (*TranslationResult, error) {
 	// 创建超时上下文
-	timeout := 30 * time.Second
-	if tm.config.TranslatorConfig != nil && tm.config.TranslatorConfig.Timeout > 0 {
-		timeout = time.Duration(tm.config.TranslatorConfig.Timeout) * time.Second
-	}
-
-	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	timeoutCtx, cancel := context.WithTimeout(ctx, tm.requestTimeout())
 	defer cancel()
 
 	// 首先尝试默认提供商
@@ -0,0 +1,61 @@
+package translator
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+)
+
+// 默认提供商返回空译文时视为失败，交给备选提供商，不会把空译文交给调用方
+func TestManagerFallbackOnEmptyTranslation(t *testing.T) {
+	msServer := newFakeServer(t, func(w http.ResponseWriter, r *http.Request, body []byte) {
+		writeJSON(w, http.StatusOK, []map[string]interface{}{
+			{"translations": []map[string]string{{"text": "你好", "to": "zh-Hans"}}},
+			{"translations": []map[string]string{{"text": " ", "to": "zh-Hans"}}},
+		})
+	})
+	googleServer := newFakeServer(t, func(w http.ResponseWriter, r *http.Request, body []byte) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"data": map[string]interface{}{
+				"translations": []map[string]string{{"translatedText": "你好"}, {"translatedText": "世界"}},
+			},
+		})
+	})
+
+	manager := NewTranslatorManager(&types.AppConfig{
+		TranslatorConfig:     &types.TranslatorConfig{DefaultProvider: "microsoft", FallbackProviders: []string{"google"}},
+		MicrosoftTransConfig: &types.MicrosoftTransConfig{Enabled: true, ApiKey: "ms-key", Endpoint: msServer.URL},
+		GoogleTransConfig:    &types.GoogleTransConfig{Enabled: true, ApiKey: "google-key", Endpoint: googleServer.URL},
+	})
+
+	result, err := manager.BatchTranslate(context.Background(), &BatchTranslationRequest{Texts: []string{"hello", "world"}, TargetLang: "zh"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if msServer.requestCount() != 1 || googleServer.requestCount() != 1 {
+		t.Errorf("expected one request per provider, got microsoft=%d google=%d", msServer.requestCount(), googleServer.requestCount())
+	}
+	if result.Provider != "google" || result.Results[1].TranslatedText != "世界" {
+		t.Fatalf("unexpected result: provider=%s %+v", result.Provider, result.Results[1])
+	}
+}
+
+// 所有提供商都返回空译文时报错
+func TestManagerEmptyTranslationExhaustsChain(t *testing.T) {
+	msServer := newFakeServer(t, func(w http.ResponseWriter, r *http.Request, body []byte) {
+		writeJSON(w, http.StatusOK, []map[string]interface{}{
+			{"translations": []map[string]string{{"text": "", "to": "zh-Hans"}}},
+		})
+	})
+
+	manager := NewTranslatorManager(&types.AppConfig{
+		TranslatorConfig:     &types.TranslatorConfig{DefaultProvider: "microsoft", FallbackProviders: []string{"microsoft"}},
+		MicrosoftTransConfig: &types.MicrosoftTransConfig{Enabled: true, ApiKey: "ms-key", Endpoint: msServer.URL},
+	})
+
+	if _, err := manager.BatchTranslate(context.Background(), &BatchTranslationRequest{Texts: []string{"hello"}, TargetLang: "zh"}); err == nil {
+		t.Fatal("expected error when every provider returns an empty translation")
+	}
+}
@@ -0,0 +1,74 @@
+package translator
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+)
+
+// MemoryProviderHuman 人工校对（TMX 导入）译文的提供商标识，查找时优先于机器翻译
+const MemoryProviderHuman = "human"
+
+// MemoryProvider 翻译记忆的提供商/模型/提示词版本组合
+type MemoryProvider struct {
+	Provider      string
+	Model         string
+	PromptVersion string // 大模型翻译器当前启用的翻译提示词版本，其他提供商为空
+}
+
+// MemoryLookup 翻译记忆查询条件
+type MemoryLookup struct {
+	Texts           []string
+	SourceLang      string // 为空或 auto 时不限制源语言
+	TargetLang      string
+	GlossaryVersion string
+	Providers       []MemoryProvider // 按优先级排列，命中多个时取靠前的
+	MaxAge          time.Duration    // 机器翻译条目的有效期，0 表示不过期
+}
+
+// MemoryRecord 翻译记忆条目
+type MemoryRecord struct {
+	SourceText      string
+	TranslatedText  string
+	SourceLang      string
+	TargetLang      string
+	Provider        string
+	Model           string
+	GlossaryVersion string
+	PromptVersion   string
+}
+
+// TranslationMemory 翻译记忆（持久化缓存），由存储层实现
+type TranslationMemory interface {
+	// Lookup 返回命中的条目，key 为 Texts 中的下标
+	Lookup(ctx context.Context, query *MemoryLookup) (map[int]*MemoryRecord, error)
+	// Save 保存（或覆盖）翻译结果
+	Save(ctx context.Context, records []*MemoryRecord) error
+}
+
+// NormalizeSourceText 规范化原文：去除首尾空白并合并连续空白
+func NormalizeSourceText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// SourceTextHash 计算规范化原文的哈希，用作翻译记忆的索引键
+func SourceTextHash(text string) string {
+	sum := sha256.Sum256([]byte(NormalizeSourceText(text)))
+	return hex.EncodeToString(sum[:])
+}
+
+// NormalizeLangCode 规范化语言代码，使 zh / zh-CN / zh-Hans 等写法落到同一个键上
+func NormalizeLangCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	switch code {
+	case "", "auto":
+		return "auto"
+	case "zh-cn", "zh-hans", "zh-sg", "cn":
+		return "zh"
+	case "zh-hk", "zh-hant":
+		return "zh-tw"
+	}
+	return code
+}
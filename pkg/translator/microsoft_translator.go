@@ -0,0 +1,305 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/ratelimit"
+)
+
+// MicrosoftTranslator Microsoft Translator v3 翻译器
+type MicrosoftTranslator struct {
+	apiKey   string
+	region   string
+	endpoint string
+	client   *http.Client
+}
+
+// Microsoft v3 单次请求最多 1000 段、50000 字符，这里按段数保守分批
+const microsoftMaxBatchSize = 100
+
+// microsoftTextItem 请求体中的文本
+type microsoftTextItem struct {
+	Text string `json:"Text"`
+}
+
+// microsoftTranslateItem 翻译响应项
+type microsoftTranslateItem struct {
+	DetectedLanguage *struct {
+		Language string  `json:"language"`
+		Score    float64 `json:"score"`
+	} `json:"detectedLanguage,omitempty"`
+	Translations []struct {
+		Text string `json:"text"`
+		To   string `json:"to"`
+	} `json:"translations"`
+}
+
+// microsoftDetectItem 语言检测响应项
+type microsoftDetectItem struct {
+	Language string  `json:"language"`
+	Score    float64 `json:"score"`
+}
+
+// microsoftErrorResponse 错误响应
+type microsoftErrorResponse struct {
+	Error *struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// NewMicrosoftTranslator 创建 Microsoft 翻译器实例
+func NewMicrosoftTranslator(config *types.MicrosoftTransConfig) (*MicrosoftTranslator, error) {
+	if config == nil {
+		return nil, fmt.Errorf("microsoft translator config is nil")
+	}
+	if !config.Enabled {
+		return nil, fmt.Errorf("microsoft translator is not enabled")
+	}
+	if config.ApiKey == "" {
+		return nil, fmt.Errorf("microsoft API key is required")
+	}
+
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = "https://api.cognitive.microsofttranslator.com"
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 30
+	}
+
+	return &MicrosoftTranslator{
+		apiKey:   config.ApiKey,
+		region:   config.Region,
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		client: &http.Client{
+			Timeout: time.Duration(timeout) * time.Second,
+		},
+	}, nil
+}
+
+// Translate 单个文本翻译
+func (m *MicrosoftTranslator) Translate(ctx context.Context, req *TranslationRequest) (*TranslationResult, error) {
+	if req.Text == "" {
+		return nil, fmt.Errorf("text cannot be empty")
+	}
+
+	result, err := m.BatchTranslate(ctx, &BatchTranslationRequest{
+		Texts:      []string{req.Text},
+		SourceLang: req.SourceLang,
+		TargetLang: req.TargetLang,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Results[0], nil
+}
+
+// BatchTranslate 批量翻译
+func (m *MicrosoftTranslator) BatchTranslate(ctx context.Context, req *BatchTranslationRequest) (*BatchTranslationResult, error) {
+	if len(req.Texts) == 0 {
+		return nil, fmt.Errorf("texts cannot be empty")
+	}
+
+	startTime := time.Now()
+	results := make([]*TranslationResult, 0, len(req.Texts))
+	totalChars := 0
+
+	query := url.Values{}
+	query.Set("api-version", "3.0")
+	query.Set("to", m.convertLanguageCode(req.TargetLang))
+	if req.SourceLang != "" && req.SourceLang != "auto" {
+		query.Set("from", m.convertLanguageCode(req.SourceLang))
+	}
+
+	for i := 0; i < len(req.Texts); i += microsoftMaxBatchSize {
+		end := i + microsoftMaxBatchSize
+		if end > len(req.Texts) {
+			end = len(req.Texts)
+		}
+		batch := req.Texts[i:end]
+
+		items := make([]microsoftTextItem, len(batch))
+		for j, text := range batch {
+			items[j] = microsoftTextItem{Text: text}
+		}
+
+		var response []microsoftTranslateItem
+		if err := m.call(ctx, "POST", "/translate", query, items, &response); err != nil {
+			return nil, err
+		}
+		if len(response) != len(batch) {
+			return nil, fmt.Errorf("microsoft API returned %d translations, expected %d", len(response), len(batch))
+		}
+
+		for j, item := range response {
+			if len(item.Translations) == 0 {
+				return nil, fmt.Errorf("microsoft API returned no translation for text %d", i+j+1)
+			}
+			sourceLang := req.SourceLang
+			confidence := 0.0
+			if item.DetectedLanguage != nil {
+				sourceLang = item.DetectedLanguage.Language
+				confidence = item.DetectedLanguage.Score
+			}
+			results = append(results, &TranslationResult{
+				OriginalText:   batch[j],
+				TranslatedText: item.Translations[0].Text,
+				SourceLang:     sourceLang,
+				TargetLang:     req.TargetLang,
+				Confidence:     confidence,
+				Provider:       "microsoft",
+				Usage:          &Usage{Characters: len(batch[j])},
+			})
+			totalChars += len(batch[j])
+		}
+	}
+
+	return &BatchTranslationResult{
+		Results:  results,
+		Provider: "microsoft",
+		Usage: &Usage{
+			Characters: totalChars,
+			Duration:   time.Since(startTime).Milliseconds(),
+		},
+	}, nil
+}
+
+// GetSupportedLanguages 获取支持的语言列表
+func (m *MicrosoftTranslator) GetSupportedLanguages(ctx context.Context) ([]LanguageInfo, error) {
+	query := url.Values{}
+	query.Set("api-version", "3.0")
+	query.Set("scope", "translation")
+
+	var response struct {
+		Translation map[string]struct {
+			Name       string `json:"name"`
+			NativeName string `json:"nativeName"`
+			Dir        string `json:"dir"`
+		} `json:"translation"`
+	}
+	if err := m.call(ctx, "GET", "/languages", query, nil, &response); err != nil {
+		return nil, err
+	}
+
+	languages := make([]LanguageInfo, 0, len(response.Translation))
+	for code, lang := range response.Translation {
+		languages = append(languages, LanguageInfo{
+			Code:        code,
+			Name:        lang.Name,
+			NativeName:  lang.NativeName,
+			Direction:   lang.Dir,
+			IsSupported: true,
+		})
+	}
+	return languages, nil
+}
+
+// DetectLanguage 检测语言
+func (m *MicrosoftTranslator) DetectLanguage(ctx context.Context, text string) (string, float64, error) {
+	if text == "" {
+		return "", 0, fmt.Errorf("text cannot be empty")
+	}
+
+	query := url.Values{}
+	query.Set("api-version", "3.0")
+
+	var response []microsoftDetectItem
+	if err := m.call(ctx, "POST", "/detect", query, []microsoftTextItem{{Text: text}}, &response); err != nil {
+		return "", 0, err
+	}
+	if len(response) == 0 {
+		return "", 0, fmt.Errorf("no detection result in response")
+	}
+	return response[0].Language, response[0].Score, nil
+}
+
+// GetInfo 获取翻译器信息
+func (m *MicrosoftTranslator) GetInfo() *TranslatorInfo {
+	return &TranslatorInfo{
+		Name:               "Microsoft Translator",
+		Provider:           "microsoft",
+		Version:            "3.0",
+		MaxTextLength:      50000,
+		SupportedLanguages: commonLanguageCodes,
+		Features:           []string{"translate", "batch_translate", "detect_language"},
+		IsOnline:           true,
+	}
+}
+
+// IsHealthy 健康检查
+func (m *MicrosoftTranslator) IsHealthy(ctx context.Context) error {
+	if _, _, err := m.DetectLanguage(ctx, "Hello"); err != nil {
+		return fmt.Errorf("microsoft health check failed: %w", err)
+	}
+	return nil
+}
+
+// call 发送请求并解析响应，API 错误统一转换为 error 并附加 HTTP 状态码
+// （错误码为 429001 这类 6 位数字，无法从错误信息中解析状态码）
+func (m *MicrosoftTranslator) call(ctx context.Context, method, path string, query url.Values, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		jsonData, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewBuffer(jsonData)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, method, m.endpoint+path+"?"+query.Encode(), reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Ocp-Apim-Subscription-Key", m.apiKey)
+	if m.region != "" {
+		httpReq.Header.Set("Ocp-Apim-Subscription-Region", m.region)
+	}
+
+	resp, err := m.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		var apiErr microsoftErrorResponse
+		if err := json.Unmarshal(respBody, &apiErr); err == nil && apiErr.Error != nil {
+			return ratelimit.WithStatus(resp.StatusCode, fmt.Errorf("microsoft API error: %d - %s", apiErr.Error.Code, apiErr.Error.Message))
+		}
+		return ratelimit.WithStatus(resp.StatusCode, fmt.Errorf("microsoft API returned status %d: %s", resp.StatusCode, truncateBody(respBody)))
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// convertLanguageCode 转换语言代码到 Microsoft 格式
+func (m *MicrosoftTranslator) convertLanguageCode(code string) string {
+	switch strings.ToLower(code) {
+	case "zh", "zh-cn", "zh-hans":
+		return "zh-Hans"
+	case "zh-tw", "zh-hk", "zh-hant":
+		return "zh-Hant"
+	}
+	return code
+}
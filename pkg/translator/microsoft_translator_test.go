@@ -0,0 +1,133 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/ratelimit"
+)
+
+func newTestMicrosoftTranslator(t *testing.T, endpoint string) *MicrosoftTranslator {
+	t.Helper()
+	tr, err := NewMicrosoftTranslator(&types.MicrosoftTransConfig{Enabled: true, ApiKey: "ms-key", Region: "eastasia", Endpoint: endpoint})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tr
+}
+
+func TestMicrosoftBatchTranslateRequest(t *testing.T) {
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request, body []byte) {
+		writeJSON(w, http.StatusOK, []map[string]interface{}{
+			{
+				"detectedLanguage": map[string]interface{}{"language": "en", "score": 0.98},
+				"translations":     []map[string]string{{"text": "你好", "to": "zh-Hans"}},
+			},
+			{
+				"detectedLanguage": map[string]interface{}{"language": "en", "score": 0.95},
+				"translations":     []map[string]string{{"text": "世界", "to": "zh-Hans"}},
+			},
+		})
+	})
+	tr := newTestMicrosoftTranslator(t, server.URL+"/")
+
+	result, err := tr.BatchTranslate(context.Background(), &BatchTranslationRequest{
+		Texts:      []string{"hello", "world"},
+		TargetLang: "zh",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := server.lastRequest(t)
+	if req.Method != "POST" || req.Path != "/translate" {
+		t.Fatalf("unexpected request %s %s", req.Method, req.Path)
+	}
+	if req.Query["api-version"][0] != "3.0" || req.Query["to"][0] != "zh-Hans" {
+		t.Errorf("unexpected query: %v", req.Query)
+	}
+	if _, ok := req.Query["from"]; ok {
+		t.Errorf("from should be omitted without a source language: %v", req.Query)
+	}
+	for header, want := range map[string]string{
+		"Content-Type":                 "application/json",
+		"Ocp-Apim-Subscription-Key":    "ms-key",
+		"Ocp-Apim-Subscription-Region": "eastasia",
+	} {
+		if got := req.Header.Get(header); got != want {
+			t.Errorf("header %s = %q, want %q", header, got, want)
+		}
+	}
+
+	var body []map[string]string
+	if err := json.Unmarshal(req.Body, &body); err != nil {
+		t.Fatal(err)
+	}
+	if len(body) != 2 || body[0]["Text"] != "hello" || body[1]["Text"] != "world" {
+		t.Errorf("unexpected body: %s", req.Body)
+	}
+
+	if result.Results[0].TranslatedText != "你好" || result.Results[1].TranslatedText != "世界" {
+		t.Fatalf("unexpected results: %+v", result.Results)
+	}
+	if result.Results[0].SourceLang != "en" || result.Results[0].Confidence != 0.98 || result.Provider != "microsoft" {
+		t.Errorf("unexpected result metadata: %+v", result.Results[0])
+	}
+}
+
+func TestMicrosoftShortResultList(t *testing.T) {
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request, body []byte) {
+		writeJSON(w, http.StatusOK, []map[string]interface{}{
+			{"translations": []map[string]string{{"text": "你好", "to": "zh-Hans"}}},
+		})
+	})
+	tr := newTestMicrosoftTranslator(t, server.URL)
+
+	_, err := tr.BatchTranslate(context.Background(), &BatchTranslationRequest{Texts: []string{"hello", "world"}, TargetLang: "zh"})
+	if err == nil || !strings.Contains(err.Error(), "returned 1 translations, expected 2") {
+		t.Fatalf("expected short list error, got %v", err)
+	}
+}
+
+func TestMicrosoftMissingTranslation(t *testing.T) {
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request, body []byte) {
+		writeJSON(w, http.StatusOK, []map[string]interface{}{{"translations": []map[string]string{}}})
+	})
+	tr := newTestMicrosoftTranslator(t, server.URL)
+
+	if _, err := tr.BatchTranslate(context.Background(), &BatchTranslationRequest{Texts: []string{"hello"}, TargetLang: "zh"}); err == nil {
+		t.Fatal("expected error for empty translations")
+	}
+}
+
+func TestMicrosoftErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		status int
+		body   string
+		code   int
+	}{
+		{"throttled", http.StatusTooManyRequests, `{"error":{"code":429001,"message":"The server rejected the request because the client has exceeded request limits."}}`, 429},
+		{"unavailable", http.StatusServiceUnavailable, `{"error":{"code":503000,"message":"The service is temporarily unavailable."}}`, 503},
+		{"plain 502", http.StatusBadGateway, "bad gateway", 502},
+		{"unauthorized", http.StatusUnauthorized, `{"error":{"code":401000,"message":"invalid credentials"}}`, 401},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			server := newFakeServer(t, statusHandler(c.status, c.body))
+			tr := newTestMicrosoftTranslator(t, server.URL)
+
+			_, err := tr.BatchTranslate(context.Background(), &BatchTranslationRequest{Texts: []string{"hello"}, TargetLang: "zh"})
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if got := ratelimit.StatusCode(err); got != c.code {
+				t.Errorf("status code = %d, want %d (%v)", got, c.code, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,195 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/ratelimit"
+)
+
+// OllamaTranslator 基于 Ollama 本地大模型（/api/chat）的翻译器
+type OllamaTranslator struct {
+	chatTranslator
+	endpoint    string
+	temperature float64
+	client      *http.Client
+}
+
+// ollamaChatRequest Ollama 对话请求
+type ollamaChatRequest struct {
+	Model    string              `json:"model"`
+	Messages []ollamaChatMessage `json:"messages"`
+	Stream   bool                `json:"stream"`
+	Options  map[string]any      `json:"options,omitempty"`
+}
+
+// ollamaChatMessage Ollama 消息
+type ollamaChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// ollamaChatResponse Ollama 对话响应（stream=false）
+type ollamaChatResponse struct {
+	Model           string            `json:"model"`
+	Message         ollamaChatMessage `json:"message"`
+	Done            bool              `json:"done"`
+	PromptEvalCount int               `json:"prompt_eval_count"`
+	EvalCount       int               `json:"eval_count"`
+	Error           string            `json:"error,omitempty"`
+}
+
+// NewOllamaTranslator 创建 Ollama 翻译器实例
+func NewOllamaTranslator(config *types.OllamaTransConfig) (*OllamaTranslator, error) {
+	if config == nil {
+		return nil, fmt.Errorf("ollama translator config is nil")
+	}
+	if !config.Enabled {
+		return nil, fmt.Errorf("ollama translator is not enabled")
+	}
+	if config.Model == "" {
+		return nil, fmt.Errorf("ollama model is required")
+	}
+
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = "http://localhost:11434"
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 120 // 本地模型较慢，默认120秒
+	}
+
+	t := &OllamaTranslator{
+		endpoint:    strings.TrimSuffix(endpoint, "/"),
+		temperature: config.Temperature,
+		client: &http.Client{
+			Timeout: time.Duration(timeout) * time.Second,
+		},
+	}
+	t.chatTranslator = chatTranslator{
+		provider: "ollama",
+		model:    config.Model,
+		chat:     t.callChatAPI,
+	}
+	return t, nil
+}
+
+// GetInfo 获取翻译器信息
+func (t *OllamaTranslator) GetInfo() *TranslatorInfo {
+	return &TranslatorInfo{
+		Name:               fmt.Sprintf("Ollama Translator (%s)", t.model),
+		Provider:           "ollama",
+		Version:            "1.0.0",
+		MaxTextLength:      8000,
+		SupportedLanguages: commonLanguageCodes,
+		Features:           []string{"translate", "batch_translate", "detect_language"},
+		IsOnline:           false,
+	}
+}
+
+// IsHealthy 健康检查：确认服务可用且模型已下载
+func (t *OllamaTranslator) IsHealthy(ctx context.Context) error {
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", t.endpoint+"/api/tags", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("ollama health check failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ollama health check returned status %d", resp.StatusCode)
+	}
+
+	var tags struct {
+		Models []struct {
+			Name string `json:"name"`
+		} `json:"models"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	for _, model := range tags.Models {
+		if model.Name == t.model || strings.TrimSuffix(model.Name, ":latest") == t.model {
+			return nil
+		}
+	}
+	return fmt.Errorf("ollama model %s not found, run: ollama pull %s", t.model, t.model)
+}
+
+// callChatAPI 调用 Ollama 对话接口
+func (t *OllamaTranslator) callChatAPI(ctx context.Context, systemPrompt, userPrompt string) (string, *Usage, error) {
+	reqBody := ollamaChatRequest{
+		Model: t.model,
+		Messages: []ollamaChatMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: userPrompt},
+		},
+		Stream: false,
+	}
+	if t.temperature > 0 {
+		reqBody.Options = map[string]any{"temperature": t.temperature}
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", t.endpoint+"/api/chat", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var response ollamaChatResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", nil, ratelimit.WithStatus(resp.StatusCode, fmt.Errorf("ollama API returned status %d: %s", resp.StatusCode, truncateBody(body)))
+	}
+	if resp.StatusCode != http.StatusOK || response.Error != "" {
+		return "", nil, ratelimit.WithStatus(resp.StatusCode, fmt.Errorf("ollama API returned status %d: %s", resp.StatusCode, response.Error))
+	}
+	if strings.TrimSpace(response.Message.Content) == "" {
+		return "", nil, fmt.Errorf("no translation result in response")
+	}
+
+	usage := &Usage{
+		InputTokens:  response.PromptEvalCount,
+		OutputTokens: response.EvalCount,
+		TotalTokens:  response.PromptEvalCount + response.EvalCount,
+	}
+	return response.Message.Content, usage, nil
+}
+
+// truncateBody 截断响应体用于错误信息
+func truncateBody(body []byte) string {
+	const maxLen = 200
+	text := strings.TrimSpace(string(body))
+	if len(text) > maxLen {
+		return text[:maxLen] + "..."
+	}
+	return text
+}
@@ -0,0 +1,134 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/ratelimit"
+)
+
+// ollamaHandler 返回 Ollama 格式响应的假服务器 handler
+func ollamaHandler(skip map[int]bool) func(w http.ResponseWriter, r *http.Request, body []byte) {
+	return func(w http.ResponseWriter, r *http.Request, body []byte) {
+		if r.URL.Path == "/api/tags" {
+			writeJSON(w, http.StatusOK, map[string]interface{}{
+				"models": []map[string]string{{"name": "qwen2.5:7b"}, {"name": "llama3:latest"}},
+			})
+			return
+		}
+		var req ollamaChatRequest
+		json.Unmarshal(body, &req)
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"model":             req.Model,
+			"message":           map[string]string{"role": "assistant", "content": fakeChatModel(req.Messages[len(req.Messages)-1].Content, skip)},
+			"done":              true,
+			"prompt_eval_count": 12,
+			"eval_count":        5,
+		})
+	}
+}
+
+func newTestOllamaTranslator(t *testing.T, endpoint, model string) *OllamaTranslator {
+	t.Helper()
+	tr, err := NewOllamaTranslator(&types.OllamaTransConfig{Enabled: true, Model: model, Endpoint: endpoint, Temperature: 0.2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tr
+}
+
+func TestOllamaTranslateRequest(t *testing.T) {
+	server := newFakeServer(t, ollamaHandler(nil))
+	tr := newTestOllamaTranslator(t, server.URL+"/", "qwen2.5:7b")
+
+	result, err := tr.Translate(context.Background(), &TranslationRequest{Text: "hello", TargetLang: "zh"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := server.lastRequest(t)
+	if req.Method != "POST" || req.Path != "/api/chat" {
+		t.Fatalf("unexpected request %s %s", req.Method, req.Path)
+	}
+	if got := req.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("content type = %q", got)
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(req.Body, &body); err != nil {
+		t.Fatal(err)
+	}
+	if body["model"] != "qwen2.5:7b" || body["stream"] != false {
+		t.Errorf("unexpected body: %s", req.Body)
+	}
+	if options, _ := body["options"].(map[string]interface{}); options["temperature"] != 0.2 {
+		t.Errorf("unexpected options: %v", body["options"])
+	}
+
+	if result.TranslatedText != "译:hello" || result.SourceLang != "auto" || result.Provider != "ollama" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if result.Usage.InputTokens != 12 || result.Usage.OutputTokens != 5 || result.Usage.TotalTokens != 17 {
+		t.Errorf("unexpected usage: %+v", result.Usage)
+	}
+}
+
+func TestOllamaBatchTranslateShortResultList(t *testing.T) {
+	server := newFakeServer(t, ollamaHandler(map[int]bool{1: true}))
+	tr := newTestOllamaTranslator(t, server.URL, "qwen2.5:7b")
+
+	result, err := tr.BatchTranslate(context.Background(), &BatchTranslationRequest{
+		Texts:      []string{"first", "second"},
+		TargetLang: "zh",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Results[0].TranslatedText != "译:first" || result.Results[1].TranslatedText != "译:second" {
+		t.Fatalf("unexpected results: %q, %q", result.Results[0].TranslatedText, result.Results[1].TranslatedText)
+	}
+}
+
+func TestOllamaErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		status int
+		body   string
+		code   int
+	}{
+		{"model missing", http.StatusNotFound, `{"error":"model 'qwen2.5:7b' not found"}`, 404},
+		{"overloaded", http.StatusServiceUnavailable, `{"error":"server busy"}`, 503},
+		{"proxy", http.StatusBadGateway, "bad gateway", 502},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			server := newFakeServer(t, statusHandler(c.status, c.body))
+			tr := newTestOllamaTranslator(t, server.URL, "qwen2.5:7b")
+
+			_, err := tr.Translate(context.Background(), &TranslationRequest{Text: "hello", TargetLang: "zh"})
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if got := ratelimit.StatusCode(err); got != c.code {
+				t.Errorf("status code = %d, want %d (%v)", got, c.code, err)
+			}
+		})
+	}
+}
+
+func TestOllamaIsHealthy(t *testing.T) {
+	server := newFakeServer(t, ollamaHandler(nil))
+
+	if err := newTestOllamaTranslator(t, server.URL, "llama3").IsHealthy(context.Background()); err != nil {
+		t.Errorf("llama3 should match llama3:latest: %v", err)
+	}
+	if err := newTestOllamaTranslator(t, server.URL, "mistral").IsHealthy(context.Background()); err == nil {
+		t.Error("expected error for a model that is not pulled")
+	}
+	if req := server.lastRequest(t); req.Method != "GET" || req.Path != "/api/tags" {
+		t.Errorf("unexpected request %s %s", req.Method, req.Path)
+	}
+}
@@ -0,0 +1,175 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/ratelimit"
+)
+
+// OpenAITranslator 基于 OpenAI 兼容接口（/chat/completions）的翻译器，
+// 适用于 OpenAI、通义千问、智谱、vLLM、LM Studio 等兼容服务
+type OpenAITranslator struct {
+	chatTranslator
+	apiKey      string
+	baseURL     string
+	maxTokens   int
+	temperature float64
+	client      *http.Client
+}
+
+// openAIChatRequest OpenAI 对话请求
+type openAIChatRequest struct {
+	Model       string              `json:"model"`
+	Messages    []openAIChatMessage `json:"messages"`
+	MaxTokens   int                 `json:"max_tokens,omitempty"`
+	Temperature float64             `json:"temperature,omitempty"`
+}
+
+// openAIChatMessage OpenAI 消息
+type openAIChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// openAIChatResponse OpenAI 对话响应
+type openAIChatResponse struct {
+	Choices []struct {
+		Message openAIChatMessage `json:"message"`
+	} `json:"choices"`
+	Usage struct {
+		PromptTokens     int `json:"prompt_tokens"`
+		CompletionTokens int `json:"completion_tokens"`
+		TotalTokens      int `json:"total_tokens"`
+	} `json:"usage"`
+	Error *struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error,omitempty"`
+}
+
+// NewOpenAITranslator 创建 OpenAI 兼容翻译器实例
+func NewOpenAITranslator(config *types.OpenAICompatibleConfig) (*OpenAITranslator, error) {
+	if config == nil {
+		return nil, fmt.Errorf("openai translator config is nil")
+	}
+	if !config.Enabled {
+		return nil, fmt.Errorf("openai translator is not enabled")
+	}
+	if config.APIKey == "" {
+		return nil, fmt.Errorf("openai API key is required")
+	}
+
+	baseURL := config.BaseURL
+	if baseURL == "" {
+		baseURL = "https://api.openai.com/v1"
+	}
+
+	model := config.Model
+	if model == "" {
+		model = "gpt-4o-mini"
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 60
+	}
+
+	maxTokens := config.MaxTokens
+	if maxTokens == 0 {
+		maxTokens = 4000
+	}
+
+	t := &OpenAITranslator{
+		apiKey:      config.APIKey,
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+		maxTokens:   maxTokens,
+		temperature: config.Temperature,
+		client: &http.Client{
+			Timeout: time.Duration(timeout) * time.Second,
+		},
+	}
+	t.chatTranslator = chatTranslator{
+		provider: "openai",
+		model:    model,
+		chat:     t.callChatAPI,
+	}
+	return t, nil
+}
+
+// GetInfo 获取翻译器信息
+func (t *OpenAITranslator) GetInfo() *TranslatorInfo {
+	return &TranslatorInfo{
+		Name:               fmt.Sprintf("OpenAI Compatible Translator (%s)", t.model),
+		Provider:           "openai",
+		Version:            "1.0.0",
+		MaxTextLength:      32000,
+		SupportedLanguages: commonLanguageCodes,
+		Features:           []string{"translate", "batch_translate", "detect_language"},
+		IsOnline:           true,
+	}
+}
+
+// callChatAPI 调用 /chat/completions 接口
+func (t *OpenAITranslator) callChatAPI(ctx context.Context, systemPrompt, userPrompt string) (string, *Usage, error) {
+	reqBody := openAIChatRequest{
+		Model: t.model,
+		Messages: []openAIChatMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: userPrompt},
+		},
+		MaxTokens:   t.maxTokens,
+		Temperature: t.temperature,
+	}
+
+	jsonData, err := json.Marshal(reqBody)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", t.baseURL+"/chat/completions", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+t.apiKey)
+
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var response openAIChatResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return "", nil, ratelimit.WithStatus(resp.StatusCode, fmt.Errorf("openai API returned status %d: %s", resp.StatusCode, truncateBody(body)))
+	}
+	if response.Error != nil {
+		return "", nil, ratelimit.WithStatus(resp.StatusCode, fmt.Errorf("openai API returned status %d: %s (%s)", resp.StatusCode, response.Error.Message, response.Error.Type))
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", nil, ratelimit.WithStatus(resp.StatusCode, fmt.Errorf("openai API returned status %d", resp.StatusCode))
+	}
+	if len(response.Choices) == 0 {
+		return "", nil, fmt.Errorf("no translation result in response")
+	}
+
+	usage := &Usage{
+		InputTokens:  response.Usage.PromptTokens,
+		OutputTokens: response.Usage.CompletionTokens,
+		TotalTokens:  response.Usage.TotalTokens,
+	}
+	return response.Choices[0].Message.Content, usage, nil
+}
@@ -0,0 +1,196 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/ratelimit"
+)
+
+// fakeChatModel 模拟大模型：结构化请求按编号返回 "译:原文"，跳过 skip 中的编号；普通请求返回 "译:原文"
+func fakeChatModel(userPrompt string, skip map[int]bool) string {
+	var payload struct {
+		Cues []CueText `json:"cues"`
+	}
+	if err := json.Unmarshal([]byte(userPrompt), &payload); err != nil || payload.Cues == nil {
+		return "译:" + userPrompt
+	}
+	out := make([]CueText, 0, len(payload.Cues))
+	for _, cue := range payload.Cues {
+		if !skip[cue.ID] {
+			out = append(out, CueText{ID: cue.ID, Text: "译:" + cue.Text})
+		}
+	}
+	data, _ := json.Marshal(out)
+	return "```json\n" + string(data) + "\n```"
+}
+
+// openAIHandler 返回 OpenAI 格式响应的假服务器 handler
+func openAIHandler(skip map[int]bool) func(w http.ResponseWriter, r *http.Request, body []byte) {
+	return func(w http.ResponseWriter, r *http.Request, body []byte) {
+		var req openAIChatRequest
+		json.Unmarshal(body, &req)
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"choices": []map[string]interface{}{
+				{"message": map[string]string{"role": "assistant", "content": fakeChatModel(req.Messages[len(req.Messages)-1].Content, skip)}},
+			},
+			"usage": map[string]int{"prompt_tokens": 10, "completion_tokens": 6, "total_tokens": 16},
+		})
+	}
+}
+
+func newTestOpenAITranslator(t *testing.T, baseURL string) *OpenAITranslator {
+	t.Helper()
+	tr, err := NewOpenAITranslator(&types.OpenAICompatibleConfig{
+		Enabled:     true,
+		APIKey:      "sk-test",
+		BaseURL:     baseURL,
+		Model:       "qwen-plus",
+		MaxTokens:   2048,
+		Temperature: 0.3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tr
+}
+
+func TestOpenAITranslateRequest(t *testing.T) {
+	server := newFakeServer(t, openAIHandler(nil))
+	tr := newTestOpenAITranslator(t, server.URL+"/v1/")
+
+	result, err := tr.Translate(context.Background(), &TranslationRequest{Text: "hello", SourceLang: "en", TargetLang: "zh"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := server.lastRequest(t)
+	if req.Method != "POST" || req.Path != "/v1/chat/completions" {
+		t.Fatalf("unexpected request %s %s", req.Method, req.Path)
+	}
+	if got := req.Header.Get("Authorization"); got != "Bearer sk-test" {
+		t.Errorf("authorization = %q", got)
+	}
+	if got := req.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("content type = %q", got)
+	}
+
+	var body openAIChatRequest
+	if err := json.Unmarshal(req.Body, &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Model != "qwen-plus" || body.MaxTokens != 2048 || body.Temperature != 0.3 {
+		t.Errorf("unexpected body: %s", req.Body)
+	}
+	if len(body.Messages) != 2 || body.Messages[0].Role != "system" || body.Messages[1].Role != "user" || body.Messages[1].Content != "hello" {
+		t.Errorf("unexpected messages: %+v", body.Messages)
+	}
+
+	if result.TranslatedText != "译:hello" || result.Model != "qwen-plus" || result.Provider != "openai" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if result.Usage.InputTokens != 10 || result.Usage.OutputTokens != 6 || result.Usage.TotalTokens != 16 {
+		t.Errorf("unexpected usage: %+v", result.Usage)
+	}
+}
+
+func TestOpenAIBatchTranslate(t *testing.T) {
+	server := newFakeServer(t, openAIHandler(nil))
+	tr := newTestOpenAITranslator(t, server.URL)
+
+	result, err := tr.BatchTranslate(context.Background(), &BatchTranslationRequest{
+		Texts:      []string{"hello", "good\nmorning", "world"},
+		TargetLang: "zh",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.requestCount() != 1 {
+		t.Fatalf("expected a single structured request, got %d", server.requestCount())
+	}
+
+	var body openAIChatRequest
+	json.Unmarshal(server.lastRequest(t).Body, &body)
+	var payload struct {
+		Cues []CueText `json:"cues"`
+	}
+	if err := json.Unmarshal([]byte(body.Messages[1].Content), &payload); err != nil {
+		t.Fatalf("user message should be a cue payload: %v", err)
+	}
+	if len(payload.Cues) != 3 || payload.Cues[1].ID != 2 || payload.Cues[1].Text != "good morning" {
+		t.Errorf("unexpected cues: %+v", payload.Cues)
+	}
+
+	want := []string{"译:hello", "译:good morning", "译:world"}
+	for i, r := range result.Results {
+		if r.TranslatedText != want[i] {
+			t.Errorf("result %d = %q, want %q", i, r.TranslatedText, want[i])
+		}
+	}
+}
+
+// 模型始终遗漏某个编号时，重试后逐条补译，结果仍与原文一一对应
+func TestOpenAIBatchTranslateShortResultList(t *testing.T) {
+	server := newFakeServer(t, openAIHandler(map[int]bool{2: true}))
+	tr := newTestOpenAITranslator(t, server.URL)
+
+	result, err := tr.BatchTranslate(context.Background(), &BatchTranslationRequest{
+		Texts:      []string{"hello", "world", "again"},
+		TargetLang: "zh",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := 1 + maxCueRetries + 1; server.requestCount() != want {
+		t.Errorf("expected %d requests, got %d", want, server.requestCount())
+	}
+	if len(result.Results) != 3 || result.Results[1].OriginalText != "world" || result.Results[1].TranslatedText != "译:world" {
+		t.Fatalf("unexpected results: %+v", result.Results)
+	}
+	if result.Results[2].TranslatedText != "译:again" {
+		t.Errorf("later cues should not shift: %q", result.Results[2].TranslatedText)
+	}
+}
+
+func TestOpenAIErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		status int
+		body   string
+		code   int
+	}{
+		{"rate limited", http.StatusTooManyRequests, `{"error":{"message":"Rate limit reached","type":"requests"}}`, 429},
+		{"server error", http.StatusInternalServerError, `{"error":{"message":"The server had an error","type":"server_error"}}`, 500},
+		{"gateway html", http.StatusBadGateway, "<html>bad gateway</html>", 502},
+		{"empty 503", http.StatusServiceUnavailable, `{}`, 503},
+		{"invalid key", http.StatusUnauthorized, `{"error":{"message":"Incorrect API key","type":"invalid_request_error"}}`, 401},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			server := newFakeServer(t, statusHandler(c.status, c.body))
+			tr := newTestOpenAITranslator(t, server.URL)
+
+			_, err := tr.Translate(context.Background(), &TranslationRequest{Text: "hello", TargetLang: "zh"})
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if got := ratelimit.StatusCode(err); got != c.code {
+				t.Errorf("status code = %d, want %d (%v)", got, c.code, err)
+			}
+		})
+	}
+}
+
+func TestOpenAINoChoices(t *testing.T) {
+	server := newFakeServer(t, statusHandler(http.StatusOK, `{"choices":[]}`))
+	tr := newTestOpenAITranslator(t, server.URL)
+
+	_, err := tr.Translate(context.Background(), &TranslationRequest{Text: "hello", TargetLang: "zh"})
+	if err == nil || !strings.Contains(err.Error(), "no translation result") {
+		t.Fatalf("expected no result error, got %v", err)
+	}
+}
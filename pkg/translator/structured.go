@@ -0,0 +1,207 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CueText 带编号的字幕文本。批量翻译以 JSON 收发编号和文本，译文按编号回填，
+// 模型遗漏或多输出某一条时不会导致后续所有字幕错位
+type CueText struct {
+	ID   int    `json:"id"`
+	Text string `json:"text"`
+}
+
+const (
+	// 缺失编号的最大重新请求次数
+	maxCueRetries = 2
+	// 重新请求缺失编号时，前后各附带几条只读上下文
+	cueContextSize = 2
+)
+
+// CueFormatPrompt 结构化批量翻译的输入输出格式说明，追加在系统提示词末尾
+func CueFormatPrompt() string {
+	return `
+输入格式：JSON 对象，cues 为需要翻译的条目（id 为编号），context 为前后文，仅供理解语境，不需要翻译也不要输出。
+
+输出格式：只返回 JSON 数组，cues 中的每个条目对应一个元素：[{"id":编号,"text":"译文"}]
+- id 必须与输入完全一致，不要遗漏、合并、拆分或新增条目
+- 不要输出 context 中的条目，不要包含任何解释或 markdown 标记`
+}
+
+// FormatCuePayload 构建结构化批量翻译的用户消息
+func FormatCuePayload(cues, context []CueText) string {
+	payload := struct {
+		Context []CueText `json:"context,omitempty"`
+		Cues    []CueText `json:"cues"`
+	}{Context: context, Cues: cues}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.Encode(payload)
+	return strings.TrimSpace(buf.String())
+}
+
+// ParseCueTranslations 解析模型返回的 JSON 译文（兼容 markdown 代码块和 {"cues": [...]} 包裹），
+// 只接受请求中的编号，返回编号到译文的映射以及缺失（未返回或译文为空）的编号
+func ParseCueTranslations(content string, ids []int) (map[int]string, []int, error) {
+	requested := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		requested[id] = true
+	}
+
+	items, err := decodeCueItems(content)
+	translations := make(map[int]string, len(ids))
+	extra := 0
+	for _, item := range items {
+		id, ok := cueID(item.ID)
+		if !ok || !requested[id] {
+			extra++
+			continue
+		}
+		text := strings.TrimSpace(item.Text)
+		if text == "" || translations[id] != "" {
+			continue
+		}
+		translations[id] = text
+	}
+	if extra > 0 {
+		logger.Warnf("Structured translation returned %d unexpected cue ids, ignored", extra)
+	}
+
+	var missing []int
+	for _, id := range ids {
+		if _, ok := translations[id]; !ok {
+			missing = append(missing, id)
+		}
+	}
+	return translations, missing, err
+}
+
+type cueItem struct {
+	ID   interface{} `json:"id"`
+	Text string      `json:"text"`
+}
+
+func decodeCueItems(content string) ([]cueItem, error) {
+	content = strings.TrimSpace(content)
+
+	if start, end := strings.Index(content, "["), strings.LastIndex(content, "]"); start >= 0 && end > start {
+		var items []cueItem
+		if err := json.Unmarshal([]byte(content[start:end+1]), &items); err == nil {
+			return items, nil
+		}
+	}
+	if start, end := strings.Index(content, "{"), strings.LastIndex(content, "}"); start >= 0 && end > start {
+		var wrapped struct {
+			Cues         []cueItem `json:"cues"`
+			Translations []cueItem `json:"translations"`
+		}
+		if err := json.Unmarshal([]byte(content[start:end+1]), &wrapped); err == nil {
+			return append(wrapped.Cues, wrapped.Translations...), nil
+		}
+	}
+	return nil, fmt.Errorf("no valid JSON cue array in response")
+}
+
+// cueID 兼容数字和字符串形式的编号
+func cueID(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case float64:
+		return int(v), v == float64(int(v))
+	case string:
+		id, err := strconv.Atoi(strings.TrimSpace(v))
+		return id, err == nil
+	}
+	return 0, false
+}
+
+// translateCues 以 JSON 编号格式批量翻译 texts（编号从 1 开始）。
+// 缺失的编号只重新请求缺失部分，并附带前后文作为只读上下文；
+// 返回与 texts 一一对应的译文、累计用量，以及多次请求后仍缺失的下标（由调用方逐条补译）
+func translateCues(ctx context.Context, systemPrompt string, texts []string, call chatFunc) ([]string, *Usage, []int, error) {
+	translated := make([]string, len(texts))
+	total := &Usage{}
+
+	pending := make([]int, len(texts))
+	for i := range texts {
+		pending[i] = i
+	}
+
+	for attempt := 0; attempt <= maxCueRetries && len(pending) > 0; attempt++ {
+		if attempt > 0 {
+			logger.Warnf("Structured translation missing %d/%d cues, re-requesting (%d/%d)", len(pending), len(texts), attempt, maxCueRetries)
+		}
+
+		cues := make([]CueText, len(pending))
+		ids := make([]int, len(pending))
+		for j, idx := range pending {
+			cues[j] = CueText{ID: idx + 1, Text: strings.Join(strings.Fields(texts[idx]), " ")}
+			ids[j] = idx + 1
+		}
+		var context []CueText
+		if attempt > 0 {
+			context = cueContext(texts, pending)
+		}
+
+		content, usage, err := call(ctx, systemPrompt, FormatCuePayload(cues, context))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if usage != nil {
+			total.InputTokens += usage.InputTokens
+			total.OutputTokens += usage.OutputTokens
+			total.TotalTokens += usage.TotalTokens
+		}
+
+		got, missing, err := ParseCueTranslations(content, ids)
+		if err != nil {
+			logger.Warnf("Failed to parse structured translation response: %v", err)
+		}
+		for id, text := range got {
+			translated[id-1] = text
+		}
+
+		pending = pending[:0]
+		for _, id := range missing {
+			pending = append(pending, id-1)
+		}
+	}
+
+	return translated, total, pending, nil
+}
+
+// cueContext 收集缺失条目前后的已翻译条目原文作为只读上下文
+func cueContext(texts []string, pending []int) []CueText {
+	isPending := make(map[int]bool, len(pending))
+	for _, idx := range pending {
+		isPending[idx] = true
+	}
+
+	selected := make(map[int]bool)
+	for _, idx := range pending {
+		for k := idx - cueContextSize; k <= idx+cueContextSize; k++ {
+			if k >= 0 && k < len(texts) && !isPending[k] {
+				selected[k] = true
+			}
+		}
+	}
+
+	indexes := make([]int, 0, len(selected))
+	for idx := range selected {
+		indexes = append(indexes, idx)
+	}
+	sort.Ints(indexes)
+
+	context := make([]CueText, len(indexes))
+	for j, idx := range indexes {
+		context[j] = CueText{ID: idx + 1, Text: strings.Join(strings.Fields(texts[idx]), " ")}
+	}
+	return context
+}
@@ -0,0 +1,350 @@
+package translator
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/ratelimit"
+)
+
+// TencentTranslator 腾讯云机器翻译（TMT）翻译器，使用 TC3-HMAC-SHA256 签名
+type TencentTranslator struct {
+	secretId  string
+	secretKey string
+	region    string
+	endpoint  string
+	host      string
+	projectId int64
+	client    *http.Client
+}
+
+const (
+	tencentService = "tmt"
+	tencentVersion = "2018-03-21"
+	// TextTranslateBatch 单次请求文本总长度需小于 6000 字符，这里按段数保守分批
+	tencentMaxBatchSize = 50
+)
+
+// tencentError 腾讯云 API 错误
+type tencentError struct {
+	Code    string `json:"Code"`
+	Message string `json:"Message"`
+}
+
+// err 转换为 error。腾讯云限流和服务端错误也以 HTTP 200 返回，按错误码附加对应的 HTTP 状态码供熔断器判断
+func (e *tencentError) err() error {
+	err := fmt.Errorf("tencent API error: %s - %s", e.Code, e.Message)
+	switch {
+	case strings.HasPrefix(e.Code, "RequestLimitExceeded"):
+		return ratelimit.WithStatus(http.StatusTooManyRequests, err)
+	case strings.HasPrefix(e.Code, "InternalError"), strings.HasPrefix(e.Code, "ResourceUnavailable"):
+		return ratelimit.WithStatus(http.StatusServiceUnavailable, err)
+	}
+	return err
+}
+
+// tencentBatchResponse TextTranslateBatch 响应
+type tencentBatchResponse struct {
+	Response struct {
+		Source         string        `json:"Source"`
+		Target         string        `json:"Target"`
+		TargetTextList []string      `json:"TargetTextList"`
+		RequestId      string        `json:"RequestId"`
+		Error          *tencentError `json:"Error,omitempty"`
+	} `json:"Response"`
+}
+
+// tencentDetectResponse LanguageDetect 响应
+type tencentDetectResponse struct {
+	Response struct {
+		Lang      string        `json:"Lang"`
+		RequestId string        `json:"RequestId"`
+		Error     *tencentError `json:"Error,omitempty"`
+	} `json:"Response"`
+}
+
+// NewTencentTranslator 创建腾讯云翻译器实例
+func NewTencentTranslator(config *types.TencentTransConfig) (*TencentTranslator, error) {
+	if config == nil {
+		return nil, fmt.Errorf("tencent translator config is nil")
+	}
+	if !config.Enabled {
+		return nil, fmt.Errorf("tencent translator is not enabled")
+	}
+	if config.SecretId == "" || config.SecretKey == "" {
+		return nil, fmt.Errorf("tencent secret_id and secret_key are required")
+	}
+
+	endpoint := config.Endpoint
+	if endpoint == "" {
+		endpoint = "https://tmt.tencentcloudapi.com"
+	}
+	parsed, err := url.Parse(endpoint)
+	if err != nil || parsed.Host == "" {
+		return nil, fmt.Errorf("invalid tencent endpoint: %s", endpoint)
+	}
+
+	region := config.Region
+	if region == "" {
+		region = "ap-guangzhou"
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 30
+	}
+
+	return &TencentTranslator{
+		secretId:  config.SecretId,
+		secretKey: config.SecretKey,
+		region:    region,
+		endpoint:  strings.TrimSuffix(endpoint, "/"),
+		host:      parsed.Host,
+		projectId: config.ProjectId,
+		client: &http.Client{
+			Timeout: time.Duration(timeout) * time.Second,
+		},
+	}, nil
+}
+
+// Translate 单个文本翻译
+func (t *TencentTranslator) Translate(ctx context.Context, req *TranslationRequest) (*TranslationResult, error) {
+	if req.Text == "" {
+		return nil, fmt.Errorf("text cannot be empty")
+	}
+
+	result, err := t.BatchTranslate(ctx, &BatchTranslationRequest{
+		Texts:      []string{req.Text},
+		SourceLang: req.SourceLang,
+		TargetLang: req.TargetLang,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result.Results[0], nil
+}
+
+// BatchTranslate 批量翻译（TextTranslateBatch）
+func (t *TencentTranslator) BatchTranslate(ctx context.Context, req *BatchTranslationRequest) (*BatchTranslationResult, error) {
+	if len(req.Texts) == 0 {
+		return nil, fmt.Errorf("texts cannot be empty")
+	}
+
+	startTime := time.Now()
+	results := make([]*TranslationResult, 0, len(req.Texts))
+	totalChars := 0
+
+	source := "auto"
+	if req.SourceLang != "" {
+		source = t.convertLanguageCode(req.SourceLang)
+	}
+	target := t.convertLanguageCode(req.TargetLang)
+
+	for i := 0; i < len(req.Texts); i += tencentMaxBatchSize {
+		end := i + tencentMaxBatchSize
+		if end > len(req.Texts) {
+			end = len(req.Texts)
+		}
+		batch := req.Texts[i:end]
+
+		var response tencentBatchResponse
+		err := t.call(ctx, "TextTranslateBatch", map[string]interface{}{
+			"Source":         source,
+			"Target":         target,
+			"ProjectId":      t.projectId,
+			"SourceTextList": batch,
+		}, &response)
+		if err != nil {
+			return nil, err
+		}
+		if apiErr := response.Response.Error; apiErr != nil {
+			return nil, apiErr.err()
+		}
+		if len(response.Response.TargetTextList) != len(batch) {
+			return nil, fmt.Errorf("tencent API returned %d translations, expected %d", len(response.Response.TargetTextList), len(batch))
+		}
+
+		for j, text := range response.Response.TargetTextList {
+			results = append(results, &TranslationResult{
+				OriginalText:   batch[j],
+				TranslatedText: text,
+				SourceLang:     response.Response.Source,
+				TargetLang:     req.TargetLang,
+				Provider:       "tencent",
+				Usage:          &Usage{Characters: len(batch[j])},
+			})
+			totalChars += len(batch[j])
+		}
+	}
+
+	return &BatchTranslationResult{
+		Results:  results,
+		Provider: "tencent",
+		Usage: &Usage{
+			Characters: totalChars,
+			Duration:   time.Since(startTime).Milliseconds(),
+		},
+	}, nil
+}
+
+// GetSupportedLanguages 获取支持的语言列表（TMT 没有查询接口，返回常用语言）
+func (t *TencentTranslator) GetSupportedLanguages(ctx context.Context) ([]LanguageInfo, error) {
+	languages := make([]LanguageInfo, 0, len(commonLanguageCodes))
+	for _, code := range commonLanguageCodes {
+		languages = append(languages, LanguageInfo{
+			Code:        code,
+			Name:        LanguageName(code),
+			NativeName:  LanguageName(code),
+			Direction:   languageDirection(code),
+			IsSupported: true,
+		})
+	}
+	return languages, nil
+}
+
+// DetectLanguage 检测语言（LanguageDetect）
+func (t *TencentTranslator) DetectLanguage(ctx context.Context, text string) (string, float64, error) {
+	if text == "" {
+		return "", 0, fmt.Errorf("text cannot be empty")
+	}
+
+	var response tencentDetectResponse
+	err := t.call(ctx, "LanguageDetect", map[string]interface{}{
+		"Text":      text,
+		"ProjectId": t.projectId,
+	}, &response)
+	if err != nil {
+		return "", 0, err
+	}
+	if apiErr := response.Response.Error; apiErr != nil {
+		return "", 0, apiErr.err()
+	}
+	return response.Response.Lang, 0.9, nil
+}
+
+// GetInfo 获取翻译器信息
+func (t *TencentTranslator) GetInfo() *TranslatorInfo {
+	return &TranslatorInfo{
+		Name:               "Tencent Machine Translation",
+		Provider:           "tencent",
+		Version:            tencentVersion,
+		MaxTextLength:      6000,
+		SupportedLanguages: commonLanguageCodes,
+		Features:           []string{"translate", "batch_translate", "detect_language"},
+		IsOnline:           true,
+	}
+}
+
+// IsHealthy 健康检查
+func (t *TencentTranslator) IsHealthy(ctx context.Context) error {
+	if _, _, err := t.DetectLanguage(ctx, "Hello"); err != nil {
+		return fmt.Errorf("tencent health check failed: %w", err)
+	}
+	return nil
+}
+
+// call 签名并调用腾讯云 API
+func (t *TencentTranslator) call(ctx context.Context, action string, payload interface{}, out interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", t.endpoint, bytes.NewBuffer(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json; charset=utf-8")
+	httpReq.Header.Set("Host", t.host)
+	httpReq.Header.Set("X-TC-Action", action)
+	httpReq.Header.Set("X-TC-Timestamp", strconv.FormatInt(timestamp, 10))
+	httpReq.Header.Set("X-TC-Version", tencentVersion)
+	httpReq.Header.Set("X-TC-Region", t.region)
+	httpReq.Header.Set("Authorization", tc3Authorization(t.secretId, t.secretKey, tencentService, t.host, body, timestamp))
+
+	resp, err := t.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return ratelimit.WithStatus(resp.StatusCode, fmt.Errorf("tencent API returned status %d: %s", resp.StatusCode, truncateBody(respBody)))
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// tc3Authorization 生成 TC3-HMAC-SHA256 签名的 Authorization 头（POST JSON 请求，签名 content-type 和 host）
+func tc3Authorization(secretId, secretKey, service, host string, payload []byte, timestamp int64) string {
+	const algorithm = "TC3-HMAC-SHA256"
+	const signedHeaders = "content-type;host"
+
+	canonicalRequest := strings.Join([]string{
+		"POST",
+		"/",
+		"",
+		"content-type:application/json; charset=utf-8\nhost:" + host + "\n",
+		signedHeaders,
+		sha256Hex(payload),
+	}, "\n")
+
+	date := time.Unix(timestamp, 0).UTC().Format("2006-01-02")
+	credentialScope := date + "/" + service + "/tc3_request"
+	stringToSign := strings.Join([]string{
+		algorithm,
+		strconv.FormatInt(timestamp, 10),
+		credentialScope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	secretDate := hmacSHA256([]byte("TC3"+secretKey), date)
+	secretService := hmacSHA256(secretDate, service)
+	secretSigning := hmacSHA256(secretService, "tc3_request")
+	signature := hex.EncodeToString(hmacSHA256(secretSigning, stringToSign))
+
+	return fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		algorithm, secretId, credentialScope, signedHeaders, signature)
+}
+
+// convertLanguageCode 转换语言代码到腾讯云格式
+func (t *TencentTranslator) convertLanguageCode(code string) string {
+	switch strings.ToLower(code) {
+	case "zh-cn", "zh-hans":
+		return "zh"
+	case "zh-tw", "zh-hk", "zh-hant":
+		return "zh-TW"
+	}
+	return code
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
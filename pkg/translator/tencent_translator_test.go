@@ -0,0 +1,187 @@
+package translator
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+	"github.com/difyz9/ytb2bili/pkg/ratelimit"
+)
+
+func newTestTencentTranslator(t *testing.T, endpoint string) *TencentTranslator {
+	t.Helper()
+	tr, err := NewTencentTranslator(&types.TencentTransConfig{
+		Enabled:   true,
+		SecretId:  "AKIDtest",
+		SecretKey: "secret",
+		Region:    "ap-shanghai",
+		Endpoint:  endpoint,
+		ProjectId: 7,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tr
+}
+
+// 腾讯云 API 3.0 签名文档中的示例（CVM DescribeInstances）
+func TestTC3AuthorizationKnownVector(t *testing.T) {
+	payload := `{"Limit": 1, "Filters": [{"Values": ["\u672a\u547d\u540d"], "Name": "instance-name"}]}`
+	got := tc3Authorization("AKIDz8krbsJ5yKBZQpn74WFkmLPx3EXAMPLE", "Gu5t9xGARNpq86cd98joQYCN3EXAMPLE",
+		"cvm", "cvm.tencentcloudapi.com", []byte(payload), 1551113065)
+	want := "TC3-HMAC-SHA256 Credential=AKIDz8krbsJ5yKBZQpn74WFkmLPx3EXAMPLE/2019-02-25/cvm/tc3_request, " +
+		"SignedHeaders=content-type;host, Signature=72e494ea809ad7a8c8f7a4507b9bddcbaa8e581f516e8da2f66e2c5a96525168"
+	if got != want {
+		t.Fatalf("signature mismatch\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestTencentBatchTranslateRequest(t *testing.T) {
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request, body []byte) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"Response": map[string]interface{}{
+				"Source":         "en",
+				"Target":         "zh",
+				"TargetTextList": []string{"你好", "世界"},
+				"RequestId":      "req-1",
+			},
+		})
+	})
+	tr := newTestTencentTranslator(t, server.URL)
+
+	result, err := tr.BatchTranslate(context.Background(), &BatchTranslationRequest{
+		Texts:      []string{"hello", "world"},
+		SourceLang: "en",
+		TargetLang: "zh-Hans",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := server.lastRequest(t)
+	if req.Method != "POST" || req.Path != "/" {
+		t.Fatalf("unexpected request %s %s", req.Method, req.Path)
+	}
+	for header, want := range map[string]string{
+		"Content-Type":   "application/json; charset=utf-8",
+		"X-TC-Action":    "TextTranslateBatch",
+		"X-TC-Version":   "2018-03-21",
+		"X-TC-Region":    "ap-shanghai",
+		"X-TC-Timestamp": req.Header.Get("X-TC-Timestamp"),
+	} {
+		if got := req.Header.Get(header); got != want || got == "" {
+			t.Errorf("header %s = %q, want %q", header, got, want)
+		}
+	}
+
+	// 用收到的请求体和时间戳重新计算签名
+	timestamp, err := strconv.ParseInt(req.Header.Get("X-TC-Timestamp"), 10, 64)
+	if err != nil {
+		t.Fatalf("invalid timestamp: %v", err)
+	}
+	if diff := time.Since(time.Unix(timestamp, 0)); diff < 0 || diff > time.Minute {
+		t.Errorf("timestamp %d is not current", timestamp)
+	}
+	host, _ := url.Parse(server.URL)
+	if want := tc3Authorization("AKIDtest", "secret", "tmt", host.Host, req.Body, timestamp); req.Header.Get("Authorization") != want {
+		t.Errorf("authorization = %q, want %q", req.Header.Get("Authorization"), want)
+	}
+	if !strings.Contains(req.Header.Get("Authorization"), "/tmt/tc3_request") {
+		t.Errorf("credential scope should use the tmt service: %s", req.Header.Get("Authorization"))
+	}
+
+	var body struct {
+		Source         string
+		Target         string
+		ProjectId      int64
+		SourceTextList []string
+	}
+	if err := json.Unmarshal(req.Body, &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Source != "en" || body.Target != "zh" || body.ProjectId != 7 || strings.Join(body.SourceTextList, "|") != "hello|world" {
+		t.Errorf("unexpected body: %+v", body)
+	}
+
+	if len(result.Results) != 2 || result.Results[0].TranslatedText != "你好" || result.Results[1].TranslatedText != "世界" {
+		t.Fatalf("unexpected results: %+v", result.Results)
+	}
+	if result.Results[0].SourceLang != "en" || result.Results[0].TargetLang != "zh-Hans" || result.Provider != "tencent" {
+		t.Errorf("unexpected result metadata: %+v", result.Results[0])
+	}
+	if result.Usage.Characters != len("hello")+len("world") {
+		t.Errorf("characters = %d", result.Usage.Characters)
+	}
+}
+
+func TestTencentShortResultList(t *testing.T) {
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request, body []byte) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"Response": map[string]interface{}{"TargetTextList": []string{"你好"}},
+		})
+	})
+	tr := newTestTencentTranslator(t, server.URL)
+
+	_, err := tr.BatchTranslate(context.Background(), &BatchTranslationRequest{Texts: []string{"hello", "world"}, TargetLang: "zh"})
+	if err == nil || !strings.Contains(err.Error(), "returned 1 translations, expected 2") {
+		t.Fatalf("expected short list error, got %v", err)
+	}
+}
+
+func TestTencentErrors(t *testing.T) {
+	cases := []struct {
+		name   string
+		status int
+		body   string
+		code   int
+	}{
+		{"http 503", http.StatusServiceUnavailable, "unavailable", 503},
+		{"http 429", http.StatusTooManyRequests, "slow down", 429},
+		{"request limit", http.StatusOK, `{"Response":{"Error":{"Code":"RequestLimitExceeded","Message":"too fast"}}}`, 429},
+		{"internal error", http.StatusOK, `{"Response":{"Error":{"Code":"InternalError.BackendTimeout","Message":"timeout"}}}`, 503},
+		{"auth failure", http.StatusOK, `{"Response":{"Error":{"Code":"AuthFailure.SignatureFailure","Message":"bad signature"}}}`, 0},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			server := newFakeServer(t, statusHandler(c.status, c.body))
+			tr := newTestTencentTranslator(t, server.URL)
+
+			_, err := tr.BatchTranslate(context.Background(), &BatchTranslationRequest{Texts: []string{"hello"}, TargetLang: "zh"})
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if got := ratelimit.StatusCode(err); got != c.code {
+				t.Errorf("status code = %d, want %d (%v)", got, c.code, err)
+			}
+		})
+	}
+}
+
+func TestTencentBatchesLargeRequests(t *testing.T) {
+	server := newFakeServer(t, func(w http.ResponseWriter, r *http.Request, body []byte) {
+		var req struct{ SourceTextList []string }
+		json.Unmarshal(body, &req)
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"Response": map[string]interface{}{"TargetTextList": req.SourceTextList},
+		})
+	})
+	tr := newTestTencentTranslator(t, server.URL)
+
+	texts := make([]string, tencentMaxBatchSize+1)
+	for i := range texts {
+		texts[i] = strconv.Itoa(i)
+	}
+	result, err := tr.BatchTranslate(context.Background(), &BatchTranslationRequest{Texts: texts, TargetLang: "zh"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if server.requestCount() != 2 || len(result.Results) != len(texts) || result.Results[len(texts)-1].TranslatedText != texts[len(texts)-1] {
+		t.Fatalf("expected 2 requests and %d results, got %d requests and %d results", len(texts), server.requestCount(), len(result.Results))
+	}
+}
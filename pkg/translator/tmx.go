@@ -0,0 +1,179 @@
+package translator
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// TMXUnit 一条双语翻译单元（TMX 中的 tu，按原文-译文拆成一对）
+type TMXUnit struct {
+	SourceLang     string
+	TargetLang     string
+	SourceText     string
+	TranslatedText string
+	Provider       string
+	Model          string
+}
+
+type tmxDocument struct {
+	XMLName xml.Name  `xml:"tmx"`
+	Version string    `xml:"version,attr"`
+	Header  tmxHeader `xml:"header"`
+	Body    tmxBody   `xml:"body"`
+}
+
+type tmxHeader struct {
+	CreationTool        string `xml:"creationtool,attr"`
+	CreationToolVersion string `xml:"creationtoolversion,attr"`
+	SegType             string `xml:"segtype,attr"`
+	OTMF                string `xml:"o-tmf,attr"`
+	AdminLang           string `xml:"adminlang,attr"`
+	SrcLang             string `xml:"srclang,attr"`
+	DataType            string `xml:"datatype,attr"`
+	CreationDate        string `xml:"creationdate,attr,omitempty"`
+}
+
+type tmxBody struct {
+	Units []tmxTU `xml:"tu"`
+}
+
+type tmxTU struct {
+	SrcLang  string    `xml:"srclang,attr,omitempty"`
+	Props    []tmxProp `xml:"prop"`
+	Variants []tmxTUV  `xml:"tuv"`
+}
+
+type tmxProp struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
+
+type tmxTUV struct {
+	Lang       string `xml:"http://www.w3.org/XML/1998/namespace lang,attr,omitempty"`
+	LegacyLang string `xml:"lang,attr,omitempty"` // TMX 1.1 使用 lang 属性
+	Seg        string `xml:"seg"`
+}
+
+func (v tmxTUV) lang() string {
+	if v.Lang != "" {
+		return v.Lang
+	}
+	return v.LegacyLang
+}
+
+// WriteTMX 将翻译单元导出为 TMX 1.4 文档
+func WriteTMX(w io.Writer, units []TMXUnit) error {
+	srcLang := "*all*"
+	for i, unit := range units {
+		if i == 0 {
+			srcLang = unit.SourceLang
+		} else if unit.SourceLang != srcLang {
+			srcLang = "*all*"
+			break
+		}
+	}
+
+	doc := tmxDocument{
+		Version: "1.4",
+		Header: tmxHeader{
+			CreationTool:        "ytb2bili",
+			CreationToolVersion: "1.0",
+			SegType:             "sentence",
+			OTMF:                "ytb2bili",
+			AdminLang:           "en",
+			SrcLang:             srcLang,
+			DataType:            "plaintext",
+			CreationDate:        time.Now().UTC().Format("20060102T150405Z"),
+		},
+	}
+	for _, unit := range units {
+		tu := tmxTU{
+			Variants: []tmxTUV{
+				{Lang: unit.SourceLang, Seg: unit.SourceText},
+				{Lang: unit.TargetLang, Seg: unit.TranslatedText},
+			},
+		}
+		if srcLang == "*all*" {
+			tu.SrcLang = unit.SourceLang
+		}
+		if unit.Provider != "" {
+			tu.Props = append(tu.Props, tmxProp{Type: "x-provider", Value: unit.Provider})
+		}
+		if unit.Model != "" {
+			tu.Props = append(tu.Props, tmxProp{Type: "x-model", Value: unit.Model})
+		}
+		doc.Body.Units = append(doc.Body.Units, tu)
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return fmt.Errorf("failed to encode TMX: %w", err)
+	}
+	return encoder.Flush()
+}
+
+// ParseTMX 解析 TMX 文档。每个 tu 以 srclang 对应的 tuv 为原文（未指定时取第一个），
+// 其余每个 tuv 生成一条翻译单元
+func ParseTMX(r io.Reader) ([]TMXUnit, error) {
+	var doc tmxDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to parse TMX: %w", err)
+	}
+
+	var units []TMXUnit
+	for _, tu := range doc.Body.Units {
+		if len(tu.Variants) < 2 {
+			continue
+		}
+
+		srcLang := tu.SrcLang
+		if srcLang == "" {
+			srcLang = doc.Header.SrcLang
+		}
+		sourceIdx := 0
+		if srcLang != "" && srcLang != "*all*" {
+			for i, variant := range tu.Variants {
+				if strings.EqualFold(variant.lang(), srcLang) {
+					sourceIdx = i
+					break
+				}
+			}
+		}
+		source := tu.Variants[sourceIdx]
+		if strings.TrimSpace(source.Seg) == "" {
+			continue
+		}
+
+		var provider, model string
+		for _, prop := range tu.Props {
+			switch prop.Type {
+			case "x-provider":
+				provider = prop.Value
+			case "x-model":
+				model = prop.Value
+			}
+		}
+
+		for i, variant := range tu.Variants {
+			if i == sourceIdx || strings.TrimSpace(variant.Seg) == "" {
+				continue
+			}
+			units = append(units, TMXUnit{
+				SourceLang:     source.lang(),
+				TargetLang:     variant.lang(),
+				SourceText:     source.Seg,
+				TranslatedText: variant.Seg,
+				Provider:       provider,
+				Model:          model,
+			})
+		}
+	}
+	return units, nil
+}
@@ -0,0 +1,134 @@
+package translator
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// 用量记录的调用类型
+const (
+	UsageKindTranslation = "translation" // 翻译提供商调用
+	UsageKindChat        = "chat"        // LLM 对话调用（元数据、标点恢复、字幕修复等）
+)
+
+// ErrBudgetExceeded AI 费用超出预算上限，超限期间暂停所有 AI 调用
+var ErrBudgetExceeded = errors.New("AI budget exceeded")
+
+// UsageRecord 一次 LLM 或翻译调用的用量
+type UsageRecord struct {
+	VideoID      string
+	Step         string
+	Provider     string
+	Model        string
+	Kind         string
+	InputTokens  int
+	OutputTokens int
+	Characters   int
+	Latency      time.Duration
+	Cost         float64 // 提供商返回的费用，为 0 时由记录器按价格表估算
+	Success      bool
+	Error        string
+}
+
+// UsageRecorder 用量记录器，由存储层实现
+type UsageRecorder interface {
+	// RecordUsage 保存一次调用的用量，失败只记录日志
+	RecordUsage(ctx context.Context, record *UsageRecord)
+	// CheckBudget 检查全局（及指定视频）的费用是否超出预算，超出时返回包装了 ErrBudgetExceeded 的错误
+	CheckBudget(videoID string) error
+}
+
+var (
+	usageRecorder UsageRecorder
+	usageMutex    sync.RWMutex
+)
+
+// SetUsageRecorder 设置全局用量记录器。翻译任务和各类 LLM 客户端按需临时创建，
+// 因此记录器放在包级别，由启动流程注入一次
+func SetUsageRecorder(recorder UsageRecorder) {
+	usageMutex.Lock()
+	defer usageMutex.Unlock()
+	usageRecorder = recorder
+}
+
+func currentUsageRecorder() UsageRecorder {
+	usageMutex.RLock()
+	defer usageMutex.RUnlock()
+	return usageRecorder
+}
+
+// RecordUsage 记录一次调用的用量，VideoID/Step 为空时从 ctx 的用量归属中补全；未设置记录器时忽略
+func RecordUsage(ctx context.Context, record *UsageRecord) {
+	recorder := currentUsageRecorder()
+	if recorder == nil || record == nil {
+		return
+	}
+
+	scope := UsageScopeFromContext(ctx)
+	if record.VideoID == "" {
+		record.VideoID = scope.VideoID
+	}
+	if record.Step == "" {
+		record.Step = scope.Step
+	}
+	recorder.RecordUsage(ctx, record)
+}
+
+// CheckBudget 检查费用预算，未设置记录器时不限制
+func CheckBudget(videoID string) error {
+	recorder := currentUsageRecorder()
+	if recorder == nil {
+		return nil
+	}
+	return recorder.CheckBudget(videoID)
+}
+
+// UsageScope 用量归属：所属视频和任务步骤
+type UsageScope struct {
+	VideoID string
+	Step    string
+}
+
+type usageScopeKey struct{}
+
+// WithUsageScope 在 ctx 中标记后续调用的用量归属
+func WithUsageScope(ctx context.Context, videoID, step string) context.Context {
+	return context.WithValue(ctx, usageScopeKey{}, UsageScope{VideoID: videoID, Step: step})
+}
+
+// UsageScopeFromContext 读取 ctx 中的用量归属
+func UsageScopeFromContext(ctx context.Context) UsageScope {
+	if ctx == nil {
+		return UsageScope{}
+	}
+	scope, _ := ctx.Value(usageScopeKey{}).(UsageScope)
+	return scope
+}
+
+// newUsageRecord 根据翻译结果的用量构造记录，提供商未返回字符数时按原文计算
+func newUsageRecord(provider, model string, texts []string, usage *Usage, latency time.Duration, err error) *UsageRecord {
+	record := &UsageRecord{
+		Provider: provider,
+		Model:    model,
+		Kind:     UsageKindTranslation,
+		Latency:  latency,
+		Success:  err == nil,
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	if usage != nil {
+		record.InputTokens = usage.InputTokens
+		record.OutputTokens = usage.OutputTokens
+		record.Characters = usage.Characters
+		record.Cost = usage.Cost
+	}
+	if record.Characters == 0 {
+		for _, text := range texts {
+			record.Characters += len([]rune(text))
+		}
+	}
+	return record
+}
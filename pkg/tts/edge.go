@@ -0,0 +1,107 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+)
+
+// 各语言的默认 Neural 音色（女声、男声）
+var edgeVoices = map[string][2]string{
+	"zh-hans": {"zh-CN-XiaoxiaoNeural", "zh-CN-YunxiNeural"},
+	"zh-hant": {"zh-TW-HsiaoChenNeural", "zh-TW-YunJheNeural"},
+	"en":      {"en-US-AriaNeural", "en-US-GuyNeural"},
+	"ja":      {"ja-JP-NanamiNeural", "ja-JP-KeitaNeural"},
+	"ko":      {"ko-KR-SunHiNeural", "ko-KR-InJoonNeural"},
+}
+
+// EdgeProvider Edge 风格的 TTS 服务（如自建的 edge-tts HTTP 服务）：音色使用 zh-CN-XiaoxiaoNeural 这类
+// Neural 音色名，语速为相对百分比。向 endpoint POST JSON {"text", "voice", "rate"}，响应体为音频
+type EdgeProvider struct {
+	endpoint string
+	apiKey   string
+	format   string
+	client   *http.Client
+}
+
+// NewEdgeProvider 创建 Edge 风格的 TTS 提供商
+func NewEdgeProvider(cfg *types.DubbingConfig, client *http.Client) (*EdgeProvider, error) {
+	endpoint := strings.TrimSpace(cfg.Endpoint)
+	if endpoint == "" {
+		return nil, fmt.Errorf("edge TTS 提供商需要配置 endpoint")
+	}
+	return &EdgeProvider{
+		endpoint: endpoint,
+		apiKey:   cfg.APIKey,
+		format:   normalizeFormat(cfg.Format),
+		client:   client,
+	}, nil
+}
+
+func (p *EdgeProvider) Name() string {
+	return ProviderEdge
+}
+
+func (p *EdgeProvider) Synthesize(ctx context.Context, req *Request) (*Audio, error) {
+	voice := req.Voice
+	if voice == "" {
+		voice = edgeVoice(req.Language, req.Gender)
+	}
+	body := map[string]interface{}{
+		"text":  req.Text,
+		"voice": voice,
+		"rate":  edgeRate(req.Speed),
+	}
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+	return doAudioRequest(p.client, httpReq, p.format)
+}
+
+// edgeVoice 按语言和性别选择默认音色，未知语言使用简体中文音色
+func edgeVoice(language, gender string) string {
+	language = strings.ToLower(language)
+	voices, ok := edgeVoices[language]
+	if !ok {
+		if i := strings.Index(language, "-"); i > 0 {
+			voices, ok = edgeVoices[language[:i]]
+		}
+		if !ok {
+			voices = edgeVoices["zh-hans"]
+		}
+	}
+	if gender == GenderMale {
+		return voices[1]
+	}
+	return voices[0]
+}
+
+// edgeRate 将语速倍数转换为相对百分比，如 1.2 -> "+20%"
+func edgeRate(speed float64) string {
+	if speed <= 0 {
+		return "+0%"
+	}
+	percent := int(math.Round((speed - 1) * 100))
+	if percent < -50 {
+		percent = -50
+	}
+	if percent > 100 {
+		percent = 100
+	}
+	return fmt.Sprintf("%+d%%", percent)
+}
@@ -0,0 +1,63 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+)
+
+// HTTPProvider 本地 TTS HTTP 服务：向 endpoint POST JSON
+// {"text", "language", "voice", "gender", "speed", "format"}，响应体为音频
+type HTTPProvider struct {
+	endpoint string
+	apiKey   string
+	format   string
+	client   *http.Client
+}
+
+// NewHTTPProvider 创建本地 TTS HTTP 服务提供商
+func NewHTTPProvider(cfg *types.DubbingConfig, client *http.Client) (*HTTPProvider, error) {
+	endpoint := strings.TrimSpace(cfg.Endpoint)
+	if endpoint == "" {
+		return nil, fmt.Errorf("http TTS 提供商需要配置 endpoint")
+	}
+	return &HTTPProvider{
+		endpoint: endpoint,
+		apiKey:   cfg.APIKey,
+		format:   normalizeFormat(cfg.Format),
+		client:   client,
+	}, nil
+}
+
+func (p *HTTPProvider) Name() string {
+	return ProviderHTTP
+}
+
+func (p *HTTPProvider) Synthesize(ctx context.Context, req *Request) (*Audio, error) {
+	body := map[string]interface{}{
+		"text":     req.Text,
+		"language": req.Language,
+		"voice":    req.Voice,
+		"gender":   req.Gender,
+		"speed":    req.Speed,
+		"format":   p.format,
+	}
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.endpoint, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+	return doAudioRequest(p.client, httpReq, p.format)
+}
@@ -0,0 +1,92 @@
+package tts
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+)
+
+// OpenAIProvider OpenAI 兼容的 /audio/speech 接口（OpenAI、Azure OpenAI 代理及各类兼容服务）
+type OpenAIProvider struct {
+	endpoint string
+	apiKey   string
+	model    string
+	format   string
+	client   *http.Client
+}
+
+// NewOpenAIProvider 创建 OpenAI 兼容的 TTS 提供商：未设置 model 时按 tier 选择 tts-1 或 tts-1-hd
+func NewOpenAIProvider(cfg *types.DubbingConfig, client *http.Client) *OpenAIProvider {
+	endpoint := strings.TrimSuffix(strings.TrimSpace(cfg.Endpoint), "/")
+	if endpoint == "" {
+		endpoint = "https://api.openai.com/v1"
+	}
+	model := cfg.Model
+	if model == "" {
+		model = "tts-1"
+		if strings.EqualFold(cfg.Tier, "hd") {
+			model = "tts-1-hd"
+		}
+	}
+	return &OpenAIProvider{
+		endpoint: endpoint,
+		apiKey:   cfg.APIKey,
+		model:    model,
+		format:   normalizeFormat(cfg.Format),
+		client:   client,
+	}
+}
+
+func (p *OpenAIProvider) Name() string {
+	return ProviderOpenAI
+}
+
+func (p *OpenAIProvider) Synthesize(ctx context.Context, req *Request) (*Audio, error) {
+	voice := req.Voice
+	if voice == "" {
+		voice = "nova"
+		if req.Gender == GenderMale {
+			voice = "onyx"
+		}
+	}
+	body := map[string]interface{}{
+		"model":           p.model,
+		"input":           req.Text,
+		"voice":           voice,
+		"response_format": p.format,
+	}
+	if req.Speed > 0 {
+		// 接口支持的语速范围为 0.25-4.0
+		body["speed"] = clamp(req.Speed, 0.25, 4)
+	}
+
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.endpoint+"/audio/speech", bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+	return doAudioRequest(p.client, httpReq, p.format)
+}
+
+// clamp 将数值限制在 [min, max] 范围内
+func clamp(value, min, max float64) float64 {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
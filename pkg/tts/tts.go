@@ -0,0 +1,139 @@
+package tts
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/difyz9/ytb2bili/internal/core/types"
+)
+
+// 支持的 TTS 提供商
+const (
+	ProviderOpenAI = "openai" // OpenAI 兼容的 /audio/speech 接口
+	ProviderHTTP   = "http"   // 本地 TTS HTTP 服务
+	ProviderEdge   = "edge"   // Edge 风格的 TTS 服务（Neural 音色名、百分比语速）
+)
+
+// 音色性别
+const (
+	GenderFemale = "female"
+	GenderMale   = "male"
+)
+
+// Request 一条语音合成请求
+type Request struct {
+	Text     string  // 合成的文本
+	Language string  // 语言代码，如 zh-Hans
+	Voice    string  // 音色名称，为空时由提供商按 Gender 选择
+	Gender   string  // female / male
+	Speed    float64 // 语速，1.0 为正常语速
+}
+
+// Audio 合成的音频
+type Audio struct {
+	Data   []byte
+	Format string // 音频格式（文件扩展名）：mp3、wav、opus 等
+}
+
+// Provider TTS 提供商
+type Provider interface {
+	// Name 提供商名称
+	Name() string
+	// Synthesize 合成一条语音
+	Synthesize(ctx context.Context, req *Request) (*Audio, error)
+}
+
+// NewProvider 按配置创建 TTS 提供商
+func NewProvider(cfg *types.DubbingConfig) (Provider, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("配音配置为空")
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 60
+	}
+	client := &http.Client{Timeout: time.Duration(timeout) * time.Second}
+
+	switch strings.ToLower(strings.TrimSpace(cfg.Provider)) {
+	case ProviderOpenAI, "":
+		return NewOpenAIProvider(cfg, client), nil
+	case ProviderHTTP:
+		return NewHTTPProvider(cfg, client)
+	case ProviderEdge:
+		return NewEdgeProvider(cfg, client)
+	default:
+		return nil, fmt.Errorf("不支持的 TTS 提供商: %s", cfg.Provider)
+	}
+}
+
+// doAudioRequest 发送请求并读取音频，非 2xx 或返回 JSON 错误时返回 error。
+// 音频格式按 Content-Type 判断，无法判断时使用 fallbackFormat
+func doAudioRequest(client *http.Client, httpReq *http.Request, fallbackFormat string) (*Audio, error) {
+	resp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("请求 TTS 服务失败: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("读取 TTS 响应失败: %w", err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("TTS 服务返回状态 %d: %s", resp.StatusCode, truncateBody(body))
+	}
+
+	contentType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if contentType == "application/json" || strings.HasPrefix(contentType, "text/") {
+		return nil, fmt.Errorf("TTS 服务没有返回音频: %s", truncateBody(body))
+	}
+	if len(body) == 0 {
+		return nil, fmt.Errorf("TTS 服务返回了空音频")
+	}
+
+	format := formatFromContentType(contentType)
+	if format == "" {
+		format = fallbackFormat
+	}
+	return &Audio{Data: body, Format: format}, nil
+}
+
+// formatFromContentType 由 Content-Type 得到音频格式，未知类型返回空字符串
+func formatFromContentType(contentType string) string {
+	switch contentType {
+	case "audio/mpeg", "audio/mp3":
+		return "mp3"
+	case "audio/wav", "audio/wave", "audio/x-wav", "audio/vnd.wave":
+		return "wav"
+	case "audio/ogg", "audio/opus":
+		return "opus"
+	case "audio/aac":
+		return "aac"
+	case "audio/flac", "audio/x-flac":
+		return "flac"
+	}
+	return ""
+}
+
+// normalizeFormat 配置的音频格式，默认 mp3
+func normalizeFormat(format string) string {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		return "mp3"
+	}
+	return format
+}
+
+// truncateBody 截断错误响应，避免日志过长
+func truncateBody(body []byte) string {
+	text := strings.TrimSpace(string(body))
+	if len(text) > 300 {
+		return text[:300] + "..."
+	}
+	return text
+}
@@ -0,0 +1,126 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 章节来源
+const (
+	ChapterSourceVideo = "source" // 来源视频自带的章节（标题已翻译）
+	ChapterSourceLLM   = "llm"    // 大模型根据带时间点的字幕生成
+)
+
+// Chapter 视频章节
+type Chapter struct {
+	Start float64 `json:"start"` // 开始时间（秒）
+	Title string  `json:"title"` // 章节标题
+}
+
+// ChapterList 章节文件内容
+type ChapterList struct {
+	Source        string    `json:"source"`                   // 章节来源：source / llm
+	Chapters      []Chapter `json:"chapters"`                 // 按开始时间排序
+	Provider      string    `json:"provider,omitempty"`       // 翻译或生成章节的服务
+	PromptVersion string    `json:"prompt_version,omitempty"` // 生成章节时使用的提示词模板版本
+}
+
+// LoadChapters 读取章节文件
+func LoadChapters(path string) (*ChapterList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var list ChapterList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("解析章节文件失败: %v", err)
+	}
+	return &list, nil
+}
+
+// SaveChapters 保存章节文件
+func SaveChapters(path string, list *ChapterList) error {
+	data, err := json.MarshalIndent(list, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0644)
+}
+
+// NormalizeChapters 整理章节：按时间排序，去掉空标题、超出视频时长和与上一章间隔小于 minGap 秒的章节，
+// 第一章从 0 秒开始，最多保留 max 章（0 表示不限制）。整理后不足 2 章时返回 nil
+func NormalizeChapters(chapters []Chapter, duration, minGap float64, max int) []Chapter {
+	sorted := make([]Chapter, 0, len(chapters))
+	for _, chapter := range chapters {
+		chapter.Title = strings.TrimSpace(strings.ReplaceAll(chapter.Title, "\n", " "))
+		if chapter.Title == "" || chapter.Start < 0 || (duration > 0 && chapter.Start >= duration) {
+			continue
+		}
+		sorted = append(sorted, chapter)
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Start < sorted[j].Start })
+
+	var result []Chapter
+	for _, chapter := range sorted {
+		if len(result) == 0 {
+			chapter.Start = 0
+		} else if chapter.Start-result[len(result)-1].Start < minGap {
+			continue
+		}
+		result = append(result, chapter)
+		if max > 0 && len(result) >= max {
+			break
+		}
+	}
+	if len(result) < 2 {
+		return nil
+	}
+	return result
+}
+
+// FormatChapterTime 格式化章节时间：mm:ss，视频超过一小时时为 h:mm:ss
+func FormatChapterTime(seconds float64, long bool) string {
+	total := int(seconds)
+	if long {
+		return fmt.Sprintf("%d:%02d:%02d", total/3600, total%3600/60, total%60)
+	}
+	return fmt.Sprintf("%02d:%02d", total/60, total%60)
+}
+
+// FormatChapterLines 将章节格式化为简介中的时间点列表，每行一章："mm:ss 标题"
+func FormatChapterLines(chapters []Chapter) string {
+	if len(chapters) == 0 {
+		return ""
+	}
+	long := chapters[len(chapters)-1].Start >= 3600
+	lines := make([]string, 0, len(chapters))
+	for _, chapter := range chapters {
+		lines = append(lines, FormatChapterTime(chapter.Start, long)+" "+chapter.Title)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ParseChapterTime 解析 hh:mm:ss、mm:ss 或秒数形式的时间
+func ParseChapterTime(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, fmt.Errorf("时间为空")
+	}
+	parts := strings.Split(value, ":")
+	if len(parts) > 3 {
+		return 0, fmt.Errorf("无效的时间: %s", value)
+	}
+	var seconds float64
+	for _, part := range parts {
+		n, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("无效的时间: %s", value)
+		}
+		seconds = seconds*60 + n
+	}
+	return seconds, nil
+}
@@ -0,0 +1,304 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// 亮度低于/高于该值的帧视为黑场/白场，不作为封面
+const (
+	coverMinBrightness = 30
+	coverMaxBrightness = 230
+)
+
+// CoverIndexFile 封面目录中记录候选封面的文件名
+const CoverIndexFile = "covers.json"
+
+// CoverSize B站封面尺寸
+type CoverSize struct {
+	Name   string `json:"name"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+}
+
+// CoverSizes 支持的封面比例
+var CoverSizes = map[string]CoverSize{
+	"16:10": {Name: "16:10", Width: 1146, Height: 717},
+	"4:3":   {Name: "4:3", Width: 1146, Height: 860},
+	"16:9":  {Name: "16:9", Width: 1280, Height: 720},
+}
+
+// CoverTemplates 标题叠加样式：drawbox/drawtext 滤镜。{text} 替换为文字参数（字体、文本文件），
+// {fontsize}、{spacing}、{border} 按封面高度换算为像素
+var CoverTemplates = map[string]string{
+	// 只裁剪缩放，不叠加文字
+	"plain": "",
+	// 底部半透明黑条 + 白字
+	"bottom_bar": "drawbox=x=0:y=ih*0.68:w=iw:h=ih*0.32:color=black@0.55:t=fill," +
+		"drawtext={text}:fontcolor=white:fontsize={fontsize}:line_spacing={spacing}:x=(w-text_w)/2:y=h*0.68+(h*0.32-text_h)/2",
+	// 顶部B站粉色横幅 + 白字
+	"top_banner": "drawbox=x=0:y=0:w=iw:h=ih*0.3:color=0xFB7299@0.85:t=fill," +
+		"drawtext={text}:fontcolor=white:fontsize={fontsize}:line_spacing={spacing}:x=(w-text_w)/2:y=(h*0.3-text_h)/2",
+	// 画面下方黄色描边大字
+	"outline": "drawtext={text}:fontcolor=yellow:borderw={border}:bordercolor=black:fontsize={fontsize}:line_spacing={spacing}:x=(w-text_w)/2:y=h*0.92-text_h",
+}
+
+// FrameQuality 候选帧的画面质量
+type FrameQuality struct {
+	Brightness float64 `json:"brightness"` // 平均亮度（0-255）
+	Sharpness  float64 `json:"sharpness"`  // 拉普拉斯方差，越大越清晰
+	Score      float64 `json:"score"`      // 综合得分，0 表示不可用（黑场/白场）
+}
+
+// CoverCandidate 候选封面
+type CoverCandidate struct {
+	Name     string  `json:"name"`     // 文件名
+	Frame    float64 `json:"frame"`    // 取自视频的时间点（秒）
+	Score    float64 `json:"score"`    // 画面质量得分
+	Template string  `json:"template"` // 标题样式
+	Size     string  `json:"size"`     // 封面比例
+	Title    string  `json:"title"`    // 叠加的标题
+	Selected bool    `json:"selected"` // 是否为当前使用的封面
+}
+
+// CoverIndex 封面目录中的候选封面列表
+type CoverIndex struct {
+	Candidates []CoverCandidate `json:"candidates"`
+}
+
+// ScoreFrame 计算帧的平均亮度和清晰度（缩小到约 320 像素宽的灰度图上计算拉普拉斯方差）
+func ScoreFrame(path string) (*FrameQuality, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("解码图片失败: %v", err)
+	}
+
+	bounds := img.Bounds()
+	step := bounds.Dx() / 320
+	if step < 1 {
+		step = 1
+	}
+	width := bounds.Dx() / step
+	height := bounds.Dy() / step
+	if width < 3 || height < 3 {
+		return nil, fmt.Errorf("图片尺寸过小")
+	}
+
+	gray := make([]float64, width*height)
+	var sum float64
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			r, g, b, _ := img.At(bounds.Min.X+x*step, bounds.Min.Y+y*step).RGBA()
+			luma := (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(b)) / 257
+			gray[y*width+x] = luma
+			sum += luma
+		}
+	}
+
+	var lapSum, lapSquares float64
+	count := 0
+	for y := 1; y < height-1; y++ {
+		for x := 1; x < width-1; x++ {
+			i := y*width + x
+			lap := gray[i-1] + gray[i+1] + gray[i-width] + gray[i+width] - 4*gray[i]
+			lapSum += lap
+			lapSquares += lap * lap
+			count++
+		}
+	}
+	mean := lapSum / float64(count)
+
+	quality := &FrameQuality{
+		Brightness: sum / float64(len(gray)),
+		Sharpness:  lapSquares/float64(count) - mean*mean,
+	}
+	if quality.Brightness >= coverMinBrightness && quality.Brightness <= coverMaxBrightness {
+		// 亮度越接近中间调越好，清晰度取对数避免噪点多的画面得分过高
+		exposure := 1 - math.Abs(quality.Brightness-128)/128*0.5
+		quality.Score = math.Log1p(quality.Sharpness) * exposure
+	}
+	return quality, nil
+}
+
+// RenderCover 将帧裁剪缩放为封面尺寸，并按模板叠加标题（标题为空时不叠加）。
+// fontFile 为字体文件路径，为空时按 fontconfig 字体名 font 查找
+func RenderCover(framePath, outputPath string, size CoverSize, template, title, fontFile, font string) error {
+	filter := fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=increase,crop=%d:%d", size.Width, size.Height, size.Width, size.Height)
+
+	layout, ok := CoverTemplates[template]
+	if !ok {
+		return fmt.Errorf("未知的封面模板: %s", template)
+	}
+	if layout != "" && strings.TrimSpace(title) != "" {
+		// 标题写入文本文件，避免滤镜参数转义问题
+		textFile := outputPath + ".txt"
+		if err := os.WriteFile(textFile, []byte(strings.Join(WrapCoverTitle(title, 14, 2), "\n")), 0644); err != nil {
+			return fmt.Errorf("写入标题文件失败: %v", err)
+		}
+		defer os.Remove(textFile)
+
+		text := "textfile=" + escapeFilterValue(textFile) + ":expansion=none"
+		if fontFile != "" {
+			text += ":fontfile=" + escapeFilterValue(fontFile)
+		} else {
+			if font == "" {
+				font = "Noto Sans CJK SC"
+			}
+			text += ":font=" + escapeFilterValue(font)
+		}
+		filter += "," + strings.NewReplacer(
+			"{text}", text,
+			"{fontsize}", strconv.Itoa(size.Height/11),
+			"{spacing}", strconv.Itoa(size.Height/60),
+			"{border}", strconv.Itoa(size.Height/120),
+		).Replace(layout)
+	}
+
+	output, err := exec.Command("ffmpeg", "-hide_banner", "-y", "-i", framePath, "-vf", filter, "-frames:v", "1", "-q:v", "2", outputPath).CombinedOutput()
+	if err != nil {
+		tail := string(output)
+		if len(tail) > 1000 {
+			tail = tail[len(tail)-1000:]
+		}
+		return fmt.Errorf("生成封面失败: %v\n%s", err, tail)
+	}
+	return nil
+}
+
+// WrapCoverTitle 按字符数把标题折成最多 maxLines 行，超出部分以省略号结尾
+func WrapCoverTitle(title string, perLine, maxLines int) []string {
+	runes := []rune(strings.TrimSpace(title))
+	var lines []string
+	for len(runes) > 0 && len(lines) < maxLines {
+		n := perLine
+		if n > len(runes) {
+			n = len(runes)
+		}
+		lines = append(lines, string(runes[:n]))
+		runes = runes[n:]
+	}
+	if len(runes) > 0 && len(lines) > 0 {
+		last := []rune(lines[len(lines)-1])
+		lines[len(lines)-1] = string(last[:len(last)-1]) + "…"
+	}
+	return lines
+}
+
+// RankFrames 按质量得分从高到低排序候选帧，去掉黑场/白场
+func RankFrames(frames []SampledFrame) ([]SampledFrame, []*FrameQuality) {
+	type scored struct {
+		frame   SampledFrame
+		quality *FrameQuality
+	}
+	var items []scored
+	for _, frame := range frames {
+		quality, err := ScoreFrame(frame.Path)
+		if err != nil || quality.Score <= 0 {
+			continue
+		}
+		items = append(items, scored{frame: frame, quality: quality})
+	}
+	sort.SliceStable(items, func(i, j int) bool { return items[i].quality.Score > items[j].quality.Score })
+
+	ranked := make([]SampledFrame, len(items))
+	qualities := make([]*FrameQuality, len(items))
+	for i, item := range items {
+		ranked[i] = item.frame
+		qualities[i] = item.quality
+	}
+	return ranked, qualities
+}
+
+// LoadCoverIndex 读取封面目录中的候选封面列表
+func LoadCoverIndex(coversDir string) (*CoverIndex, error) {
+	data, err := os.ReadFile(filepath.Join(coversDir, CoverIndexFile))
+	if err != nil {
+		return nil, err
+	}
+	var index CoverIndex
+	if err := json.Unmarshal(data, &index); err != nil {
+		return nil, fmt.Errorf("解析封面列表失败: %v", err)
+	}
+	return &index, nil
+}
+
+// SaveCoverIndex 保存候选封面列表
+func SaveCoverIndex(coversDir string, index *CoverIndex) error {
+	data, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(coversDir, CoverIndexFile), data, 0644)
+}
+
+// SelectCover 将指定候选封面复制为视频封面并更新选中状态
+func SelectCover(coversDir, name, coverPath string) error {
+	index, err := LoadCoverIndex(coversDir)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for i := range index.Candidates {
+		index.Candidates[i].Selected = index.Candidates[i].Name == name
+		found = found || index.Candidates[i].Selected
+	}
+	if !found {
+		return fmt.Errorf("候选封面不存在: %s", name)
+	}
+
+	if err := CopyFile(filepath.Join(coversDir, name), coverPath); err != nil {
+		return fmt.Errorf("复制封面失败: %v", err)
+	}
+	return SaveCoverIndex(coversDir, index)
+}
+
+// SaveCustomCover 将上传的 JPEG/PNG 图片重新编码为 JPEG 作为视频封面，并取消候选封面的选中状态
+func SaveCustomCover(r io.Reader, coversDir, coverPath string) error {
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return fmt.Errorf("解码图片失败: %v", err)
+	}
+	if bounds := img.Bounds(); bounds.Dx() < 320 || bounds.Dy() < 180 {
+		return fmt.Errorf("图片尺寸过小: %dx%d（至少 320x180）", bounds.Dx(), bounds.Dy())
+	}
+
+	file, err := os.Create(coverPath)
+	if err != nil {
+		return err
+	}
+	if err := jpeg.Encode(file, img, &jpeg.Options{Quality: 90}); err != nil {
+		file.Close()
+		return fmt.Errorf("保存封面失败: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+
+	index, err := LoadCoverIndex(coversDir)
+	if err != nil {
+		// 没有候选封面时无需更新
+		return nil
+	}
+	for i := range index.Candidates {
+		index.Candidates[i].Selected = false
+	}
+	return SaveCoverIndex(coversDir, index)
+}
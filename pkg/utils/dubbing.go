@@ -0,0 +1,220 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+)
+
+// DubbingSampleRate 配音音轨的采样率（16 位单声道 PCM）
+const DubbingSampleRate = 24000
+
+// FitAudioClip 将一条合成的语音解码为 16 位单声道 PCM（DubbingSampleRate），
+// tempo > 1 时加速（不改变音调），maxSeconds > 0 时截断到该时长并在末尾淡出
+func FitAudioClip(inputPath string, tempo, maxSeconds float64) ([]byte, error) {
+	var filters []string
+	if tempo > 1.001 {
+		filters = append(filters, atempoFilters(tempo)...)
+	}
+	if maxSeconds > 0 {
+		fade := 0.08
+		if maxSeconds < fade*2 {
+			fade = maxSeconds / 2
+		}
+		filters = append(filters,
+			fmt.Sprintf("atrim=end=%.3f", maxSeconds),
+			fmt.Sprintf("afade=t=out:st=%.3f:d=%.3f", maxSeconds-fade, fade))
+	}
+
+	args := []string{"-hide_banner", "-v", "error", "-i", inputPath}
+	if len(filters) > 0 {
+		args = append(args, "-af", strings.Join(filters, ","))
+	}
+	args = append(args, "-ar", strconv.Itoa(DubbingSampleRate), "-ac", "1", "-f", "s16le", "-acodec", "pcm_s16le", "pipe:1")
+
+	var stderr bytes.Buffer
+	cmd := exec.Command("ffmpeg", args...)
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("处理配音片段失败: %v\n%s", err, ffmpegTail(stderr.String()))
+	}
+	return output, nil
+}
+
+// atempoFilters atempo 单个滤镜只支持 0.5-2.0 倍，超过时串联多个
+func atempoFilters(tempo float64) []string {
+	var filters []string
+	for tempo > 2.0 {
+		filters = append(filters, "atempo=2.0")
+		tempo /= 2.0
+	}
+	return append(filters, fmt.Sprintf("atempo=%.4f", tempo))
+}
+
+// WaveWriter 以流方式写入 16 位单声道 PCM WAV 文件，Close 时回填文件头中的长度
+type WaveWriter struct {
+	file       *os.File
+	sampleRate int
+	dataBytes  int64
+}
+
+// CreateWave 创建 WAV 文件并写入文件头
+func CreateWave(path string, sampleRate int) (*WaveWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	w := &WaveWriter{file: file, sampleRate: sampleRate}
+	if err := w.writeHeader(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+// Write 写入 PCM 数据
+func (w *WaveWriter) Write(pcm []byte) error {
+	n, err := w.file.Write(pcm)
+	w.dataBytes += int64(n)
+	return err
+}
+
+// WriteSilence 写入指定毫秒数的静音
+func (w *WaveWriter) WriteSilence(ms int64) error {
+	remaining := ms * int64(w.sampleRate) / 1000 * 2
+	chunk := make([]byte, 64*1024)
+	for remaining > 0 {
+		n := int64(len(chunk))
+		if remaining < n {
+			n = remaining
+		}
+		if err := w.Write(chunk[:n]); err != nil {
+			return err
+		}
+		remaining -= n
+	}
+	return nil
+}
+
+// PositionMs 已写入音频的时长（毫秒）
+func (w *WaveWriter) PositionMs() int64 {
+	return w.dataBytes / 2 * 1000 / int64(w.sampleRate)
+}
+
+// Close 回填文件头并关闭文件
+func (w *WaveWriter) Close() error {
+	if _, err := w.file.Seek(0, 0); err != nil {
+		w.file.Close()
+		return err
+	}
+	if err := w.writeHeader(); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+// writeHeader 写入 44 字节的 WAV 文件头
+func (w *WaveWriter) writeHeader() error {
+	header := make([]byte, 44)
+	copy(header[0:], "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(36+w.dataBytes))
+	copy(header[8:], "WAVE")
+	copy(header[12:], "fmt ")
+	binary.LittleEndian.PutUint32(header[16:], 16)
+	binary.LittleEndian.PutUint16(header[20:], 1) // PCM
+	binary.LittleEndian.PutUint16(header[22:], 1) // 单声道
+	binary.LittleEndian.PutUint32(header[24:], uint32(w.sampleRate))
+	binary.LittleEndian.PutUint32(header[28:], uint32(w.sampleRate*2))
+	binary.LittleEndian.PutUint16(header[32:], 2)
+	binary.LittleEndian.PutUint16(header[34:], 16)
+	copy(header[36:], "data")
+	binary.LittleEndian.PutUint32(header[40:], uint32(w.dataBytes))
+	_, err := w.file.Write(header)
+	return err
+}
+
+// EncodeMP3 将音频编码为 MP3
+func EncodeMP3(inputPath, outputPath string) error {
+	output, err := exec.Command("ffmpeg", "-hide_banner", "-y", "-i", inputPath, "-c:a", "libmp3lame", "-q:a", "4", "-f", "mp3", outputPath).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("编码配音音轨失败: %v\n%s", err, ffmpegTail(string(output)))
+	}
+	return nil
+}
+
+// HasAudioStream 媒体文件是否包含音频流
+func HasAudioStream(inputPath string) bool {
+	output, err := exec.Command("ffprobe", "-v", "error", "-select_streams", "a",
+		"-show_entries", "stream=index", "-of", "csv=p=0", inputPath).Output()
+	return err == nil && strings.TrimSpace(string(output)) != ""
+}
+
+// DubbingMixOptions 配音混音参数
+type DubbingMixOptions struct {
+	OriginalVolume float64 // 原声音量（0-1）
+	DuckRatio      float64 // 配音时压低原声的压缩比（1-20）
+	VoiceVolume    float64 // 配音音量
+	AudioBitrate   string  // 输出音频码率，默认 192k
+}
+
+// MixDubbing 将配音音轨与原声混音后替换视频的音频（视频流直接复制）：配音作为侧链压低原声，
+// 视频没有音频流时只使用配音
+func MixDubbing(videoPath, voicePath, outputPath string, opts DubbingMixOptions) error {
+	if opts.OriginalVolume <= 0 {
+		opts.OriginalVolume = 0.8
+	}
+	if opts.VoiceVolume <= 0 {
+		opts.VoiceVolume = 1.0
+	}
+	if opts.DuckRatio < 1 {
+		opts.DuckRatio = 1
+	}
+	if opts.DuckRatio > 20 {
+		opts.DuckRatio = 20
+	}
+	if opts.AudioBitrate == "" {
+		opts.AudioBitrate = "192k"
+	}
+
+	const format = "aformat=sample_fmts=fltp:sample_rates=48000:channel_layouts=stereo"
+	var filter string
+	args := []string{"-hide_banner", "-y", "-i", videoPath, "-i", voicePath}
+	if HasAudioStream(videoPath) {
+		// amix 会将每路输入按 1/输入数 缩放，混音后乘回
+		filter = fmt.Sprintf("[1:a]%s,volume=%.2f,asplit=2[voice][key];"+
+			"[0:a]%s,volume=%.2f[orig];"+
+			"[orig][key]sidechaincompress=threshold=0.02:ratio=%.1f:attack=20:release=400[ducked];"+
+			"[ducked][voice]amix=inputs=2:duration=first:dropout_transition=0,volume=2[aout]",
+			format, opts.VoiceVolume, format, opts.OriginalVolume, opts.DuckRatio)
+	} else {
+		filter = fmt.Sprintf("[1:a]%s,volume=%.2f,apad[aout]", format, opts.VoiceVolume)
+		args = append(args, "-shortest")
+	}
+	args = append(args,
+		"-filter_complex", filter,
+		"-map", "0:v:0", "-map", "[aout]",
+		"-c:v", "copy",
+		"-c:a", "aac", "-b:a", opts.AudioBitrate,
+		// 输出路径可能是临时文件名，显式指定 mp4 格式
+		"-movflags", "+faststart", "-f", "mp4", outputPath)
+
+	output, err := exec.Command("ffmpeg", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("配音混音失败: %v\n%s", err, ffmpegTail(string(output)))
+	}
+	return nil
+}
+
+// ffmpegTail 只保留 ffmpeg 最后的输出，进度信息很长
+func ffmpegTail(output string) string {
+	if len(output) > 2000 {
+		return output[len(output)-2000:]
+	}
+	return output
+}
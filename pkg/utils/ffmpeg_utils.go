@@ -7,6 +7,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"regexp"
 	"strconv"
 	"strings"
 )
@@ -40,6 +41,66 @@ func TranscodeVideo(inputVideoPath, outputVideoPath, preset string, crf int, aud
 	return nil
 }
 
+// BurnInOptions 字幕烧录参数
+type BurnInOptions struct {
+	VideoCodec   string // 视频编码器，默认 libx264
+	Preset       string // 编码预设，默认 medium
+	CRF          int    // 画质，默认 23
+	AudioBitrate string // 音频码率，为空时直接复制音频流
+	FontsDir     string // 字体目录（可选）
+}
+
+// BurnSubtitles 使用 ffmpeg subtitles 滤镜将字幕（SRT/ASS）烧录进视频
+func BurnSubtitles(inputVideoPath, subtitlePath, outputVideoPath string, opts BurnInOptions) error {
+	if opts.VideoCodec == "" {
+		opts.VideoCodec = "libx264"
+	}
+	if opts.Preset == "" {
+		opts.Preset = "medium"
+	}
+	if opts.CRF <= 0 {
+		opts.CRF = 23
+	}
+
+	filter := "subtitles=filename=" + escapeFilterValue(subtitlePath)
+	if opts.FontsDir != "" {
+		filter += ":fontsdir=" + escapeFilterValue(opts.FontsDir)
+	}
+
+	args := []string{
+		"-y",
+		"-i", inputVideoPath,
+		"-vf", filter,
+		"-c:v", opts.VideoCodec,
+		"-preset", opts.Preset,
+		"-crf", strconv.Itoa(opts.CRF),
+	}
+	if opts.AudioBitrate != "" {
+		args = append(args, "-c:a", "aac", "-b:a", opts.AudioBitrate)
+	} else {
+		args = append(args, "-c:a", "copy")
+	}
+	// 输出路径可能是临时文件名，显式指定 mp4 格式
+	args = append(args, "-movflags", "+faststart", "-f", "mp4", outputVideoPath)
+
+	output, err := exec.Command("ffmpeg", args...).CombinedOutput()
+	if err != nil {
+		// 只保留最后的输出，ffmpeg 的进度信息很长
+		tail := string(output)
+		if len(tail) > 2000 {
+			tail = tail[len(tail)-2000:]
+		}
+		return fmt.Errorf("字幕烧录失败: %v\n%s", err, tail)
+	}
+	return nil
+}
+
+// escapeFilterValue 转义 ffmpeg 滤镜参数值：先按滤镜选项转义，再按滤镜图转义
+func escapeFilterValue(value string) string {
+	option := strings.NewReplacer(`\`, `\\`, `'`, `\'`, `:`, `\:`).Replace(value)
+	return strings.NewReplacer(`\`, `\\`, `'`, `\'`, `[`, `\[`, `]`, `\]`, `,`, `\,`, `;`, `\;`).Replace(option)
+}
+
 // ExtractWaveAudio 从视频文件中分离出WAV格式的音频
 func ExtractWaveAudio(inputFile, outputFile string) error {
 	// 构造 ffmpeg 命令，提取音频并转换为WAV格式
@@ -181,6 +242,21 @@ func ExtractThumbnail(videoPath, outputPath string) error {
 	return nil
 }
 
+// GetMediaDuration 使用 ffprobe 获取媒体文件时长（秒）
+func GetMediaDuration(inputFile string) (float64, error) {
+	cmd := exec.Command("ffprobe", "-v", "error", "-show_entries", "format=duration",
+		"-of", "default=noprint_wrappers=1:nokey=1", inputFile)
+	output, err := cmd.Output()
+	if err != nil {
+		return 0, fmt.Errorf("执行 ffprobe 命令出错: %v", err)
+	}
+	duration, err := strconv.ParseFloat(strings.TrimSpace(string(output)), 64)
+	if err != nil {
+		return 0, fmt.Errorf("解析媒体时长失败: %v", err)
+	}
+	return duration, nil
+}
+
 func ConvertToHLS(inputPath, outputDir string) error {
 	// 确保输出目录存在
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
@@ -268,3 +344,99 @@ func ParseM3U8File(filePath string) ([]string, error) {
 
 	return fullPaths, nil
 }
+
+// AudioActivityStats 音频活动分析结果（基于 silencedetect + volumedetect）
+type AudioActivityStats struct {
+	Duration        float64 `json:"duration"`         // 音频总时长（秒）
+	SilenceDuration float64 `json:"silence_duration"` // 静音总时长（秒）
+	SilenceSegments int     `json:"silence_segments"` // 静音片段数
+	MeanVolume      float64 `json:"mean_volume"`      // 平均音量（dB）
+	MaxVolume       float64 `json:"max_volume"`       // 最大音量（dB）
+}
+
+// ActiveRatio 非静音部分占总时长的比例
+func (s *AudioActivityStats) ActiveRatio() float64 {
+	if s.Duration <= 0 {
+		return 0
+	}
+	ratio := 1 - s.SilenceDuration/s.Duration
+	if ratio < 0 {
+		return 0
+	}
+	return ratio
+}
+
+var (
+	ffmpegDurationRe        = regexp.MustCompile(`Duration: (\d+):(\d+):(\d+(?:\.\d+)?)`)
+	ffmpegSilenceDurationRe = regexp.MustCompile(`silence_duration: (-?\d+(?:\.\d+)?)`)
+	ffmpegSilenceStartRe    = regexp.MustCompile(`silence_start: (-?\d+(?:\.\d+)?)`)
+	ffmpegMeanVolumeRe      = regexp.MustCompile(`mean_volume: (-?\d+(?:\.\d+)?) dB`)
+	ffmpegMaxVolumeRe       = regexp.MustCompile(`max_volume: (-?\d+(?:\.\d+)?) dB`)
+)
+
+// AnalyzeAudioActivity 使用 ffmpeg silencedetect/volumedetect 分析音频中的静音与音量
+// noiseDB 为静音阈值（如 -35），minSilence 为最短静音时长（秒）
+func AnalyzeAudioActivity(inputFile string, noiseDB, minSilence float64) (*AudioActivityStats, error) {
+	filter := fmt.Sprintf("silencedetect=noise=%.1fdB:d=%.2f,volumedetect", noiseDB, minSilence)
+	cmd := exec.Command(
+		"ffmpeg",
+		"-hide_banner",
+		"-i", inputFile,
+		"-vn",
+		"-af", filter,
+		"-f", "null",
+		"-",
+	)
+
+	// silencedetect/volumedetect 的结果输出在 stderr
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg 音频分析失败: %v", err)
+	}
+
+	return parseAudioActivityOutput(string(output)), nil
+}
+
+// parseAudioActivityOutput 解析 ffmpeg 输出中的时长、静音片段和音量信息
+func parseAudioActivityOutput(output string) *AudioActivityStats {
+	stats := &AudioActivityStats{
+		MeanVolume: -91,
+		MaxVolume:  -91,
+	}
+
+	if m := ffmpegDurationRe.FindStringSubmatch(output); len(m) == 4 {
+		h, _ := strconv.ParseFloat(m[1], 64)
+		min, _ := strconv.ParseFloat(m[2], 64)
+		sec, _ := strconv.ParseFloat(m[3], 64)
+		stats.Duration = h*3600 + min*60 + sec
+	}
+
+	for _, m := range ffmpegSilenceDurationRe.FindAllStringSubmatch(output, -1) {
+		if d, err := strconv.ParseFloat(m[1], 64); err == nil && d > 0 {
+			stats.SilenceDuration += d
+			stats.SilenceSegments++
+		}
+	}
+
+	// 音频以静音结尾时 ffmpeg 不会输出最后一段的 silence_duration，需要补齐
+	starts := ffmpegSilenceStartRe.FindAllStringSubmatch(output, -1)
+	if len(starts) > stats.SilenceSegments && stats.Duration > 0 {
+		if start, err := strconv.ParseFloat(starts[len(starts)-1][1], 64); err == nil && start < stats.Duration {
+			stats.SilenceDuration += stats.Duration - start
+			stats.SilenceSegments++
+		}
+	}
+
+	if m := ffmpegMeanVolumeRe.FindStringSubmatch(output); len(m) == 2 {
+		stats.MeanVolume, _ = strconv.ParseFloat(m[1], 64)
+	}
+	if m := ffmpegMaxVolumeRe.FindStringSubmatch(output); len(m) == 2 {
+		stats.MaxVolume, _ = strconv.ParseFloat(m[1], 64)
+	}
+
+	if stats.SilenceDuration > stats.Duration && stats.Duration > 0 {
+		stats.SilenceDuration = stats.Duration
+	}
+
+	return stats
+}
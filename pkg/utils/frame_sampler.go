@@ -0,0 +1,156 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+// 关键帧采样方式
+const (
+	FrameSampleModeScene    = "scene"    // 场景切换检测，场景数不足时回退到均匀间隔
+	FrameSampleModeInterval = "interval" // 按均匀间隔采样
+)
+
+// FrameSampleOptions 关键帧采样参数
+type FrameSampleOptions struct {
+	Count          int     `json:"count"`           // 采样帧数
+	Mode           string  `json:"mode"`            // scene / interval
+	SceneThreshold float64 `json:"scene_threshold"` // 场景切换阈值（0-1），越小检测到的场景越多
+	MaxWidth       int     `json:"max_width"`       // 输出图片最大宽度（像素），超过时等比缩放
+}
+
+// SampledFrame 采样得到的关键帧
+type SampledFrame struct {
+	Path      string  `json:"path"`
+	Timestamp float64 `json:"timestamp"` // 在视频中的时间（秒）
+}
+
+var ffmpegShowInfoPtsRe = regexp.MustCompile(`Parsed_showinfo.*?pts_time:\s*(\d+(?:\.\d+)?)`)
+
+// SampleFrames 从视频中采样关键帧（JPEG），按时间顺序返回。
+// 场景模式下检测到的场景切换多于 Count 时均匀挑选，少于 Count 时改为均匀间隔采样
+func SampleFrames(videoPath, outputDir string, opts FrameSampleOptions) ([]SampledFrame, error) {
+	if opts.Count <= 0 {
+		return nil, fmt.Errorf("采样帧数必须大于 0")
+	}
+	if opts.SceneThreshold <= 0 || opts.SceneThreshold >= 1 {
+		opts.SceneThreshold = 0.3
+	}
+	if opts.MaxWidth <= 0 {
+		opts.MaxWidth = 768
+	}
+	if err := os.MkdirAll(outputDir, os.ModePerm); err != nil {
+		return nil, fmt.Errorf("创建采样目录失败: %v", err)
+	}
+
+	if opts.Mode != FrameSampleModeInterval {
+		frames, err := sampleSceneFrames(videoPath, outputDir, opts)
+		if err != nil {
+			return nil, err
+		}
+		if len(frames) >= opts.Count {
+			return frames, nil
+		}
+		removeFrames(frames)
+	}
+
+	return sampleIntervalFrames(videoPath, outputDir, opts)
+}
+
+// sampleSceneFrames 用 select=gt(scene,x) 提取场景切换帧，只解码关键帧以加快长视频的处理
+func sampleSceneFrames(videoPath, outputDir string, opts FrameSampleOptions) ([]SampledFrame, error) {
+	pattern := filepath.Join(outputDir, "scene_%04d.jpg")
+	filter := fmt.Sprintf("select='gt(scene,%.2f)',showinfo,scale='min(%d,iw)':-2", opts.SceneThreshold, opts.MaxWidth)
+	cmd := exec.Command(
+		"ffmpeg",
+		"-hide_banner",
+		"-y",
+		"-skip_frame", "nokey",
+		"-i", videoPath,
+		"-vf", filter,
+		"-vsync", "vfr",
+		"-q:v", "3",
+		pattern,
+	)
+
+	// showinfo 的输出在 stderr，顺序与输出文件编号一致
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg 场景检测失败: %v", err)
+	}
+
+	var frames []SampledFrame
+	for i, m := range ffmpegShowInfoPtsRe.FindAllStringSubmatch(string(output), -1) {
+		timestamp, _ := strconv.ParseFloat(m[1], 64)
+		path := filepath.Join(outputDir, fmt.Sprintf("scene_%04d.jpg", i+1))
+		if _, err := os.Stat(path); err != nil {
+			break
+		}
+		frames = append(frames, SampledFrame{Path: path, Timestamp: timestamp})
+	}
+
+	if len(frames) <= opts.Count {
+		return frames, nil
+	}
+
+	// 均匀挑选 Count 帧，删除其余图片
+	selected := make([]SampledFrame, 0, opts.Count)
+	keep := make(map[int]bool, opts.Count)
+	for i := 0; i < opts.Count; i++ {
+		index := i * len(frames) / opts.Count
+		keep[index] = true
+		selected = append(selected, frames[index])
+	}
+	for i, frame := range frames {
+		if !keep[i] {
+			os.Remove(frame.Path)
+		}
+	}
+	return selected, nil
+}
+
+// sampleIntervalFrames 将视频均分为 Count 段，取每段中点的画面
+func sampleIntervalFrames(videoPath, outputDir string, opts FrameSampleOptions) ([]SampledFrame, error) {
+	duration, err := GetMediaDuration(videoPath)
+	if err != nil {
+		return nil, err
+	}
+	if duration <= 0 {
+		return nil, fmt.Errorf("无法获取视频时长")
+	}
+
+	frames := make([]SampledFrame, 0, opts.Count)
+	for i := 0; i < opts.Count; i++ {
+		timestamp := duration * (float64(i) + 0.5) / float64(opts.Count)
+		path := filepath.Join(outputDir, fmt.Sprintf("interval_%04d.jpg", i+1))
+		cmd := exec.Command(
+			"ffmpeg",
+			"-hide_banner",
+			"-y",
+			"-ss", strconv.FormatFloat(timestamp, 'f', 3, 64),
+			"-i", videoPath,
+			"-frames:v", "1",
+			"-vf", fmt.Sprintf("scale='min(%d,iw)':-2", opts.MaxWidth),
+			"-q:v", "3",
+			path,
+		)
+		if err := cmd.Run(); err != nil {
+			removeFrames(frames)
+			return nil, fmt.Errorf("ffmpeg 截取第 %d 帧失败: %v", i+1, err)
+		}
+		frames = append(frames, SampledFrame{Path: path, Timestamp: timestamp})
+	}
+
+	return frames, nil
+}
+
+// removeFrames 删除采样得到的图片文件
+func removeFrames(frames []SampledFrame) {
+	for _, frame := range frames {
+		os.Remove(frame.Path)
+	}
+}
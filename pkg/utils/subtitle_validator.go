@@ -3,34 +3,54 @@ package utils
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
-	"path/filepath"
 	"regexp"
 	"strings"
 	"time"
 
+	"github.com/difyz9/ytb2bili/pkg/prompt"
+	"github.com/difyz9/ytb2bili/pkg/ratelimit"
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+	"github.com/difyz9/ytb2bili/pkg/translator"
 	"go.uber.org/zap"
 )
 
+// ChatFunc 对话补全函数，返回模型回复（由调用方负责选择提供商、重试和记录用量）
+type ChatFunc func(ctx context.Context, systemPrompt, userPrompt string) (string, error)
+
 // SubtitleValidator 字幕校验和优化器
 type SubtitleValidator struct {
 	logger        *zap.SugaredLogger
 	apiKey        string
+	chat          ChatFunc // 设置后代替内置的 DeepSeek 请求
 	maxRetries    int
 	retryInterval time.Duration
+	glossary      []translator.GlossaryTerm
+
+	// 修复提示词的模板变量（原视频标题、来源频道）和实际使用的模板版本
+	sourceTitle   string
+	channel       string
+	promptVersion string
+
+	// 用量归属（记录 token 用量和费用时使用）
+	videoID string
+	step    string
 }
 
 // SubtitleEntry 字幕条目
 type SubtitleEntry struct {
 	Index      int
-	TimeCode   string
+	Start      int64  // 开始时间（毫秒）
+	End        int64  // 结束时间（毫秒）
 	Original   string // 原始英文
 	Translated string // 翻译中文
-	Status     string // 状态: "ok", "missing", "incomplete", "error"
+	Status     string // 状态: "ok", "missing", "incomplete", "glossary", "error"
 }
 
 // ValidationResult 校验结果
@@ -40,6 +60,8 @@ type ValidationResult struct {
 	MissingEntries int             `json:"missing_entries"`
 	ErrorEntries   []int           `json:"error_entries"`
 	FixedEntries   []int           `json:"fixed_entries"`
+	GlossaryIssues []int           `json:"glossary_issues,omitempty"` // 未按术语表翻译的条目
+	PromptVersion  string          `json:"prompt_version,omitempty"`  // 修复问题条目使用的提示词模板版本
 	IssueDetails   map[int]string  `json:"issue_details"`
 	ProcessingTime time.Duration   `json:"processing_time"`
 	Entries        []SubtitleEntry `json:"entries"`
@@ -55,6 +77,28 @@ func NewSubtitleValidator(logger *zap.SugaredLogger, apiKey string) *SubtitleVal
 	}
 }
 
+// SetGlossary 设置术语表：未按规定译法翻译的条目视为问题条目，修复时术语表会注入提示词
+func (v *SubtitleValidator) SetGlossary(terms []translator.GlossaryTerm) {
+	v.glossary = terms
+}
+
+// SetChat 设置对话补全函数，设置后修复和评分请求不再直接调用 DeepSeek（apiKey 可以为空）
+func (v *SubtitleValidator) SetChat(chat ChatFunc) {
+	v.chat = chat
+}
+
+// SetPromptContext 设置修复提示词模板中的原视频标题和来源频道
+func (v *SubtitleValidator) SetPromptContext(sourceTitle, channel string) {
+	v.sourceTitle = sourceTitle
+	v.channel = channel
+}
+
+// SetUsageScope 设置 LLM 调用的用量归属（视频和任务步骤）
+func (v *SubtitleValidator) SetUsageScope(videoID, step string) {
+	v.videoID = videoID
+	v.step = step
+}
+
 // ValidateAndFixSubtitles 校验并修复字幕文件
 func (v *SubtitleValidator) ValidateAndFixSubtitles(originalSRTPath, translatedSRTPath, outputPath string) (*ValidationResult, error) {
 	startTime := time.Now()
@@ -94,24 +138,31 @@ func (v *SubtitleValidator) ValidateAndFixSubtitles(originalSRTPath, translatedS
 			result.MissingEntries++
 			problemEntries = append(problemEntries, entry)
 			result.IssueDetails[entry.Index] = fmt.Sprintf("状态: %s, 内容: %s", entry.Status, entry.Translated)
+		case "glossary":
+			result.GlossaryIssues = append(result.GlossaryIssues, entry.Index)
+			problemEntries = append(problemEntries, entry)
+			result.IssueDetails[entry.Index] = fmt.Sprintf("术语未按规定翻译: %s", v.describeGlossaryIssues(entry))
 		case "error":
 			result.ErrorEntries = append(result.ErrorEntries, entry.Index)
 			result.IssueDetails[entry.Index] = fmt.Sprintf("错误条目: %s", entry.Translated)
 		}
 	}
 
-	v.logger.Infof("📋 分析结果: 有效 %d 条，问题 %d 条，错误 %d 条",
-		result.ValidEntries, result.MissingEntries, len(result.ErrorEntries))
+	v.logger.Infof("📋 分析结果: 有效 %d 条，问题 %d 条，术语不一致 %d 条，错误 %d 条",
+		result.ValidEntries, result.MissingEntries, len(result.GlossaryIssues), len(result.ErrorEntries))
 
 	// 6. 修复问题条目
 	if len(problemEntries) > 0 {
 		v.logger.Infof("🔧 开始修复 %d 个问题条目...", len(problemEntries))
-		fixedEntries, err := v.fixProblemEntries(problemEntries)
+		fixedEntries, err := v.fixProblemEntries(problemEntries, entries)
 		if err != nil {
 			v.logger.Errorf("❌ 修复过程中发生错误: %v", err)
 		} else {
 			// 应用修复结果
 			for _, fixed := range fixedEntries {
+				if fixed.Status == "ok" && v.hasGlossaryIssues(fixed) {
+					fixed.Status = "glossary"
+				}
 				for i, entry := range entries {
 					if entry.Index == fixed.Index {
 						entries[i] = fixed
@@ -121,6 +172,7 @@ func (v *SubtitleValidator) ValidateAndFixSubtitles(originalSRTPath, translatedS
 				}
 			}
 			v.logger.Infof("✅ 成功修复 %d 个条目", len(fixedEntries))
+			result.PromptVersion = v.promptVersion
 		}
 	}
 
@@ -138,12 +190,15 @@ func (v *SubtitleValidator) ValidateAndFixSubtitles(originalSRTPath, translatedS
 	result.ValidEntries = 0
 	result.MissingEntries = 0
 	result.ErrorEntries = []int{}
+	result.GlossaryIssues = nil
 	for _, entry := range entries {
 		switch entry.Status {
 		case "ok":
 			result.ValidEntries++
 		case "missing", "incomplete":
 			result.MissingEntries++
+		case "glossary":
+			result.GlossaryIssues = append(result.GlossaryIssues, entry.Index)
 		case "error":
 			result.ErrorEntries = append(result.ErrorEntries, entry.Index)
 		}
@@ -160,57 +215,21 @@ func (v *SubtitleValidator) ValidateAndFixSubtitles(originalSRTPath, translatedS
 
 // parseSRTFile 解析SRT文件
 func (v *SubtitleValidator) parseSRTFile(filePath string) ([]SubtitleEntry, error) {
-	file, err := os.Open(filePath)
+	doc, err := subtitle.ReadFile(filePath)
 	if err != nil {
 		return nil, err
 	}
-	defer file.Close()
-
-	var entries []SubtitleEntry
-	scanner := bufio.NewScanner(file)
-
-	var currentEntry SubtitleEntry
-	var textLines []string
-	stage := 0 // 0=等待序号, 1=等待时间码, 2=读取文本
-
-	for scanner.Scan() {
-		line := strings.TrimSpace(scanner.Text())
-
-		if line == "" {
-			// 空行表示一个条目结束
-			if stage == 2 && len(textLines) > 0 {
-				currentEntry.Translated = strings.Join(textLines, "\n")
-				entries = append(entries, currentEntry)
-				textLines = nil
-				stage = 0
-			}
-			continue
-		}
-
-		switch stage {
-		case 0: // 读取序号
-			var index int
-			if _, err := fmt.Sscanf(line, "%d", &index); err == nil {
-				currentEntry = SubtitleEntry{Index: index}
-				stage = 1
-			}
-		case 1: // 读取时间码
-			if strings.Contains(line, "-->") {
-				currentEntry.TimeCode = line
-				stage = 2
-			}
-		case 2: // 读取文本
-			textLines = append(textLines, line)
-		}
-	}
 
-	// 处理最后一个条目
-	if stage == 2 && len(textLines) > 0 {
-		currentEntry.Translated = strings.Join(textLines, "\n")
-		entries = append(entries, currentEntry)
+	entries := make([]SubtitleEntry, 0, doc.Len())
+	for _, cue := range doc.Cues {
+		entries = append(entries, SubtitleEntry{
+			Index:      cue.Index,
+			Start:      cue.Start,
+			End:        cue.End,
+			Translated: cue.Text,
+		})
 	}
-
-	return entries, scanner.Err()
+	return entries, nil
 }
 
 // mergeAndAnalyzeEntries 合并并分析原始和翻译字幕
@@ -227,7 +246,8 @@ func (v *SubtitleValidator) mergeAndAnalyzeEntries(original, translated []Subtit
 	for _, translatedEntry := range translated {
 		entry := SubtitleEntry{
 			Index:      translatedEntry.Index,
-			TimeCode:   translatedEntry.TimeCode,
+			Start:      translatedEntry.Start,
+			End:        translatedEntry.End,
 			Translated: translatedEntry.Translated,
 		}
 
@@ -238,6 +258,9 @@ func (v *SubtitleValidator) mergeAndAnalyzeEntries(original, translated []Subtit
 
 		// 分析翻译状态
 		entry.Status = v.analyzeTranslationStatus(entry.Translated)
+		if entry.Status == "ok" && v.hasGlossaryIssues(entry) {
+			entry.Status = "glossary"
+		}
 
 		entries = append(entries, entry)
 	}
@@ -294,6 +317,20 @@ func (v *SubtitleValidator) analyzeTranslationStatus(text string) string {
 	return "ok"
 }
 
+// hasGlossaryIssues 条目是否存在未按术语表翻译的术语
+func (v *SubtitleValidator) hasGlossaryIssues(entry SubtitleEntry) bool {
+	return len(v.glossary) > 0 && len(translator.CheckGlossary(entry.Original, entry.Translated, v.glossary)) > 0
+}
+
+// describeGlossaryIssues 描述条目中未遵守的术语
+func (v *SubtitleValidator) describeGlossaryIssues(entry SubtitleEntry) string {
+	var issues []string
+	for _, term := range translator.CheckGlossary(entry.Original, entry.Translated, v.glossary) {
+		issues = append(issues, fmt.Sprintf("%s → %s", term.Source, term.ExpectedTarget()))
+	}
+	return strings.Join(issues, ", ")
+}
+
 // isPureEnglish 检测是否为纯英文
 func (v *SubtitleValidator) isPureEnglish(text string) bool {
 	// 简单的中文字符检测
@@ -301,9 +338,9 @@ func (v *SubtitleValidator) isPureEnglish(text string) bool {
 	return !chinesePattern.MatchString(text)
 }
 
-// fixProblemEntries 修复问题条目
-func (v *SubtitleValidator) fixProblemEntries(problemEntries []SubtitleEntry) ([]SubtitleEntry, error) {
-	if v.apiKey == "" {
+// fixProblemEntries 修复问题条目，allEntries 用于给模型提供前后文
+func (v *SubtitleValidator) fixProblemEntries(problemEntries, allEntries []SubtitleEntry) ([]SubtitleEntry, error) {
+	if v.apiKey == "" && v.chat == nil {
 		return nil, fmt.Errorf("API Key 未配置，无法进行自动修复")
 	}
 
@@ -319,7 +356,7 @@ func (v *SubtitleValidator) fixProblemEntries(problemEntries []SubtitleEntry) ([
 		batch := problemEntries[i:end]
 		v.logger.Infof("🔧 修复第 %d-%d 条问题字幕...", i+1, end)
 
-		fixedBatch, err := v.fixBatchEntries(batch)
+		fixedBatch, err := v.fixBatchEntries(batch, allEntries)
 		if err != nil {
 			v.logger.Warnf("⚠️  批次修复失败: %v", err)
 			// 继续处理其他批次
@@ -337,107 +374,110 @@ func (v *SubtitleValidator) fixProblemEntries(problemEntries []SubtitleEntry) ([
 	return fixedEntries, nil
 }
 
-// fixBatchEntries 修复一批条目
-func (v *SubtitleValidator) fixBatchEntries(entries []SubtitleEntry) ([]SubtitleEntry, error) {
+// fixBatchEntries 修复一批条目：以 JSON 编号（字幕序号）收发，相邻字幕作为只读上下文，
+// 缺失的编号单独重新请求一次，只返回确实拿到新译文的条目
+func (v *SubtitleValidator) fixBatchEntries(entries, allEntries []SubtitleEntry) ([]SubtitleEntry, error) {
 	if len(entries) == 0 {
 		return []SubtitleEntry{}, nil
 	}
 
-	// 准备翻译文本
+	// 准备翻译文本（没有原文的条目无法重新翻译）
+	pending := make(map[int]SubtitleEntry, len(entries))
 	var englishTexts []string
 	for _, entry := range entries {
-		if entry.Original != "" {
-			englishTexts = append(englishTexts, entry.Original)
-		} else {
-			// 如果没有原始英文，尝试从现有翻译中推断
-			englishTexts = append(englishTexts, "[需要重新翻译]")
+		if entry.Original == "" {
+			continue
 		}
+		pending[entry.Index] = entry
+		englishTexts = append(englishTexts, entry.Original)
 	}
-
-	// 构建修复提示
-	systemPrompt := fmt.Sprintf(`你是专业的视频字幕翻译专家。现在需要重新翻译 %d 句有问题的英文字幕。
-
-翻译要求：
-1. 自然流畅：使用口语化表达，符合中文字幕习惯  
-2. 准确传神：忠实原文含义，保持语气和情感
-3. 简洁明了：字幕需要快速阅读，避免冗长
-4. 完整输出：必须为每句英文提供完整的中文翻译
-5. 数量严格：必须输出 %d 句翻译，不多不少
-6. 分隔符：每句翻译用"###SENTENCE_BREAK###"分隔
-
-注意：之前的翻译中可能有缺失或错误，请提供完整准确的重新翻译。
-
-输出格式：只返回中文翻译，用"###SENTENCE_BREAK###"分隔，不要添加序号或其他内容。`,
-		len(englishTexts), len(englishTexts))
-
-	combinedText := strings.Join(englishTexts, "\n###SENTENCE_BREAK###\n")
-
-	// 调用翻译API
-	translatedText, err := v.callDeepSeekAPI(systemPrompt, combinedText)
-	if err != nil {
-		return nil, fmt.Errorf("调用翻译API失败: %v", err)
+	if len(pending) == 0 {
+		return []SubtitleEntry{}, nil
 	}
 
-	// 解析翻译结果
-	translatedSentences := strings.Split(translatedText, "###SENTENCE_BREAK###")
-	for i := range translatedSentences {
-		translatedSentences[i] = strings.TrimSpace(translatedSentences[i])
+	// 构建修复提示（提示词模板可通过接口修改，结构化格式说明固定追加）
+	rendered, err := prompt.Render(prompt.SubtitleFix, &prompt.Data{
+		SourceTitle:    v.sourceTitle,
+		Channel:        v.channel,
+		TargetLanguage: "简体中文",
+		Glossary:       translator.GlossaryPrompt(translator.FilterGlossary(v.glossary, englishTexts)),
+	})
+	if err != nil {
+		return nil, err
 	}
+	v.promptVersion = rendered.Version
+	systemPrompt := rendered.System + "\n" + translator.CueFormatPrompt()
 
-	// 确保数量匹配
-	if len(translatedSentences) != len(entries) {
-		v.logger.Warnf("⚠️  修复结果数量不匹配: 期望%d，实际%d", len(entries), len(translatedSentences))
-		// 补齐或截断
-		for len(translatedSentences) < len(entries) {
-			translatedSentences = append(translatedSentences, "[修复失败]")
+	var fixedEntries []SubtitleEntry
+	for attempt := 0; attempt < 2 && len(pending) > 0; attempt++ {
+		var cues []translator.CueText
+		var ids []int
+		for _, entry := range entries {
+			if _, ok := pending[entry.Index]; ok {
+				cues = append(cues, translator.CueText{ID: entry.Index, Text: entry.Original})
+				ids = append(ids, entry.Index)
+			}
 		}
-		if len(translatedSentences) > len(entries) {
-			translatedSentences = translatedSentences[:len(entries)]
+
+		// 调用翻译API
+		translatedText, err := v.callChatAPI(systemPrompt, translator.FormatCuePayload(cues, v.cueContext(pending, allEntries)))
+		if err != nil {
+			if len(fixedEntries) > 0 {
+				break
+			}
+			return nil, fmt.Errorf("调用翻译API失败: %v", err)
 		}
-	}
 
-	// 生成修复后的条目
-	var fixedEntries []SubtitleEntry
-	for i, entry := range entries {
-		fixed := entry
-		if i < len(translatedSentences) && translatedSentences[i] != "" {
-			fixed.Translated = translatedSentences[i]
+		// 按编号回填，缺失的编号下一轮单独重新请求
+		translations, missing, err := translator.ParseCueTranslations(translatedText, ids)
+		if err != nil {
+			v.logger.Warnf("⚠️  解析修复结果失败: %v", err)
+		}
+		for id, text := range translations {
+			fixed := pending[id]
+			fixed.Translated = text
 			fixed.Status = "ok" // 标记为已修复
+			fixedEntries = append(fixedEntries, fixed)
+			delete(pending, id)
+		}
+		if len(missing) > 0 {
+			v.logger.Warnf("⚠️  修复结果缺少 %d 条: %v", len(missing), missing)
 		}
-		fixedEntries = append(fixedEntries, fixed)
 	}
 
 	return fixedEntries, nil
 }
 
+// cueContext 取待修复条目前后各 2 条的原文作为只读上下文
+func (v *SubtitleValidator) cueContext(pending map[int]SubtitleEntry, allEntries []SubtitleEntry) []translator.CueText {
+	var context []translator.CueText
+	for i, entry := range allEntries {
+		if _, ok := pending[entry.Index]; ok || entry.Original == "" {
+			continue
+		}
+		for k := i - 2; k <= i+2; k++ {
+			if k < 0 || k >= len(allEntries) {
+				continue
+			}
+			if _, ok := pending[allEntries[k].Index]; ok {
+				context = append(context, translator.CueText{ID: entry.Index, Text: entry.Original})
+				break
+			}
+		}
+	}
+	return context
+}
+
 // generateOptimizedSRT 生成优化后的SRT文件
 func (v *SubtitleValidator) generateOptimizedSRT(entries []SubtitleEntry, outputPath string) error {
-	// 确保输出目录存在
-	dir := filepath.Dir(outputPath)
-	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("创建输出目录失败: %v", err)
+	doc := subtitle.NewDocument("")
+	for _, entry := range entries {
+		doc.Add(entry.Start, entry.End, entry.Translated)
 	}
 
-	file, err := os.Create(outputPath)
-	if err != nil {
+	if err := subtitle.WriteFileAs(outputPath, doc, subtitle.FormatSRT); err != nil {
 		return fmt.Errorf("创建输出文件失败: %v", err)
 	}
-	defer file.Close()
-
-	writer := bufio.NewWriter(file)
-	defer writer.Flush()
-
-	for _, entry := range entries {
-		// 写入序号
-		fmt.Fprintf(writer, "%d\n", entry.Index)
-
-		// 写入时间码
-		fmt.Fprintf(writer, "%s\n", entry.TimeCode)
-
-		// 写入翻译内容
-		fmt.Fprintf(writer, "%s\n\n", entry.Translated)
-	}
-
 	return nil
 }
 
@@ -479,6 +519,10 @@ func (v *SubtitleValidator) GenerateValidationReport(result *ValidationResult, r
 		fmt.Fprintf(writer, "修复的条目: %v\n", result.FixedEntries)
 	}
 
+	if len(result.GlossaryIssues) > 0 {
+		fmt.Fprintf(writer, "术语不一致的条目: %v\n", result.GlossaryIssues)
+	}
+
 	return nil
 }
 
@@ -506,6 +550,12 @@ type deepSeekResponse struct {
 	Created int64            `json:"created"`
 	Model   string           `json:"model"`
 	Choices []deepSeekChoice `json:"choices"`
+	Usage   *deepSeekUsage   `json:"usage"`
+}
+
+type deepSeekUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
 }
 
 type deepSeekChoice struct {
@@ -514,8 +564,16 @@ type deepSeekChoice struct {
 	FinishReason string          `json:"finish_reason"`
 }
 
-// callDeepSeekAPI 调用DeepSeek API
-func (v *SubtitleValidator) callDeepSeekAPI(systemPrompt, userPrompt string) (string, error) {
+// callChatAPI 调用对话接口：设置了 ChatFunc 时使用它，否则直接调用 DeepSeek API
+func (v *SubtitleValidator) callChatAPI(systemPrompt, userPrompt string) (string, error) {
+	if v.chat != nil {
+		return v.chat(translator.WithUsageScope(context.Background(), v.videoID, v.step), systemPrompt, userPrompt)
+	}
+
+	if err := translator.CheckBudget(v.videoID); err != nil {
+		return "", err
+	}
+
 	var lastErr error
 
 	for attempt := 0; attempt <= v.maxRetries; attempt++ {
@@ -529,6 +587,11 @@ func (v *SubtitleValidator) callDeepSeekAPI(systemPrompt, userPrompt string) (st
 		}
 
 		lastErr = err
+
+		// 熔断期间重试没有意义
+		if errors.Is(err, ratelimit.ErrCircuitOpen) {
+			return "", err
+		}
 		v.logger.Warnf("API调用失败 (尝试 %d/%d): %v", attempt+1, v.maxRetries+1, err)
 
 		// 如果是API限制错误，延长等待时间
@@ -540,8 +603,40 @@ func (v *SubtitleValidator) callDeepSeekAPI(systemPrompt, userPrompt string) (st
 	return "", fmt.Errorf("重试 %d 次后仍然失败: %v", v.maxRetries, lastErr)
 }
 
-// doRequest 执行单次API请求
+// doRequest 执行单次API请求并记录用量
 func (v *SubtitleValidator) doRequest(systemPrompt, userPrompt string) (string, error) {
+	permit, err := ratelimit.Acquire(context.Background(), "deepseek", ratelimit.EstimateTokens(systemPrompt, userPrompt))
+	if err != nil {
+		return "", err
+	}
+
+	start := time.Now()
+	content, usage, err := v.post(systemPrompt, userPrompt)
+	permit.Done(err)
+
+	record := &translator.UsageRecord{
+		VideoID:  v.videoID,
+		Step:     v.step,
+		Provider: "deepseek",
+		Model:    "deepseek-chat",
+		Kind:     translator.UsageKindChat,
+		Latency:  time.Since(start),
+		Success:  err == nil,
+	}
+	if usage != nil {
+		record.InputTokens = usage.PromptTokens
+		record.OutputTokens = usage.CompletionTokens
+	}
+	if err != nil {
+		record.Error = err.Error()
+	}
+	translator.RecordUsage(context.Background(), record)
+
+	return content, err
+}
+
+// post 执行 HTTP 请求并解析响应
+func (v *SubtitleValidator) post(systemPrompt, userPrompt string) (string, *deepSeekUsage, error) {
 	request := deepSeekRequest{
 		Model: "deepseek-chat",
 		Messages: []deepSeekMessage{
@@ -563,12 +658,12 @@ func (v *SubtitleValidator) doRequest(systemPrompt, userPrompt string) (string,
 
 	jsonData, err := json.Marshal(request)
 	if err != nil {
-		return "", fmt.Errorf("序列化请求失败: %v", err)
+		return "", nil, fmt.Errorf("序列化请求失败: %v", err)
 	}
 
 	req, err := http.NewRequest("POST", "https://api.deepseek.com/v1/chat/completions", bytes.NewBuffer(jsonData))
 	if err != nil {
-		return "", fmt.Errorf("创建请求失败: %v", err)
+		return "", nil, fmt.Errorf("创建请求失败: %v", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
@@ -577,27 +672,27 @@ func (v *SubtitleValidator) doRequest(systemPrompt, userPrompt string) (string,
 	client := &http.Client{Timeout: 60 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("发送请求失败: %v", err)
+		return "", nil, fmt.Errorf("发送请求失败: %v", err)
 	}
 	defer resp.Body.Close()
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("读取响应失败: %v", err)
+		return "", nil, fmt.Errorf("读取响应失败: %v", err)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("API返回错误 (状态码: %d): %s", resp.StatusCode, string(body))
+		return "", nil, ratelimit.WithStatus(resp.StatusCode, fmt.Errorf("API返回错误 (状态码: %d): %s", resp.StatusCode, string(body)))
 	}
 
 	var response deepSeekResponse
 	if err := json.Unmarshal(body, &response); err != nil {
-		return "", fmt.Errorf("解析响应失败: %v", err)
+		return "", nil, fmt.Errorf("解析响应失败: %v", err)
 	}
 
 	if len(response.Choices) == 0 {
-		return "", fmt.Errorf("API响应中没有结果")
+		return "", nil, fmt.Errorf("API响应中没有结果")
 	}
 
-	return response.Choices[0].Message.Content, nil
+	return response.Choices[0].Message.Content, response.Usage, nil
 }
@@ -0,0 +1,264 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/difyz9/ytb2bili/pkg/subtitle"
+)
+
+// 原文少于该字数的条目不做长度比检查（"Yeah." 这类短句的长度比没有参考意义）
+const minLengthCheckRunes = 15
+
+// TranslationQualityOptions 译文质量评估参数
+type TranslationQualityOptions struct {
+	Language       string  // 目标语言代码（zh-Hans、ja 等），用于判断英文残留
+	MinLengthRatio float64 // 译文/原文长度比下限，低于视为异常（0 表示不检查）
+	MaxLengthRatio float64 // 译文/原文长度比上限，高于视为异常（0 表示不检查）
+}
+
+// TranslationQuality 译文质量统计
+type TranslationQuality struct {
+	Language           string  `json:"language"`
+	File               string  `json:"file"`
+	TotalEntries       int     `json:"total_entries"`        // 原文条目数
+	TranslatedEntries  int     `json:"translated_entries"`   // 有译文的条目数
+	MissingEntries     []int   `json:"missing_entries"`      // 缺失译文的条目
+	EnglishEntries     []int   `json:"english_entries"`      // 仍为英文的条目
+	IncompleteEntries  []int   `json:"incomplete_entries"`   // 疑似不完整的条目
+	LengthAnomalies    []int   `json:"length_anomalies"`     // 译文长度与原文明显不成比例的条目
+	Coverage           float64 `json:"coverage"`             // 覆盖率：有译文的条目 / 原文条目
+	EnglishRatio       float64 `json:"english_ratio"`        // 英文残留占比
+	LengthAnomalyRatio float64 `json:"length_anomaly_ratio"` // 长度比异常占比
+
+	Sample *SampleScore `json:"sample,omitempty"` // LLM 抽样评分（未启用时为空）
+}
+
+// SampleScore LLM 抽样评分结果
+type SampleScore struct {
+	Average float64           `json:"average"` // 平均分（1-10）
+	Items   []SampleScoreItem `json:"items"`
+}
+
+// SampleScoreItem 单条抽样评分
+type SampleScoreItem struct {
+	ID      int     `json:"id"`
+	Score   float64 `json:"score"`
+	Comment string  `json:"comment,omitempty"`
+}
+
+// AssessTranslation 对照原文评估译文质量：覆盖率、英文残留和长度比异常，
+// 返回统计结果和合并后的条目（供抽样评分使用）。
+// 译文按时间重叠对齐到原文条目（质检修复或人工编辑后条目数量可能与原文不同），不依赖序号一一对应
+func (v *SubtitleValidator) AssessTranslation(originalPath, translatedPath string, opts TranslationQualityOptions) (*TranslationQuality, []SubtitleEntry, error) {
+	original, err := subtitle.ReadFile(originalPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("解析原文字幕失败: %v", err)
+	}
+	translated, err := subtitle.ReadFile(translatedPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("解析译文字幕失败: %v", err)
+	}
+	original.Sort()
+	translated.Sort()
+	aligned := subtitle.AlignBilingual(original, translated)
+
+	quality := &TranslationQuality{
+		Language:     opts.Language,
+		File:         translatedPath,
+		TotalEntries: original.Len(),
+	}
+
+	entries := make([]SubtitleEntry, 0, original.Len())
+	for _, origin := range aligned {
+		entry := SubtitleEntry{
+			Index:      origin.Index,
+			Start:      origin.Start,
+			End:        origin.End,
+			Original:   origin.Text,
+			Translated: origin.Secondary, // 与该原文条目重叠时间最长的译文
+		}
+		entry.Status = v.analyzeTranslationStatus(entry.Translated)
+
+		switch {
+		case entry.Status == "missing":
+			quality.MissingEntries = append(quality.MissingEntries, entry.Index)
+		case isEnglishLeftover(entry.Original, entry.Translated, opts.Language):
+			entry.Status = "english"
+			quality.EnglishEntries = append(quality.EnglishEntries, entry.Index)
+		case entry.Status == "incomplete":
+			quality.IncompleteEntries = append(quality.IncompleteEntries, entry.Index)
+		}
+		if entry.Status != "missing" {
+			quality.TranslatedEntries++
+			if isLengthAnomaly(entry.Original, entry.Translated, opts) {
+				quality.LengthAnomalies = append(quality.LengthAnomalies, entry.Index)
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	if quality.TotalEntries > 0 {
+		total := float64(quality.TotalEntries)
+		quality.Coverage = float64(quality.TranslatedEntries) / total
+		quality.EnglishRatio = float64(len(quality.EnglishEntries)) / total
+		quality.LengthAnomalyRatio = float64(len(quality.LengthAnomalies)) / total
+	}
+
+	return quality, entries, nil
+}
+
+// ScoreTranslationSample 从已翻译的条目中均匀抽取 size 条，由 LLM 按 1-10 分评价翻译质量
+func (v *SubtitleValidator) ScoreTranslationSample(entries []SubtitleEntry, size int) (*SampleScore, error) {
+	var candidates []SubtitleEntry
+	for _, entry := range entries {
+		if entry.Status != "missing" && entry.Original != "" {
+			candidates = append(candidates, entry)
+		}
+	}
+	if len(candidates) == 0 || size <= 0 {
+		return nil, fmt.Errorf("没有可抽样的字幕条目")
+	}
+
+	sample := candidates
+	if len(candidates) > size {
+		sample = make([]SubtitleEntry, size)
+		step := float64(len(candidates)) / float64(size)
+		for i := range sample {
+			sample[i] = candidates[int(float64(i)*step)]
+		}
+	}
+
+	type sampleItem struct {
+		ID          int    `json:"id"`
+		Source      string `json:"source"`
+		Translation string `json:"translation"`
+	}
+	items := make([]sampleItem, len(sample))
+	ids := make(map[int]bool, len(sample))
+	for i, entry := range sample {
+		items[i] = sampleItem{ID: entry.Index, Source: entry.Original, Translation: entry.Translated}
+		ids[entry.Index] = true
+	}
+	payload, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("序列化抽样条目失败: %v", err)
+	}
+
+	systemPrompt := `你是专业的视频字幕翻译审校。请逐条评价字幕译文的质量，从准确性、流畅度和完整性综合打分。
+
+评分标准（1-10 分）：
+- 9-10：准确流畅，可直接发布
+- 6-8：意思正确，表达略有生硬
+- 3-5：有明显错译、漏译或语句不通
+- 1-2：未翻译、答非所问或完全错误
+
+输入为 JSON 数组，每个元素包含 id、source（原文）和 translation（译文）。
+只返回 JSON 数组：[{"id":编号,"score":分数,"comment":"简短问题说明，没有问题时留空"}]，不要包含任何解释或 markdown 标记`
+
+	content, err := v.callChatAPI(systemPrompt, string(payload))
+	if err != nil {
+		return nil, fmt.Errorf("调用评分API失败: %v", err)
+	}
+
+	start, end := strings.Index(content, "["), strings.LastIndex(content, "]")
+	if start < 0 || end <= start {
+		return nil, fmt.Errorf("评分结果不是有效的 JSON 数组")
+	}
+	var raw []struct {
+		ID      interface{} `json:"id"`
+		Score   float64     `json:"score"`
+		Comment string      `json:"comment"`
+	}
+	if err := json.Unmarshal([]byte(content[start:end+1]), &raw); err != nil {
+		return nil, fmt.Errorf("解析评分结果失败: %v", err)
+	}
+
+	result := &SampleScore{}
+	total := 0.0
+	for _, item := range raw {
+		id, ok := sampleID(item.ID)
+		if !ok || !ids[id] || item.Score < 1 || item.Score > 10 {
+			continue
+		}
+		delete(ids, id)
+		result.Items = append(result.Items, SampleScoreItem{ID: id, Score: item.Score, Comment: strings.TrimSpace(item.Comment)})
+		total += item.Score
+	}
+	if len(result.Items) == 0 {
+		return nil, fmt.Errorf("评分结果中没有有效的评分")
+	}
+	result.Average = total / float64(len(result.Items))
+
+	return result, nil
+}
+
+// sampleID 兼容数字和字符串形式的编号
+func sampleID(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case float64:
+		return int(v), v == float64(int(v))
+	case string:
+		id, err := strconv.Atoi(strings.TrimSpace(v))
+		return id, err == nil
+	}
+	return 0, false
+}
+
+// isEnglishLeftover 判断译文是否仍为英文：目标语言为中日韩时译文中没有对应文字，
+// 其他语言时译文与原文相同
+func isEnglishLeftover(original, translated, language string) bool {
+	text := strings.TrimSpace(translated)
+	if countLetters(text, unicode.Latin) < 10 {
+		return false
+	}
+
+	if IsCJKLanguage(language) {
+		return countLetters(text, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) == 0
+	}
+	return strings.EqualFold(strings.Join(strings.Fields(text), " "), strings.Join(strings.Fields(original), " "))
+}
+
+// isLengthAnomaly 判断译文长度是否与原文明显不成比例（按去掉空白后的字符数计算）
+func isLengthAnomaly(original, translated string, opts TranslationQualityOptions) bool {
+	originalLen := countNonSpace(original)
+	if originalLen < minLengthCheckRunes {
+		return false
+	}
+
+	ratio := float64(countNonSpace(translated)) / float64(originalLen)
+	if opts.MinLengthRatio > 0 && ratio < opts.MinLengthRatio {
+		return true
+	}
+	return opts.MaxLengthRatio > 0 && ratio > opts.MaxLengthRatio
+}
+
+// IsCJKLanguage 是否为按字计数的中日韩语言（空语言按中文处理）
+func IsCJKLanguage(language string) bool {
+	language = strings.ToLower(language)
+	return language == "" || strings.HasPrefix(language, "zh") || language == "ja" || language == "ko"
+}
+
+func countLetters(text string, tables ...*unicode.RangeTable) int {
+	count := 0
+	for _, r := range text {
+		if unicode.IsOneOf(tables, r) {
+			count++
+		}
+	}
+	return count
+}
+
+func countNonSpace(text string) int {
+	count := 0
+	for _, r := range text {
+		if !unicode.IsSpace(r) {
+			count++
+		}
+	}
+	return count
+}
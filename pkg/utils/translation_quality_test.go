@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+func writeSRT(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// 译文条目数量与原文不同（质检拆分/合并、人工编辑后）时按时间对齐，不会错位评估
+func TestAssessTranslationDifferentCueCount(t *testing.T) {
+	dir := t.TempDir()
+	original := writeSRT(t, dir, "source.srt", `1
+00:00:00,000 --> 00:00:02,000
+Welcome back to the channel everyone.
+
+2
+00:00:02,000 --> 00:00:06,000
+Today we are going to build a small wooden table from scratch.
+
+3
+00:00:06,000 --> 00:00:08,000
+Let's get started right away.
+
+4
+00:00:08,000 --> 00:00:10,000
+This part was never translated at all.
+`)
+	// 第 2 条被拆成两条，第 4 条缺失：按序号对应时第 3 条会与拆分出的后半句错配
+	translated := writeSRT(t, dir, "zh.srt", `1
+00:00:00,000 --> 00:00:02,000
+欢迎回到频道。
+
+2
+00:00:02,000 --> 00:00:04,000
+今天我们要从零开始
+
+3
+00:00:04,000 --> 00:00:06,000
+做一张小木桌。
+
+4
+00:00:06,000 --> 00:00:08,000
+我们马上开始吧。
+`)
+
+	validator := NewSubtitleValidator(zap.NewNop().Sugar(), "")
+	quality, entries, err := validator.AssessTranslation(original, translated, TranslationQualityOptions{Language: "zh-Hans"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if quality.TotalEntries != 4 || quality.TranslatedEntries != 3 {
+		t.Fatalf("unexpected counts: total %d, translated %d", quality.TotalEntries, quality.TranslatedEntries)
+	}
+	if len(quality.MissingEntries) != 1 || quality.MissingEntries[0] != 4 {
+		t.Errorf("missing entries = %v, want [4]", quality.MissingEntries)
+	}
+	if quality.Coverage != 0.75 {
+		t.Errorf("coverage = %v, want 0.75", quality.Coverage)
+	}
+
+	want := []string{"欢迎回到频道。", "今天我们要从零开始 做一张小木桌。", "我们马上开始吧。", ""}
+	for i, entry := range entries {
+		if entry.Translated != want[i] {
+			t.Errorf("entry %d translated = %q, want %q", i+1, entry.Translated, want[i])
+		}
+	}
+}
@@ -132,6 +132,7 @@ export default function TaskQueueStats({ onVideoSelect }: TaskQueueStatsProps) {
     const statusMap: { [key: string]: { label: string; color: string; icon: any; category: TabType } } = {
       '001': { label: '待处理', color: 'bg-gray-100 text-gray-700', icon: Clock, category: 'processing' },
       '002': { label: '处理中', color: 'bg-blue-100 text-blue-700', icon: Play, category: 'processing' },
+      '003': { label: '预算暂停', color: 'bg-orange-100 text-orange-700', icon: Clock, category: 'processing' },
       '200': { label: '准备就绪', color: 'bg-green-100 text-green-700', icon: CheckCircle, category: 'processing' },
       '201': { label: '上传视频中', color: 'bg-purple-100 text-purple-700', icon: Upload, category: 'uploading' },
       '299': { label: '上传失败', color: 'bg-red-100 text-red-700', icon: AlertCircle, category: 'failed' },
@@ -149,6 +150,7 @@ export default function TaskQueueStats({ onVideoSelect }: TaskQueueStatsProps) {
     const stageMap: { [key: string]: string } = {
       '001': '等待开始处理',
       '002': '正在执行准备任务链（下载视频→生成字幕→翻译字幕→生成元数据）',
+      '003': 'AI 费用超出预算，预算恢复后从暂停的步骤继续执行',
       '200': '准备阶段完成，等待视频上传（每小时上传1个）',
       '201': '正在上传视频到Bilibili',
       '299': '视频上传失败，需要重试',
@@ -164,7 +166,7 @@ export default function TaskQueueStats({ onVideoSelect }: TaskQueueStatsProps) {
   // 分类视频
   const categorizeVideos = () => {
     return {
-      processing: videos.filter(v => ['001', '002', '200'].includes(v.status)),
+      processing: videos.filter(v => ['001', '002', '003', '200'].includes(v.status)),
       uploading: videos.filter(v => ['201', '301'].includes(v.status)),
       uploaded: videos.filter(v => v.status === '300'),
       completed: videos.filter(v => v.status === '400'),
@@ -15,6 +15,18 @@ const STATUS_CONFIG = {
     icon: RefreshCw,
     description: '正在下载和处理视频'
   },
+  '003': {
+    label: '预算暂停',
+    className: 'status-budget-paused',
+    icon: Clock,
+    description: 'AI 费用超出预算，预算恢复后自动继续处理'
+  },
+  '150': {
+    label: '待人工审核',
+    className: 'status-needs-review',
+    icon: AlertCircle,
+    description: '翻译质量未达标，审核通过后才会上传'
+  },
   '200': {
     label: '准备就绪',
     className: 'status-ready',
@@ -27,6 +27,8 @@ export default function TaskStepList({ steps, onRetryStep, isRetrying = false }:
         return <XCircle className={`${className} text-red-500`} />;
       case 'skipped':
         return <AlertCircle className={`${className} text-yellow-500`} />;
+      case 'budget_paused':
+        return <Clock className={`${className} text-orange-500`} />;
       default:
         return <Clock className={`${className} text-gray-500`} />;
     }
@@ -136,9 +136,9 @@ export interface VideoFile {
   created_at: string;
 }
 
-export type TaskStepStatus = 'pending' | 'running' | 'completed' | 'failed' | 'skipped';
+export type TaskStepStatus = 'pending' | 'running' | 'completed' | 'failed' | 'skipped' | 'budget_paused';
 
-export type VideoStatus = '001' | '002' | '200' | '999';
+export type VideoStatus = '001' | '002' | '003' | '150' | '200' | '999';
 
 export interface Subtitle {
   id?: number;
@@ -195,6 +195,8 @@ export interface UploadValidation {
 export const VIDEO_STATUS_MAP = {
   '001': { label: '待处理', className: 'status-pending' },
   '002': { label: '处理中', className: 'status-processing' },
+  '003': { label: '预算暂停', className: 'status-budget-paused' },
+  '150': { label: '待人工审核', className: 'status-needs-review' },
   '200': { label: '已完成', className: 'status-completed' },
   '999': { label: '失败', className: 'status-failed' },
 } as const;
@@ -205,6 +207,7 @@ export const TASK_STEP_STATUS_MAP = {
   'completed': { label: '已完成', className: 'bg-green-100 text-green-800', color: 'green' },
   'failed': { label: '失败', className: 'bg-red-100 text-red-800', color: 'red' },
   'skipped': { label: '已跳过', className: 'bg-yellow-100 text-yellow-800', color: 'yellow' },
+  'budget_paused': { label: '预算暂停', className: 'bg-orange-100 text-orange-800', color: 'orange' },
 } as const;
 
 export const TASK_STEP_NAMES = {